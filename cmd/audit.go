@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/audit"
+)
+
+// runAudit runs an audit subcommand against the audit log and returns the
+// process exit code
+func runAudit(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, auditUsage)
+		return 2
+	}
+	switch args[0] {
+	case "fingerprints":
+		return runAuditFingerprints(args[1:], stdout, stderr)
+	case "verify":
+		return runAuditVerify(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown audit command %q\n%s\n", args[0], auditUsage)
+		return 2
+	}
+}
+
+const auditUsage = "usage: mcp-trino audit fingerprints|verify [flags]"
+
+// runAuditFingerprints prints the most frequent query shapes in the audit log
+func runAuditFingerprints(args []string, stdout, stderr io.Writer) int {
+	flagSet := flag.NewFlagSet("mcp-trino audit fingerprints", flag.ContinueOnError)
+	flagSet.SetOutput(stderr)
+	file := flagSet.String("file", getEnv("MCP_AUDIT_LOG", ""), "Audit log to read (default: MCP_AUDIT_LOG)")
+	since := flagSet.Duration("since", 7*24*time.Hour, "Only include calls within this window")
+	limit := flagSet.Int("limit", 20, "Maximum fingerprints to list (0 = all)")
+	asJSON := flagSet.Bool("json", false, "Print JSON instead of a table")
+	if err := flagSet.Parse(args); err != nil {
+		return 2
+	}
+	if *file == "" {
+		fmt.Fprintln(stderr, "no audit log: set MCP_AUDIT_LOG or pass -file")
+		return 2
+	}
+
+	records, err := audit.ReadAll(*file)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	stats := audit.AggregateByFingerprint(records, time.Now().Add(-*since), *limit)
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(stats); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	}
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FINGERPRINT\tCALLS\tFAILURES\tUSERS\tLAST SEEN\tQUERY")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\n", s.Fingerprint, s.Calls, s.Failures, s.Users, s.LastSeen.Format(time.RFC3339), s.NormalizedQuery)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// runAuditVerify checks the audit log's hash chain, and its signatures when
+// MCP_AUDIT_SIGNING_KEY is set, exiting 1 at the first altered record
+func runAuditVerify(args []string, stdout, stderr io.Writer) int {
+	flagSet := flag.NewFlagSet("mcp-trino audit verify", flag.ContinueOnError)
+	flagSet.SetOutput(stderr)
+	file := flagSet.String("file", getEnv("MCP_AUDIT_LOG", ""), "Audit log to verify (default: MCP_AUDIT_LOG)")
+	asJSON := flagSet.Bool("json", false, "Print JSON instead of text")
+	if err := flagSet.Parse(args); err != nil {
+		return 2
+	}
+	// Rotated logs can be listed oldest first to verify them as one chain
+	files := flagSet.Args()
+	if len(files) == 0 && *file != "" {
+		files = []string{*file}
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "no audit log: set MCP_AUDIT_LOG, pass -file, or list files")
+		return 2
+	}
+
+	result, err := audit.VerifyFiles(files, []byte(getEnv("MCP_AUDIT_SIGNING_KEY", "")))
+	var chainErr *audit.ChainError
+	if err != nil && !errors.As(err, &chainErr) {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if *asJSON {
+		output := struct {
+			*audit.VerifyResult
+			Valid bool   `json:"valid"`
+			Error string `json:"error,omitempty"`
+		}{VerifyResult: result, Valid: err == nil}
+		if err != nil {
+			output.Error = err.Error()
+		}
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if encodeErr := encoder.Encode(output); encodeErr != nil {
+			fmt.Fprintln(stderr, encodeErr)
+			return 1
+		}
+	} else if err != nil {
+		fmt.Fprintf(stdout, "FAILED: %v (%d records verified before it)\n", err, result.Records)
+	} else {
+		signatures := "signatures not checked (MCP_AUDIT_SIGNING_KEY unset)"
+		if result.Signed {
+			signatures = "signatures valid"
+		}
+		fmt.Fprintf(stdout, "OK: %d records (seq %d-%d), %s\n", result.Records, result.FirstSeq, result.LastSeq, signatures)
+		if result.Unchained > 0 {
+			fmt.Fprintf(stdout, "%d earlier records predate chaining and are not covered\n", result.Unchained)
+		}
+		if result.Anchor != "" {
+			fmt.Fprintf(stdout, "anchor: %s\n", result.Anchor)
+		}
+		fmt.Fprintf(stdout, "head: %s\n", result.Head)
+	}
+	if err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/audit"
+)
+
+func writeAuditLog(t *testing.T, queries ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := audit.Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, query := range queries {
+		record := audit.Record{Time: time.Now().UTC(), User: "alice", Tool: "execute_query", Status: audit.StatusOK}
+		record.SetQuery(query)
+		if err := logger.Log(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunAuditFingerprints(t *testing.T) {
+	path := writeAuditLog(t, "SELECT * FROM orders WHERE id = 1", "SELECT * FROM orders WHERE id = 2", "SHOW CATALOGS")
+
+	var stdout, stderr bytes.Buffer
+	if code := runAudit([]string{"fingerprints", "-file", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("runAudit() = %d, stderr: %s", code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "select * from orders where id=?") || !strings.Contains(lines[1], " 2 ") {
+		t.Errorf("table output:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := runAudit([]string{"fingerprints", "-file", path, "-json", "-limit", "1"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("runAudit() -json = %d, stderr: %s", code, stderr.String())
+	}
+	var stats []audit.FingerprintStats
+	if err := json.Unmarshal(stdout.Bytes(), &stats); err != nil || len(stats) != 1 || stats[0].Calls != 2 {
+		t.Errorf("JSON output = %s (%v)", stdout.String(), err)
+	}
+}
+
+func TestRunAuditUsage(t *testing.T) {
+	t.Setenv("MCP_AUDIT_LOG", "")
+	var stdout, stderr bytes.Buffer
+	for _, args := range [][]string{nil, {"rewrite"}, {"fingerprints"}, {"verify"}} {
+		if code := runAudit(args, &stdout, &stderr); code != 2 {
+			t.Errorf("runAudit(%v) = %d, want 2", args, code)
+		}
+	}
+}
+
+func TestRunAuditVerify(t *testing.T) {
+	t.Setenv("MCP_AUDIT_SIGNING_KEY", "")
+	path := writeAuditLog(t, "SELECT 1", "SELECT 2")
+
+	var stdout, stderr bytes.Buffer
+	if code := runAudit([]string{"verify", "-file", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("runAudit(verify) = %d, stdout: %s stderr: %s", code, stdout.String(), stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "OK: 2 records (seq 1-2)") || !strings.Contains(stdout.String(), "head: ") {
+		t.Errorf("verify output:\n%s", stdout.String())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, bytes.Replace(data, []byte("SELECT 2"), []byte("SELECT 3"), 1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if code := runAudit([]string{"verify", "-file", path, "-json"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("runAudit(verify) of tampered log = %d, want 1", code)
+	}
+	var output struct {
+		Records int    `json:"records"`
+		Valid   bool   `json:"valid"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil || output.Valid || output.Records != 1 || !strings.Contains(output.Error, "line 2") {
+		t.Errorf("JSON output = %s (%v)", stdout.String(), err)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckReadyURL(t *testing.T) {
+	ready := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":"unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ready"}`))
+	}))
+	defer srv.Close()
+
+	if err := checkReadyURL(context.Background(), srv.URL+"/readyz"); err != nil {
+		t.Errorf("checkReadyURL() error = %v, want nil when ready", err)
+	}
+
+	ready = false
+	err := checkReadyURL(context.Background(), srv.URL+"/readyz")
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("checkReadyURL() error = %v, want a 503 failure", err)
+	}
+}
+
+func TestLocalReadyURL(t *testing.T) {
+	t.Setenv("MCP_PORT", "9090")
+	t.Setenv("HTTPS_CERT_FILE", "")
+	t.Setenv("HTTPS_KEY_FILE", "")
+	if got := localReadyURL(); got != "http://localhost:9090/readyz" {
+		t.Errorf("localReadyURL() = %q", got)
+	}
+
+	t.Setenv("HTTPS_CERT_FILE", "/tls/cert.pem")
+	t.Setenv("HTTPS_KEY_FILE", "/tls/key.pem")
+	if got := localReadyURL(); got != "https://localhost:9090/readyz" {
+		t.Errorf("localReadyURL() = %q with TLS configured", got)
+	}
+}
+
+func TestRunHealthcheckURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	if code := runHealthcheck([]string{"-url", srv.URL + "/readyz", "-timeout", "2s"}); code != 1 {
+		t.Errorf("runHealthcheck() = %d, want 1 for an unready server", code)
+	}
+	if code := runHealthcheck([]string{"-bogus"}); code != 2 {
+		t.Errorf("runHealthcheck() = %d, want 2 for invalid flags", code)
+	}
+}
@@ -30,6 +30,15 @@ func main() {
 
 	args := os.Args[1:]
 
+	// Healthchecks and audit commands run before mode detection so they never
+	// start a server
+	if len(args) > 0 && args[0] == "healthcheck" {
+		os.Exit(runHealthcheck(args[1:]))
+	}
+	if len(args) > 0 && args[0] == "audit" {
+		os.Exit(runAudit(args[1:], os.Stdout, os.Stderr))
+	}
+
 	// Check for version flag first (works for both modes)
 	for _, arg := range args {
 		if arg == "--version" || arg == "-v" {
@@ -138,6 +147,11 @@ func runMCPServer() {
 	// Create MCP server
 	log.Println("Initializing MCP server...")
 	server := mcp.NewServer(trinoClient, trinoConfig, Version)
+	defer func() {
+		if err := server.Close(); err != nil {
+			log.Printf("Error closing MCP server: %v", err)
+		}
+	}()
 
 	// Choose server mode
 	transport := getEnv("MCP_TRANSPORT", "stdio")
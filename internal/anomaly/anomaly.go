@@ -0,0 +1,204 @@
+// Package anomaly flags unusual behavior per identity: sudden enumeration of
+// many tables, queries that scan far more data than the identity usually
+// does, and access outside business hours. Alerts give early warning of
+// compromised tokens and runaway agents. State is kept in memory, so each
+// server instance observes only the calls it serves.
+package anomaly
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Anomaly kinds
+const (
+	KindTableEnumeration = "table_enumeration"
+	KindScanSpike        = "scan_spike"
+	KindOffHours         = "off_hours"
+)
+
+const (
+	// DefaultCooldown is the minimum time between alerts of one kind for one
+	// identity, so a runaway agent raises one alert rather than thousands
+	DefaultCooldown = time.Hour
+
+	// scanBaselineSamples is how many queries an identity runs before its
+	// scans are compared with its baseline
+	scanBaselineSamples = 10
+
+	// scanBaselineWeight is the weight of the newest query in the moving
+	// average of bytes scanned
+	scanBaselineWeight = 0.1
+
+	// idleTTL is how long an identity's state is kept without activity
+	idleTTL = 24 * time.Hour
+)
+
+// Options configures a detector. A zero threshold or factor disables that
+// check.
+type Options struct {
+	TableThreshold  int            // distinct tables within Window that flag enumeration
+	Window          time.Duration  // window for counting distinct tables
+	ScanSpikeFactor float64        // multiple of the identity's average scan that flags a spike
+	ScanMinBytes    int64          // scans smaller than this are never spikes
+	BusinessHours   *BusinessHours // calls outside these hours are flagged (nil = disabled)
+	Cooldown        time.Duration  // minimum time between alerts of one kind per identity (default: DefaultCooldown)
+}
+
+// Alert is one detected anomaly.
+type Alert struct {
+	Time   time.Time
+	User   string
+	Kind   string
+	Tool   string // empty for scan spikes, which are detected after the call
+	Detail string
+}
+
+// Call is one tool call by an identity.
+type Call struct {
+	Time   time.Time
+	User   string
+	Tool   string
+	Tables []string // tables the call read or described
+}
+
+// Detector tracks recent behavior per identity. It is safe for concurrent
+// use.
+type Detector struct {
+	opts Options
+
+	mu         sync.Mutex
+	identities map[string]*identity
+	lastSweep  time.Time
+}
+
+// identity is the recent behavior of one caller
+type identity struct {
+	lastSeen    time.Time
+	tables      map[string]time.Time // table -> last access within the window
+	scanMean    float64              // moving average of bytes scanned per query
+	scanSamples int
+	lastAlert   map[string]time.Time // kind -> last alert
+}
+
+// New creates a detector.
+func New(opts Options) *Detector {
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = DefaultCooldown
+	}
+	return &Detector{opts: opts, identities: make(map[string]*identity)}
+}
+
+// ObserveCall records a tool call and returns the anomalies it reveals.
+func (d *Detector) ObserveCall(call Call) []Alert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	id := d.identity(call.User, call.Time)
+
+	var alerts []Alert
+	if hours := d.opts.BusinessHours; hours != nil && !hours.Contains(call.Time) {
+		detail := fmt.Sprintf("%s called at %s, outside business hours (%s)", call.Tool, call.Time.In(hours.location).Format("Mon 15:04 MST"), hours)
+		alerts = d.alert(alerts, id, call.User, KindOffHours, call.Tool, detail, call.Time)
+	}
+
+	if d.opts.TableThreshold > 0 && len(call.Tables) > 0 {
+		for _, table := range call.Tables {
+			id.tables[table] = call.Time
+		}
+		cutoff := call.Time.Add(-d.opts.Window)
+		for table, seen := range id.tables {
+			if seen.Before(cutoff) {
+				delete(id.tables, table)
+			}
+		}
+		if len(id.tables) >= d.opts.TableThreshold {
+			detail := fmt.Sprintf("%d distinct tables accessed within %s, including %s", len(id.tables), d.opts.Window, sampleTables(id.tables, 5))
+			alerts = d.alert(alerts, id, call.User, KindTableEnumeration, call.Tool, detail, call.Time)
+		}
+	}
+	return alerts
+}
+
+// ObserveScan records the bytes one query scanned and returns an alert if
+// the scan is far above the identity's average.
+func (d *Detector) ObserveScan(user, queryID string, scannedBytes int64, now time.Time) []Alert {
+	if d.opts.ScanSpikeFactor <= 0 {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	id := d.identity(user, now)
+
+	var alerts []Alert
+	if id.scanSamples >= scanBaselineSamples && scannedBytes >= d.opts.ScanMinBytes &&
+		float64(scannedBytes) >= d.opts.ScanSpikeFactor*id.scanMean {
+		detail := fmt.Sprintf("query %s scanned %s, %.1fx the usual %s", queryID, formatBytes(float64(scannedBytes)), float64(scannedBytes)/max(id.scanMean, 1), formatBytes(id.scanMean))
+		alerts = d.alert(alerts, id, user, KindScanSpike, "", detail, now)
+	}
+
+	if id.scanSamples == 0 {
+		id.scanMean = float64(scannedBytes)
+	} else {
+		id.scanMean += scanBaselineWeight * (float64(scannedBytes) - id.scanMean)
+	}
+	id.scanSamples++
+	return alerts
+}
+
+// identity returns the state for a user, creating it if needed. Idle
+// identities are dropped at most once per idleTTL.
+func (d *Detector) identity(user string, now time.Time) *identity {
+	if now.Sub(d.lastSweep) >= idleTTL {
+		for name, id := range d.identities {
+			if now.Sub(id.lastSeen) >= idleTTL {
+				delete(d.identities, name)
+			}
+		}
+		d.lastSweep = now
+	}
+	id, ok := d.identities[user]
+	if !ok {
+		id = &identity{tables: make(map[string]time.Time), lastAlert: make(map[string]time.Time)}
+		d.identities[user] = id
+	}
+	if now.After(id.lastSeen) {
+		id.lastSeen = now
+	}
+	return id
+}
+
+// alert appends an alert unless one of the same kind was raised for the
+// identity within the cooldown
+func (d *Detector) alert(alerts []Alert, id *identity, user, kind, tool, detail string, now time.Time) []Alert {
+	if last, ok := id.lastAlert[kind]; ok && now.Sub(last) < d.opts.Cooldown {
+		return alerts
+	}
+	id.lastAlert[kind] = now
+	return append(alerts, Alert{Time: now, User: user, Kind: kind, Tool: tool, Detail: detail})
+}
+
+// sampleTables lists up to n of the tables, sorted, for alert details
+func sampleTables(tables map[string]time.Time, n int) string {
+	names := make([]string, 0, len(tables))
+	for table := range tables {
+		names = append(names, table)
+	}
+	sort.Strings(names)
+	if len(names) > n {
+		return fmt.Sprintf("%v and %d more", names[:n], len(names)-n)
+	}
+	return fmt.Sprint(names)
+}
+
+// formatBytes renders a byte count with a binary unit
+func formatBytes(bytes float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	i := 0
+	for bytes >= 1024 && i < len(units)-1 {
+		bytes /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", bytes, units[i])
+}
@@ -0,0 +1,92 @@
+package anomaly
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+
+func TestTableEnumeration(t *testing.T) {
+	d := New(Options{TableThreshold: 5, Window: 10 * time.Minute})
+
+	// Tables outside the window do not count
+	d.ObserveCall(Call{Time: start, User: "alice", Tool: "get_table_schema", Tables: []string{"hive.raw.t0"}})
+	for i := 1; i <= 3; i++ {
+		call := Call{Time: start.Add(20*time.Minute + time.Duration(i)*time.Second), User: "alice", Tool: "get_table_schema", Tables: []string{fmt.Sprintf("hive.raw.t%d", i)}}
+		if alerts := d.ObserveCall(call); len(alerts) != 0 {
+			t.Fatalf("alert after %d tables: %+v", i, alerts)
+		}
+	}
+	// Repeated tables and other identities do not count
+	d.ObserveCall(Call{Time: start.Add(21 * time.Minute), User: "alice", Tool: "execute_query", Tables: []string{"hive.raw.t1"}})
+	d.ObserveCall(Call{Time: start.Add(21 * time.Minute), User: "bob", Tool: "execute_query", Tables: []string{"hive.raw.t4"}})
+
+	alerts := d.ObserveCall(Call{Time: start.Add(22 * time.Minute), User: "alice", Tool: "execute_query", Tables: []string{"hive.raw.t4", "hive.raw.t5"}})
+	if len(alerts) != 1 || alerts[0].Kind != KindTableEnumeration || alerts[0].User != "alice" || alerts[0].Tool != "execute_query" {
+		t.Fatalf("alerts = %+v, want one enumeration alert for alice", alerts)
+	}
+	if !strings.Contains(alerts[0].Detail, "5 distinct tables") || !strings.Contains(alerts[0].Detail, "hive.raw.t1") {
+		t.Errorf("Detail = %q", alerts[0].Detail)
+	}
+
+	// The cooldown suppresses repeats
+	if alerts := d.ObserveCall(Call{Time: start.Add(23 * time.Minute), User: "alice", Tool: "execute_query", Tables: []string{"hive.raw.t6"}}); len(alerts) != 0 {
+		t.Errorf("alert within the cooldown: %+v", alerts)
+	}
+}
+
+func TestScanSpike(t *testing.T) {
+	d := New(Options{ScanSpikeFactor: 10, ScanMinBytes: 1 << 30})
+	for i := 0; i < scanBaselineSamples; i++ {
+		if alerts := d.ObserveScan("alice", "q", 200<<20, start); len(alerts) != 0 {
+			t.Fatalf("alert while learning the baseline: %+v", alerts)
+		}
+	}
+	// Large relative to the baseline, but under the minimum
+	if alerts := d.ObserveScan("alice", "q", 900<<20, start); len(alerts) != 0 {
+		t.Errorf("alert under the minimum scan: %+v", alerts)
+	}
+	alerts := d.ObserveScan("alice", "20261014_100000_00001_abcde", 50<<30, start)
+	if len(alerts) != 1 || alerts[0].Kind != KindScanSpike || !strings.Contains(alerts[0].Detail, "20261014_100000_00001_abcde scanned 50.0 GB") {
+		t.Fatalf("alerts = %+v, want a scan spike", alerts)
+	}
+	// A new identity has no baseline yet
+	if alerts := d.ObserveScan("bob", "q", 50<<30, start); len(alerts) != 0 {
+		t.Errorf("alert without a baseline: %+v", alerts)
+	}
+}
+
+func TestOffHours(t *testing.T) {
+	hours, err := ParseBusinessHours("mon-fri 08:00-18:00", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := New(Options{BusinessHours: hours, Cooldown: 30 * time.Minute})
+
+	if alerts := d.ObserveCall(Call{Time: start, User: "alice", Tool: "list_catalogs"}); len(alerts) != 0 {
+		t.Errorf("alert during business hours: %+v", alerts)
+	}
+	night := time.Date(2026, 10, 17, 2, 14, 0, 0, time.UTC)
+	alerts := d.ObserveCall(Call{Time: night, User: "alice", Tool: "execute_query"})
+	if len(alerts) != 1 || alerts[0].Kind != KindOffHours || !strings.Contains(alerts[0].Detail, "Sat 02:14 UTC") {
+		t.Fatalf("alerts = %+v, want an off-hours alert", alerts)
+	}
+	if alerts := d.ObserveCall(Call{Time: night.Add(10 * time.Minute), User: "alice", Tool: "execute_query"}); len(alerts) != 0 {
+		t.Errorf("alert within the cooldown: %+v", alerts)
+	}
+	if alerts := d.ObserveCall(Call{Time: night.Add(40 * time.Minute), User: "alice", Tool: "execute_query"}); len(alerts) != 1 {
+		t.Errorf("alerts after the cooldown = %+v, want one", alerts)
+	}
+}
+
+func TestIdleIdentitiesDropped(t *testing.T) {
+	d := New(Options{TableThreshold: 100, Window: time.Hour})
+	d.ObserveCall(Call{Time: start, User: "alice", Tables: []string{"a.b.c"}})
+	d.ObserveCall(Call{Time: start.Add(2 * idleTTL), User: "bob", Tables: []string{"a.b.c"}})
+	if _, ok := d.identities["alice"]; ok || len(d.identities) != 1 {
+		t.Errorf("identities = %d, want only bob after alice went idle", len(d.identities))
+	}
+}
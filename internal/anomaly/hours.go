@@ -0,0 +1,109 @@
+package anomaly
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // time zone names resolve in images without zoneinfo
+)
+
+// weekdays maps day abbreviations to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// BusinessHours is a weekly window of expected activity.
+type BusinessHours struct {
+	spec     string
+	days     [7]bool
+	start    int // minutes after midnight
+	end      int // minutes after midnight; before start for windows past midnight
+	location *time.Location
+}
+
+// ParseBusinessHours parses a window such as "mon-fri 08:00-18:00" in the
+// given location. Days are a range or comma-separated list of three-letter
+// names, and every day is included when they are omitted. An end before
+// the start spans midnight, as in "22:00-06:00".
+func ParseBusinessHours(spec string, location *time.Location) (*BusinessHours, error) {
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid business hours %q: want [days] HH:MM-HH:MM, such as mon-fri 08:00-18:00", spec)
+	}
+	hours := &BusinessHours{spec: strings.Join(fields, " "), location: location}
+	if len(fields) == 1 {
+		hours.days = [7]bool{true, true, true, true, true, true, true}
+	} else if err := hours.parseDays(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid business hours %q: %w", spec, err)
+	}
+
+	startText, endText, ok := strings.Cut(fields[len(fields)-1], "-")
+	var err error
+	if ok {
+		if hours.start, err = parseClock(startText); err == nil {
+			hours.end, err = parseClock(endText)
+		}
+	}
+	if !ok || err != nil || hours.start == hours.end {
+		return nil, fmt.Errorf("invalid business hours %q: want a time range such as 08:00-18:00", spec)
+	}
+	return hours, nil
+}
+
+// parseDays parses "mon-fri" or "mon,wed,fri"
+func (b *BusinessHours) parseDays(spec string) error {
+	for _, part := range strings.Split(spec, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		from, ok := weekdays[first]
+		if !ok {
+			return fmt.Errorf("unknown day %q", first)
+		}
+		to := from
+		if isRange {
+			if to, ok = weekdays[last]; !ok {
+				return fmt.Errorf("unknown day %q", last)
+			}
+		}
+		for day := from; ; day = (day + 1) % 7 {
+			b.days[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseClock parses HH:MM into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains reports whether t falls within business hours. A window that
+// spans midnight belongs to the day it starts on.
+func (b *BusinessHours) Contains(t time.Time) bool {
+	t = t.In(b.location)
+	minute := t.Hour()*60 + t.Minute()
+	if b.start < b.end {
+		return b.days[t.Weekday()] && minute >= b.start && minute < b.end
+	}
+	if minute >= b.start {
+		return b.days[t.Weekday()]
+	}
+	return minute < b.end && b.days[(t.Weekday()+6)%7]
+}
+
+// String returns the window as configured, with its location.
+func (b *BusinessHours) String() string {
+	return b.spec + " " + b.location.String()
+}
@@ -0,0 +1,76 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBusinessHours(t *testing.T) {
+	hours, err := ParseBusinessHours("Mon-Fri 08:00-18:00", time.UTC)
+	if err != nil {
+		t.Fatalf("ParseBusinessHours() error = %v", err)
+	}
+	tests := []struct {
+		time time.Time
+		want bool
+	}{
+		{time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC), true},   // Wednesday morning
+		{time.Date(2026, 10, 14, 18, 0, 0, 0, time.UTC), false},  // end is exclusive
+		{time.Date(2026, 10, 14, 7, 59, 0, 0, time.UTC), false},  // before start
+		{time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC), false},  // Saturday
+		{time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), true},   // Friday
+		{time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), true},    // Monday at the start
+		{time.Date(2026, 10, 14, 23, 0, 0, 0, time.UTC), false},  // night
+		{time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC), false}, // Sunday night
+	}
+	for _, tt := range tests {
+		if got := hours.Contains(tt.time); got != tt.want {
+			t.Errorf("Contains(%s) = %t, want %t", tt.time.Format(time.RFC1123), got, tt.want)
+		}
+	}
+	if hours.String() != "mon-fri 08:00-18:00 UTC" {
+		t.Errorf("String() = %q", hours.String())
+	}
+}
+
+func TestParseBusinessHoursOvernight(t *testing.T) {
+	// A night shift starting Sunday through Thursday
+	hours, err := ParseBusinessHours("sun-thu 22:00-06:00", time.UTC)
+	if err != nil {
+		t.Fatalf("ParseBusinessHours() error = %v", err)
+	}
+	if !hours.Contains(time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)) { // Sunday night
+		t.Error("Sunday 23:00 should be within the shift")
+	}
+	if !hours.Contains(time.Date(2026, 10, 16, 5, 0, 0, 0, time.UTC)) { // Friday early, from Thursday's shift
+		t.Error("Friday 05:00 should be within Thursday's shift")
+	}
+	if hours.Contains(time.Date(2026, 10, 17, 5, 0, 0, 0, time.UTC)) { // Saturday early, Friday has no shift
+		t.Error("Saturday 05:00 should be outside the shift")
+	}
+	if hours.Contains(time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)) {
+		t.Error("midday should be outside the shift")
+	}
+}
+
+func TestParseBusinessHoursLocation(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+	hours, err := ParseBusinessHours("09:00-17:00", tokyo)
+	if err != nil {
+		t.Fatalf("ParseBusinessHours() error = %v", err)
+	}
+	if !hours.Contains(time.Date(2026, 10, 17, 1, 0, 0, 0, time.UTC)) { // 10:00 JST, any day
+		t.Error("10:00 JST should be within business hours")
+	}
+	if hours.Contains(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)) { // 21:00 JST
+		t.Error("21:00 JST should be outside business hours")
+	}
+}
+
+func TestParseBusinessHoursInvalid(t *testing.T) {
+	for _, spec := range []string{"", "weekdays 08:00-18:00", "mon-fri", "mon-fri 8am-6pm", "08:00-08:00", "mon 08:00-18:00 extra"} {
+		if _, err := ParseBusinessHours(spec, time.UTC); err == nil {
+			t.Errorf("ParseBusinessHours(%q) should fail", spec)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+// Package audit records every tool call to an append-only JSON Lines file
+// and aggregates the records by query fingerprint, so reviews can ask what
+// kinds of queries agents run instead of reading every raw query. Records
+// are hash chained, and optionally signed, so later edits are detectable.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Record is one tool call.
+type Record struct {
+	Time            time.Time `json:"time"`
+	User            string    `json:"user"`
+	Tool            string    `json:"tool"`
+	Query           string    `json:"query,omitempty"`
+	NormalizedQuery string    `json:"normalized_query,omitempty"`
+	Fingerprint     string    `json:"fingerprint,omitempty"`
+	Tables          []string  `json:"tables,omitempty"` // tables the call read or modified
+	Write           bool      `json:"write,omitempty"`  // the query modifies data
+	Status          string    `json:"status"`           // "ok" or "error"
+	Error           string    `json:"error,omitempty"`
+	DurationMs      int64     `json:"duration_ms"`
+
+	// Chain fields, filled in by Logger.Log
+	Seq       int64  `json:"seq,omitempty"`
+	PrevHash  string `json:"prev_hash,omitempty"`
+	Hash      string `json:"hash,omitempty"`
+	Signature string `json:"signature,omitempty"` // HMAC of Hash when a signing key is set
+}
+
+// Record statuses
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// SetQuery stores the query with its normalized form and fingerprint.
+func (r *Record) SetQuery(query string) {
+	r.Query = query
+	r.NormalizedQuery = Normalize(query)
+	r.Fingerprint = Fingerprint(r.NormalizedQuery)
+}
+
+// Logger appends hash-chained records to an audit file.
+type Logger struct {
+	mu         sync.Mutex
+	path       string
+	file       *os.File
+	signingKey []byte
+	seq        int64  // sequence number of the last record
+	head       string // hash of the last record
+}
+
+// Open opens the audit file for appending, creating it if needed, and
+// continues the hash chain from its last record. A non-empty signingKey
+// also signs every record.
+func Open(path string, signingKey []byte) (*Logger, error) {
+	seq, head, err := chainHead(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 -- operator-configured audit file
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Logger{path: path, file: file, signingKey: signingKey, seq: seq, head: head}, nil
+}
+
+// chainHead returns the sequence number and hash of the last record in an
+// existing audit file, or in its most recent rotated file when it is empty,
+// or zero values when there is none
+func chainHead(path string) (int64, string, error) {
+	candidates := []string{path}
+	if rotated, _ := filepath.Glob(path + ".*"); len(rotated) > 0 {
+		sort.Strings(rotated)
+		candidates = append(candidates, rotated[len(rotated)-1])
+	}
+	for _, candidate := range candidates {
+		last, err := lastRecord(candidate)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		if last != nil {
+			return last.Seq, last.Hash, nil
+		}
+	}
+	return 0, "", nil
+}
+
+// tailChunkBytes is how much of an audit file lastRecord reads at a time
+const tailChunkBytes = 64 << 10
+
+// lastRecord returns the last record in an audit file, or nil when it has
+// none. It reads backwards from the end of the file and parses only the last
+// line, so opening a large audit file stays fast.
+func lastRecord(path string) (*Record, error) {
+	file, err := os.Open(path) // #nosec G304 -- operator-configured audit file
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	// Find the last line, skipping the newlines that end the file
+	start, end := int64(0), info.Size()
+	buf := make([]byte, tailChunkBytes)
+scan:
+	for pos := end; pos > 0; {
+		n := min(int64(len(buf)), pos)
+		pos -= n
+		if _, err := file.ReadAt(buf[:n], pos); err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+		for i := n - 1; i >= 0; i-- {
+			if c := buf[i]; c != '\n' && c != '\r' {
+				continue
+			}
+			if pos+i+1 == end {
+				end = pos + i
+				continue
+			}
+			start = pos + i + 1
+			break scan
+		}
+		if end-pos > maxRecordBytes {
+			return nil, fmt.Errorf("failed to read audit log: last record is larger than %d bytes", maxRecordBytes)
+		}
+	}
+	if start >= end {
+		return nil, nil
+	}
+
+	line := make([]byte, end-start)
+	if _, err := file.ReadAt(line, start); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	var record Record
+	if err := json.Unmarshal(line, &record); err != nil {
+		return nil, fmt.Errorf("audit log last line: %w", err)
+	}
+	return &record, nil
+}
+
+// Log chains the record to the previous one and appends it as one JSON line.
+func (l *Logger) Log(record Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	record.Seq, record.PrevHash = l.seq+1, l.head
+	if err := seal(&record, l.signingKey); err != nil {
+		return err
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	l.seq, l.head = record.Seq, record.Hash
+	return nil
+}
+
+// Path returns the audit file's path.
+func (l *Logger) Path() string {
+	return l.path
+}
+
+// Size returns the current size of the audit file in bytes.
+func (l *Logger) Size() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	info, err := l.file.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// Rotate renames the audit file with a timestamp suffix and continues in a
+// new file. The hash chain carries over, so the new file's first prev_hash is
+// the rotated file's head. An empty file is not rotated.
+func (l *Logger) Rotate(now time.Time) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	info, err := l.file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	if info.Size() == 0 {
+		return "", nil
+	}
+
+	rotated := l.path + "." + now.UTC().Format("20060102T150405Z")
+	for i := 1; fileExists(rotated); i++ {
+		rotated = fmt.Sprintf("%s.%s-%d", l.path, now.UTC().Format("20060102T150405Z"), i)
+	}
+	if err := os.Rename(l.path, rotated); err != nil {
+		return "", fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 -- operator-configured audit file
+	if err != nil {
+		return "", fmt.Errorf("failed to reopen audit log after rotation: %w", err)
+	}
+	_ = l.file.Close()
+	l.file = file
+	return rotated, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Close closes the audit file.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// ReadFile reads the records in an audit file.
+func ReadFile(path string) ([]Record, error) {
+	file, err := os.Open(path) // #nosec G304 -- operator-supplied audit file
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	return Read(file)
+}
+
+// ReadAll reads the records in an audit file and its rotated files, oldest
+// first. A missing live file is not an error when rotated files exist.
+func ReadAll(path string) ([]Record, error) {
+	rotated, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rotated audit logs: %w", err)
+	}
+	sort.Strings(rotated)
+
+	var records []Record
+	for _, file := range append(rotated, path) {
+		fileRecords, err := ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) && len(rotated) > 0 {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+	return records, nil
+}
+
+// maxRecordBytes is the longest audit record line read
+const maxRecordBytes = 16 << 20
+
+// Read decodes records from JSON Lines.
+func Read(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordBytes)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("audit log line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return records, nil
+}
+
+// FingerprintStats aggregates the calls that share a query fingerprint.
+type FingerprintStats struct {
+	Fingerprint     string    `json:"fingerprint"`
+	NormalizedQuery string    `json:"normalized_query"`
+	Calls           int       `json:"calls"`
+	Failures        int       `json:"failures"`
+	Users           int       `json:"users"`
+	Tools           []string  `json:"tools"`
+	TotalDurationMs int64     `json:"total_duration_ms"`
+	FirstSeen       time.Time `json:"first_seen"`
+	LastSeen        time.Time `json:"last_seen"`
+	SampleQuery     string    `json:"sample_query"` // most recent raw query
+}
+
+// AggregateByFingerprint groups records with a query at or after since by
+// fingerprint, most frequent first. Records without a query, such as
+// listing tool calls, are skipped. A limit of 0 returns every fingerprint.
+func AggregateByFingerprint(records []Record, since time.Time, limit int) []FingerprintStats {
+	byFingerprint := make(map[string]*FingerprintStats)
+	users := make(map[string]map[string]bool)
+	tools := make(map[string]map[string]bool)
+	for _, record := range records {
+		if record.Fingerprint == "" || record.Time.Before(since) {
+			continue
+		}
+		stats, ok := byFingerprint[record.Fingerprint]
+		if !ok {
+			stats = &FingerprintStats{
+				Fingerprint:     record.Fingerprint,
+				NormalizedQuery: record.NormalizedQuery,
+				FirstSeen:       record.Time,
+			}
+			byFingerprint[record.Fingerprint] = stats
+			users[record.Fingerprint] = make(map[string]bool)
+			tools[record.Fingerprint] = make(map[string]bool)
+		}
+		stats.Calls++
+		if record.Status == StatusError {
+			stats.Failures++
+		}
+		stats.TotalDurationMs += record.DurationMs
+		if record.Time.Before(stats.FirstSeen) {
+			stats.FirstSeen = record.Time
+		}
+		if !record.Time.Before(stats.LastSeen) {
+			stats.LastSeen = record.Time
+			stats.SampleQuery = record.Query
+		}
+		users[record.Fingerprint][record.User] = true
+		tools[record.Fingerprint][record.Tool] = true
+	}
+
+	ranked := make([]FingerprintStats, 0, len(byFingerprint))
+	for fingerprint, stats := range byFingerprint {
+		stats.Users = len(users[fingerprint])
+		for tool := range tools[fingerprint] {
+			stats.Tools = append(stats.Tools, tool)
+		}
+		sort.Strings(stats.Tools)
+		ranked = append(ranked, *stats)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Calls != ranked[j].Calls {
+			return ranked[i].Calls > ranked[j].Calls
+		}
+		return ranked[i].Fingerprint < ranked[j].Fingerprint
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
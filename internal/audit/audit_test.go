@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := Open(path, nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	record := Record{Time: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), User: "alice", Tool: "execute_query", Status: StatusOK, DurationMs: 120}
+	record.SetQuery("SELECT * FROM orders WHERE id = 1")
+	if err := logger.Log(record); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening appends rather than truncating
+	logger, err = Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Log(Record{Time: record.Time, User: "bob", Tool: "list_catalogs", Status: StatusOK}); err != nil {
+		t.Fatal(err)
+	}
+	_ = logger.Close()
+
+	records, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if len(records) != 2 || records[1].User != "bob" {
+		t.Fatalf("ReadFile() = %+v", records)
+	}
+	// Reopening continues the hash chain
+	if records[1].Seq != 2 || records[1].PrevHash != records[0].Hash {
+		t.Errorf("chain not continued after reopen: %+v", records[1])
+	}
+	stored := records[0]
+	stored.Seq, stored.PrevHash, stored.Hash, stored.Signature = 0, "", "", ""
+	if !reflect.DeepEqual(stored, record) {
+		t.Errorf("ReadFile() = %+v, want %+v", stored, record)
+	}
+	if record.NormalizedQuery != "select * from orders where id=?" || record.Fingerprint == "" {
+		t.Errorf("SetQuery() = %q, %q", record.NormalizedQuery, record.Fingerprint)
+	}
+}
+
+func TestOpenContinuesChainFromLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The last record spans several of the chunks read from the end
+	long := Record{User: "alice", Tool: "execute_query", Status: StatusOK}
+	long.SetQuery("SELECT '" + strings.Repeat("x", 3*tailChunkBytes) + "'")
+	for _, record := range []Record{{User: "alice", Tool: "list_catalogs", Status: StatusOK}, long} {
+		if err := logger.Log(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = logger.Close()
+
+	// Blank lines after the last record are skipped
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.WriteString("\n\n")
+	_ = file.Close()
+
+	if last, err := lastRecord(path); err != nil || last == nil || last.Seq != 2 {
+		t.Fatalf("lastRecord() = %+v, %v; want record 2", last, err)
+	}
+	logger, err = Open(path, nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := logger.Log(Record{User: "bob", Tool: "list_catalogs", Status: StatusOK}); err != nil {
+		t.Fatal(err)
+	}
+	_ = logger.Close()
+
+	records, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[2].Seq != 3 || records[2].PrevHash != records[1].Hash {
+		t.Errorf("chain not continued from the last line: %+v", records[len(records)-1])
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.jsonl")
+	if err := os.WriteFile(empty, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := lastRecord(empty); err != nil || last != nil {
+		t.Errorf("lastRecord() of an empty file = %+v, %v; want none", last, err)
+	}
+}
+
+func TestAggregateByFingerprint(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	call := func(minutes int, user, query, status string) Record {
+		record := Record{Time: start.Add(time.Duration(minutes) * time.Minute), User: user, Tool: "execute_query", Status: status, DurationMs: 100}
+		if query != "" {
+			record.SetQuery(query)
+		}
+		return record
+	}
+	records := []Record{
+		call(-60, "alice", "SELECT * FROM orders WHERE id = 0", StatusOK), // before since
+		call(1, "alice", "SELECT * FROM orders WHERE id = 1", StatusOK),
+		call(2, "bob", "SELECT * FROM orders WHERE id = 2", StatusError),
+		call(3, "alice", "SELECT * FROM orders WHERE id = 3", StatusOK),
+		call(4, "carol", "SELECT count(*) FROM refunds", StatusOK),
+		call(5, "carol", "", StatusOK),
+	}
+
+	stats := AggregateByFingerprint(records, start, 0)
+	if len(stats) != 2 {
+		t.Fatalf("AggregateByFingerprint() returned %d fingerprints, want 2", len(stats))
+	}
+	top := stats[0]
+	if top.NormalizedQuery != "select * from orders where id=?" || top.Calls != 3 || top.Failures != 1 || top.Users != 2 || top.TotalDurationMs != 300 {
+		t.Errorf("top fingerprint = %+v", top)
+	}
+	if !top.FirstSeen.Equal(start.Add(time.Minute)) || !top.LastSeen.Equal(start.Add(3*time.Minute)) || top.SampleQuery != "SELECT * FROM orders WHERE id = 3" {
+		t.Errorf("top fingerprint times = %s..%s, sample %q", top.FirstSeen, top.LastSeen, top.SampleQuery)
+	}
+	if got := AggregateByFingerprint(records, start, 1); len(got) != 1 {
+		t.Errorf("limit 1 returned %d fingerprints", len(got))
+	}
+}
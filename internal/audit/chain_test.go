@@ -0,0 +1,171 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeChain(t *testing.T, key []byte, users ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, user := range users {
+		record := Record{Time: time.Date(2026, 10, 1, 9, i, 0, 0, time.UTC), User: user, Tool: "execute_query", Status: StatusOK}
+		record.SetQuery("SELECT 1")
+		if err := logger.Log(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestVerifyIntactChain(t *testing.T) {
+	key := []byte(strings.Repeat("k", 32))
+	path := writeChain(t, key, "alice", "bob", "carol")
+
+	result, err := VerifyFile(path, key)
+	if err != nil {
+		t.Fatalf("VerifyFile() error = %v", err)
+	}
+	if result.Records != 3 || !result.Signed || result.FirstSeq != 1 || result.LastSeq != 3 || result.Anchor != "" || result.Head == "" {
+		t.Errorf("VerifyFile() = %+v", result)
+	}
+	// Without the key the chain still verifies, unsigned
+	if result, err := VerifyFile(path, nil); err != nil || result.Signed {
+		t.Errorf("VerifyFile() without key = %+v, %v", result, err)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	key := []byte(strings.Repeat("k", 32))
+	tests := []struct {
+		name   string
+		edit   func(lines []string) []string
+		key    []byte
+		line   int
+		reason string
+	}{
+		{"edited record", func(lines []string) []string {
+			lines[1] = strings.Replace(lines[1], `"bob"`, `"mallory"`, 1)
+			return lines
+		}, nil, 2, "does not match its hash"},
+		{"deleted record", func(lines []string) []string {
+			return append(lines[:1], lines[2:]...)
+		}, nil, 2, "prev_hash"},
+		{"reordered records", func(lines []string) []string {
+			lines[1], lines[2] = lines[2], lines[1]
+			return lines
+		}, nil, 2, "prev_hash"},
+		{"inserted unchained record", func(lines []string) []string {
+			return append(lines[:2], append([]string{`{"user":"mallory","tool":"execute_query","status":"ok","duration_ms":0}`}, lines[2:]...)...)
+		}, nil, 3, "not chained"},
+		{"wrong signing key", func(lines []string) []string { return lines }, []byte(strings.Repeat("x", 32)), 1, "invalid signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeChain(t, key, "alice", "bob", "carol")
+			lines := tt.edit(readLines(t, path))
+			if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			verifyKey := key
+			if tt.key != nil {
+				verifyKey = tt.key
+			}
+			_, err := VerifyFile(path, verifyKey)
+			var chainErr *ChainError
+			if !errors.As(err, &chainErr) || chainErr.Line != tt.line || !strings.Contains(chainErr.Reason, tt.reason) {
+				t.Errorf("VerifyFile() error = %v, want line %d %q", err, tt.line, tt.reason)
+			}
+		})
+	}
+}
+
+func TestVerifyLegacyAndRotatedFiles(t *testing.T) {
+	path := writeChain(t, nil, "alice", "bob", "carol")
+	lines := readLines(t, path)
+
+	// Records from before chaining are allowed ahead of the chain
+	legacy := `{"time":"2026-09-30T00:00:00Z","user":"old","tool":"list_catalogs","status":"ok","duration_ms":3}`
+	legacyPath := filepath.Join(t.TempDir(), "legacy.jsonl")
+	if err := os.WriteFile(legacyPath, []byte(legacy+"\n"+strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := VerifyFile(legacyPath, nil); err != nil || result.Unchained != 1 || result.Records != 3 {
+		t.Errorf("VerifyFile() with legacy records = %+v, %v", result, err)
+	}
+
+	// A file starting mid-chain reports its anchor
+	rotatedPath := filepath.Join(t.TempDir(), "rotated.jsonl")
+	if err := os.WriteFile(rotatedPath, []byte(strings.Join(lines[1:], "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	first, _ := VerifyFile(path, nil)
+	result, err := VerifyFile(rotatedPath, nil)
+	if err != nil || result.FirstSeq != 2 || result.Anchor == "" || result.Head != first.Head {
+		t.Errorf("VerifyFile() of rotated file = %+v, %v", result, err)
+	}
+}
+
+func TestRotationContinuesChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := func(user string) {
+		if err := logger.Log(Record{Time: time.Now().UTC(), User: user, Tool: "list_catalogs", Status: StatusOK}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	log("alice")
+	log("bob")
+	rotated, err := logger.Rotate(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC))
+	if err != nil || rotated != path+".20261017T120000Z" {
+		t.Fatalf("Rotate() = %q, %v", rotated, err)
+	}
+	if logger.Size() != 0 {
+		t.Errorf("Size() after rotation = %d", logger.Size())
+	}
+	if again, err := logger.Rotate(time.Now()); err != nil || again != "" {
+		t.Errorf("Rotate() of an empty log = %q, %v", again, err)
+	}
+	_ = logger.Close()
+
+	// Reopening an empty log resumes from the rotated file's head
+	logger, err = Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log("carol")
+	_ = logger.Close()
+
+	result, err := VerifyFiles([]string{rotated, path}, nil)
+	if err != nil || result.Records != 3 || result.FirstSeq != 1 || result.LastSeq != 3 {
+		t.Errorf("VerifyFiles() = %+v, %v", result, err)
+	}
+	_, err = VerifyFiles([]string{path, rotated}, nil)
+	var chainErr *ChainError
+	if !errors.As(err, &chainErr) || chainErr.File != rotated {
+		t.Errorf("VerifyFiles() out of order error = %v", err)
+	}
+}
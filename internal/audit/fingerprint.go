@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+var (
+	// valueList collapses IN (?, ?, ?) and similar lists to a single value
+	valueList = regexp.MustCompile(`\(\?(?:,\?)+\)`)
+	// punctuationSpace is whitespace around punctuation, dropped so spacing
+	// differences do not split fingerprints
+	punctuationSpace = regexp.MustCompile(`\s*([(),.;=<>!]+)\s*`)
+)
+
+// Normalize reduces a query to its shape: comments are removed, string and
+// numeric literals become ?, lists of literals collapse to one, identifiers
+// and keywords are lowercased, and whitespace is collapsed. Queries that
+// differ only in their literal values normalize to the same text.
+func Normalize(query string) string {
+	var b strings.Builder
+	for i := 0; i < len(query); {
+		ch := query[i]
+		switch {
+		case ch == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexAny(query[i:], "\r\n")
+			if end < 0 {
+				end = len(query) - i
+			}
+			b.WriteByte(' ')
+			i += end
+		case ch == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i - 4
+			}
+			b.WriteByte(' ')
+			i += end + 4
+		case ch == '\'':
+			b.WriteByte('?')
+			i = skipQuoted(query, i, '\'')
+		case ch == '"':
+			end := skipQuoted(query, i, '"')
+			b.WriteString(strings.ToLower(query[i:end]))
+			i = end
+		case isWordByte(ch) && !isDigit(ch):
+			end := i + 1
+			for end < len(query) && isWordByte(query[end]) {
+				end++
+			}
+			b.WriteString(strings.ToLower(query[i:end]))
+			i = end
+		case isDigit(ch):
+			end := i + 1
+			for end < len(query) && (isDigit(query[end]) || query[end] == '.' || query[end] == 'e' || query[end] == 'E') {
+				end++
+			}
+			b.WriteByte('?')
+			i = end
+		default:
+			b.WriteByte(ch)
+			i++
+		}
+	}
+
+	normalized := strings.Join(strings.Fields(b.String()), " ")
+	normalized = punctuationSpace.ReplaceAllString(normalized, "$1")
+	normalized = valueList.ReplaceAllString(normalized, "(?)")
+	return strings.TrimSuffix(normalized, ";")
+}
+
+// Fingerprint returns a short stable identifier for a normalized query.
+func Fingerprint(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:8])
+}
+
+// skipQuoted returns the index just past the quoted text starting at start,
+// where a doubled quote character is an escaped quote
+func skipQuoted(query string, start int, quote byte) int {
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(query)
+}
+
+func isWordByte(ch byte) bool {
+	return ch == '_' || isDigit(ch) || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
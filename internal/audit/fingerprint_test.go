@@ -0,0 +1,37 @@
+package audit
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT * FROM orders WHERE id = 42", "select * from orders where id=?"},
+		{"select *\n  from ORDERS\twhere id=7;", "select * from orders where id=?"},
+		{"SELECT name FROM users WHERE email = 'a@b.com' -- lookup\nAND region IN ('EU', 'US', 'APAC')", "select name from users where email=? and region in(?)"},
+		{"SELECT \"Order Id\" /* quoted */ FROM t1 WHERE ts > DATE '2026-01-01' LIMIT 10", "select \"order id\" from t1 where ts>date ? limit ?"},
+		{"SELECT 'it''s' FROM t", "select ? from t"},
+		{"SELECT col_2, 3.5e2 FROM t", "select col_2,? from t"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.query); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFingerprintGroupsLiteralVariants(t *testing.T) {
+	a := Fingerprint(Normalize("SELECT * FROM orders WHERE id IN (1, 2, 3)"))
+	b := Fingerprint(Normalize("select * from orders where id in (99)"))
+	c := Fingerprint(Normalize("SELECT * FROM refunds WHERE id IN (1, 2, 3)"))
+	if a != b {
+		t.Errorf("literal variants have different fingerprints: %s, %s", a, b)
+	}
+	if a == c {
+		t.Error("different tables share a fingerprint")
+	}
+	if len(a) != 16 {
+		t.Errorf("Fingerprint() = %q, want 16 hex characters", a)
+	}
+}
@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccessSummary aggregates one user's calls that touched one table.
+type AccessSummary struct {
+	User        string    `json:"user"`
+	Table       string    `json:"table"`
+	Calls       int       `json:"calls"`
+	Writes      int       `json:"writes"`   // successful calls that modified the table
+	Failures    int       `json:"failures"` // calls that failed or were rejected, including blocked writes
+	FirstAccess time.Time `json:"first_access"`
+	LastAccess  time.Time `json:"last_access"`
+}
+
+// AccessReport summarizes table access per user and table for records in
+// [from, to), sorted by user and table. A non-empty user limits the report
+// to that user. Records that touched no table are skipped.
+func AccessReport(records []Record, from, to time.Time, user string) []AccessSummary {
+	type key struct{ user, table string }
+	byKey := make(map[key]*AccessSummary)
+	for _, record := range records {
+		if record.Time.Before(from) || !record.Time.Before(to) || (user != "" && record.User != user) {
+			continue
+		}
+		for _, table := range record.Tables {
+			k := key{record.User, table}
+			summary, ok := byKey[k]
+			if !ok {
+				summary = &AccessSummary{User: record.User, Table: table, FirstAccess: record.Time}
+				byKey[k] = summary
+			}
+			summary.Calls++
+			switch {
+			case record.Status == StatusError:
+				summary.Failures++
+			case record.Write:
+				summary.Writes++
+			}
+			if record.Time.Before(summary.FirstAccess) {
+				summary.FirstAccess = record.Time
+			}
+			if record.Time.After(summary.LastAccess) {
+				summary.LastAccess = record.Time
+			}
+		}
+	}
+
+	report := make([]AccessSummary, 0, len(byKey))
+	for _, summary := range byKey {
+		report = append(report, *summary)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].User != report[j].User {
+			return report[i].User < report[j].User
+		}
+		return report[i].Table < report[j].Table
+	})
+	return report
+}
+
+// WriteAccessCSV writes an access report as CSV with a header row.
+func WriteAccessCSV(w io.Writer, report []AccessSummary) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"user", "table", "calls", "writes", "failures", "first_access", "last_access"}); err != nil {
+		return err
+	}
+	for _, summary := range report {
+		if err := writer.Write([]string{
+			csvSafe(summary.User),
+			csvSafe(summary.Table),
+			strconv.Itoa(summary.Calls),
+			strconv.Itoa(summary.Writes),
+			strconv.Itoa(summary.Failures),
+			summary.FirstAccess.UTC().Format(time.RFC3339),
+			summary.LastAccess.UTC().Format(time.RFC3339),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafe prefixes values that spreadsheets would evaluate as formulas. Quoted
+// table names and user identities can contain any text.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccessReport(t *testing.T) {
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	call := func(days int, user, status string, write bool, tables ...string) Record {
+		return Record{Time: start.AddDate(0, 0, days), User: user, Tool: "execute_query", Status: status, Write: write, Tables: tables}
+	}
+	records := []Record{
+		call(-1, "alice", StatusOK, false, "sales.orders"), // before from
+		call(1, "alice", StatusOK, false, "sales.orders", "sales.customers"),
+		call(2, "alice", StatusOK, true, "sales.orders"),
+		call(3, "alice", StatusError, true, "sales.orders"), // blocked write
+		call(4, "bob", StatusOK, false, "sales.orders"),
+		call(5, "bob", StatusOK, false),                     // no tables
+		call(30, "bob", StatusOK, false, "sales.customers"), // at to, excluded
+	}
+
+	report := AccessReport(records, start, start.AddDate(0, 0, 30), "")
+	if len(report) != 3 {
+		t.Fatalf("AccessReport() = %+v, want 3 rows", report)
+	}
+	orders := report[1]
+	if orders.User != "alice" || orders.Table != "sales.orders" || orders.Calls != 3 || orders.Writes != 1 || orders.Failures != 1 {
+		t.Errorf("alice orders = %+v", orders)
+	}
+	if !orders.FirstAccess.Equal(start.AddDate(0, 0, 1)) || !orders.LastAccess.Equal(start.AddDate(0, 0, 3)) {
+		t.Errorf("alice orders access = %s..%s", orders.FirstAccess, orders.LastAccess)
+	}
+	if report[0].Table != "sales.customers" || report[2].User != "bob" {
+		t.Errorf("report order = %+v", report)
+	}
+	if got := AccessReport(records, start, start.AddDate(0, 0, 30), "bob"); len(got) != 1 || got[0].User != "bob" {
+		t.Errorf("AccessReport() for bob = %+v", got)
+	}
+}
+
+func TestWriteAccessCSV(t *testing.T) {
+	at := time.Date(2026, 9, 2, 8, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err := WriteAccessCSV(&buf, []AccessSummary{
+		{User: "alice", Table: "sales.orders", Calls: 3, Writes: 1, Failures: 1, FirstAccess: at, LastAccess: at},
+		{User: "bob", Table: `=HYPERLINK("x")`, Calls: 1, FirstAccess: at, LastAccess: at},
+	})
+	if err != nil {
+		t.Fatalf("WriteAccessCSV() error = %v", err)
+	}
+	want := "user,table,calls,writes,failures,first_access,last_access\n" +
+		"alice,sales.orders,3,1,1,2026-09-02T08:00:00Z,2026-09-02T08:00:00Z\n" +
+		"bob,\"'=HYPERLINK(\"\"x\"\")\",1,0,0,2026-09-02T08:00:00Z,2026-09-02T08:00:00Z\n"
+	if buf.String() != want {
+		t.Errorf("WriteAccessCSV() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestReadAllIncludesRotatedFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, user := range []string{"alice", "bob"} {
+		if err := logger.Log(Record{Time: time.Now().UTC(), User: user, Tool: "list_catalogs", Status: StatusOK}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := logger.Rotate(time.Date(2026, 10, 1, i, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = logger.Close()
+
+	records, err := ReadAll(path)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(records) != 2 || records[0].User != "alice" || records[1].User != "bob" {
+		t.Errorf("ReadAll() = %+v", records)
+	}
+
+	// Only rotated files left
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if records, err := ReadAll(path); err != nil || len(records) != 2 {
+		t.Errorf("ReadAll() without live file = %d records, %v", len(records), err)
+	}
+	if _, err := ReadAll(filepath.Join(t.TempDir(), "missing.jsonl")); err == nil || !strings.Contains(err.Error(), "failed to open") {
+		t.Errorf("ReadAll() of missing log error = %v", err)
+	}
+}
@@ -0,0 +1,347 @@
+// Package compact shrinks query results for agents with small context
+// windows. It keeps a limited set of columns, cuts long values behind an
+// ellipsis marker, and replaces large row sets with per-column summary
+// statistics and a few sample rows.
+package compact
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Verbosity levels
+const (
+	VerbosityFull    = "full"
+	VerbosityCompact = "compact"
+)
+
+// Option defaults and limits
+const (
+	DefaultMaxColumns  = 10
+	DefaultMaxValueLen = 60
+	DefaultMaxRows     = 20
+	MaxMaxColumns      = 100
+	MaxMaxValueLen     = 10000
+	MaxMaxRows         = 1000
+	minMaxValueLen     = 8
+)
+
+// sampleRows is how many rows accompany a summary
+const sampleRows = 5
+
+// maxDistinct caps the distinct values counted per column
+const maxDistinct = 1000
+
+// Ellipsis marks the end of a cut value.
+const Ellipsis = "…"
+
+// Options bounds a compact result. Zero values select the defaults.
+type Options struct {
+	MaxColumns  int `json:"max_columns,omitempty"`      // columns kept, in result order
+	MaxValueLen int `json:"max_value_length,omitempty"` // characters kept per value
+	MaxRows     int `json:"max_rows,omitempty"`         // results with more rows are summarized
+}
+
+// Validate checks that each set option is within its limits.
+func (o Options) Validate() error {
+	switch {
+	case o.MaxColumns < 0 || o.MaxColumns > MaxMaxColumns:
+		return fmt.Errorf("max_columns must be between 1 and %d", MaxMaxColumns)
+	case o.MaxValueLen != 0 && (o.MaxValueLen < minMaxValueLen || o.MaxValueLen > MaxMaxValueLen):
+		return fmt.Errorf("max_value_length must be between %d and %d", minMaxValueLen, MaxMaxValueLen)
+	case o.MaxRows < 0 || o.MaxRows > MaxMaxRows:
+		return fmt.Errorf("max_rows must be between 1 and %d", MaxMaxRows)
+	}
+	return nil
+}
+
+// withDefaults fills unset options
+func (o Options) withDefaults() Options {
+	if o.MaxColumns <= 0 {
+		o.MaxColumns = DefaultMaxColumns
+	}
+	if o.MaxValueLen <= 0 {
+		o.MaxValueLen = DefaultMaxValueLen
+	}
+	if o.MaxRows <= 0 {
+		o.MaxRows = DefaultMaxRows
+	}
+	return o
+}
+
+// Result is a compact view of a query result. Small results carry every row;
+// larger ones carry Summary and the first few rows instead. Rows stay objects
+// keyed by column, and Summary is keyed by column too, so column-based PII
+// masking still applies.
+type Result struct {
+	Columns         []string                  `json:"columns"`
+	OmittedColumns  []string                  `json:"omitted_columns,omitempty"`
+	RowCount        int                       `json:"row_count"`
+	Rows            []map[string]interface{}  `json:"rows,omitempty"`
+	SampleRows      []map[string]interface{}  `json:"sample_rows,omitempty"`
+	Summary         map[string]*ColumnSummary `json:"summary,omitempty"`
+	TruncatedValues int                       `json:"truncated_values,omitempty"` // values cut behind an Ellipsis
+	Message         string                    `json:"message"`
+}
+
+// ColumnSummary describes one column's values across every row.
+type ColumnSummary struct {
+	Nulls          int         `json:"nulls"`
+	Distinct       int         `json:"distinct"`
+	DistinctCapped bool        `json:"distinct_capped,omitempty"` // more than maxDistinct values; Distinct is a lower bound
+	Min            interface{} `json:"min,omitempty"`             // numbers, strings, and times only
+	Max            interface{} `json:"max,omitempty"`
+	Mean           *float64    `json:"mean,omitempty"` // numbers only
+}
+
+// Compact builds a compact view of rows. columns gives the result's column
+// order; when it is empty the columns of the first row are used, sorted.
+func Compact(columns []string, rows []map[string]interface{}, opts Options) *Result {
+	result, _ := CompactFrom(columns, func(fn func(row map[string]interface{}) error) error {
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, opts)
+	return result
+}
+
+// CompactFrom builds a compact view of the rows iterate yields, such as a
+// spill file's, holding at most opts.MaxRows of them in memory.
+func CompactFrom(columns []string, iterate func(fn func(row map[string]interface{}) error) error, opts Options) (*Result, error) {
+	opts = opts.withDefaults()
+	result := &Result{}
+	var kept []string
+	var stats map[string]*columnStats
+	var cuts []int // values cut in each of result.Rows
+	err := iterate(func(row map[string]interface{}) error {
+		if kept == nil {
+			if len(columns) == 0 {
+				columns = sortedKeys(row)
+			}
+			kept = columns[:min(len(columns), opts.MaxColumns)]
+			result.OmittedColumns = columns[len(kept):]
+			stats = make(map[string]*columnStats, len(kept))
+			for _, column := range kept {
+				stats[column] = &columnStats{distinct: map[string]struct{}{}}
+			}
+		}
+		result.RowCount++
+		for _, column := range kept {
+			stats[column].add(row[column])
+		}
+		if len(result.Rows) < opts.MaxRows {
+			compacted, cut := compactRow(row, kept, opts.MaxValueLen)
+			result.Rows = append(result.Rows, compacted)
+			cuts = append(cuts, cut)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Columns = kept
+	if result.Columns == nil {
+		result.Columns = append([]string{}, columns[:min(len(columns), opts.MaxColumns)]...)
+		result.OmittedColumns = columns[len(result.Columns):]
+	}
+
+	if result.RowCount > opts.MaxRows {
+		result.SampleRows = result.Rows[:min(sampleRows, len(result.Rows))]
+		result.Rows = nil
+		cuts = cuts[:len(result.SampleRows)]
+		result.Summary = make(map[string]*ColumnSummary, len(kept))
+		for _, column := range kept {
+			result.Summary[column] = stats[column].summary(opts.MaxValueLen)
+		}
+	}
+	for _, cut := range cuts {
+		result.TruncatedValues += cut
+	}
+	result.Message = result.describe(opts)
+	return result, nil
+}
+
+// compactRow keeps a row's kept columns, cutting long values, and returns
+// how many it cut
+func compactRow(row map[string]interface{}, columns []string, maxLen int) (map[string]interface{}, int) {
+	compacted := make(map[string]interface{}, len(columns))
+	cuts := 0
+	for _, column := range columns {
+		value, cut := shorten(row[column], maxLen)
+		if cut {
+			cuts++
+		}
+		compacted[column] = value
+	}
+	return compacted, cuts
+}
+
+// describe explains what was left out of the result
+func (r *Result) describe(opts Options) string {
+	var parts []string
+	if r.Summary != nil {
+		parts = append(parts, fmt.Sprintf("%d rows are summarized per column, with the first %d as samples", r.RowCount, len(r.SampleRows)))
+	} else {
+		parts = append(parts, fmt.Sprintf("%d rows", r.RowCount))
+	}
+	if len(r.OmittedColumns) > 0 {
+		parts = append(parts, fmt.Sprintf("%d of %d columns are omitted (listed in omitted_columns); select the ones you need explicitly", len(r.OmittedColumns), len(r.Columns)+len(r.OmittedColumns)))
+	}
+	if r.TruncatedValues > 0 {
+		parts = append(parts, fmt.Sprintf("%d values longer than %d characters end in %s", r.TruncatedValues, opts.MaxValueLen, Ellipsis))
+	}
+	return "Compact result: " + strings.Join(parts, "; ") + `. Pass verbosity "full" for the complete result.`
+}
+
+// shorten cuts strings, and arrays, maps, and rows rendered as JSON, to
+// maxLen characters, reporting whether it cut the value
+func shorten(value interface{}, maxLen int) (interface{}, bool) {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case []interface{}, map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return value, false
+		}
+		text = string(data)
+	default:
+		return value, false
+	}
+	if utf8.RuneCountInString(text) <= maxLen {
+		return value, false
+	}
+	runes := []rune(text)
+	return string(runes[:maxLen-utf8.RuneCountInString(Ellipsis)]) + Ellipsis, true
+}
+
+// columnStats accumulates one column's summary
+type columnStats struct {
+	nulls    int
+	distinct map[string]struct{}
+	capped   bool
+	min, max interface{}
+	sum      float64
+	numbers  int
+	others   int // non-null values that are not numbers
+}
+
+func (s *columnStats) add(value interface{}) {
+	if value == nil {
+		s.nulls++
+		return
+	}
+	if !s.capped {
+		key := fmt.Sprintf("%T:%v", value, value)
+		if _, seen := s.distinct[key]; !seen {
+			if len(s.distinct) == maxDistinct {
+				s.capped = true
+			} else {
+				s.distinct[key] = struct{}{}
+			}
+		}
+	}
+	if n, ok := number(value); ok {
+		s.sum += n
+		s.numbers++
+	} else {
+		s.others++
+	}
+	if s.min == nil || less(value, s.min) {
+		s.min = orderable(value, s.min)
+	}
+	if s.max == nil || less(s.max, value) {
+		s.max = orderable(value, s.max)
+	}
+}
+
+func (s *columnStats) summary(maxLen int) *ColumnSummary {
+	summary := &ColumnSummary{Nulls: s.nulls, Distinct: len(s.distinct), DistinctCapped: s.capped}
+	summary.Min, _ = shorten(s.min, maxLen)
+	summary.Max, _ = shorten(s.max, maxLen)
+	if s.numbers > 0 && s.others == 0 {
+		mean := s.sum / float64(s.numbers)
+		summary.Mean = &mean
+	}
+	return summary
+}
+
+// orderable returns value if it can be ordered, or keeps current
+func orderable(value, current interface{}) interface{} {
+	if _, ok := number(value); ok {
+		return value
+	}
+	switch value.(type) {
+	case string, time.Time:
+		return value
+	}
+	return current
+}
+
+// less orders two values of the same kind; values of different kinds are
+// unordered
+func less(a, b interface{}) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x < y
+	}
+	switch x := a.(type) {
+	case string:
+		y, ok := b.(string)
+		return ok && x < y
+	case time.Time:
+		y, ok := b.(time.Time)
+		return ok && x.Before(y)
+	}
+	return false
+}
+
+// number converts numeric values to float64
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// sortedKeys returns a row's columns in name order
+func sortedKeys(row map[string]interface{}) []string {
+	keys := make([]string, 0, len(row))
+	for key := range row {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
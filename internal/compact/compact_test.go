@@ -0,0 +1,111 @@
+package compact
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestCompactSmallResult(t *testing.T) {
+	long := strings.Repeat("é", 100)
+	rows := []map[string]interface{}{
+		{"id": int64(1), "name": "short", "tags": []interface{}{"a", "b"}, "notes": long},
+		{"id": int64(2), "name": nil, "tags": []interface{}{long}, "notes": "ok"},
+	}
+	got := Compact([]string{"id", "name", "notes", "tags"}, rows, Options{MaxColumns: 3, MaxValueLen: 20})
+
+	if got.RowCount != 2 || len(got.Rows) != 2 || got.Summary != nil || got.SampleRows != nil {
+		t.Fatalf("Compact() = %+v, want both rows without a summary", got)
+	}
+	if strings.Join(got.Columns, ",") != "id,name,notes" || strings.Join(got.OmittedColumns, ",") != "tags" {
+		t.Errorf("columns = %v, omitted = %v, want the first 3 kept", got.Columns, got.OmittedColumns)
+	}
+	if _, ok := got.Rows[0]["tags"]; ok {
+		t.Error("omitted column kept in rows")
+	}
+	notes, _ := got.Rows[0]["notes"].(string)
+	if utf8.RuneCountInString(notes) != 20 || !strings.HasSuffix(notes, Ellipsis) || !strings.HasPrefix(notes, "éé") {
+		t.Errorf("long value = %q, want it cut to 20 characters ending in %s", notes, Ellipsis)
+	}
+	if got.Rows[1]["notes"] != "ok" || got.Rows[1]["name"] != nil || got.Rows[0]["id"] != int64(1) {
+		t.Errorf("short values changed: %v", got.Rows)
+	}
+	if got.TruncatedValues != 1 || !strings.Contains(got.Message, "1 of 4 columns are omitted") || !strings.Contains(got.Message, "1 values longer than 20") {
+		t.Errorf("truncated = %d, message = %q", got.TruncatedValues, got.Message)
+	}
+}
+
+func TestCompactSummarizesLargeResult(t *testing.T) {
+	start := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	var rows []map[string]interface{}
+	for i := 1; i <= 50; i++ {
+		row := map[string]interface{}{"n": int64(i), "price": float64(i) / 2, "day": start.Add(time.Duration(i) * time.Hour), "status": "ok"}
+		if i%10 == 0 {
+			row["status"] = nil
+		}
+		if i == 7 {
+			row["status"] = strings.Repeat("x", 100)
+		}
+		rows = append(rows, row)
+	}
+	got := Compact(nil, rows, Options{MaxRows: 10})
+
+	if got.RowCount != 50 || got.Rows != nil || len(got.SampleRows) != sampleRows || got.SampleRows[0]["n"] != int64(1) {
+		t.Fatalf("Compact() rows = %d, %d rows, %d samples, want a summary with %d samples", got.RowCount, len(got.Rows), len(got.SampleRows), sampleRows)
+	}
+	if strings.Join(got.Columns, ",") != "day,n,price,status" {
+		t.Errorf("columns = %v, want the first row's columns sorted", got.Columns)
+	}
+	n := got.Summary["n"]
+	if n.Nulls != 0 || n.Distinct != 50 || n.Min != int64(1) || n.Max != int64(50) || n.Mean == nil || *n.Mean != 25.5 {
+		t.Errorf("summary of n = %+v", n)
+	}
+	if day := got.Summary["day"]; day.Min != start.Add(time.Hour) || day.Max != start.Add(50*time.Hour) || day.Mean != nil {
+		t.Errorf("summary of day = %+v", day)
+	}
+	status := got.Summary["status"]
+	if status.Nulls != 5 || status.Distinct != 2 || status.Mean != nil || status.Min != "ok" {
+		t.Errorf("summary of status = %+v", status)
+	}
+	if max, _ := status.Max.(string); utf8.RuneCountInString(max) != DefaultMaxValueLen || !strings.HasSuffix(max, Ellipsis) {
+		t.Errorf("long max = %q, want it cut", max)
+	}
+	if got.TruncatedValues != 0 || !strings.Contains(got.Message, "50 rows are summarized") {
+		t.Errorf("truncated = %d, message = %q; the cut value is not among the samples", got.TruncatedValues, got.Message)
+	}
+}
+
+func TestCompactDistinctCap(t *testing.T) {
+	var rows []map[string]interface{}
+	for i := 0; i < maxDistinct+10; i++ {
+		rows = append(rows, map[string]interface{}{"id": fmt.Sprint(i)})
+	}
+	summary := Compact(nil, rows, Options{}).Summary["id"]
+	if summary.Distinct != maxDistinct || !summary.DistinctCapped {
+		t.Errorf("summary = %+v, want distinct values capped at %d", summary, maxDistinct)
+	}
+}
+
+func TestCompactEmptyResult(t *testing.T) {
+	got := Compact([]string{"a", "b"}, nil, Options{MaxColumns: 1})
+	if got.RowCount != 0 || strings.Join(got.Columns, ",") != "a" || strings.Join(got.OmittedColumns, ",") != "b" || got.Summary != nil {
+		t.Errorf("Compact() = %+v", got)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	valid := []Options{{}, {MaxColumns: 1, MaxValueLen: 8, MaxRows: 1}, {MaxColumns: MaxMaxColumns, MaxValueLen: MaxMaxValueLen, MaxRows: MaxMaxRows}}
+	for _, opts := range valid {
+		if err := opts.Validate(); err != nil {
+			t.Errorf("Validate(%+v) error = %v", opts, err)
+		}
+	}
+	invalid := []Options{{MaxColumns: -1}, {MaxColumns: MaxMaxColumns + 1}, {MaxValueLen: 3}, {MaxRows: MaxMaxRows + 1}}
+	for _, opts := range invalid {
+		if err := opts.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded", opts)
+		}
+	}
+}
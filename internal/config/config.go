@@ -4,11 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/tuannvm/mcp-trino/internal/anomaly"
 	"github.com/tuannvm/mcp-trino/internal/secret"
 )
 
@@ -27,6 +34,7 @@ type TrinoConfig struct {
 	AllowWriteQueries bool          // Controls whether non-read-only SQL queries are allowed
 	QueryTimeout      time.Duration // Query execution timeout
 	MaxRows           int           // Maximum number of rows returned per query (0 = unlimited)
+	RecoveryTimeout   time.Duration // How long to wait for Trino to come back after a connection failure (0 = no recovery)
 
 	// OAuth mode configuration
 	OAuthEnabled  bool   // Enable OAuth 2.1 authentication
@@ -46,12 +54,150 @@ type TrinoConfig struct {
 	AllowedSchemas  []string // List of allowed schemas in catalog.schema format
 	AllowedTables   []string // List of allowed tables in catalog.schema.table format
 
+	// Catalog aliases rewritten in queries and listing tools
+	CatalogAliases map[string]string // Lowercase alias -> catalog (empty = no aliases)
+
 	// Impersonation configuration
 	EnableImpersonation bool   // Enable Trino user impersonation via X-Trino-User header
 	ImpersonationField  string // JWT field to use for impersonation: "username", "email", or "subject" (default: "username")
 
 	// Query attribution
 	TrinoSource string // Value for X-Trino-Source header (identifies query source to Trino)
+
+	// Client identification headers set on every Trino request. Values are
+	// templates whose {user}, {session}, {request_id}, and {tool}
+	// placeholders are filled in per request.
+	TrinoClientInfo string            // X-Trino-Client-Info template (empty = the caller's username)
+	TrinoTraceToken string            // X-Trino-Trace-Token template (empty = not sent)
+	TrinoHeaders    map[string]string // Extra header name -> value template
+
+	// Shared state configuration for multi-replica deployments
+	StateStore string // Backend for MCP session IDs and per-session settings: "memory" or "redis" (default: "memory")
+	RedisURL   string // Redis connection URL used by redis-backed components
+
+	// Rate limiting configuration (per authenticated identity)
+	RateLimitPerMinute int    // Tool calls allowed per minute per user (0 = disabled)
+	RateLimitBurst     int    // Maximum burst size for the local limiter (default: RateLimitPerMinute)
+	RateLimitBackend   string // Limiter backend: "local" or "redis" (default: "local")
+
+	// Result pagination configuration
+	ResultPageSize int           // Rows per page for paginated results (0 = pagination disabled)
+	ResultStore    string        // Result store backend: "memory", "redis", "s3", or "disk" (default: "memory", or "disk" when spill is enabled)
+	ResultTTL      time.Duration // How long stored results remain retrievable
+	ResultS3Bucket string        // S3 bucket for the s3 result store
+	ResultS3Prefix string        // S3 key prefix for the s3 result store
+
+	// Disk spill configuration for very large results
+	SpillThresholdRows int    // Rows held in memory before spilling to disk (0 = spill disabled)
+	SpillDir           string // Directory for spill files and disk-stored results
+	SpillEncrypt       bool   // Encrypt spill files with an ephemeral per-process key
+
+	// Response chunking configuration
+	MaxResponseBytes int // Tool responses larger than this are stored and fetched in chunks (0 = disabled)
+
+	// Query scheduling configuration
+	MaxConcurrentQueries int           // Concurrent Trino queries before requests queue (0 = unlimited)
+	QueryQueueTimeout    time.Duration // How long a queued query waits for a slot before failing
+
+	// Result memory guard configuration
+	MemoryBudgetMB     int           // Estimated memory shared by all in-flight results (0 = unlimited)
+	MemoryQueueTimeout time.Duration // How long a new query waits for budget before failing
+
+	// Keepalive configuration for long-running tool calls
+	KeepaliveInterval time.Duration // Interval between keepalive notifications during a tool call (0 = disabled)
+
+	// Data quality checks configuration
+	ChecksFile string // YAML file defining the checks run by run_checks (empty = none)
+
+	// Secret file configuration for zero-restart rotation
+	PasswordFile         string        // File holding the Trino password; overrides TRINO_PASSWORD
+	OIDCClientSecretFile string        // File holding the OIDC client secret; overrides OIDC_CLIENT_SECRET
+	SecretPollInterval   time.Duration // How often secret files and TLS certificates are checked for changes (0 = disabled)
+
+	// Multi-tenant configuration
+	TenantsFile string // YAML file mapping tenants to clusters and allowlists (empty = single tenant)
+	TenantClaim string // Access token claim naming the caller's tenant (default: "tenant")
+
+	// Environment profiles configuration
+	ProfilesFile string // YAML file of profiles selectable with the profile tool argument (empty = disabled)
+
+	// Audit configuration
+	AuditLogFile    string // JSON Lines file recording every tool call (empty = auditing disabled)
+	AuditSigningKey string // HMAC key signing every audit record (empty = hash chain only)
+
+	// Security event forwarding configuration
+	SyslogAddress  string // host:port of the syslog collector for security events (empty = disabled)
+	SyslogProtocol string // "tls" (default) or "tcp"
+	SyslogCAFile   string // PEM bundle verifying the collector's certificate (empty = system roots)
+	SyslogFacility string // Syslog facility name (default: "authpriv")
+
+	// Anomaly detection configuration
+	AnomalyDetection     bool          // Flag unusual behavior per identity as security events
+	AnomalyTables        int           // Distinct tables accessed within AnomalyWindow that flag enumeration (0 = disabled)
+	AnomalyWindow        time.Duration // Window for counting distinct tables
+	AnomalySpikeFactor   int           // Multiple of an identity's average bytes scanned that flags a spike (0 = disabled)
+	AnomalyScanMinMB     int           // Scans smaller than this are never flagged as spikes
+	AnomalyHours         string        // Expected activity, such as "mon-fri 08:00-18:00" (empty = off-hours check disabled)
+	AnomalyTimezone      string        // IANA time zone of AnomalyHours (default: "UTC")
+	AnomalyWebhookURL    string        // Endpoint receiving anomaly alerts as JSON (empty = none)
+	AnomalyWebhookSecret string        // HMAC key signing webhook bodies (empty = unsigned)
+
+	// Query subscription configuration
+	Subscriptions    bool          // Allow subscribe_query to re-run queries in the background
+	SubMinInterval   time.Duration // Shortest re-run interval a subscription may ask for
+	SubMaxLifetime   time.Duration // Longest a subscription may stay active
+	SubMaxPerUser    int           // Active subscriptions allowed per identity
+	SubWebhooks      []string      // URL prefixes subscriptions may post changes to (empty = no webhooks)
+	SubWebhookSecret string        // HMAC key signing subscription webhook bodies (empty = unsigned)
+
+	// Metadata warm-up configuration
+	MetadataWarmup   bool          // List catalogs, schemas, and tables at startup to warm metadata caches
+	WarmupInterval   time.Duration // How often to repeat the warm-up (0 = startup only)
+	WarmupMaxSchemas int           // Schemas whose tables are listed per warm-up (0 = catalogs and schemas only)
+	WarmupTables     []string      // Hot tables described each warm-up, as catalog.schema.table or schema.table
+
+	// Query history configuration
+	HistoryFile       string        // Embedded database recording each user's execute_query calls (empty = history disabled)
+	HistoryMaxAge     time.Duration // History entries older than this are removed by the retention janitor (0 = keep)
+	HistoryMaxPerUser int           // Newest history entries kept per user (0 = unlimited)
+
+	// Usage policy acknowledgment in the OAuth proxy flow
+	UsagePolicyFile    string // Text file presented before tokens are issued (empty = no acknowledgment step)
+	UsagePolicyVersion string // Version recorded with each acknowledgment (default: hash of the policy text)
+
+	// Access report configuration
+	ReportAdmins []string // Users allowed to call generate_access_report when OAuth is enabled
+	AdminToken   string   // Bearer token for the /admin endpoints (empty = endpoints disabled)
+
+	// Usage accounting and chargeback configuration
+	UsageLogFile     string  // JSON Lines file recording each query's Trino resource usage (empty = accounting disabled)
+	UsageTeamClaim   string  // Access token claim naming the caller's team in chargeback reports (empty = no teams)
+	CostPerTBScanned float64 // Price of a terabyte (2^40 bytes) scanned, for chargeback costs (0 = not priced)
+	CostPerCPUHour   float64 // Price of an hour of Trino CPU time, for chargeback costs (0 = not priced)
+
+	// Retention configuration, enforced by a background janitor
+	RetentionInterval time.Duration // How often the janitor sweeps (0 = janitor disabled)
+	AuditRotateMB     int           // Audit log size that triggers rotation (0 = never rotate)
+	AuditMaxAge       time.Duration // Rotated audit logs older than this are removed (0 = keep)
+	AuditMaxTotalMB   int           // Total size kept for rotated audit logs (0 = unlimited)
+	ResultMaxAge      time.Duration // Disk-stored results older than this are removed (0 = TTL only)
+	ResultMaxTotalMB  int           // Total size kept for disk-stored results (0 = unlimited)
+	SpillMaxAge       time.Duration // Spill files older than this are removed
+	SpillMaxTotalMB   int           // Total size kept for spill files (0 = unlimited)
+
+	// PII scanning configuration for tool responses
+	PIIMode    string   // "off", "warn" (annotate responses), or "mask" (also redact values) (default: "off")
+	PIIColumns []string // Column name patterns tagged as personal data, such as email or *_ssn
+
+	// Log redaction configuration, independent of PII masking
+	RedactColumns       []string // Columns whose values never appear in logs, audit records, query history, or error messages
+	RedactColumnPattern string   // Regular expression over column names, redacted like RedactColumns (empty = none)
+
+	// Workload routing configuration
+	BatchHost      string // Trino coordinator for expensive queries (empty = all queries on Host)
+	BatchPort      int    // Port of the batch coordinator (default: Port)
+	BatchMinScanMB int    // Estimated scan size that sends a query to the batch cluster
+	BatchFullScans bool   // Also send queries with an unfiltered table scan to the batch cluster
 }
 
 // NewTrinoConfig creates a new TrinoConfig with values from environment variables or defaults
@@ -164,11 +310,20 @@ func NewTrinoConfigWithVersion(version string) (*TrinoConfig, error) {
 
 	queryTimeout := time.Duration(timeoutInt) * time.Second
 
+	// Parse connection recovery configuration
+	recoveryTimeout := time.Duration(parseNonNegativeInt(resolveEnv, "TRINO_RECOVERY_TIMEOUT", 30)) * time.Second
+
 	// Parse allowlist configuration
 	allowedCatalogs := parseAllowlist(resolveEnv("TRINO_ALLOWED_CATALOGS", ""))
 	allowedSchemas := parseAllowlist(resolveEnv("TRINO_ALLOWED_SCHEMAS", ""))
 	allowedTables := parseAllowlist(resolveEnv("TRINO_ALLOWED_TABLES", ""))
 
+	// Parse catalog aliases
+	catalogAliases, err := parseCatalogAliases(resolveEnv("TRINO_CATALOG_ALIASES", ""))
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse impersonation configuration
 	enableImpersonation, _ := strconv.ParseBool(resolveEnv("TRINO_ENABLE_IMPERSONATION", "false"))
 	impersonationField := strings.ToLower(resolveEnv("TRINO_IMPERSONATION_FIELD", "username"))
@@ -180,6 +335,140 @@ func NewTrinoConfigWithVersion(version string) (*TrinoConfig, error) {
 		trinoSource = fmt.Sprintf("mcp-trino/%s", version)
 	}
 
+	// Parse client identification header templates
+	trinoClientInfo := strings.TrimSpace(resolveEnv("TRINO_CLIENT_INFO", ""))
+	trinoTraceToken := strings.TrimSpace(resolveEnv("TRINO_TRACE_TOKEN", ""))
+	trinoHeaders, err := parseHeaderTemplates(resolveEnv("TRINO_EXTRA_HEADERS", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse shared state store configuration
+	stateStore := strings.ToLower(strings.TrimSpace(resolveEnv("MCP_STATE_STORE", "memory")))
+	redisURL := resolveEnv("MCP_REDIS_URL", "")
+
+	// Parse rate limiting configuration
+	rateLimitPerMinute := parseNonNegativeInt(resolveEnv, "MCP_RATE_LIMIT_PER_MINUTE", 0)
+	rateLimitBurst := parseNonNegativeInt(resolveEnv, "MCP_RATE_LIMIT_BURST", 0)
+	rateLimitBackend := strings.ToLower(strings.TrimSpace(resolveEnv("MCP_RATE_LIMIT_BACKEND", "local")))
+
+	// Parse result pagination configuration
+	resultPageSize := parseNonNegativeInt(resolveEnv, "MCP_RESULT_PAGE_SIZE", 0)
+	resultStore := strings.ToLower(strings.TrimSpace(resolveEnv("MCP_RESULT_STORE", "")))
+	resultTTL := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_RESULT_TTL", 3600)) * time.Second
+	resultS3Bucket := resolveEnv("MCP_RESULT_S3_BUCKET", "")
+	resultS3Prefix := resolveEnv("MCP_RESULT_S3_PREFIX", "mcp-trino/results/")
+
+	// Parse disk spill configuration
+	spillThresholdRows := parseNonNegativeInt(resolveEnv, "MCP_SPILL_THRESHOLD_ROWS", 0)
+	spillDir := resolveEnv("MCP_SPILL_DIR", filepath.Join(os.TempDir(), "mcp-trino-spill"))
+	spillEncrypt, _ := strconv.ParseBool(resolveEnv("MCP_SPILL_ENCRYPT", "false"))
+
+	// Parse response chunking configuration
+	maxResponseBytes := parseNonNegativeInt(resolveEnv, "MCP_MAX_RESPONSE_BYTES", 0)
+
+	// Parse query scheduling configuration
+	maxConcurrentQueries := parseNonNegativeInt(resolveEnv, "MCP_MAX_CONCURRENT_QUERIES", 0)
+	queryQueueTimeout := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_QUERY_QUEUE_TIMEOUT", 60)) * time.Second
+
+	// Parse result memory guard configuration
+	memoryBudgetMB := parseNonNegativeInt(resolveEnv, "MCP_MEMORY_BUDGET_MB", 0)
+	memoryQueueTimeout := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_MEMORY_QUEUE_TIMEOUT", 30)) * time.Second
+
+	// Parse keepalive configuration
+	keepaliveInterval := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_KEEPALIVE_INTERVAL", 15)) * time.Second
+
+	// Parse data quality checks configuration
+	checksFile := strings.TrimSpace(resolveEnv("MCP_CHECKS_FILE", ""))
+
+	// Parse secret file configuration. Files take precedence over the
+	// variables so mounted secrets can be rotated without a restart.
+	password := resolveEnv("TRINO_PASSWORD", "")
+	passwordFile := strings.TrimSpace(resolveEnv("TRINO_PASSWORD_FILE", ""))
+	if passwordFile != "" {
+		if password, err = ReadSecretFile(passwordFile); err != nil {
+			return nil, fmt.Errorf("failed to read TRINO_PASSWORD_FILE: %w", err)
+		}
+	}
+	oidcClientSecretFile := strings.TrimSpace(resolveEnv("OIDC_CLIENT_SECRET_FILE", ""))
+	if oidcClientSecretFile != "" {
+		if oidcClientSecret, err = ReadSecretFile(oidcClientSecretFile); err != nil {
+			return nil, fmt.Errorf("failed to read OIDC_CLIENT_SECRET_FILE: %w", err)
+		}
+	}
+	secretPollInterval := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_SECRET_POLL_INTERVAL", 30)) * time.Second
+
+	// Parse multi-tenant configuration
+	tenantsFile := strings.TrimSpace(resolveEnv("MCP_TENANTS_FILE", ""))
+	tenantClaim := strings.TrimSpace(resolveEnv("MCP_TENANT_CLAIM", "tenant"))
+
+	// Parse environment profiles configuration
+	profilesFile := strings.TrimSpace(resolveEnv("MCP_PROFILES_FILE", ""))
+
+	// Parse audit configuration
+	auditLogFile := strings.TrimSpace(resolveEnv("MCP_AUDIT_LOG", ""))
+	auditSigningKey := resolveEnv("MCP_AUDIT_SIGNING_KEY", "")
+	syslogAddress := strings.TrimSpace(resolveEnv("MCP_SYSLOG_ADDR", ""))
+	syslogProtocol := strings.ToLower(strings.TrimSpace(resolveEnv("MCP_SYSLOG_PROTOCOL", "tls")))
+	syslogCAFile := strings.TrimSpace(resolveEnv("MCP_SYSLOG_CA_FILE", ""))
+	syslogFacility := strings.ToLower(strings.TrimSpace(resolveEnv("MCP_SYSLOG_FACILITY", "authpriv")))
+	anomalyDetection, _ := strconv.ParseBool(resolveEnv("MCP_ANOMALY_DETECTION", "false"))
+	anomalyTables := parseNonNegativeInt(resolveEnv, "MCP_ANOMALY_TABLE_THRESHOLD", 50)
+	anomalyWindow := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_ANOMALY_WINDOW_MINUTES", 10)) * time.Minute
+	anomalySpikeFactor := parseNonNegativeInt(resolveEnv, "MCP_ANOMALY_SCAN_SPIKE_FACTOR", 10)
+	anomalyScanMinMB := parseNonNegativeInt(resolveEnv, "MCP_ANOMALY_SCAN_MIN_MB", 1024)
+	anomalyHours := strings.TrimSpace(resolveEnv("MCP_ANOMALY_BUSINESS_HOURS", ""))
+	anomalyTimezone := strings.TrimSpace(resolveEnv("MCP_ANOMALY_TIMEZONE", "UTC"))
+	anomalyWebhookURL := strings.TrimSpace(resolveEnv("MCP_ANOMALY_WEBHOOK_URL", ""))
+	anomalyWebhookSecret := resolveEnv("MCP_ANOMALY_WEBHOOK_SECRET", "")
+	subscriptions, _ := strconv.ParseBool(resolveEnv("MCP_SUBSCRIPTIONS", "false"))
+	subMinInterval := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_SUBSCRIPTION_MIN_INTERVAL", 60)) * time.Second
+	subMaxLifetime := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_SUBSCRIPTION_MAX_HOURS", 24)) * time.Hour
+	subMaxPerUser := parseNonNegativeInt(resolveEnv, "MCP_SUBSCRIPTION_MAX_PER_USER", 5)
+	subWebhooks := parseAllowlist(resolveEnv("MCP_SUBSCRIPTION_WEBHOOKS", ""))
+	subWebhookSecret := resolveEnv("MCP_SUBSCRIPTION_WEBHOOK_SECRET", "")
+	metadataWarmup, _ := strconv.ParseBool(resolveEnv("MCP_METADATA_WARMUP", "false"))
+	warmupInterval := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_WARMUP_INTERVAL_MINUTES", 0)) * time.Minute
+	warmupMaxSchemas := parseNonNegativeInt(resolveEnv, "MCP_WARMUP_MAX_SCHEMAS", 100)
+	warmupTables := parseAllowlist(resolveEnv("MCP_WARMUP_TABLES", ""))
+	historyFile := strings.TrimSpace(resolveEnv("MCP_QUERY_HISTORY_FILE", ""))
+	historyMaxAge := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_QUERY_HISTORY_RETENTION_DAYS", 90)) * 24 * time.Hour
+	historyMaxPerUser := parseNonNegativeInt(resolveEnv, "MCP_QUERY_HISTORY_MAX_PER_USER", 1000)
+	usagePolicyFile := strings.TrimSpace(resolveEnv("MCP_USAGE_POLICY_FILE", ""))
+	usagePolicyVersion := strings.TrimSpace(resolveEnv("MCP_USAGE_POLICY_VERSION", ""))
+	reportAdmins := parseAllowlist(resolveEnv("MCP_REPORT_ADMINS", ""))
+	adminToken := resolveEnv("MCP_ADMIN_TOKEN", "")
+
+	// Parse usage accounting configuration
+	usageLogFile := strings.TrimSpace(resolveEnv("MCP_USAGE_LOG", ""))
+	usageTeamClaim := strings.TrimSpace(resolveEnv("MCP_USAGE_TEAM_CLAIM", ""))
+	costPerTBScanned := parseNonNegativeFloat(resolveEnv, "MCP_COST_PER_TB_SCANNED")
+	costPerCPUHour := parseNonNegativeFloat(resolveEnv, "MCP_COST_PER_CPU_HOUR")
+
+	// Parse retention configuration
+	retentionInterval := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_RETENTION_INTERVAL", 600)) * time.Second
+	auditRotateMB := parseNonNegativeInt(resolveEnv, "MCP_AUDIT_ROTATE_MB", 0)
+	auditMaxAge := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_AUDIT_RETENTION_DAYS", 0)) * 24 * time.Hour
+	auditMaxTotalMB := parseNonNegativeInt(resolveEnv, "MCP_AUDIT_MAX_TOTAL_MB", 0)
+	resultMaxAge := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_RESULT_RETENTION_HOURS", 0)) * time.Hour
+	resultMaxTotalMB := parseNonNegativeInt(resolveEnv, "MCP_RESULT_MAX_TOTAL_MB", 0)
+	spillMaxAge := time.Duration(parseNonNegativeInt(resolveEnv, "MCP_SPILL_RETENTION_HOURS", 24)) * time.Hour
+	spillMaxTotalMB := parseNonNegativeInt(resolveEnv, "MCP_SPILL_MAX_TOTAL_MB", 0)
+
+	// Parse PII scanning configuration
+	piiMode := strings.ToLower(strings.TrimSpace(resolveEnv("MCP_PII_MODE", "off")))
+	piiColumns := parseAllowlist(resolveEnv("MCP_PII_COLUMNS", ""))
+
+	// Parse log redaction configuration
+	redactColumns := parseAllowlist(resolveEnv("MCP_REDACT_COLUMNS", ""))
+	redactColumnPattern := strings.TrimSpace(resolveEnv("MCP_REDACT_COLUMN_PATTERN", ""))
+
+	// Parse workload routing configuration
+	batchHost := strings.TrimSpace(resolveEnv("TRINO_BATCH_HOST", ""))
+	batchPort := parseNonNegativeInt(resolveEnv, "TRINO_BATCH_PORT", port)
+	batchMinScanMB := parseNonNegativeInt(resolveEnv, "TRINO_BATCH_MIN_SCAN_MB", 10240)
+	batchFullScans, _ := strconv.ParseBool(resolveEnv("TRINO_BATCH_FULL_SCANS", "false"))
+
 	// Validate allowlist formats
 	if err := validateAllowlist("TRINO_ALLOWED_SCHEMAS", allowedSchemas, 1); err != nil { // Must have catalog.schema format
 		return nil, err
@@ -218,6 +507,9 @@ func NewTrinoConfigWithVersion(version string) (*TrinoConfig, error) {
 
 	// Log allowlist configuration
 	logAllowlistConfiguration(allowedCatalogs, allowedSchemas, allowedTables)
+	if len(catalogAliases) > 0 {
+		log.Printf("INFO: Catalog aliases configured: %d (TRINO_CATALOG_ALIASES)", len(catalogAliases))
+	}
 
 	// Validate impersonation field
 	validFields := map[string]bool{"username": true, "email": true, "subject": true}
@@ -246,37 +538,403 @@ func NewTrinoConfigWithVersion(version string) (*TrinoConfig, error) {
 	// Log query attribution configuration
 	log.Printf("INFO: Trino query source attribution: %s", trinoSource)
 
+	// Validate client identification header templates
+	if err := validateHeaderTemplate("TRINO_CLIENT_INFO", trinoClientInfo); err != nil {
+		return nil, err
+	}
+	if err := validateHeaderTemplate("TRINO_TRACE_TOKEN", trinoTraceToken); err != nil {
+		return nil, err
+	}
+	if trinoClientInfo != "" {
+		log.Printf("INFO: Trino client info template: %s (TRINO_CLIENT_INFO)", trinoClientInfo)
+	}
+	if trinoTraceToken != "" {
+		log.Printf("INFO: Trino trace token template: %s (TRINO_TRACE_TOKEN)", trinoTraceToken)
+	}
+	if len(trinoHeaders) > 0 {
+		log.Printf("INFO: Extra headers on Trino requests: %d (TRINO_EXTRA_HEADERS)", len(trinoHeaders))
+	}
+
+	// Validate shared state store configuration
+	switch stateStore {
+	case "", "memory":
+		stateStore = "memory"
+	case "redis":
+		if redisURL == "" {
+			return nil, fmt.Errorf("MCP_STATE_STORE=redis requires MCP_REDIS_URL")
+		}
+		log.Println("INFO: Shared state store: redis (MCP sessions shared across replicas)")
+	default:
+		return nil, fmt.Errorf("invalid MCP_STATE_STORE '%s'. Supported stores: memory, redis", stateStore)
+	}
+
+	// Validate rate limiting configuration
+	switch rateLimitBackend {
+	case "", "local":
+		rateLimitBackend = "local"
+	case "redis":
+		if rateLimitPerMinute > 0 && redisURL == "" {
+			return nil, fmt.Errorf("MCP_RATE_LIMIT_BACKEND=redis requires MCP_REDIS_URL")
+		}
+	default:
+		return nil, fmt.Errorf("invalid MCP_RATE_LIMIT_BACKEND '%s'. Supported backends: local, redis", rateLimitBackend)
+	}
+	if rateLimitPerMinute > 0 {
+		log.Printf("INFO: Rate limiting enabled: %d tool calls per minute per user (backend: %s)", rateLimitPerMinute, rateLimitBackend)
+	}
+
+	// Validate result store configuration. Spilled results default to disk
+	// storage, since keeping their pages in memory would defeat the spill.
+	if resultStore == "" && spillThresholdRows > 0 {
+		resultStore = "disk"
+	}
+	switch resultStore {
+	case "", "memory":
+		resultStore = "memory"
+	case "redis":
+		if (resultPageSize > 0 || maxResponseBytes > 0) && redisURL == "" {
+			return nil, fmt.Errorf("MCP_RESULT_STORE=redis requires MCP_REDIS_URL")
+		}
+	case "s3":
+		if (resultPageSize > 0 || maxResponseBytes > 0) && resultS3Bucket == "" {
+			return nil, fmt.Errorf("MCP_RESULT_STORE=s3 requires MCP_RESULT_S3_BUCKET")
+		}
+	case "disk":
+	default:
+		return nil, fmt.Errorf("invalid MCP_RESULT_STORE '%s'. Supported stores: memory, redis, s3, disk", resultStore)
+	}
+
+	// Spilled results are served page by page, so spilling implies pagination
+	if spillThresholdRows > 0 {
+		if resultPageSize == 0 {
+			resultPageSize = spillThresholdRows
+		}
+		log.Printf("INFO: Disk spill enabled: results over %d rows spill to %s (encrypted: %t)", spillThresholdRows, spillDir, spillEncrypt)
+	}
+	if resultTTL <= 0 {
+		resultTTL = time.Hour
+	}
+	if maxResponseBytes > 0 {
+		log.Printf("INFO: Response chunking enabled: responses over %d bytes are fetched with fetch_result_chunk", maxResponseBytes)
+	}
+	if maxConcurrentQueries > 0 {
+		log.Printf("INFO: Query scheduling enabled: %d concurrent queries, fair queueing per user (queue timeout: %s)", maxConcurrentQueries, queryQueueTimeout)
+	}
+	if memoryBudgetMB > 0 {
+		log.Printf("INFO: Result memory budget: %d MB shared by in-flight queries (queue timeout: %s)", memoryBudgetMB, memoryQueueTimeout)
+	}
+	// Tenants are identified by their access token, so tenancy needs OAuth
+	if tenantsFile != "" {
+		if !oauthEnabled {
+			return nil, fmt.Errorf("MCP_TENANTS_FILE requires OAUTH_ENABLED=true")
+		}
+		log.Printf("INFO: Multi-tenant mode: tenants from %s, identified by the %q token claim", tenantsFile, tenantClaim)
+	}
+	// A tenant's workspace is fixed by its token, so profiles cannot switch it
+	if profilesFile != "" {
+		if tenantsFile != "" {
+			return nil, fmt.Errorf("MCP_PROFILES_FILE cannot be combined with MCP_TENANTS_FILE")
+		}
+		log.Printf("INFO: Environment profiles enabled from %s", profilesFile)
+	}
+	if auditLogFile != "" {
+		log.Printf("INFO: Audit logging enabled: tool calls are recorded in %s", auditLogFile)
+	}
+	if auditSigningKey != "" {
+		if auditLogFile == "" {
+			log.Printf("WARNING: MCP_AUDIT_SIGNING_KEY is set but MCP_AUDIT_LOG is not, so nothing is signed")
+		} else if len(auditSigningKey) < 32 {
+			return nil, fmt.Errorf("MCP_AUDIT_SIGNING_KEY must be at least 32 bytes")
+		} else {
+			log.Printf("INFO: Audit records are signed")
+		}
+	}
+	if syslogAddress != "" {
+		if syslogProtocol != "tls" && syslogProtocol != "tcp" {
+			return nil, fmt.Errorf("invalid MCP_SYSLOG_PROTOCOL '%s'. Supported protocols: tls, tcp", syslogProtocol)
+		}
+		if syslogProtocol == "tcp" {
+			log.Printf("WARNING: Security events are sent to %s over plain TCP; use MCP_SYSLOG_PROTOCOL=tls outside trusted networks", syslogAddress)
+		} else {
+			log.Printf("INFO: Security events are forwarded to syslog collector %s over TLS", syslogAddress)
+		}
+	}
+	if anomalyDetection {
+		location, err := time.LoadLocation(anomalyTimezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MCP_ANOMALY_TIMEZONE: %w", err)
+		}
+		if anomalyHours != "" {
+			if _, err := anomaly.ParseBusinessHours(anomalyHours, location); err != nil {
+				return nil, fmt.Errorf("invalid MCP_ANOMALY_BUSINESS_HOURS: %w", err)
+			}
+		}
+		if anomalyWebhookURL != "" {
+			if u, err := url.Parse(anomalyWebhookURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return nil, fmt.Errorf("invalid MCP_ANOMALY_WEBHOOK_URL '%s': must be an http or https URL", anomalyWebhookURL)
+			}
+		}
+		if anomalyWindow <= 0 {
+			anomalyWindow = 10 * time.Minute
+		}
+		log.Printf("INFO: Anomaly detection enabled (table enumeration: %d tables in %s, scan spikes: %dx, business hours: %q)",
+			anomalyTables, anomalyWindow, anomalySpikeFactor, anomalyHours)
+		if anomalySpikeFactor > 0 && usageLogFile == "" {
+			log.Printf("WARNING: Bytes-scanned spikes are only detected with MCP_USAGE_LOG set")
+		}
+		if syslogAddress == "" && anomalyWebhookURL == "" {
+			log.Printf("WARNING: Anomalies are only logged; set MCP_SYSLOG_ADDR or MCP_ANOMALY_WEBHOOK_URL to forward them")
+		}
+	} else if anomalyWebhookURL != "" || anomalyHours != "" {
+		log.Printf("WARNING: Anomaly settings are configured but MCP_ANOMALY_DETECTION is not enabled")
+	}
+	if subscriptions {
+		for _, prefix := range subWebhooks {
+			if u, err := url.Parse(prefix); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return nil, fmt.Errorf("invalid MCP_SUBSCRIPTION_WEBHOOKS entry '%s': must be an http or https URL prefix", prefix)
+			}
+		}
+		if subMinInterval <= 0 {
+			subMinInterval = time.Minute
+		}
+		if subMaxLifetime <= 0 {
+			subMaxLifetime = 24 * time.Hour
+		}
+		if subMaxPerUser == 0 {
+			subMaxPerUser = 5
+		}
+		log.Printf("INFO: Query subscriptions enabled (minimum interval: %s, maximum lifetime: %s, %d per user, %d webhook prefixes)",
+			subMinInterval, subMaxLifetime, subMaxPerUser, len(subWebhooks))
+	} else if len(subWebhooks) > 0 {
+		log.Printf("WARNING: MCP_SUBSCRIPTION_WEBHOOKS is set but MCP_SUBSCRIPTIONS is not enabled")
+	}
+	if metadataWarmup {
+		for _, table := range warmupTables {
+			parts := strings.Split(table, ".")
+			if len(parts) > 3 || slices.Contains(parts, "") {
+				return nil, fmt.Errorf("invalid MCP_WARMUP_TABLES entry '%s': expected catalog.schema.table, schema.table, or table", table)
+			}
+		}
+		schedule := "at startup"
+		if warmupInterval > 0 {
+			schedule = "at startup and every " + warmupInterval.String()
+		}
+		log.Printf("INFO: Metadata warm-up enabled %s (tables listed in up to %d schemas, %d hot tables)", schedule, warmupMaxSchemas, len(warmupTables))
+	} else if len(warmupTables) > 0 {
+		log.Printf("WARNING: MCP_WARMUP_TABLES is set but MCP_METADATA_WARMUP is not enabled")
+	}
+	if historyFile != "" {
+		log.Printf("INFO: Query history enabled: execute_query calls are recorded in %s (kept %s, %d per user)", historyFile, historyMaxAge, historyMaxPerUser)
+		if retentionInterval == 0 && (historyMaxAge > 0 || historyMaxPerUser > 0) {
+			log.Printf("WARNING: Query history limits are not enforced because MCP_RETENTION_INTERVAL is 0")
+		}
+	}
+	if usagePolicyFile != "" {
+		switch {
+		case !oauthEnabled:
+			log.Printf("WARNING: MCP_USAGE_POLICY_FILE is ignored because OAuth is disabled")
+		case oauthMode != "proxy":
+			return nil, fmt.Errorf("MCP_USAGE_POLICY_FILE requires OAUTH_MODE=proxy: in native mode clients get tokens from the identity provider directly")
+		default:
+			log.Printf("INFO: Users must acknowledge the usage policy in %s before tokens are issued", usagePolicyFile)
+		}
+	}
+	if adminToken != "" {
+		if len(adminToken) < 32 {
+			return nil, fmt.Errorf("MCP_ADMIN_TOKEN must be at least 32 bytes")
+		}
+		if auditLogFile == "" {
+			log.Printf("WARNING: MCP_ADMIN_TOKEN is set but MCP_AUDIT_LOG is not, so access reports are empty")
+		} else {
+			log.Printf("INFO: Admin access report endpoint enabled at /admin/access-report")
+		}
+		if usageLogFile != "" {
+			log.Printf("INFO: Admin chargeback endpoint enabled at /admin/chargeback")
+		}
+	}
+	if usageLogFile != "" {
+		log.Printf("INFO: Usage accounting enabled: query resource usage is recorded in %s", usageLogFile)
+		if usageTeamClaim != "" && !oauthEnabled {
+			log.Printf("WARNING: MCP_USAGE_TEAM_CLAIM is ignored because OAuth is disabled")
+		}
+	} else if usageTeamClaim != "" || costPerTBScanned > 0 || costPerCPUHour > 0 {
+		log.Printf("WARNING: Chargeback settings are configured but MCP_USAGE_LOG is not set")
+	}
+	if oauthEnabled && auditLogFile != "" && len(reportAdmins) == 0 {
+		log.Printf("INFO: generate_access_report is disabled for all users; set MCP_REPORT_ADMINS to allow it")
+	}
+	if (auditRotateMB > 0 || auditMaxAge > 0 || auditMaxTotalMB > 0) && auditLogFile == "" && usageLogFile == "" {
+		log.Printf("WARNING: Audit retention is configured but MCP_AUDIT_LOG is not set")
+	}
+	switch piiMode {
+	case "", "off":
+		piiMode = "off"
+	case "warn", "mask":
+		log.Printf("INFO: PII scanning enabled (mode: %s, %d tagged column patterns)", piiMode, len(piiColumns))
+	default:
+		return nil, fmt.Errorf("invalid MCP_PII_MODE '%s'. Supported modes: off, warn, mask", piiMode)
+	}
+	if redactColumnPattern != "" {
+		if _, err := regexp.Compile(redactColumnPattern); err != nil {
+			return nil, fmt.Errorf("invalid MCP_REDACT_COLUMN_PATTERN: %w", err)
+		}
+	}
+	if len(redactColumns) > 0 || redactColumnPattern != "" {
+		log.Printf("INFO: Log redaction enabled for %d columns (pattern: %q)", len(redactColumns), redactColumnPattern)
+	}
+	if batchHost != "" {
+		log.Printf("INFO: Workload routing enabled: queries scanning over %d MB run on %s:%d (unfiltered scans: %t)", batchMinScanMB, batchHost, batchPort, batchFullScans)
+	}
+	if resultPageSize > 0 {
+		log.Printf("INFO: Result pagination enabled: %d rows per page (store: %s, ttl: %s)", resultPageSize, resultStore, resultTTL)
+	}
+
 	return &TrinoConfig{
-		Host:                resolveEnv("TRINO_HOST", "localhost"),
-		Port:                port,
-		User:                resolveEnv("TRINO_USER", "trino"),
-		Password:            resolveEnv("TRINO_PASSWORD", ""),
-		Catalog:             resolveEnv("TRINO_CATALOG", "memory"),
-		Schema:              resolveEnv("TRINO_SCHEMA", "default"),
-		Scheme:              scheme,
-		SSL:                 ssl,
-		SSLInsecure:         sslInsecure,
-		AllowWriteQueries:   allowWriteQueries,
-		QueryTimeout:        queryTimeout,
-		MaxRows:             maxRows,
-		OAuthEnabled:        oauthEnabled,
-		OAuthMode:           oauthMode,
-		OAuthProvider:       oauthProvider,
-		JWTSecret:           jwtSecret,
-		OIDCIssuer:          oidcIssuer,
-		OIDCAudience:        oidcAudience,
-		OIDCClientID:        oidcClientID,
-		OIDCClientSecret:    oidcClientSecret,
-		OAuthRedirectURIs:   oauthRedirectURIs,
-		AllowedCatalogs:     allowedCatalogs,
-		AllowedSchemas:      allowedSchemas,
-		AllowedTables:       allowedTables,
-		EnableImpersonation: enableImpersonation,
-		ImpersonationField:  impersonationField,
-		TrinoSource:         trinoSource,
+		Host:                 resolveEnv("TRINO_HOST", "localhost"),
+		Port:                 port,
+		User:                 resolveEnv("TRINO_USER", "trino"),
+		Password:             password,
+		Catalog:              resolveEnv("TRINO_CATALOG", "memory"),
+		Schema:               resolveEnv("TRINO_SCHEMA", "default"),
+		Scheme:               scheme,
+		SSL:                  ssl,
+		SSLInsecure:          sslInsecure,
+		AllowWriteQueries:    allowWriteQueries,
+		QueryTimeout:         queryTimeout,
+		MaxRows:              maxRows,
+		RecoveryTimeout:      recoveryTimeout,
+		OAuthEnabled:         oauthEnabled,
+		OAuthMode:            oauthMode,
+		OAuthProvider:        oauthProvider,
+		JWTSecret:            jwtSecret,
+		OIDCIssuer:           oidcIssuer,
+		OIDCAudience:         oidcAudience,
+		OIDCClientID:         oidcClientID,
+		OIDCClientSecret:     oidcClientSecret,
+		OAuthRedirectURIs:    oauthRedirectURIs,
+		AllowedCatalogs:      allowedCatalogs,
+		AllowedSchemas:       allowedSchemas,
+		AllowedTables:        allowedTables,
+		CatalogAliases:       catalogAliases,
+		EnableImpersonation:  enableImpersonation,
+		ImpersonationField:   impersonationField,
+		TrinoSource:          trinoSource,
+		TrinoClientInfo:      trinoClientInfo,
+		TrinoTraceToken:      trinoTraceToken,
+		TrinoHeaders:         trinoHeaders,
+		StateStore:           stateStore,
+		RedisURL:             redisURL,
+		RateLimitPerMinute:   rateLimitPerMinute,
+		RateLimitBurst:       rateLimitBurst,
+		RateLimitBackend:     rateLimitBackend,
+		ResultPageSize:       resultPageSize,
+		ResultStore:          resultStore,
+		ResultTTL:            resultTTL,
+		ResultS3Bucket:       resultS3Bucket,
+		ResultS3Prefix:       resultS3Prefix,
+		SpillThresholdRows:   spillThresholdRows,
+		SpillDir:             spillDir,
+		SpillEncrypt:         spillEncrypt,
+		MaxResponseBytes:     maxResponseBytes,
+		MaxConcurrentQueries: maxConcurrentQueries,
+		QueryQueueTimeout:    queryQueueTimeout,
+		MemoryBudgetMB:       memoryBudgetMB,
+		MemoryQueueTimeout:   memoryQueueTimeout,
+		KeepaliveInterval:    keepaliveInterval,
+		ChecksFile:           checksFile,
+		PasswordFile:         passwordFile,
+		OIDCClientSecretFile: oidcClientSecretFile,
+		SecretPollInterval:   secretPollInterval,
+		TenantsFile:          tenantsFile,
+		TenantClaim:          tenantClaim,
+		ProfilesFile:         profilesFile,
+		AuditLogFile:         auditLogFile,
+		AuditSigningKey:      auditSigningKey,
+		SyslogAddress:        syslogAddress,
+		SyslogProtocol:       syslogProtocol,
+		SyslogCAFile:         syslogCAFile,
+		SyslogFacility:       syslogFacility,
+		AnomalyDetection:     anomalyDetection,
+		AnomalyTables:        anomalyTables,
+		AnomalyWindow:        anomalyWindow,
+		AnomalySpikeFactor:   anomalySpikeFactor,
+		AnomalyScanMinMB:     anomalyScanMinMB,
+		AnomalyHours:         anomalyHours,
+		AnomalyTimezone:      anomalyTimezone,
+		AnomalyWebhookURL:    anomalyWebhookURL,
+		AnomalyWebhookSecret: anomalyWebhookSecret,
+		Subscriptions:        subscriptions,
+		SubMinInterval:       subMinInterval,
+		SubMaxLifetime:       subMaxLifetime,
+		SubMaxPerUser:        subMaxPerUser,
+		SubWebhooks:          subWebhooks,
+		SubWebhookSecret:     subWebhookSecret,
+		MetadataWarmup:       metadataWarmup,
+		WarmupInterval:       warmupInterval,
+		WarmupMaxSchemas:     warmupMaxSchemas,
+		WarmupTables:         warmupTables,
+		HistoryFile:          historyFile,
+		HistoryMaxAge:        historyMaxAge,
+		HistoryMaxPerUser:    historyMaxPerUser,
+		UsagePolicyFile:      usagePolicyFile,
+		UsagePolicyVersion:   usagePolicyVersion,
+		ReportAdmins:         reportAdmins,
+		AdminToken:           adminToken,
+		UsageLogFile:         usageLogFile,
+		UsageTeamClaim:       usageTeamClaim,
+		CostPerTBScanned:     costPerTBScanned,
+		CostPerCPUHour:       costPerCPUHour,
+		RetentionInterval:    retentionInterval,
+		AuditRotateMB:        auditRotateMB,
+		AuditMaxAge:          auditMaxAge,
+		AuditMaxTotalMB:      auditMaxTotalMB,
+		ResultMaxAge:         resultMaxAge,
+		ResultMaxTotalMB:     resultMaxTotalMB,
+		SpillMaxAge:          spillMaxAge,
+		SpillMaxTotalMB:      spillMaxTotalMB,
+		PIIMode:              piiMode,
+		PIIColumns:           piiColumns,
+		RedactColumns:        redactColumns,
+		RedactColumnPattern:  redactColumnPattern,
+		BatchHost:            batchHost,
+		BatchPort:            batchPort,
+		BatchMinScanMB:       batchMinScanMB,
+		BatchFullScans:       batchFullScans,
 	}, nil
 }
 
+// parseNonNegativeInt reads an integer setting, falling back to the default
+// with a warning when the value is malformed or negative
+func parseNonNegativeInt(resolveEnv func(string, string) string, key string, fallback int) int {
+	raw := resolveEnv(key, strconv.Itoa(fallback))
+	value, err := strconv.Atoi(strings.TrimSpace(raw))
+	switch {
+	case err != nil:
+		log.Printf("WARNING: Invalid %s '%s': not an integer. Using default of %d", key, raw, fallback)
+		return fallback
+	case value < 0:
+		log.Printf("WARNING: Invalid %s '%d': must be non-negative. Using default of %d", key, value, fallback)
+		return fallback
+	}
+	return value
+}
+
+// parseNonNegativeFloat reads a decimal setting such as a price, falling back
+// to zero with a warning when the value is malformed or negative
+func parseNonNegativeFloat(resolveEnv func(string, string) string, key string) float64 {
+	raw := strings.TrimSpace(resolveEnv(key, "0"))
+	value, err := strconv.ParseFloat(raw, 64)
+	switch {
+	case err != nil || math.IsNaN(value) || math.IsInf(value, 0):
+		log.Printf("WARNING: Invalid %s '%s': not a number. Using 0", key, raw)
+		return 0
+	case value < 0:
+		log.Printf("WARNING: Invalid %s '%s': must be non-negative. Using 0", key, raw)
+		return 0
+	}
+	return value
+}
+
 // parseAllowlist parses a comma-separated allowlist from an environment variable
 func parseAllowlist(value string) []string {
 	if value == "" {
@@ -295,6 +953,98 @@ func parseAllowlist(value string) []string {
 	return result
 }
 
+// parseCatalogAliases parses comma-separated alias=catalog pairs
+func parseCatalogAliases(value string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	for _, item := range parseAllowlist(value) {
+		alias, catalog, ok := strings.Cut(item, "=")
+		alias, catalog = strings.ToLower(strings.TrimSpace(alias)), strings.TrimSpace(catalog)
+		if !ok || alias == "" || catalog == "" {
+			return nil, fmt.Errorf("invalid format in TRINO_CATALOG_ALIASES: '%s' (expected alias=catalog)", item)
+		}
+		if _, duplicate := aliases[alias]; duplicate {
+			return nil, fmt.Errorf("invalid TRINO_CATALOG_ALIASES: alias '%s' is defined twice", alias)
+		}
+		aliases[alias] = catalog
+	}
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+	return aliases, nil
+}
+
+// headerTemplateFields are the placeholders header templates may use
+var headerTemplateFields = map[string]bool{"user": true, "session": true, "request_id": true, "tool": true}
+
+// headerTemplatePlaceholder matches a {field} placeholder in a header template
+var headerTemplatePlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// validateHeaderTemplate checks that a header template only uses known
+// placeholders and has no control characters
+func validateHeaderTemplate(envVar, template string) error {
+	for _, r := range template {
+		if (r < ' ' && r != '\t') || r == 0x7f {
+			return fmt.Errorf("invalid %s: control characters are not allowed in header values", envVar)
+		}
+	}
+	for _, match := range headerTemplatePlaceholder.FindAllStringSubmatch(template, -1) {
+		if !headerTemplateFields[match[1]] {
+			return fmt.Errorf("invalid %s: unknown placeholder {%s}. Supported placeholders: {user}, {session}, {request_id}, {tool}", envVar, match[1])
+		}
+	}
+	return nil
+}
+
+// headerName matches a valid HTTP header name
+var headerName = regexp.MustCompile(`^[A-Za-z0-9!#$%&'*+.^_|~-]+$`)
+
+// reservedHeaders are set by the driver, authentication, or a dedicated
+// setting, and cannot be overridden through TRINO_EXTRA_HEADERS
+var reservedHeaders = map[string]string{
+	"authorization":       "it carries the Trino credentials",
+	"host":                "it is set from TRINO_HOST",
+	"content-length":      "it is set by the HTTP client",
+	"content-type":        "it is set by the HTTP client",
+	"transfer-encoding":   "it is set by the HTTP client",
+	"connection":          "it is set by the HTTP client",
+	"x-trino-user":        "it is set from the Trino user or impersonation",
+	"x-trino-source":      "use TRINO_SOURCE",
+	"x-trino-client-info": "use TRINO_CLIENT_INFO",
+	"x-trino-trace-token": "use TRINO_TRACE_TOKEN",
+}
+
+// parseHeaderTemplates parses comma-separated Name=template pairs. Besides
+// the reserved headers, only X-Trino-Client-Tags may be set among the
+// X-Trino-* protocol headers the driver manages.
+func parseHeaderTemplates(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, item := range parseAllowlist(value) {
+		name, template, ok := strings.Cut(item, "=")
+		name, template = http.CanonicalHeaderKey(strings.TrimSpace(name)), strings.TrimSpace(template)
+		if !ok || !headerName.MatchString(name) || template == "" {
+			return nil, fmt.Errorf("invalid format in TRINO_EXTRA_HEADERS: '%s' (expected Header-Name=value)", item)
+		}
+		lower := strings.ToLower(name)
+		if reason, reserved := reservedHeaders[lower]; reserved {
+			return nil, fmt.Errorf("invalid TRINO_EXTRA_HEADERS: %s cannot be set here; %s", name, reason)
+		}
+		if strings.HasPrefix(lower, "x-trino-") && lower != "x-trino-client-tags" {
+			return nil, fmt.Errorf("invalid TRINO_EXTRA_HEADERS: %s is a Trino protocol header managed by the driver", name)
+		}
+		if _, duplicate := headers[name]; duplicate {
+			return nil, fmt.Errorf("invalid TRINO_EXTRA_HEADERS: header '%s' is defined twice", name)
+		}
+		if err := validateHeaderTemplate("TRINO_EXTRA_HEADERS", template); err != nil {
+			return nil, err
+		}
+		headers[name] = template
+	}
+	if len(headers) == 0 {
+		return nil, nil
+	}
+	return headers, nil
+}
+
 // validateAllowlist validates the format of allowlist entries
 func validateAllowlist(envVar string, allowlist []string, expectedDots int) error {
 	for _, item := range allowlist {
@@ -325,6 +1075,16 @@ func logAllowlistConfiguration(catalogs, schemas, tables []string) {
 	}
 }
 
+// ReadSecretFile reads a secret from a mounted file, dropping the trailing
+// newline most secret tooling writes
+func ReadSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-configured secret file
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
 // getEnv retrieves an environment variable or returns a default value
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -387,3 +389,470 @@ func TestNewTrinoConfigFailsWhenRequiredSecretsFail(t *testing.T) {
 		t.Fatalf("expected NewTrinoConfig() to fail when required secret source is unavailable")
 	}
 }
+
+func TestNewTrinoConfigSpillDefaults(t *testing.T) {
+	for _, key := range []string{"MCP_SPILL_THRESHOLD_ROWS", "MCP_RESULT_PAGE_SIZE", "MCP_RESULT_STORE", "OAUTH_ENABLED"} {
+		orig, had := os.LookupEnv(key)
+		defer func(key, orig string, had bool) {
+			if had {
+				_ = os.Setenv(key, orig)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		}(key, orig, had)
+	}
+	_ = os.Setenv("OAUTH_ENABLED", "false")
+	_ = os.Unsetenv("MCP_RESULT_PAGE_SIZE")
+	_ = os.Unsetenv("MCP_RESULT_STORE")
+
+	_ = os.Setenv("MCP_SPILL_THRESHOLD_ROWS", "5000")
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.ResultPageSize != 5000 {
+		t.Errorf("ResultPageSize = %d, want 5000 when spilling", cfg.ResultPageSize)
+	}
+	if cfg.ResultStore != "disk" {
+		t.Errorf("ResultStore = %q, want disk when spilling", cfg.ResultStore)
+	}
+
+	// An explicit store is kept
+	_ = os.Setenv("MCP_RESULT_STORE", "memory")
+	cfg, err = NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.ResultStore != "memory" {
+		t.Errorf("ResultStore = %q, want explicit memory", cfg.ResultStore)
+	}
+
+	// Spill disabled leaves pagination off
+	_ = os.Unsetenv("MCP_RESULT_STORE")
+	_ = os.Setenv("MCP_SPILL_THRESHOLD_ROWS", "0")
+	cfg, err = NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.ResultPageSize != 0 || cfg.ResultStore != "memory" {
+		t.Errorf("ResultPageSize = %d, ResultStore = %q; want 0, memory", cfg.ResultPageSize, cfg.ResultStore)
+	}
+}
+
+func TestNewTrinoConfigKeepaliveInterval(t *testing.T) {
+	for _, key := range []string{"MCP_KEEPALIVE_INTERVAL", "OAUTH_ENABLED"} {
+		orig, had := os.LookupEnv(key)
+		defer func(key, orig string, had bool) {
+			if had {
+				_ = os.Setenv(key, orig)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		}(key, orig, had)
+	}
+	_ = os.Setenv("OAUTH_ENABLED", "false")
+
+	_ = os.Unsetenv("MCP_KEEPALIVE_INTERVAL")
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.KeepaliveInterval != 15*time.Second {
+		t.Errorf("KeepaliveInterval = %s, want 15s by default", cfg.KeepaliveInterval)
+	}
+
+	_ = os.Setenv("MCP_KEEPALIVE_INTERVAL", "0")
+	cfg, err = NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.KeepaliveInterval != 0 {
+		t.Errorf("KeepaliveInterval = %s, want 0 when disabled", cfg.KeepaliveInterval)
+	}
+}
+
+func TestNewTrinoConfigSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("file-pass\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("TRINO_PASSWORD", "env-pass")
+	t.Setenv("TRINO_PASSWORD_FILE", passwordFile)
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.Password != "file-pass" {
+		t.Errorf("Password = %q, want file-pass from TRINO_PASSWORD_FILE", cfg.Password)
+	}
+	if cfg.PasswordFile != passwordFile || cfg.SecretPollInterval != 30*time.Second {
+		t.Errorf("PasswordFile = %q, SecretPollInterval = %s", cfg.PasswordFile, cfg.SecretPollInterval)
+	}
+
+	t.Setenv("OIDC_CLIENT_SECRET_FILE", filepath.Join(dir, "missing"))
+	if _, err := NewTrinoConfig(); err == nil {
+		t.Error("expected an error for an unreadable OIDC_CLIENT_SECRET_FILE")
+	}
+}
+
+func TestNewTrinoConfigTenantsRequireOAuth(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("MCP_TENANTS_FILE", "/etc/mcp-trino/tenants.yaml")
+
+	if _, err := NewTrinoConfig(); err == nil {
+		t.Fatal("expected an error when multi-tenant mode is enabled without OAuth")
+	}
+}
+
+func TestNewTrinoConfigWorkloadRouting(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("TRINO_PORT", "8443")
+	t.Setenv("TRINO_BATCH_HOST", "batch.trino.example.com")
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.BatchHost != "batch.trino.example.com" || cfg.BatchPort != 8443 || cfg.BatchMinScanMB != 10240 || cfg.BatchFullScans {
+		t.Errorf("workload routing = %q:%d, %d MB, full scans %t", cfg.BatchHost, cfg.BatchPort, cfg.BatchMinScanMB, cfg.BatchFullScans)
+	}
+}
+
+func TestParseCatalogAliases(t *testing.T) {
+	aliases, err := parseCatalogAliases(" Warehouse = iceberg_prod_eu , lake=hive_prod")
+	if err != nil {
+		t.Fatalf("parseCatalogAliases() error = %v", err)
+	}
+	want := map[string]string{"warehouse": "iceberg_prod_eu", "lake": "hive_prod"}
+	if !reflect.DeepEqual(aliases, want) {
+		t.Errorf("parseCatalogAliases() = %v, want %v", aliases, want)
+	}
+
+	if aliases, err := parseCatalogAliases(""); err != nil || aliases != nil {
+		t.Errorf("parseCatalogAliases(\"\") = %v, %v, want no aliases", aliases, err)
+	}
+	for _, value := range []string{"warehouse", "warehouse=", "=hive", "a=hive,A=iceberg"} {
+		if _, err := parseCatalogAliases(value); err == nil {
+			t.Errorf("parseCatalogAliases(%q) expected an error", value)
+		}
+	}
+}
+
+func TestNewTrinoConfigClientHeaders(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("TRINO_CLIENT_INFO", "mcp-trino {tool} for {user}")
+	t.Setenv("TRINO_TRACE_TOKEN", "{session}:{request_id}")
+	t.Setenv("TRINO_EXTRA_HEADERS", "x-gateway-route=mcp-{user}, X-Trino-Client-Tags=mcp")
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.TrinoClientInfo != "mcp-trino {tool} for {user}" || cfg.TrinoTraceToken != "{session}:{request_id}" {
+		t.Errorf("client info = %q, trace token = %q", cfg.TrinoClientInfo, cfg.TrinoTraceToken)
+	}
+	want := map[string]string{"X-Gateway-Route": "mcp-{user}", "X-Trino-Client-Tags": "mcp"}
+	if !reflect.DeepEqual(cfg.TrinoHeaders, want) {
+		t.Errorf("TrinoHeaders = %v, want %v", cfg.TrinoHeaders, want)
+	}
+
+	t.Setenv("TRINO_TRACE_TOKEN", "{trace}")
+	if _, err := NewTrinoConfig(); err == nil || !strings.Contains(err.Error(), "unknown placeholder {trace}") {
+		t.Errorf("NewTrinoConfig() error = %v, want an unknown placeholder error", err)
+	}
+}
+
+func TestParseHeaderTemplates(t *testing.T) {
+	if headers, err := parseHeaderTemplates(""); err != nil || headers != nil {
+		t.Errorf("parseHeaderTemplates(\"\") = %v, %v, want no headers", headers, err)
+	}
+	for _, value := range []string{
+		"X-Route",                    // no value
+		"X-Route=",                   // empty value
+		"Bad Name=x",                 // invalid header name
+		"Authorization=Bearer x",     // credentials
+		"X-Trino-User={user}",        // would bypass impersonation
+		"X-Trino-Client-Info={user}", // has its own setting
+		"X-Trino-Catalog=hive",       // managed by the driver
+		"X-Route=a,x-route=b",        // duplicate
+		"X-Route={team}",             // unknown placeholder
+	} {
+		if _, err := parseHeaderTemplates(value); err == nil {
+			t.Errorf("parseHeaderTemplates(%q) expected an error", value)
+		}
+	}
+}
+
+func TestNewTrinoConfigProfilesExcludeTenants(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "true")
+	t.Setenv("MCP_TENANTS_FILE", "/etc/mcp-trino/tenants.yaml")
+	t.Setenv("MCP_PROFILES_FILE", "/etc/mcp-trino/profiles.yaml")
+
+	if _, err := NewTrinoConfig(); err == nil || !strings.Contains(err.Error(), "MCP_PROFILES_FILE") {
+		t.Fatalf("NewTrinoConfig() error = %v, want profiles and tenants rejected together", err)
+	}
+}
+
+func TestNewTrinoConfigPIIMode(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("MCP_PII_COLUMNS", "email, *_ssn")
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.PIIMode != "off" || !reflect.DeepEqual(cfg.PIIColumns, []string{"email", "*_ssn"}) {
+		t.Errorf("PIIMode = %q, PIIColumns = %v", cfg.PIIMode, cfg.PIIColumns)
+	}
+
+	t.Setenv("MCP_PII_MODE", "scrub")
+	if _, err := NewTrinoConfig(); err == nil {
+		t.Error("expected an error for an invalid MCP_PII_MODE")
+	}
+}
+
+func TestNewTrinoConfigAuditSigningKey(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("MCP_AUDIT_LOG", "/var/lib/mcp-trino/audit.jsonl")
+	t.Setenv("MCP_AUDIT_SIGNING_KEY", strings.Repeat("k", 32))
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.AuditLogFile != "/var/lib/mcp-trino/audit.jsonl" || cfg.AuditSigningKey != strings.Repeat("k", 32) {
+		t.Errorf("AuditLogFile = %q, AuditSigningKey = %q", cfg.AuditLogFile, cfg.AuditSigningKey)
+	}
+
+	t.Setenv("MCP_AUDIT_SIGNING_KEY", "short")
+	if _, err := NewTrinoConfig(); err == nil || !strings.Contains(err.Error(), "MCP_AUDIT_SIGNING_KEY") {
+		t.Errorf("NewTrinoConfig() error = %v, want short signing key rejected", err)
+	}
+}
+
+func TestNewTrinoConfigRetention(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.RetentionInterval != 10*time.Minute || cfg.SpillMaxAge != 24*time.Hour || cfg.AuditRotateMB != 0 || cfg.AuditMaxAge != 0 {
+		t.Errorf("retention defaults = %s, %s, %d, %s", cfg.RetentionInterval, cfg.SpillMaxAge, cfg.AuditRotateMB, cfg.AuditMaxAge)
+	}
+
+	t.Setenv("MCP_AUDIT_ROTATE_MB", "256")
+	t.Setenv("MCP_AUDIT_RETENTION_DAYS", "400")
+	t.Setenv("MCP_RESULT_RETENTION_HOURS", "6")
+	t.Setenv("MCP_SPILL_MAX_TOTAL_MB", "2048")
+	cfg, err = NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.AuditRotateMB != 256 || cfg.AuditMaxAge != 400*24*time.Hour || cfg.ResultMaxAge != 6*time.Hour || cfg.SpillMaxTotalMB != 2048 {
+		t.Errorf("retention = %d MB, %s, %s, %d MB", cfg.AuditRotateMB, cfg.AuditMaxAge, cfg.ResultMaxAge, cfg.SpillMaxTotalMB)
+	}
+}
+
+func TestNewTrinoConfigAccessReport(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("MCP_AUDIT_LOG", "/var/lib/mcp-trino/audit.jsonl")
+	t.Setenv("MCP_REPORT_ADMINS", "governance@example.com, alice")
+	t.Setenv("MCP_ADMIN_TOKEN", strings.Repeat("t", 32))
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg.ReportAdmins, []string{"governance@example.com", "alice"}) || cfg.AdminToken != strings.Repeat("t", 32) {
+		t.Errorf("ReportAdmins = %v, AdminToken = %q", cfg.ReportAdmins, cfg.AdminToken)
+	}
+
+	t.Setenv("MCP_ADMIN_TOKEN", "short")
+	if _, err := NewTrinoConfig(); err == nil || !strings.Contains(err.Error(), "MCP_ADMIN_TOKEN") {
+		t.Errorf("NewTrinoConfig() error = %v, want short admin token rejected", err)
+	}
+}
+
+func TestNewTrinoConfigSyslog(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("MCP_SYSLOG_ADDR", "siem.example.com:6514")
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.SyslogProtocol != "tls" || cfg.SyslogFacility != "authpriv" {
+		t.Errorf("SyslogProtocol = %q, SyslogFacility = %q, want tls, authpriv", cfg.SyslogProtocol, cfg.SyslogFacility)
+	}
+
+	t.Setenv("MCP_SYSLOG_PROTOCOL", "UDP")
+	if _, err := NewTrinoConfig(); err == nil || !strings.Contains(err.Error(), "MCP_SYSLOG_PROTOCOL") {
+		t.Errorf("NewTrinoConfig() error = %v, want unsupported protocol rejected", err)
+	}
+}
+
+func TestNewTrinoConfigRedaction(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("MCP_REDACT_COLUMNS", "ssn, salary")
+	t.Setenv("MCP_REDACT_COLUMN_PATTERN", `_(token|secret)$`)
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg.RedactColumns, []string{"ssn", "salary"}) || cfg.RedactColumnPattern != `_(token|secret)$` {
+		t.Errorf("RedactColumns = %v, RedactColumnPattern = %q", cfg.RedactColumns, cfg.RedactColumnPattern)
+	}
+
+	t.Setenv("MCP_REDACT_COLUMN_PATTERN", "(")
+	if _, err := NewTrinoConfig(); err == nil || !strings.Contains(err.Error(), "MCP_REDACT_COLUMN_PATTERN") {
+		t.Errorf("NewTrinoConfig() error = %v, want invalid pattern rejected", err)
+	}
+}
+
+func TestNewTrinoConfigUsagePolicy(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "true")
+	t.Setenv("OAUTH_PROVIDER", "hmac")
+	t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+	t.Setenv("OAUTH_MODE", "proxy")
+	t.Setenv("MCP_USAGE_POLICY_FILE", "/etc/mcp-trino/usage-policy.txt")
+	t.Setenv("MCP_USAGE_POLICY_VERSION", "2026-10")
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.UsagePolicyFile != "/etc/mcp-trino/usage-policy.txt" || cfg.UsagePolicyVersion != "2026-10" {
+		t.Errorf("UsagePolicyFile = %q, UsagePolicyVersion = %q", cfg.UsagePolicyFile, cfg.UsagePolicyVersion)
+	}
+
+	t.Setenv("OAUTH_MODE", "native")
+	if _, err := NewTrinoConfig(); err == nil || !strings.Contains(err.Error(), "OAUTH_MODE=proxy") {
+		t.Errorf("NewTrinoConfig() error = %v, want native mode rejected", err)
+	}
+}
+
+func TestNewTrinoConfigUsageAccounting(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("MCP_USAGE_LOG", "/var/log/mcp-trino/usage.jsonl")
+	t.Setenv("MCP_USAGE_TEAM_CLAIM", "team")
+	t.Setenv("MCP_COST_PER_TB_SCANNED", "5.25")
+	t.Setenv("MCP_COST_PER_CPU_HOUR", "-1")
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.UsageLogFile != "/var/log/mcp-trino/usage.jsonl" || cfg.UsageTeamClaim != "team" {
+		t.Errorf("UsageLogFile = %q, UsageTeamClaim = %q", cfg.UsageLogFile, cfg.UsageTeamClaim)
+	}
+	if cfg.CostPerTBScanned != 5.25 || cfg.CostPerCPUHour != 0 {
+		t.Errorf("CostPerTBScanned = %v, CostPerCPUHour = %v, want 5.25 and 0 for a negative price", cfg.CostPerTBScanned, cfg.CostPerCPUHour)
+	}
+}
+
+func TestNewTrinoConfigAnomalyDetection(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("MCP_ANOMALY_DETECTION", "true")
+	t.Setenv("MCP_ANOMALY_TABLE_THRESHOLD", "25")
+	t.Setenv("MCP_ANOMALY_BUSINESS_HOURS", "mon-fri 08:00-18:00")
+	t.Setenv("MCP_ANOMALY_TIMEZONE", "Europe/Berlin")
+	t.Setenv("MCP_ANOMALY_WEBHOOK_URL", "https://hooks.example.com/soc")
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if !cfg.AnomalyDetection || cfg.AnomalyTables != 25 || cfg.AnomalyWindow != 10*time.Minute || cfg.AnomalySpikeFactor != 10 || cfg.AnomalyScanMinMB != 1024 {
+		t.Errorf("anomaly thresholds = %d tables in %s, %dx spikes over %d MB", cfg.AnomalyTables, cfg.AnomalyWindow, cfg.AnomalySpikeFactor, cfg.AnomalyScanMinMB)
+	}
+	if cfg.AnomalyHours != "mon-fri 08:00-18:00" || cfg.AnomalyTimezone != "Europe/Berlin" || cfg.AnomalyWebhookURL != "https://hooks.example.com/soc" {
+		t.Errorf("AnomalyHours = %q, AnomalyTimezone = %q, AnomalyWebhookURL = %q", cfg.AnomalyHours, cfg.AnomalyTimezone, cfg.AnomalyWebhookURL)
+	}
+
+	for key, value := range map[string]string{
+		"MCP_ANOMALY_BUSINESS_HOURS": "weekdays",
+		"MCP_ANOMALY_TIMEZONE":       "Mars/Olympus_Mons",
+		"MCP_ANOMALY_WEBHOOK_URL":    "ftp://hooks.example.com",
+	} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			if _, err := NewTrinoConfig(); err == nil || !strings.Contains(err.Error(), key) {
+				t.Errorf("NewTrinoConfig() error = %v, want %s rejected", err, key)
+			}
+		})
+	}
+}
+
+func TestNewTrinoConfigSubscriptions(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("MCP_SUBSCRIPTIONS", "true")
+	t.Setenv("MCP_SUBSCRIPTION_MIN_INTERVAL", "300")
+	t.Setenv("MCP_SUBSCRIPTION_WEBHOOKS", "https://hooks.example.com/data/, https://chat.example.com/hooks/")
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if !cfg.Subscriptions || cfg.SubMinInterval != 5*time.Minute || cfg.SubMaxLifetime != 24*time.Hour || cfg.SubMaxPerUser != 5 {
+		t.Errorf("subscription limits = %s interval, %s lifetime, %d per user", cfg.SubMinInterval, cfg.SubMaxLifetime, cfg.SubMaxPerUser)
+	}
+	if len(cfg.SubWebhooks) != 2 || cfg.SubWebhooks[1] != "https://chat.example.com/hooks/" {
+		t.Errorf("SubWebhooks = %q", cfg.SubWebhooks)
+	}
+
+	t.Setenv("MCP_SUBSCRIPTION_WEBHOOKS", "hooks.example.com")
+	if _, err := NewTrinoConfig(); err == nil || !strings.Contains(err.Error(), "MCP_SUBSCRIPTION_WEBHOOKS") {
+		t.Errorf("NewTrinoConfig() error = %v, want the bare host rejected", err)
+	}
+}
+
+func TestNewTrinoConfigMetadataWarmup(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+	t.Setenv("MCP_METADATA_WARMUP", "true")
+	t.Setenv("MCP_WARMUP_INTERVAL_MINUTES", "30")
+	t.Setenv("MCP_WARMUP_TABLES", "hive.sales.orders, events.clicks")
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if !cfg.MetadataWarmup || cfg.WarmupInterval != 30*time.Minute || cfg.WarmupMaxSchemas != 100 {
+		t.Errorf("warm-up = %v every %s over %d schemas", cfg.MetadataWarmup, cfg.WarmupInterval, cfg.WarmupMaxSchemas)
+	}
+	if len(cfg.WarmupTables) != 2 || cfg.WarmupTables[1] != "events.clicks" {
+		t.Errorf("WarmupTables = %q", cfg.WarmupTables)
+	}
+
+	t.Setenv("MCP_WARMUP_TABLES", "hive..orders")
+	if _, err := NewTrinoConfig(); err == nil || !strings.Contains(err.Error(), "MCP_WARMUP_TABLES") {
+		t.Errorf("NewTrinoConfig() error = %v, want the malformed table rejected", err)
+	}
+}
+
+func TestNewTrinoConfigQueryHistory(t *testing.T) {
+	t.Setenv("OAUTH_ENABLED", "false")
+
+	cfg, err := NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.HistoryFile != "" || cfg.HistoryMaxAge != 90*24*time.Hour || cfg.HistoryMaxPerUser != 1000 {
+		t.Errorf("history defaults = %q, %s, %d", cfg.HistoryFile, cfg.HistoryMaxAge, cfg.HistoryMaxPerUser)
+	}
+
+	t.Setenv("MCP_QUERY_HISTORY_FILE", "/var/lib/mcp-trino/history.db")
+	t.Setenv("MCP_QUERY_HISTORY_RETENTION_DAYS", "0")
+	t.Setenv("MCP_QUERY_HISTORY_MAX_PER_USER", "200")
+	cfg, err = NewTrinoConfig()
+	if err != nil {
+		t.Fatalf("NewTrinoConfig() error = %v", err)
+	}
+	if cfg.HistoryFile != "/var/lib/mcp-trino/history.db" || cfg.HistoryMaxAge != 0 || cfg.HistoryMaxPerUser != 200 {
+		t.Errorf("history = %q, %s, %d", cfg.HistoryFile, cfg.HistoryMaxAge, cfg.HistoryMaxPerUser)
+	}
+}
@@ -0,0 +1,87 @@
+package filewatch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWatcherReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	cert := filepath.Join(dir, "cert.pem")
+	key := filepath.Join(dir, "key.pem")
+	writeFile(t, cert, "cert-1")
+	writeFile(t, key, "key-1")
+
+	reloads := 0
+	w := New(0)
+	w.Watch("TLS certificate", func() error { reloads++; return nil }, cert, key)
+
+	w.Poll()
+	if reloads != 0 {
+		t.Fatalf("reloads = %d before any change, want 0", reloads)
+	}
+
+	writeFile(t, key, "key-2")
+	w.Poll()
+	w.Poll()
+	if reloads != 1 {
+		t.Errorf("reloads = %d after one change, want 1", reloads)
+	}
+}
+
+func TestWatcherRetriesFailedReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	writeFile(t, path, "old")
+
+	fail := true
+	reloads := 0
+	w := New(0)
+	w.Watch("Trino password", func() error {
+		reloads++
+		if fail {
+			return errors.New("half-rotated")
+		}
+		return nil
+	}, path)
+
+	writeFile(t, path, "new")
+	w.Poll()
+	fail = false
+	w.Poll()
+	w.Poll()
+	if reloads != 2 {
+		t.Errorf("reloads = %d, want a failed attempt, one retry, then nothing", reloads)
+	}
+}
+
+func TestWatcherIgnoresMissingFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	writeFile(t, path, "v1")
+
+	reloads := 0
+	w := New(0)
+	w.Watch("secret", func() error { reloads++; return nil }, path)
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	w.Poll()
+	if reloads != 0 {
+		t.Errorf("reloads = %d while the file is missing, want 0", reloads)
+	}
+
+	writeFile(t, path, "v1")
+	w.Poll()
+	if reloads != 0 {
+		t.Errorf("reloads = %d after the same content reappeared, want 0", reloads)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,281 @@
+// Package history keeps a persistent record of the queries each user ran
+// through the server, in an embedded bbolt database, so users can find and
+// re-run earlier queries across sessions and server restarts, and after the
+// cluster's own query history has rotated.
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/retention"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Entry states
+const (
+	StateSucceeded = "succeeded"
+	StateFailed    = "failed"
+)
+
+// List limits
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// MaxQueryBytes caps the query text kept per entry; longer queries are cut
+// and flagged
+const MaxQueryBytes = 64 << 10
+
+// maxErrorBytes caps the error message kept per entry
+const maxErrorBytes = 1 << 10
+
+// usersBucket holds one bucket of entries per user, keyed by entry ID
+var usersBucket = []byte("users")
+
+// ErrNotFound is returned when a user has no entry with the requested ID.
+var ErrNotFound = errors.New("no query history entry with that id")
+
+// Entry is one query a user ran.
+type Entry struct {
+	ID             uint64    `json:"id"`
+	Time           time.Time `json:"time"`
+	User           string    `json:"user"`
+	Query          string    `json:"query"`
+	QueryTruncated bool      `json:"query_truncated,omitempty"` // the query was longer than MaxQueryBytes
+	TrinoQueryIDs  []string  `json:"trino_query_ids,omitempty"`
+	State          string    `json:"state"`
+	Error          string    `json:"error,omitempty"`
+	DurationMs     int64     `json:"duration_ms"`
+	Rows           int       `json:"rows"`
+	Truncated      bool      `json:"truncated,omitempty"`  // the result was cut at the row limit
+	ResultRef      string    `json:"result_ref,omitempty"` // query_id of the stored result, for get_query_results while it is kept
+}
+
+// Filter selects entries to list. Zero values select everything.
+type Filter struct {
+	Search string    // case-insensitive substring of the query
+	State  string    // StateSucceeded or StateFailed
+	Since  time.Time // entries at or after this time
+	Limit  int       // newest entries to return (default DefaultLimit, at most MaxLimit)
+}
+
+// Store is a query history database.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the history database, creating it if needed. Only one process
+// can hold it open; Open fails rather than waiting when another does.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second}) // #nosec G304 -- operator-configured history file
+	if err != nil {
+		return nil, fmt.Errorf("failed to open query history %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(usersBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize query history: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Add records an entry under its user and returns it with its ID assigned.
+// IDs increase per user, so later entries have higher IDs.
+func (s *Store) Add(entry Entry) (Entry, error) {
+	if entry.User == "" {
+		return entry, fmt.Errorf("query history entry has no user")
+	}
+	if len(entry.Query) > MaxQueryBytes {
+		entry.Query, entry.QueryTruncated = truncate(entry.Query, MaxQueryBytes), true
+	}
+	entry.Error = truncate(entry.Error, maxErrorBytes)
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		user, err := tx.Bucket(usersBucket).CreateBucketIfNotExists([]byte(entry.User))
+		if err != nil {
+			return err
+		}
+		if entry.ID, err = user.NextSequence(); err != nil {
+			return err
+		}
+		value, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		return user.Put(entryKey(entry.ID), value)
+	})
+	if err != nil {
+		return entry, fmt.Errorf("failed to record query history: %w", err)
+	}
+	return entry, nil
+}
+
+// List returns a user's entries matching the filter, newest first.
+func (s *Store) List(user string, filter Filter) ([]Entry, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+	search := strings.ToLower(filter.Search)
+
+	var entries []Entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(usersBucket).Bucket([]byte(user))
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.Last(); k != nil && len(entries) < limit; k, v = c.Prev() {
+			var entry Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return fmt.Errorf("entry %d: %w", binary.BigEndian.Uint64(k), err)
+			}
+			// Entries are in time order, so older ones cannot match either
+			if !filter.Since.IsZero() && entry.Time.Before(filter.Since) {
+				break
+			}
+			if filter.State != "" && entry.State != filter.State {
+				continue
+			}
+			if search != "" && !strings.Contains(strings.ToLower(entry.Query), search) {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query history: %w", err)
+	}
+	return entries, nil
+}
+
+// Get returns one of a user's entries.
+func (s *Store) Get(user string, id uint64) (Entry, error) {
+	var entry Entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(usersBucket).Bucket([]byte(user))
+		if bucket == nil {
+			return ErrNotFound
+		}
+		value := bucket.Get(entryKey(id))
+		if value == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(value, &entry)
+	})
+	if errors.Is(err, ErrNotFound) {
+		return entry, err
+	}
+	if err != nil {
+		return entry, fmt.Errorf("failed to read query history: %w", err)
+	}
+	return entry, nil
+}
+
+// Prune removes entries recorded before cutoff and, past maxPerUser, each
+// user's oldest entries. A zero cutoff or maxPerUser disables that limit. It
+// returns the entries removed and the bytes they held, which the database
+// reuses for new entries rather than returning to the file system.
+func (s *Store) Prune(cutoff time.Time, maxPerUser int) (int, int64, error) {
+	var removed int
+	var freed int64
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		// Emptied user buckets are kept, so their IDs are never reused
+		users := tx.Bucket(usersBucket)
+		return users.ForEachBucket(func(name []byte) error {
+			bucket := users.Bucket(name)
+			excess := 0
+			if maxPerUser > 0 {
+				excess = bucket.Stats().KeyN - maxPerUser
+			}
+			c := bucket.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.First() {
+				if excess <= 0 && !expired(v, cutoff) {
+					break
+				}
+				freed += int64(len(v))
+				if err := c.Delete(); err != nil {
+					return err
+				}
+				removed++
+				excess--
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prune query history: %w", err)
+	}
+	return removed, freed, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// expired reports whether an encoded entry was recorded before cutoff
+func expired(value []byte, cutoff time.Time) bool {
+	if cutoff.IsZero() {
+		return false
+	}
+	var entry struct {
+		Time time.Time `json:"time"`
+	}
+	if err := json.Unmarshal(value, &entry); err != nil {
+		return true // unreadable entries are pruned with expired ones
+	}
+	return entry.Time.Before(cutoff)
+}
+
+// entryKey encodes an entry ID so keys sort in ID order
+func entryKey(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 sequence
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := n
+	for cut > 0 && cut < len(s) && s[cut]&0xC0 == 0x80 {
+		cut--
+	}
+	return s[:cut]
+}
+
+// Retention is a retention target pruning a history store's old entries.
+type Retention struct {
+	Store      *Store
+	MaxAge     time.Duration // entries older than this are removed (0 = keep)
+	MaxPerUser int           // newest entries kept per user (0 = unlimited)
+}
+
+// Name returns the target's label.
+func (r *Retention) Name() string {
+	return "query history"
+}
+
+// Enforce removes entries past the age and per-user limits.
+func (r *Retention) Enforce(now time.Time) (retention.Report, error) {
+	var cutoff time.Time
+	if r.MaxAge > 0 {
+		cutoff = now.Add(-r.MaxAge)
+	}
+	removed, freed, err := r.Store.Prune(cutoff, r.MaxPerUser)
+	return retention.Report{Removed: removed, FreedBytes: freed, Unit: "entries"}, err
+}
@@ -0,0 +1,150 @@
+package history
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func add(t *testing.T, s *Store, entry Entry) Entry {
+	t.Helper()
+	entry, err := s.Add(entry)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	return entry
+}
+
+func queries(entries []Entry) string {
+	texts := make([]string, len(entries))
+	for i, entry := range entries {
+		texts[i] = entry.Query
+	}
+	return strings.Join(texts, "; ")
+}
+
+func TestStoreListAndGet(t *testing.T) {
+	s := openStore(t)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	add(t, s, Entry{User: "alice", Time: now.Add(-3 * time.Hour), Query: "SELECT 1", State: StateSucceeded})
+	add(t, s, Entry{User: "bob", Time: now.Add(-2 * time.Hour), Query: "SELECT * FROM orders", State: StateSucceeded})
+	add(t, s, Entry{User: "alice", Time: now.Add(-2 * time.Hour), Query: "SELECT * FROM Orders", State: StateFailed, Error: "table not found"})
+	last := add(t, s, Entry{User: "alice", Time: now.Add(-time.Hour), Query: "SELECT count(*) FROM orders", State: StateSucceeded, Rows: 1})
+
+	if last.ID != 3 {
+		t.Errorf("ID = %d, want IDs counted per user", last.ID)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{"all, newest first", Filter{}, "SELECT count(*) FROM orders; SELECT * FROM Orders; SELECT 1"},
+		{"search ignores case", Filter{Search: "from ORDERS"}, "SELECT count(*) FROM orders; SELECT * FROM Orders"},
+		{"state", Filter{State: StateFailed}, "SELECT * FROM Orders"},
+		{"since", Filter{Since: now.Add(-2 * time.Hour)}, "SELECT count(*) FROM orders; SELECT * FROM Orders"},
+		{"limit", Filter{Limit: 1}, "SELECT count(*) FROM orders"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := s.List("alice", tt.filter)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if got := queries(entries); got != tt.want {
+				t.Errorf("List() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	entry, err := s.Get("alice", last.ID)
+	if err != nil || entry.Query != last.Query || entry.Rows != 1 {
+		t.Errorf("Get() = %+v, %v", entry, err)
+	}
+	if _, err := s.Get("bob", last.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of another user's entry error = %v, want ErrNotFound", err)
+	}
+	if entries, err := s.List("carol", Filter{}); err != nil || len(entries) != 0 {
+		t.Errorf("List() for a user without history = %v, %v", entries, err)
+	}
+}
+
+func TestStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	add(t, s, Entry{User: "alice", Time: time.Now(), Query: "SELECT 1", State: StateSucceeded})
+	if _, err := Open(path); err == nil {
+		t.Error("Open() of a database another store holds succeeded")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() after Close() error = %v", err)
+	}
+	defer func() { _ = s.Close() }()
+	entries, err := s.List("alice", Filter{})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("List() after reopening = %v, %v", entries, err)
+	}
+	if entry := add(t, s, Entry{User: "alice", Time: time.Now(), Query: "SELECT 2"}); entry.ID != 2 {
+		t.Errorf("ID after reopening = %d, want 2", entry.ID)
+	}
+}
+
+func TestStoreAddTruncates(t *testing.T) {
+	s := openStore(t)
+	if _, err := s.Add(Entry{Query: "SELECT 1"}); err == nil {
+		t.Error("Add() without a user succeeded")
+	}
+	entry := add(t, s, Entry{User: "alice", Query: "SELECT '" + strings.Repeat("é", MaxQueryBytes) + "'"})
+	if !entry.QueryTruncated || len(entry.Query) > MaxQueryBytes || !strings.HasSuffix(entry.Query, "é") {
+		t.Errorf("long query kept %d bytes (truncated: %v), want at most %d ending on a whole character", len(entry.Query), entry.QueryTruncated, MaxQueryBytes)
+	}
+}
+
+func TestRetentionEnforce(t *testing.T) {
+	s := openStore(t)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	for i := 5; i >= 1; i-- {
+		add(t, s, Entry{User: "alice", Time: now.Add(-time.Duration(i) * 24 * time.Hour), Query: "SELECT " + string(rune('0'+i))})
+	}
+	add(t, s, Entry{User: "bob", Time: now.Add(-10 * 24 * time.Hour), Query: "SELECT 10"})
+
+	target := &Retention{Store: s, MaxAge: 4*24*time.Hour + time.Minute, MaxPerUser: 3}
+	report, err := target.Enforce(now)
+	if err != nil {
+		t.Fatalf("Enforce() error = %v", err)
+	}
+	if report.Removed != 3 || report.FreedBytes == 0 || report.Unit != "entries" {
+		t.Errorf("report = %+v, want 3 entries removed", report)
+	}
+	entries, _ := s.List("alice", Filter{})
+	if got := queries(entries); got != "SELECT 1; SELECT 2; SELECT 3" {
+		t.Errorf("alice's history after retention = %q", got)
+	}
+	if entries, _ := s.List("bob", Filter{}); len(entries) != 0 {
+		t.Errorf("bob's expired history was kept: %v", entries)
+	}
+	if entry := add(t, s, Entry{User: "bob", Time: now, Query: "SELECT 11"}); entry.ID != 2 {
+		t.Errorf("ID after pruning = %d, want IDs not reused", entry.ID)
+	}
+}
@@ -0,0 +1,194 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/audit"
+	"github.com/tuannvm/mcp-trino/internal/secevents"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+// defaultReportDays is the range covered when no start date is given
+const defaultReportDays = 30
+
+// accessReport is the JSON form of an access report
+type accessReport struct {
+	From time.Time             `json:"from"`
+	To   time.Time             `json:"to"`
+	User string                `json:"user,omitempty"`
+	Rows []audit.AccessSummary `json:"rows"`
+}
+
+// parseReportRange parses a report's start and end, each a date
+// (YYYY-MM-DD) or an RFC 3339 timestamp. A date as the end includes that
+// whole day. The end defaults to now and the start to defaultReportDays
+// before the end.
+func parseReportRange(fromParam, toParam string, now time.Time) (time.Time, time.Time, error) {
+	parse := func(name, value string, endOfDay bool) (time.Time, error) {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s must be a date (YYYY-MM-DD) or RFC 3339 timestamp, got %q", name, value)
+		}
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+
+	to := now
+	if toParam != "" {
+		var err error
+		if to, err = parse("to", toParam, true); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	from := to.AddDate(0, 0, -defaultReportDays)
+	if fromParam != "" {
+		var err error
+		if from, err = parse("from", fromParam, false); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+	}
+	return from, to, nil
+}
+
+// buildAccessReport reads the audit log, including rotated files, and
+// summarizes table access over the range
+func buildAccessReport(auditLogFile string, from, to time.Time, user string) (*accessReport, error) {
+	if auditLogFile == "" {
+		return nil, fmt.Errorf("access reports need the audit log: set MCP_AUDIT_LOG")
+	}
+	records, err := audit.ReadAll(auditLogFile)
+	if err != nil {
+		return nil, err
+	}
+	return &accessReport{From: from.UTC(), To: to.UTC(), User: user, Rows: audit.AccessReport(records, from, to, user)}, nil
+}
+
+// canGenerateAccessReport reports whether the caller may see every user's
+// access. Without OAuth there are no identities to restrict, so the local
+// operator may; with OAuth the caller must be listed in MCP_REPORT_ADMINS.
+func (h *TrinoHandlers) canGenerateAccessReport(ctx context.Context) bool {
+	if !h.Config.OAuthEnabled {
+		return true
+	}
+	user := trino.UserIdentity(ctx)
+	for _, admin := range h.Config.ReportAdmins {
+		if user != "" && strings.EqualFold(admin, user) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateAccessReport handles per-user, per-table access summaries from the audit log
+func (h *TrinoHandlers) GenerateAccessReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if !h.canGenerateAccessReport(ctx) {
+		mcpErr := fmt.Errorf("access reports are restricted to the users in MCP_REPORT_ADMINS")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	fromParam, _ := args["from"].(string)
+	toParam, _ := args["to"].(string)
+	user, _ := args["user"].(string)
+	format, _ := args["format"].(string)
+
+	from, to, err := parseReportRange(fromParam, toParam, time.Now())
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(err.Error(), err), nil
+	}
+	report, err := buildAccessReport(h.Config.AuditLogFile, from, to, user)
+	if err != nil {
+		log.Printf("Error generating access report: %v", err)
+		mcpErr := fmt.Errorf("failed to generate access report: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	if format == "csv" {
+		var buf bytes.Buffer
+		if err := audit.WriteAccessCSV(&buf, report.Rows); err != nil {
+			mcpErr := fmt.Errorf("failed to write access report CSV: %w", err)
+			return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+		}
+		return mcp.NewToolResultText(buf.String()), nil
+	}
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal access report to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	return mcp.NewToolResultStructured(report, string(jsonData)), nil
+}
+
+// authorizeAdmin checks that an admin endpoint request is a GET carrying
+// MCP_ADMIN_TOKEN, and writes the error response when it is not
+func (s *Server) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AdminToken)) != 1 {
+		if s.security != nil {
+			s.security.Emit(secevents.Event{Time: time.Now(), Type: secevents.TypeAuthFailure, RemoteAddr: r.RemoteAddr, Detail: "invalid admin token for " + r.URL.Path})
+		}
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// handleAccessReport serves access reports to governance tooling at
+// /admin/access-report, authenticated with MCP_ADMIN_TOKEN. Query
+// parameters match the generate_access_report tool; CSV is the default.
+func (s *Server) handleAccessReport(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+
+	params := r.URL.Query()
+	from, to, err := parseReportRange(params.Get("from"), params.Get("to"), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	report, err := buildAccessReport(s.config.AuditLogFile, from, to, params.Get("user"))
+	if err != nil {
+		log.Printf("Error generating access report: %v", err)
+		http.Error(w, "failed to generate access report", http.StatusInternalServerError)
+		return
+	}
+
+	if params.Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(report)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="access-report-%s-%s.csv"`, from.UTC().Format("20060102"), to.UTC().Format("20060102")))
+	if err := audit.WriteAccessCSV(w, report.Rows); err != nil {
+		log.Printf("Error writing access report: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/audit"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+func writeAccessAudit(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := audit.Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range []audit.Record{
+		{Time: time.Date(2026, 9, 3, 10, 0, 0, 0, time.UTC), User: "alice", Tool: "execute_query", Status: audit.StatusOK, Tables: []string{"sales.orders"}},
+		{Time: time.Date(2026, 9, 4, 10, 0, 0, 0, time.UTC), User: "alice", Tool: "execute_query", Status: audit.StatusOK, Write: true, Tables: []string{"sales.orders"}},
+		{Time: time.Date(2026, 10, 4, 10, 0, 0, 0, time.UTC), User: "bob", Tool: "sample_table", Status: audit.StatusOK, Tables: []string{"sales.orders"}},
+	} {
+		if err := logger.Log(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseReportRange(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	from, to, err := parseReportRange("2026-09-01", "2026-09-30", now)
+	if err != nil || !from.Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("parseReportRange(dates) = %s, %s, %v", from, to, err)
+	}
+	from, to, err = parseReportRange("", "", now)
+	if err != nil || !to.Equal(now) || !from.Equal(now.AddDate(0, 0, -defaultReportDays)) {
+		t.Errorf("parseReportRange(defaults) = %s, %s, %v", from, to, err)
+	}
+	if _, _, err := parseReportRange("2026-09-01T08:00:00Z", "", now); err != nil {
+		t.Errorf("parseReportRange(RFC 3339) error = %v", err)
+	}
+	for _, bad := range [][2]string{{"yesterday", ""}, {"2026-10-01", "2026-09-01"}} {
+		if _, _, err := parseReportRange(bad[0], bad[1], now); err == nil {
+			t.Errorf("parseReportRange(%q, %q) should fail", bad[0], bad[1])
+		}
+	}
+}
+
+func TestGenerateAccessReport(t *testing.T) {
+	path := writeAccessAudit(t)
+	call := func(ctx context.Context, cfg *config.TrinoConfig, args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "generate_access_report"
+		req.Params.Arguments = args
+		result, err := newTestHandlers(cfg).GenerateAccessReport(ctx, req)
+		if err != nil {
+			t.Fatalf("GenerateAccessReport() error = %v", err)
+		}
+		return result
+	}
+
+	cfg := &config.TrinoConfig{AuditLogFile: path}
+	result := call(context.Background(), cfg, map[string]interface{}{"from": "2026-09-01", "to": "2026-09-30"})
+	if result.IsError {
+		t.Fatalf("GenerateAccessReport() = %s", resultText(result))
+	}
+	rows := structuredMap(t, result)["rows"].([]interface{})
+	if len(rows) != 1 || rows[0].(map[string]interface{})["writes"].(float64) != 1 {
+		t.Errorf("rows = %v", rows)
+	}
+
+	result = call(context.Background(), cfg, map[string]interface{}{"from": "2026-09-01", "to": "2026-10-31", "format": "csv"})
+	if lines := strings.Split(strings.TrimSpace(resultText(result)), "\n"); len(lines) != 3 || !strings.HasPrefix(lines[2], "bob,sales.orders,1,0,0,") {
+		t.Errorf("CSV report =\n%s", resultText(result))
+	}
+
+	// With OAuth, only report admins may call it
+	oauthCfg := &config.TrinoConfig{AuditLogFile: path, OAuthEnabled: true, ReportAdmins: []string{"Governance@example.com"}}
+	admin := oauth.WithUser(context.Background(), &oauth.User{Username: "governance@example.com"})
+	if result := call(admin, oauthCfg, nil); result.IsError {
+		t.Errorf("admin call rejected: %s", resultText(result))
+	}
+	other := oauth.WithUser(context.Background(), &oauth.User{Username: "alice"})
+	if result := call(other, oauthCfg, nil); !result.IsError || !strings.Contains(resultText(result), "MCP_REPORT_ADMINS") {
+		t.Errorf("non-admin call = %s", resultText(result))
+	}
+
+	if result := call(context.Background(), &config.TrinoConfig{}, nil); !result.IsError || !strings.Contains(resultText(result), "MCP_AUDIT_LOG") {
+		t.Errorf("call without audit log = %s", resultText(result))
+	}
+}
+
+func TestHandleAccessReport(t *testing.T) {
+	token := strings.Repeat("t", 32)
+	s := &Server{config: &config.TrinoConfig{AuditLogFile: writeAccessAudit(t), AdminToken: token}}
+
+	request := func(auth, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/access-report?"+query, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", "Bearer "+auth)
+		}
+		rec := httptest.NewRecorder()
+		s.handleAccessReport(rec, req)
+		return rec
+	}
+
+	if rec := request("", "from=2026-09-01"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("no token: status %d", rec.Code)
+	}
+	if rec := request("wrong", "from=2026-09-01"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: status %d", rec.Code)
+	}
+	if rec := request(token, "from=soon"); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad range: status %d", rec.Code)
+	}
+
+	rec := request(token, "from=2026-09-01&to=2026-09-30")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/csv" {
+		t.Fatalf("report: status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Header().Get("Content-Disposition"), "access-report-20260901-20261001.csv") || !strings.Contains(rec.Body.String(), "alice,sales.orders,2,1,0,") {
+		t.Errorf("report:\n%s", rec.Body.String())
+	}
+	if rec := request(token, "from=2026-09-01&format=json&user=bob"); !strings.Contains(rec.Body.String(), `"user":"bob"`) {
+		t.Errorf("JSON report: %s", rec.Body.String())
+	}
+}
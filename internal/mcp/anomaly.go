@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/anomaly"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/secevents"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+	"github.com/tuannvm/mcp-trino/internal/usage"
+)
+
+// anomalyMonitor feeds tool calls and query usage to the anomaly detector
+// and reports its alerts as security events
+type anomalyMonitor struct {
+	detector *anomaly.Detector
+	security secevents.Sink // syslog forwarding (nil if disabled)
+	webhook  secevents.Sink // anomaly webhook (nil if not configured)
+}
+
+// newAnomalyMonitor creates the anomaly detector, or returns nil when
+// detection is disabled or misconfigured
+func newAnomalyMonitor(cfg *config.TrinoConfig, security secevents.Sink) *anomalyMonitor {
+	if !cfg.AnomalyDetection {
+		return nil
+	}
+	opts := anomaly.Options{
+		TableThreshold:  cfg.AnomalyTables,
+		Window:          cfg.AnomalyWindow,
+		ScanSpikeFactor: float64(cfg.AnomalySpikeFactor),
+		ScanMinBytes:    int64(cfg.AnomalyScanMinMB) << 20,
+	}
+	if cfg.AnomalyHours != "" {
+		location, err := time.LoadLocation(cfg.AnomalyTimezone)
+		if err == nil {
+			opts.BusinessHours, err = anomaly.ParseBusinessHours(cfg.AnomalyHours, location)
+		}
+		if err != nil {
+			// Validated with the configuration, so this is not expected
+			log.Printf("ERROR: Failed to configure anomaly detection: %v", err)
+			return nil
+		}
+	}
+	monitor := &anomalyMonitor{detector: anomaly.New(opts), security: security}
+	if cfg.AnomalyWebhookURL != "" {
+		webhook, err := secevents.NewWebhook(cfg.AnomalyWebhookURL, cfg.AnomalyWebhookSecret)
+		if err != nil {
+			log.Printf("ERROR: Failed to configure the anomaly webhook, anomalies will not be posted: %v", err)
+		} else {
+			monitor.webhook = webhook
+		}
+	}
+	return monitor
+}
+
+// middleware observes every tool call with its caller and the tables it
+// touched
+func (m *anomalyMonitor) middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			call := anomaly.Call{Time: time.Now(), User: trino.UserIdentity(ctx), Tool: request.Params.Name}
+			args := request.GetArguments()
+			if query, _ := args["query"].(string); query != "" {
+				call.Tables, _ = trino.QueryAccess(query)
+			} else if table := argumentTable(args); table != "" {
+				call.Tables = []string{table}
+			}
+			m.report(m.detector.ObserveCall(call))
+			return next(ctx, request)
+		}
+	}
+}
+
+// observeUsage checks a query's bytes scanned against its caller's baseline
+func (m *anomalyMonitor) observeUsage(record usage.Record) {
+	m.report(m.detector.ObserveScan(record.User, record.QueryID, record.ScannedBytes, record.Time))
+}
+
+// report logs alerts and forwards them as security events
+func (m *anomalyMonitor) report(alerts []anomaly.Alert) {
+	for _, alert := range alerts {
+		log.Printf("WARNING: Anomaly %s for %s: %s", alert.Kind, alert.User, alert.Detail)
+		event := secevents.Event{
+			Time:   alert.Time,
+			Type:   secevents.TypeAnomaly,
+			Kind:   alert.Kind,
+			User:   alert.User,
+			Tool:   alert.Tool,
+			Detail: alert.Detail,
+		}
+		if m.security != nil {
+			m.security.Emit(event)
+		}
+		if m.webhook != nil {
+			m.webhook.Emit(event)
+		}
+	}
+}
+
+// Close stops the webhook sender after posting queued alerts. The security
+// sink is shared, and closed by the server.
+func (m *anomalyMonitor) Close() error {
+	if m.webhook != nil {
+		return m.webhook.Close()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/anomaly"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/secevents"
+	"github.com/tuannvm/mcp-trino/internal/usage"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+func TestNewAnomalyMonitor(t *testing.T) {
+	if monitor := newAnomalyMonitor(&config.TrinoConfig{}, nil); monitor != nil {
+		t.Error("newAnomalyMonitor() should be nil when detection is disabled")
+	}
+	cfg := &config.TrinoConfig{AnomalyDetection: true, AnomalyHours: "mon-fri 08:00-18:00", AnomalyTimezone: "UTC"}
+	monitor := newAnomalyMonitor(cfg, nil)
+	if monitor == nil || monitor.webhook != nil {
+		t.Fatalf("newAnomalyMonitor() = %+v, want a monitor without a webhook", monitor)
+	}
+	_ = monitor.Close()
+}
+
+func TestAnomalyMonitorMiddleware(t *testing.T) {
+	sink := &recordingSink{}
+	monitor := &anomalyMonitor{detector: anomaly.New(anomaly.Options{TableThreshold: 3, Window: time.Hour}), security: sink}
+	ctx := oauth.WithUser(context.Background(), &oauth.User{Username: "mallory"})
+
+	handler := monitor.middleware()(textHandler("ok"))
+	for i, args := range []map[string]interface{}{
+		{"catalog": "hive", "schema": "hr", "table": "salaries"},
+		{"query": "SELECT * FROM hive.hr.reviews"},
+		{"catalog": "hive", "schema": "hr", "table": "salaries"}, // repeated
+		{"query": "SELECT * FROM hive.hr.payroll JOIN hive.hr.bonuses USING (id)"},
+	} {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = fmt.Sprintf("tool_%d", i)
+		req.Params.Arguments = args
+		if _, err := handler(ctx, req); err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+	}
+
+	if len(sink.events) != 1 {
+		t.Fatalf("events = %+v, want one enumeration alert", sink.events)
+	}
+	event := sink.events[0]
+	if event.Type != secevents.TypeAnomaly || event.Kind != anomaly.KindTableEnumeration || event.User != "mallory" || event.Tool != "tool_3" {
+		t.Errorf("event = %+v", event)
+	}
+	if !strings.Contains(event.Detail, "4 distinct tables") {
+		t.Errorf("Detail = %q", event.Detail)
+	}
+}
+
+func TestAnomalyMonitorObserveUsage(t *testing.T) {
+	sink := &recordingSink{}
+	monitor := &anomalyMonitor{detector: anomaly.New(anomaly.Options{ScanSpikeFactor: 5, ScanMinBytes: 1 << 20}), security: sink}
+	now := time.Now()
+	for i := 0; i < 20; i++ {
+		monitor.observeUsage(usage.Record{Time: now, User: "agent", QueryID: "q", ScannedBytes: 1 << 20})
+	}
+	monitor.observeUsage(usage.Record{Time: now, User: "agent", QueryID: "runaway", ScannedBytes: 1 << 30})
+
+	if len(sink.events) != 1 || sink.events[0].Kind != anomaly.KindScanSpike || !strings.Contains(sink.events[0].Detail, "runaway") {
+		t.Errorf("events = %+v, want one scan spike", sink.events)
+	}
+}
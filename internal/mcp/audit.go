@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/audit"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/redact"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+// maxAuditErrorBytes caps the error text kept in an audit record
+const maxAuditErrorBytes = 500
+
+// newAuditLogger opens the configured audit log, or returns nil when
+// auditing is disabled or the file cannot be opened
+func newAuditLogger(cfg *config.TrinoConfig) *audit.Logger {
+	if cfg.AuditLogFile == "" {
+		return nil
+	}
+	logger, err := audit.Open(cfg.AuditLogFile, []byte(cfg.AuditSigningKey))
+	if err != nil {
+		log.Printf("ERROR: Failed to open audit log %s, tool calls will not be audited: %v", cfg.AuditLogFile, err)
+		return nil
+	}
+	return logger
+}
+
+// newRedactor creates the redactor for the configured log redaction
+// columns, or returns nil when none are configured
+func newRedactor(cfg *config.TrinoConfig) *redact.Redactor {
+	redactor, err := redact.New(cfg.RedactColumns, cfg.RedactColumnPattern)
+	if err != nil {
+		// The pattern is validated with the configuration, so this is not expected
+		log.Printf("ERROR: Failed to configure log redaction: %v", err)
+		return nil
+	}
+	return redactor
+}
+
+// auditMiddleware records every tool call with its caller, outcome, and
+// duration. Calls with a query argument also record the query fingerprint.
+// Values of redacted columns are scrubbed from the query and error text.
+func auditMiddleware(logger *audit.Logger, redactor *redact.Redactor) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, request)
+
+			record := audit.Record{
+				Time:       start.UTC(),
+				User:       trino.UserIdentity(ctx),
+				Tool:       request.Params.Name,
+				Status:     audit.StatusOK,
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			args := request.GetArguments()
+			query, _ := args["query"].(string)
+			if query != "" {
+				record.SetQuery(query)
+				record.Query = redactor.Query(query)
+				record.Tables, record.Write = trino.QueryAccess(query)
+			} else if table := argumentTable(args); table != "" {
+				record.Tables = []string{table}
+			}
+			switch {
+			case err != nil:
+				record.Status, record.Error = audit.StatusError, truncate(redactor.Message(err.Error(), redactor.References(query)), maxAuditErrorBytes)
+			case result != nil && result.IsError:
+				record.Status, record.Error = audit.StatusError, truncate(redactor.Message(resultText(result), redactor.References(query)), maxAuditErrorBytes)
+			}
+			if logErr := logger.Log(record); logErr != nil {
+				log.Printf("ERROR: Failed to audit %s call: %v", request.Params.Name, logErr)
+			}
+			return result, err
+		}
+	}
+}
+
+// argumentTable returns the table named by a tool's catalog, schema, and
+// table arguments, such as sample_table's, qualified as far as given
+func argumentTable(args map[string]interface{}) string {
+	table, _ := args["table"].(string)
+	if table == "" {
+		return ""
+	}
+	parts := []string{table}
+	if schema, _ := args["schema"].(string); schema != "" {
+		parts = append([]string{schema}, parts...)
+		if catalog, _ := args["catalog"].(string); catalog != "" {
+			parts = append([]string{catalog}, parts...)
+		}
+	}
+	return strings.ToLower(strings.Join(parts, "."))
+}
+
+// truncate shortens s to at most n bytes
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/audit"
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestAuditMiddleware(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger := newAuditLogger(&config.TrinoConfig{AuditLogFile: path})
+	if logger == nil {
+		t.Fatal("newAuditLogger() = nil")
+	}
+
+	call := func(name string, args map[string]interface{}, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = args
+		if _, err := auditMiddleware(logger, nil)(handler)(context.Background(), req); err != nil && name != "broken" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	call("execute_query", map[string]interface{}{"query": "SELECT * FROM orders WHERE id = 7"}, textHandler("[]"))
+	call("sample_table", map[string]interface{}{"catalog": "hive", "schema": "Sales", "table": "orders"}, textHandler("[]"))
+	call("execute_query", map[string]interface{}{"query": "DROP TABLE orders"}, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("security restriction"), nil
+	})
+	call("broken", nil, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("boom")
+	})
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := audit.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("recorded %d calls, want 4", len(records))
+	}
+	if sampled := records[1]; len(sampled.Tables) != 1 || sampled.Tables[0] != "hive.sales.orders" || sampled.Fingerprint != "" {
+		t.Errorf("sample_table record = %+v", sampled)
+	}
+	records = append(records[:1], records[2:]...)
+	if records[0].Status != audit.StatusOK || records[0].NormalizedQuery != "select * from orders where id=?" || records[0].Fingerprint == "" {
+		t.Errorf("query record = %+v", records[0])
+	}
+	if records[0].Tables[0] != "orders" || records[0].Write {
+		t.Errorf("query record access = %v, write %t", records[0].Tables, records[0].Write)
+	}
+	if records[1].Status != audit.StatusError || records[1].Error != "security restriction" || !records[1].Write {
+		t.Errorf("rejected record = %+v", records[1])
+	}
+	if records[2].Status != audit.StatusError || records[2].Error != "boom" || records[2].Fingerprint != "" {
+		t.Errorf("failed record = %+v", records[2])
+	}
+}
+
+func TestAuditMiddlewareRedaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	cfg := &config.TrinoConfig{AuditLogFile: path, RedactColumns: []string{"ssn"}}
+	logger := newAuditLogger(cfg)
+	if logger == nil {
+		t.Fatal("newAuditLogger() = nil")
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "execute_query"
+	req.Params.Arguments = map[string]interface{}{"query": "SELECT CAST(ssn AS INTEGER) FROM hr.employees WHERE ssn = '123-45-6789'"}
+	_, _ = auditMiddleware(logger, newRedactor(cfg))(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("query execution failed: Cannot cast '987-65-4321' to INT"), nil
+	})(context.Background(), req)
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := audit.ReadFile(path)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("ReadFile() = %d records, %v", len(records), err)
+	}
+	record := records[0]
+	if record.Query != "SELECT CAST(ssn AS INTEGER) FROM hr.employees WHERE ssn = '[REDACTED]'" {
+		t.Errorf("Query = %q", record.Query)
+	}
+	if record.Error != "query execution failed: Cannot cast '[REDACTED]' to INT" {
+		t.Errorf("Error = %q", record.Error)
+	}
+	// Redaction does not change the normalized query or its fingerprint
+	if record.Fingerprint != audit.Fingerprint(audit.Normalize(req.GetArguments()["query"].(string))) {
+		t.Errorf("Fingerprint = %q", record.Fingerprint)
+	}
+}
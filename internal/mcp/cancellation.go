@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// methodNotificationCancelled is sent by clients to cancel an in-flight request
+const methodNotificationCancelled = "notifications/cancelled"
+
+// requestIDMetaKey carries the JSON-RPC request ID from the BeforeCallTool
+// hook to the tool middleware, which mcp-go does not otherwise expose it to
+const requestIDMetaKey = "io.github.tuannvm.mcp-trino/request-id"
+
+// cancellationRegistry tracks in-flight tool calls so that a client's
+// notifications/cancelled can cancel the matching call's context, which in
+// turn kills the Trino query it is running. HTTP disconnects already cancel
+// the request context; this covers explicit cancellation on any transport.
+type cancellationRegistry struct {
+	mu       sync.Mutex
+	inflight map[string]context.CancelFunc
+}
+
+func newCancellationRegistry() *cancellationRegistry {
+	return &cancellationRegistry{inflight: make(map[string]context.CancelFunc)}
+}
+
+// hooks returns server hooks that tag each tool call with its request ID
+func (r *cancellationRegistry) hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest) {
+		key, ok := requestKey(ctx, id)
+		if !ok {
+			return
+		}
+		if message.Params.Meta == nil {
+			message.Params.Meta = &mcp.Meta{}
+		}
+		if message.Params.Meta.AdditionalFields == nil {
+			message.Params.Meta.AdditionalFields = make(map[string]any)
+		}
+		message.Params.Meta.AdditionalFields[requestIDMetaKey] = key
+	})
+	return hooks
+}
+
+// middleware gives each tagged tool call a cancellable context for its duration
+func (r *cancellationRegistry) middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if request.Params.Meta == nil {
+				return next(ctx, request)
+			}
+			key, ok := request.Params.Meta.AdditionalFields[requestIDMetaKey].(string)
+			if !ok {
+				return next(ctx, request)
+			}
+
+			ctx, cancel := context.WithCancel(ctx)
+			r.mu.Lock()
+			r.inflight[key] = cancel
+			r.mu.Unlock()
+			defer func() {
+				r.mu.Lock()
+				delete(r.inflight, key)
+				r.mu.Unlock()
+				cancel()
+			}()
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// handleCancelled cancels the in-flight call named by a notifications/cancelled
+func (r *cancellationRegistry) handleCancelled(ctx context.Context, notification mcp.JSONRPCNotification) {
+	requestID, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+	key, ok := requestKey(ctx, requestID)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	cancel, found := r.inflight[key]
+	r.mu.Unlock()
+	if !found {
+		return // already finished; cancellation may race completion
+	}
+	reason, _ := notification.Params.AdditionalFields["reason"].(string)
+	log.Printf("INFO: Client cancelled tool call %s: %s", key, reason)
+	cancel()
+}
+
+// requestKey scopes a JSON-RPC request ID to its session. IDs are normalized
+// through JSON so numeric IDs match whether parsed as int64 or float64.
+func requestKey(ctx context.Context, id any) (string, bool) {
+	if id == nil {
+		return "", false
+	}
+	data, err := json.Marshal(id)
+	if err != nil || string(data) == "null" {
+		return "", false
+	}
+	sessionID := ""
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		sessionID = session.SessionID()
+	}
+	return sessionID + "|" + string(data), true
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// TestCancelledNotificationCancelsToolCall verifies that notifications/cancelled
+// cancels the context of the matching in-flight tool call.
+func TestCancelledNotificationCancelsToolCall(t *testing.T) {
+	registry := newCancellationRegistry()
+	srv := mcpserver.NewMCPServer("test-server", "0.0.1",
+		mcpserver.WithToolCapabilities(true),
+		mcpserver.WithHooks(registry.hooks()),
+		mcpserver.WithToolHandlerMiddleware(registry.middleware()),
+	)
+	srv.AddNotificationHandler(methodNotificationCancelled, registry.handleCancelled)
+
+	started := make(chan struct{})
+	srv.AddTool(mcp.NewTool("block"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return mcp.NewToolResultText("cancelled"), nil
+		case <-time.After(5 * time.Second):
+			return mcp.NewToolResultText("finished"), nil
+		}
+	})
+
+	done := make(chan mcp.JSONRPCMessage, 1)
+	go func() {
+		done <- srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"block","arguments":{}}}`))
+	}()
+
+	<-started
+	srv.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user aborted"}}`))
+
+	select {
+	case msg := <-done:
+		data, _ := json.Marshal(msg)
+		var resp struct {
+			Result mcp.CallToolResult `json:"result"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		assertContentContains(t, &resp.Result, "cancelled")
+	case <-time.After(2 * time.Second):
+		t.Fatal("tool call was not cancelled")
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if len(registry.inflight) != 0 {
+		t.Errorf("expected no in-flight calls after completion, got %d", len(registry.inflight))
+	}
+}
+
+func TestRequestKeyNormalizesNumericIDs(t *testing.T) {
+	fromRequest, ok := requestKey(context.Background(), mcp.NewRequestId(int64(3)))
+	if !ok {
+		t.Fatal("expected key for request ID")
+	}
+	fromNotification, _ := requestKey(context.Background(), float64(3))
+	if fromRequest != fromNotification {
+		t.Errorf("request key %q != notification key %q", fromRequest, fromNotification)
+	}
+	if _, ok := requestKey(context.Background(), nil); ok {
+		t.Error("expected no key for nil ID")
+	}
+}
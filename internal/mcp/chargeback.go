@@ -0,0 +1,247 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/tenancy"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+	"github.com/tuannvm/mcp-trino/internal/usage"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+// usageQueueSize bounds the tool calls waiting for their query stats; calls
+// beyond it are not accounted rather than delaying the caller
+const usageQueueSize = 256
+
+// usageStatsTimeout bounds looking up the stats of one tool call's queries
+const usageStatsTimeout = 30 * time.Second
+
+// usageCall is a finished tool call whose queries await accounting
+type usageCall struct {
+	ctx     context.Context // the call's context, detached from its cancellation
+	time    time.Time
+	user    string
+	team    string
+	tool    string
+	queries *trino.QueryLog
+}
+
+// usageRecorder records the Trino resources each tool call's queries used.
+// Query stats are final only after a query completes, so they are looked up
+// by a background goroutine once the call has returned.
+type usageRecorder struct {
+	store     *usage.Store
+	teamClaim string             // token claim naming the caller's team ("" = no teams)
+	onRecord  func(usage.Record) // observes each record written (nil = none)
+
+	mu      sync.Mutex
+	closed  bool
+	dropped int
+	queue   chan usageCall
+	wg      sync.WaitGroup
+}
+
+// newUsageRecorder opens the usage log and starts the recorder, or returns
+// nil when accounting is disabled or the log cannot be opened
+func newUsageRecorder(cfg *config.TrinoConfig) *usageRecorder {
+	if cfg.UsageLogFile == "" {
+		return nil
+	}
+	store, err := usage.Open(cfg.UsageLogFile)
+	if err != nil {
+		log.Printf("ERROR: Failed to open usage log, usage accounting is disabled: %v", err)
+		return nil
+	}
+	r := &usageRecorder{store: store, queue: make(chan usageCall, usageQueueSize)}
+	if cfg.OAuthEnabled {
+		r.teamClaim = cfg.UsageTeamClaim
+	}
+	r.wg.Add(1)
+	go r.run()
+	return r
+}
+
+// middleware logs the queries each tool call runs and queues them for
+// accounting once the call returns
+func (r *usageRecorder) middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, queries := trino.WithQueryLog(ctx)
+			result, err := next(ctx, request)
+			if queries.Len() > 0 {
+				r.enqueue(usageCall{
+					ctx:     context.WithoutCancel(ctx),
+					time:    time.Now().UTC(),
+					user:    trino.UserIdentity(ctx),
+					team:    r.team(ctx),
+					tool:    request.Params.Name,
+					queries: queries,
+				})
+			}
+			return result, err
+		}
+	}
+}
+
+// team reads the caller's team from their access token, or returns "" when
+// no team claim is configured or the token has none
+func (r *usageRecorder) team(ctx context.Context) string {
+	if r.teamClaim == "" {
+		return ""
+	}
+	token, ok := oauth.GetOAuthToken(ctx)
+	if !ok {
+		return ""
+	}
+	team, err := tenancy.ClaimFromToken(token, r.teamClaim)
+	if err != nil {
+		return ""
+	}
+	return team
+}
+
+// enqueue queues a call for accounting, dropping it if the queue is full
+func (r *usageRecorder) enqueue(call usageCall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.queue <- call:
+	default:
+		r.dropped++
+	}
+}
+
+// run records queued calls until the queue is closed
+func (r *usageRecorder) run() {
+	defer r.wg.Done()
+	for call := range r.queue {
+		r.reportDropped()
+		r.record(call)
+	}
+}
+
+// record looks up the stats of a call's queries and writes one usage record
+// per query
+func (r *usageRecorder) record(call usageCall) {
+	ctx, cancel := context.WithTimeout(call.ctx, usageStatsTimeout)
+	defer cancel()
+	stats, err := call.queries.Stats(ctx)
+	if err != nil {
+		log.Printf("WARNING: Usage of some queries by %s could not be recorded: %v", call.user, err)
+	}
+	for _, s := range stats {
+		record := usage.Record{
+			Time:            call.time,
+			User:            call.user,
+			Team:            call.team,
+			Tool:            call.tool,
+			QueryID:         s.QueryID,
+			State:           s.State,
+			CPUMs:           s.CPUTime.Milliseconds(),
+			ElapsedMs:       s.Elapsed.Milliseconds(),
+			ScannedBytes:    s.ScannedBytes,
+			ProcessedBytes:  s.ProcessedBytes,
+			PeakMemoryBytes: s.PeakMemoryBytes,
+		}
+		if err := r.store.Log(record); err != nil {
+			log.Printf("ERROR: Failed to write usage record: %v", err)
+		}
+		if r.onRecord != nil {
+			r.onRecord(record)
+		}
+	}
+}
+
+// reportDropped logs calls dropped while the queue was full
+func (r *usageRecorder) reportDropped() {
+	r.mu.Lock()
+	dropped := r.dropped
+	r.dropped = 0
+	r.mu.Unlock()
+	if dropped > 0 {
+		log.Printf("WARNING: %d tool calls were not accounted while the usage queue was full", dropped)
+	}
+}
+
+// Close records the calls already queued, then closes the usage log.
+func (r *usageRecorder) Close() error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+	r.wg.Wait()
+	return r.store.Close()
+}
+
+// chargebackReport is the JSON form of a chargeback report
+type chargebackReport struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	GroupBy string         `json:"group_by"`
+	Rows    []usage.Charge `json:"rows"`
+}
+
+// handleChargeback serves usage per team or user at /admin/chargeback,
+// authenticated with MCP_ADMIN_TOKEN. The range parameters match the access
+// report; group_by is team (the default when a team claim is configured) or
+// user, and format is csv (the default) or json.
+func (s *Server) handleChargeback(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+
+	params := r.URL.Query()
+	from, to, err := parseReportRange(params.Get("from"), params.Get("to"), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	groupBy := params.Get("group_by")
+	if groupBy == "" {
+		groupBy = usage.GroupByUser
+		if s.config.UsageTeamClaim != "" {
+			groupBy = usage.GroupByTeam
+		}
+	}
+	if groupBy != usage.GroupByTeam && groupBy != usage.GroupByUser {
+		http.Error(w, fmt.Sprintf("group_by must be %s or %s", usage.GroupByTeam, usage.GroupByUser), http.StatusBadRequest)
+		return
+	}
+
+	records, err := usage.ReadAll(s.config.UsageLogFile)
+	var rows []usage.Charge
+	if err == nil {
+		rates := usage.Rates{PerTBScanned: s.config.CostPerTBScanned, PerCPUHour: s.config.CostPerCPUHour}
+		rows, err = usage.Chargeback(records, from, to, groupBy, rates)
+	}
+	if err != nil {
+		log.Printf("Error generating chargeback report: %v", err)
+		http.Error(w, "failed to generate chargeback report", http.StatusInternalServerError)
+		return
+	}
+
+	if params.Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(chargebackReport{From: from.UTC(), To: to.UTC(), GroupBy: groupBy, Rows: rows})
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="chargeback-%s-%s.csv"`, from.UTC().Format("20060102"), to.UTC().Format("20060102")))
+	if err := usage.WriteChargebackCSV(w, groupBy, rows); err != nil {
+		log.Printf("Error writing chargeback report: %v", err)
+	}
+}
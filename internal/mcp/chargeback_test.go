@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+	"github.com/tuannvm/mcp-trino/internal/usage"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+// newUsageTestClient returns a Trino client whose fake coordinator answers
+// every statement with one row and reports fixed stats for it
+func newUsageTestClient(t *testing.T) *trino.Client {
+	t.Helper()
+	const queryID = "20261017_000000_00001_abcde"
+	coordinator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/statement":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      queryID,
+				"infoUri": "http://" + r.Host + "/ui/query.html?" + queryID,
+				"columns": []map[string]any{{"name": "n", "type": "integer", "typeSignature": map[string]any{"rawType": "integer", "arguments": []any{}}}},
+				"data":    [][]any{{1}},
+				"stats":   map[string]any{"state": "FINISHED"},
+			})
+		case r.URL.Path == "/v1/query/"+queryID:
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"queryId": queryID,
+				"state":   "FINISHED",
+				"queryStats": map[string]string{
+					"totalCpuTime":              "2.50s",
+					"elapsedTime":               "3.00s",
+					"physicalInputDataSize":     "2.00GB",
+					"processedInputDataSize":    "1.00GB",
+					"peakUserMemoryReservation": "8.00MB",
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(coordinator.Close)
+
+	u, _ := url.Parse(coordinator.URL)
+	port, _ := strconv.Atoi(u.Port())
+	client, err := trino.NewClient(&config.TrinoConfig{
+		Host: u.Hostname(), Port: port, Scheme: "http", User: "svc",
+		Catalog: "memory", Schema: "default", QueryTimeout: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("trino.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestUsageRecorderMiddleware(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	recorder := newUsageRecorder(&config.TrinoConfig{UsageLogFile: path})
+	if recorder == nil {
+		t.Fatal("newUsageRecorder() = nil")
+	}
+	client := newUsageTestClient(t)
+
+	handler := recorder.middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := client.ExecuteQueryWithContext(ctx, "SELECT 1"); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+	listing := recorder.middleware()(textHandler("no queries"))
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "execute_query"
+	ctx := oauth.WithUser(context.Background(), &oauth.User{Username: "alice"})
+	if _, err := handler(ctx, req); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	req.Params.Name = "list_catalogs"
+	if _, err := listing(ctx, req); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	records, err := usage.ReadAll(path)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("records = %+v, want only the call that ran a query", records)
+	}
+	record := records[0]
+	if record.User != "alice" || record.Tool != "execute_query" || record.QueryID != "20261017_000000_00001_abcde" || record.State != "FINISHED" {
+		t.Errorf("record = %+v", record)
+	}
+	if record.CPUMs != 2500 || record.ElapsedMs != 3000 || record.ScannedBytes != 2<<30 || record.ProcessedBytes != 1<<30 || record.PeakMemoryBytes != 8<<20 {
+		t.Errorf("record usage = %+v", record)
+	}
+}
+
+func TestHandleChargeback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	store, err := usage.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range []usage.Record{
+		{Time: time.Date(2026, 9, 3, 10, 0, 0, 0, time.UTC), User: "alice", Team: "growth", QueryID: "q1", State: "FINISHED", CPUMs: 7_200_000, ScannedBytes: 1 << 40},
+		{Time: time.Date(2026, 9, 4, 10, 0, 0, 0, time.UTC), User: "bob", Team: "growth", QueryID: "q2", State: "FINISHED", CPUMs: 3_600_000},
+		{Time: time.Date(2026, 9, 5, 10, 0, 0, 0, time.UTC), User: "carol", Team: "finance", QueryID: "q3", State: "FAILED"},
+	} {
+		if err := store.Log(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	token := strings.Repeat("t", 32)
+	s := &Server{config: &config.TrinoConfig{UsageLogFile: path, UsageTeamClaim: "team", AdminToken: token, CostPerTBScanned: 5, CostPerCPUHour: 0.5}}
+	request := func(auth, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/chargeback?"+query, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", "Bearer "+auth)
+		}
+		rec := httptest.NewRecorder()
+		s.handleChargeback(rec, req)
+		return rec
+	}
+
+	if rec := request("wrong", "from=2026-09-01"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: status %d", rec.Code)
+	}
+	if rec := request(token, "from=2026-09-01&group_by=tenant"); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad grouping: status %d", rec.Code)
+	}
+
+	rec := request(token, "from=2026-09-01&to=2026-09-30")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/csv" {
+		t.Fatalf("report: status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	// 1 TB at 5 plus 3 CPU hours at 0.5
+	if body := rec.Body.String(); !strings.HasPrefix(body, "team,") || !strings.Contains(body, "growth,2,2,0,10800.000,1099511627776,0,6.5000") {
+		t.Errorf("report:\n%s", body)
+	}
+
+	rec = request(token, "from=2026-09-01&group_by=user&format=json")
+	var report chargebackReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("JSON report: %v\n%s", err, rec.Body.String())
+	}
+	if report.GroupBy != "user" || len(report.Rows) != 3 || report.Rows[2].Group != "carol" || report.Rows[2].FailedQueries != 1 {
+		t.Errorf("JSON report = %+v", report)
+	}
+}
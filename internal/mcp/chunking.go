@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/resultstore"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+// fetchResultChunkTool is exempt from chunking; its responses are sized to fit
+const fetchResultChunkTool = "fetch_result_chunk"
+
+// chunkEnvelopeBytes is reserved in each chunk response for metadata
+const chunkEnvelopeBytes = 1024
+
+// responseChunkingMiddleware replaces tool responses larger than maxBytes with
+// a handle to the stored response, which clients page through with
+// fetch_result_chunk. Some MCP clients hard-fail on multi-MB payloads.
+func responseChunkingMiddleware(chunker *resultstore.Chunker, maxBytes int) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || result.IsError || request.Params.Name == fetchResultChunkTool {
+				return result, err
+			}
+
+			size := responseSize(result)
+			if size <= maxBytes {
+				return result, nil
+			}
+
+			meta, saveErr := chunker.Save(ctx, trino.UserIdentity(ctx), request.Params.Name, resultText(result))
+			if saveErr != nil {
+				log.Printf("ERROR: Failed to store oversized %s response (%d bytes): %v", request.Params.Name, size, saveErr)
+				mcpErr := fmt.Errorf("response of %d bytes exceeds the %d-byte limit and could not be stored; add a LIMIT to your query", size, maxBytes)
+				return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+			}
+			log.Printf("INFO: Stored oversized %s response (%d bytes) as %d chunks", request.Params.Name, size, meta.ChunkCount)
+
+			handle := map[string]interface{}{
+				"result_handle": meta.Handle,
+				"total_bytes":   meta.TotalBytes,
+				"chunk_count":   meta.ChunkCount,
+				"message": fmt.Sprintf("Response of %d bytes exceeds the %d-byte limit. Call fetch_result_chunk with result_handle %q and chunk 1 through %d, then concatenate the chunk text in order.",
+					size, maxBytes, meta.Handle, meta.ChunkCount),
+			}
+			jsonData, _ := json.MarshalIndent(handle, "", "  ")
+			return mcp.NewToolResultStructured(handle, string(jsonData)), nil
+		}
+	}
+}
+
+// responseSize approximates the wire size of a tool result
+func responseSize(result *mcp.CallToolResult) int {
+	size := 0
+	for _, content := range result.Content {
+		if tc, ok := content.(mcp.TextContent); ok {
+			size += len(tc.Text)
+		}
+	}
+	if result.StructuredContent != nil {
+		if data, err := json.Marshal(result.StructuredContent); err == nil {
+			size += len(data)
+		}
+	}
+	return size
+}
+
+// resultText returns the text a client would read from the result. Structured
+// content duplicates the text for our tools, so the text content is stored.
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if tc, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, tc.Text)
+		}
+	}
+	if len(parts) == 0 && result.StructuredContent != nil {
+		if data, err := json.Marshal(result.StructuredContent); err == nil {
+			return string(data)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
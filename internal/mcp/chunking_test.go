@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/resultstore"
+)
+
+func newTestChunker(t *testing.T, chunkSize int) *resultstore.Chunker {
+	t.Helper()
+	store, err := resultstore.NewBlobStore(context.Background(), resultstore.Options{Backend: resultstore.BackendMemory})
+	if err != nil {
+		t.Fatalf("failed to create result store: %v", err)
+	}
+	return resultstore.NewChunker(store, chunkSize, time.Hour)
+}
+
+func textHandler(text string) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(text), nil
+	}
+}
+
+func TestResponseChunkingMiddleware_SmallResponsePassesThrough(t *testing.T) {
+	chunker := newTestChunker(t, 100)
+	handler := responseChunkingMiddleware(chunker, 100)(textHandler("small"))
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertContentContains(t, result, "small")
+	if result.StructuredContent != nil {
+		t.Error("expected small response to be returned unchanged")
+	}
+}
+
+func TestResponseChunkingMiddleware_LargeResponseRoundTrip(t *testing.T) {
+	chunker := newTestChunker(t, 40)
+	original := strings.Repeat("0123456789", 10)
+	handler := responseChunkingMiddleware(chunker, 50)(textHandler(original))
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "execute_query"
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected handle result, got error: %+v", result.Content)
+	}
+	assertContentContains(t, result, "fetch_result_chunk")
+
+	sc := structuredMap(t, result)
+	handle, _ := sc["result_handle"].(string)
+	chunkCount := int(sc["chunk_count"].(float64))
+	if handle == "" || chunkCount != 3 {
+		t.Fatalf("unexpected handle metadata: %v", sc)
+	}
+
+	handlers := newTestHandlers(&config.TrinoConfig{})
+	handlers.ResultChunker = chunker
+
+	var sb strings.Builder
+	for i := 1; i <= chunkCount; i++ {
+		fetch := mcp.CallToolRequest{}
+		fetch.Params.Name = fetchResultChunkTool
+		fetch.Params.Arguments = map[string]interface{}{"result_handle": handle, "chunk": float64(i)}
+		chunk, err := handlers.FetchResultChunk(context.Background(), fetch)
+		if err != nil || chunk.IsError {
+			t.Fatalf("FetchResultChunk(%d) = %+v, %v", i, chunk, err)
+		}
+		sb.WriteString(chunk.Content[0].(mcp.TextContent).Text)
+	}
+	if sb.String() != original {
+		t.Errorf("reassembled response = %q, want %q", sb.String(), original)
+	}
+}
+
+func TestResponseChunkingMiddleware_SkipsFetchTool(t *testing.T) {
+	chunker := newTestChunker(t, 10)
+	handler := responseChunkingMiddleware(chunker, 10)(textHandler(strings.Repeat("x", 100)))
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = fetchResultChunkTool
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.StructuredContent != nil {
+		t.Error("fetch_result_chunk responses must not be chunked again")
+	}
+}
+
+func TestFetchResultChunk_Disabled(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"result_handle": "x"}
+	result, err := handlers.FetchResultChunk(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true when chunking is disabled")
+	}
+	assertContentContains(t, result, "response chunking is disabled")
+}
@@ -1,22 +1,54 @@
 package mcp
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/compact"
 	"github.com/tuannvm/mcp-trino/internal/config"
-	oauth "github.com/tuannvm/oauth-mcp-proxy"
+	"github.com/tuannvm/mcp-trino/internal/history"
+	"github.com/tuannvm/mcp-trino/internal/redact"
+	"github.com/tuannvm/mcp-trino/internal/resultstore"
+	"github.com/tuannvm/mcp-trino/internal/state"
+	"github.com/tuannvm/mcp-trino/internal/subscription"
 	"github.com/tuannvm/mcp-trino/internal/trino"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
 )
 
 // TrinoHandlers contains all handlers for Trino-related tools
 type TrinoHandlers struct {
-	TrinoClient *trino.Client
-	Config      *config.TrinoConfig
+	TrinoClient   *trino.Client
+	Config        *config.TrinoConfig
+	ResultPager   *resultstore.Pager   // Stores paginated results (nil if pagination disabled)
+	ResultChunker *resultstore.Chunker // Stores oversized responses (nil if chunking disabled)
+	Checks        []trino.Check        // Data quality checks run by run_checks (nil if none configured)
+
+	// ProfileClient returns the Trino client of an environment profile, for
+	// diff_queries' other_profile (nil if profiles are disabled)
+	ProfileClient func(name string) (*trino.Client, error)
+
+	// Subscriptions re-runs queries for subscribe_query (nil if
+	// subscriptions are disabled)
+	Subscriptions *subscription.Manager
+
+	// History records execute_query calls for my_query_history (nil if
+	// query history is disabled)
+	History *history.Store
+
+	// Redactor scrubs redacted column values from query history entries
+	// (nil if redaction is disabled)
+	Redactor *redact.Redactor
+
+	// Sessions keeps per-session settings such as set_response_mode's
+	// (nil if session settings are unavailable)
+	Sessions state.Store
 }
 
 // NewTrinoHandlers creates a new set of Trino handlers
@@ -52,10 +84,10 @@ func (h *TrinoHandlers) prepareImpersonationContext(ctx context.Context) context
 }
 
 // ExecuteQuery handles query execution
-func (h *TrinoHandlers) ExecuteQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-    if h.Config.EnableImpersonation {
-        ctx = h.prepareImpersonationContext(ctx)
-    }
+func (h *TrinoHandlers) ExecuteQuery(ctx context.Context, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
 
 	// Type assert Arguments to map[string]interface{}
 	args, ok := request.Params.Arguments.(map[string]interface{})
@@ -71,13 +103,58 @@ func (h *TrinoHandlers) ExecuteQuery(ctx context.Context, request mcp.CallToolRe
 		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
 	}
 
+	mode, err := h.responseMode(ctx, args)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(err.Error(), err), nil
+	}
+
+	// Record the call in the caller's query history once it returns
+	var entry history.Entry
+	if h.History != nil {
+		var queries *trino.QueryLog
+		ctx, queries = trino.WithQueryLog(ctx)
+		entry = history.Entry{Time: time.Now().UTC(), User: trino.UserIdentity(ctx), Query: query}
+		defer func() { h.recordHistory(&entry, queries, result) }()
+	}
+
 	// Execute the query - SQL injection protection is handled within the client
-	qr, err := h.TrinoClient.ExecuteQueryWithContext(ctx, query)
+	qr, err := h.TrinoClient.ExecuteQueryWithSpill(ctx, query)
 	if err != nil {
 		log.Printf("Error executing query: %v", err)
 		mcpErr := fmt.Errorf("query execution failed: %w", err)
+		// Point queries written for other dialects at translate_sql
+		if summary := trino.TranslateSQL(query).Summary(); summary != "" {
+			mcpErr = fmt.Errorf("%w. %s", mcpErr, summary)
+		}
 		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
 	}
+	entry.Rows, entry.Truncated = len(qr.Rows), qr.Truncated
+	if qr.Spill != nil {
+		entry.Rows = qr.Spill.Rows()
+	}
+
+	// Release the result's memory budget and spill file once the response is built
+	defer func() {
+		if err := qr.Close(); err != nil {
+			log.Printf("Error releasing query result: %v", err)
+		}
+	}()
+
+	// Compact results are summarized and trimmed for small context windows
+	if mode.Verbosity == compact.VerbosityCompact {
+		return h.compactQueryResult(ctx, qr, mode.Options)
+	}
+
+	// Spilled results are copied page by page from the spill file into the
+	// result store
+	if qr.Spill != nil {
+		return h.spilledResult(ctx, qr)
+	}
+
+	// Large results are stored server-side and returned one page at a time
+	if h.ResultPager != nil && len(qr.Rows) > h.ResultPager.PageSize() {
+		return h.paginatedResult(ctx, qr)
+	}
 
 	// Build the bare JSON array as backward-compatible text content
 	// This preserves the original response format for older MCP clients
@@ -102,6 +179,162 @@ func (h *TrinoHandlers) ExecuteQuery(ctx context.Context, request mcp.CallToolRe
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
+// paginatedResult stores the full result and returns its first page. The text
+// content stays a bare JSON array of the page rows; pagination metadata is
+// carried in structuredContent, mirroring the truncation envelope.
+func (h *TrinoHandlers) paginatedResult(ctx context.Context, qr *trino.QueryResult) (*mcp.CallToolResult, error) {
+	meta, err := h.ResultPager.Save(ctx, trino.UserIdentity(ctx), qr.Rows, qr.Truncated)
+	if err != nil {
+		log.Printf("Error storing paginated result: %v", err)
+		mcpErr := fmt.Errorf("failed to store query results: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return pageResult(meta, 1, qr.Rows[:meta.PageSize])
+}
+
+// spilledResult pages a result that was spilled to disk and returns page 1
+func (h *TrinoHandlers) spilledResult(ctx context.Context, qr *trino.QueryResult) (*mcp.CallToolResult, error) {
+	if h.ResultPager == nil {
+		mcpErr := fmt.Errorf("result of %d rows was spilled to disk but result pagination is disabled; add LIMIT to your query", qr.Spill.Rows())
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	owner := trino.UserIdentity(ctx)
+	meta, err := h.ResultPager.SaveFrom(ctx, owner, qr.Spill.Iterate, qr.Truncated)
+	if err != nil {
+		log.Printf("Error storing spilled result: %v", err)
+		mcpErr := fmt.Errorf("failed to store query results: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	_, rows, err := h.ResultPager.Page(ctx, meta.QueryID, owner, 1)
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to fetch query results: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	return pageResult(meta, 1, rows)
+}
+
+// GetQueryResults handles retrieval of additional pages of a stored result
+func (h *TrinoHandlers) GetQueryResults(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.ResultPager == nil {
+		mcpErr := fmt.Errorf("result pagination is disabled (set MCP_RESULT_PAGE_SIZE to enable)")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	queryID, ok := args["query_id"].(string)
+	if !ok || queryID == "" {
+		mcpErr := fmt.Errorf("query_id parameter is required")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	page := 1
+	if pageParam, ok := args["page"].(float64); ok {
+		page = int(pageParam)
+	}
+	mode, err := h.responseMode(ctx, args)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(err.Error(), err), nil
+	}
+
+	meta, rows, err := h.ResultPager.Page(ctx, queryID, trino.UserIdentity(ctx), page)
+	if err != nil {
+		log.Printf("Error fetching result page: %v", err)
+		mcpErr := fmt.Errorf("failed to fetch query results: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	if mode.Verbosity == compact.VerbosityCompact {
+		view := compact.Compact(nil, rows, mode.Options)
+		view.Message += fmt.Sprintf(" This is page %d of %d (%d total rows).", page, meta.PageCount, meta.TotalRows)
+		return compactResult(view, meta, page, meta.Truncated)
+	}
+
+	return pageResult(meta, page, rows)
+}
+
+// FetchResultChunk handles retrieval of a chunk of an oversized response
+func (h *TrinoHandlers) FetchResultChunk(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.ResultChunker == nil {
+		mcpErr := fmt.Errorf("response chunking is disabled (set MCP_MAX_RESPONSE_BYTES to enable)")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	handle, ok := args["result_handle"].(string)
+	if !ok || handle == "" {
+		mcpErr := fmt.Errorf("result_handle parameter is required")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	chunk := 1
+	if chunkParam, ok := args["chunk"].(float64); ok {
+		chunk = int(chunkParam)
+	}
+
+	meta, text, err := h.ResultChunker.Chunk(ctx, handle, trino.UserIdentity(ctx), chunk)
+	if err != nil {
+		log.Printf("Error fetching result chunk: %v", err)
+		mcpErr := fmt.Errorf("failed to fetch result chunk: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	structured := map[string]interface{}{
+		"result_handle": meta.Handle,
+		"chunk":         chunk,
+		"chunk_count":   meta.ChunkCount,
+		"total_bytes":   meta.TotalBytes,
+	}
+	if chunk < meta.ChunkCount {
+		structured["next_chunk"] = chunk + 1
+	}
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{mcp.NewTextContent(text)},
+		StructuredContent: structured,
+	}, nil
+}
+
+// pageResult builds the tool result for one page of a stored result
+func pageResult(meta *resultstore.Meta, page int, rows []map[string]interface{}) (*mcp.CallToolResult, error) {
+	jsonData, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal results to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	structured := map[string]interface{}{
+		"results":    rows,
+		"query_id":   meta.QueryID,
+		"page":       page,
+		"page_count": meta.PageCount,
+		"total_rows": meta.TotalRows,
+		"rowCount":   len(rows),
+		"truncated":  meta.Truncated,
+	}
+	if page < meta.PageCount {
+		structured["next_page"] = page + 1
+		structured["message"] = fmt.Sprintf("Showing page %d of %d (%d total rows). Call get_query_results with query_id %q and page %d for more.",
+			page, meta.PageCount, meta.TotalRows, meta.QueryID, page+1)
+	} else {
+		structured["message"] = fmt.Sprintf("Showing final page %d of %d (%d total rows).", page, meta.PageCount, meta.TotalRows)
+	}
+	return mcp.NewToolResultStructured(structured, string(jsonData)), nil
+}
+
 // ListCatalogs handles catalog listing
 func (h *TrinoHandlers) ListCatalogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if h.Config.EnableImpersonation {
@@ -293,53 +526,996 @@ func (h *TrinoHandlers) ExplainQuery(ctx context.Context, request mcp.CallToolRe
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
-// RegisterTrinoTools registers all Trino-related tools with the MCP server.
-// OAuth middleware is applied server-wide via WithToolHandlerMiddleware(),
-// so no per-tool middleware application needed.
-func RegisterTrinoTools(m *server.MCPServer, h *TrinoHandlers) {
+// BuildQueryContext handles schema context assembly for natural-language questions
+func (h *TrinoHandlers) BuildQueryContext(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
 
-	m.AddTool(mcp.NewTool("execute_query",
-		mcp.WithDescription("Execute SQL queries on Trino's fast distributed query engine for big data analytics. By default, only read-only queries (SELECT, SHOW, DESCRIBE, EXPLAIN) are allowed for security. When TRINO_ALLOW_WRITE_QUERIES=true is set, supports all SQL statements including INSERT, UPDATE, DELETE, CREATE, DROP, and other DML/DDL operations. Perfect for complex analytics, aggregations, joins, and cross-system data exploration on large datasets."),
-		mcp.WithTitleAnnotation("Execute Query"),
-		mcp.WithDestructiveHintAnnotation(true),
-		mcp.WithString("query", mcp.Required(), mcp.Description("SQL query to execute. By default read-only queries only; DML/DDL requires TRINO_ALLOW_WRITE_QUERIES=true")),
-	), h.ExecuteQuery)
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
 
-	m.AddTool(mcp.NewTool("list_catalogs",
-		mcp.WithDescription("Discover available Trino catalogs - each catalog represents a connector to different data systems (PostgreSQL, MySQL, S3, HDFS, Kafka, etc.). Catalogs are your entry point to querying data across heterogeneous systems in a single SQL query."),
-		mcp.WithTitleAnnotation("List Catalogs"),
-		mcp.WithReadOnlyHintAnnotation(true)),
-		h.ListCatalogs)
+	question, ok := args["question"].(string)
+	if !ok || question == "" {
+		mcpErr := fmt.Errorf("question parameter is required")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
 
-	m.AddTool(mcp.NewTool("list_schemas",
-		mcp.WithDescription("Browse schemas (databases/namespaces) within a Trino catalog. Each schema contains related tables and views. Use this to navigate the data hierarchy before querying specific datasets."),
-		mcp.WithTitleAnnotation("List Schemas"),
-		mcp.WithReadOnlyHintAnnotation(true),
-		mcp.WithString("catalog", mcp.Description("Trino catalog name (optional; defaults to server configuration if omitted)"))),
-		h.ListSchemas)
+	opts := trino.QueryContextOptions{Question: question}
+	if catalogParam, ok := args["catalog"].(string); ok {
+		opts.Catalog = catalogParam
+	}
+	if schemaParam, ok := args["schema"].(string); ok {
+		opts.Schema = schemaParam
+	}
+	if maxTablesParam, ok := args["max_tables"].(float64); ok {
+		opts.MaxTables = int(maxTablesParam)
+	}
+	if sampleRowsParam, ok := args["sample_rows"].(float64); ok {
+		opts.SampleRows = int(sampleRowsParam)
+		if opts.SampleRows == 0 {
+			opts.SampleRows = -1 // an explicit 0 disables samples rather than using the default
+		}
+	}
+	if budgetParam, ok := args["token_budget"].(float64); ok {
+		opts.TokenBudget = int(budgetParam)
+	}
 
-	m.AddTool(mcp.NewTool("list_tables",
-		mcp.WithDescription("Discover tables and views available for querying in Trino schemas. Essential for finding datasets to analyze. Can scope to specific catalog/schema or browse all available data across the distributed system."),
-		mcp.WithTitleAnnotation("List Tables"),
-		mcp.WithReadOnlyHintAnnotation(true),
-		mcp.WithString("catalog", mcp.Description("Trino catalog name (optional)")),
-		mcp.WithString("schema", mcp.Description("Schema name within catalog (optional)"))),
-		h.ListTables)
+	qc, err := h.TrinoClient.BuildQueryContext(ctx, opts)
+	if err != nil {
+		log.Printf("Error building query context: %v", err)
+		mcpErr := fmt.Errorf("failed to build query context: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
 
-	m.AddTool(mcp.NewTool("get_table_schema",
-		mcp.WithDescription("Inspect table structure and column metadata from Trino's distributed data sources. Shows column names, data types, nullability, and constraints. Critical for understanding data before writing analytical queries."),
-		mcp.WithTitleAnnotation("Get Table Schema"),
-		mcp.WithReadOnlyHintAnnotation(true),
-		mcp.WithString("catalog", mcp.Description("Trino catalog containing the table (optional)")),
-		mcp.WithString("schema", mcp.Description("Schema containing the table (optional)")),
-		mcp.WithString("table", mcp.Required(), mcp.Description("Table name to inspect"))),
-		h.GetTableSchema)
+	// Compact JSON keeps the payload within the token budget it was sized for
+	jsonData, err := json.Marshal(qc)
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal query context to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
 
-	m.AddTool(mcp.NewTool("explain_query",
-		mcp.WithDescription("Analyze Trino query execution plans without running expensive queries. Shows distributed execution stages, data movement between nodes, and resource estimates. Essential for query optimization and performance tuning."),
-		mcp.WithTitleAnnotation("Explain Query"),
-		mcp.WithReadOnlyHintAnnotation(true),
-		mcp.WithString("query", mcp.Required(), mcp.Description("SQL query to analyze (SELECT, JOIN, aggregations, etc.)")),
-		mcp.WithString("format", mcp.Description("Plan type: LOGICAL, DISTRIBUTED, VALIDATE, or IO (optional)"))),
-		h.ExplainQuery)
+	return mcp.NewToolResultStructured(qc, string(jsonData)), nil
+}
+
+// EstimateQueryCost handles IO-based cost estimation of a query
+func (h *TrinoHandlers) EstimateQueryCost(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Extract the query parameter
+	query, ok := args["query"].(string)
+	if !ok {
+		mcpErr := fmt.Errorf("query parameter must be a string")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	estimate, err := h.TrinoClient.EstimateQueryCost(ctx, query)
+	if err != nil {
+		log.Printf("Error estimating query cost: %v", err)
+		mcpErr := fmt.Errorf("query cost estimation failed: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	jsonData, err := json.MarshalIndent(estimate, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal cost estimate to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(estimate, string(jsonData)), nil
+}
+
+// AdviseQuery handles query optimization advice
+func (h *TrinoHandlers) AdviseQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Extract the query parameter
+	query, ok := args["query"].(string)
+	if !ok {
+		mcpErr := fmt.Errorf("query parameter must be a string")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	advice, err := h.TrinoClient.AdviseQuery(ctx, query)
+	if err != nil {
+		log.Printf("Error advising on query: %v", err)
+		mcpErr := fmt.Errorf("query analysis failed: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	jsonData, err := json.MarshalIndent(advice, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal query advice to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(advice, string(jsonData)), nil
+}
+
+// TranslateSQL handles rewriting queries written for other SQL dialects
+func (h *TrinoHandlers) TranslateSQL(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Extract the query parameter
+	query, ok := args["query"].(string)
+	if !ok {
+		mcpErr := fmt.Errorf("query parameter must be a string")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	translation := trino.TranslateSQL(query)
+
+	jsonData, err := json.MarshalIndent(translation, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal translation to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(translation, string(jsonData)), nil
+}
+
+// BenchmarkQuery handles repeated timed runs of a query
+func (h *TrinoHandlers) BenchmarkQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Extract the query parameter
+	query, ok := args["query"].(string)
+	if !ok {
+		mcpErr := fmt.Errorf("query parameter must be a string")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	opts := trino.BenchmarkOptions{Query: query}
+	if runsParam, ok := args["runs"].(float64); ok {
+		opts.Runs = int(runsParam)
+	}
+	if warmupParam, ok := args["warmup"].(float64); ok {
+		opts.Warmup = int(warmupParam)
+	}
+
+	benchmark, err := h.TrinoClient.BenchmarkQuery(ctx, opts)
+	if err != nil {
+		log.Printf("Error benchmarking query: %v", err)
+		mcpErr := fmt.Errorf("query benchmark failed: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	jsonData, err := json.MarshalIndent(benchmark, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal benchmark to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(benchmark, string(jsonData)), nil
+}
+
+// DiffQueries handles comparing the results of two queries, environments,
+// or table versions
+func (h *TrinoHandlers) DiffQueries(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Extract the query parameter
+	query, ok := args["query"].(string)
+	if !ok {
+		mcpErr := fmt.Errorf("query parameter must be a string")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	opts := trino.DiffOptions{Query: query, KeyColumns: stringSliceArg(args, "key_columns")}
+	for name, value := range map[string]*string{
+		"other_query":     &opts.OtherQuery,
+		"catalog":         &opts.Catalog,
+		"schema":          &opts.Schema,
+		"table":           &opts.Table,
+		"version":         &opts.Version,
+		"timestamp":       &opts.Timestamp,
+		"other_version":   &opts.OtherVersion,
+		"other_timestamp": &opts.OtherTimestamp,
+	} {
+		if param, ok := args[name].(string); ok {
+			*value = param
+		}
+	}
+	if samplesParam, ok := args["sample_size"].(float64); ok {
+		opts.Samples = int(samplesParam)
+	}
+
+	profile, _ := args[profileArgument].(string)
+	otherProfile, _ := args["other_profile"].(string)
+	if otherProfile != "" {
+		if h.ProfileClient == nil {
+			mcpErr := fmt.Errorf("other_profile requires environment profiles (MCP_PROFILES_FILE)")
+			return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+		}
+		client, err := h.ProfileClient(otherProfile)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr(err.Error(), err), nil
+		}
+		opts.Other = client
+	}
+
+	diff, err := h.TrinoClient.DiffQueries(ctx, opts)
+	if err != nil {
+		log.Printf("Error comparing queries: %v", err)
+		mcpErr := fmt.Errorf("query comparison failed: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	if otherProfile != "" {
+		diff.Left.Environment, diff.Right.Environment = cmp.Or(profile, "default"), otherProfile
+	}
+
+	jsonData, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal query diff to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(diff, string(jsonData)), nil
+}
+
+// SuggestCreateTable handles suggesting a table definition for a query's
+// result
+func (h *TrinoHandlers) SuggestCreateTable(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Extract the query parameter
+	query, ok := args["query"].(string)
+	if !ok {
+		mcpErr := fmt.Errorf("query parameter must be a string")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	opts := trino.CreateTableOptions{Query: query}
+	opts.Table, _ = args["table"].(string)
+	opts.Catalog, _ = args["catalog"].(string)
+	opts.Schema, _ = args["schema"].(string)
+
+	suggestion, err := h.TrinoClient.SuggestCreateTable(ctx, opts)
+	if err != nil {
+		log.Printf("Error suggesting table definition: %v", err)
+		mcpErr := fmt.Errorf("table suggestion failed: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	jsonData, err := json.MarshalIndent(suggestion, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal table suggestion to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(suggestion, string(jsonData)), nil
+}
+
+// GetTopTables handles the most-queried tables report
+func (h *TrinoHandlers) GetTopTables(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.usageReport(ctx, request, "tables", func(records []trino.UsageRecord, limit int) interface{} {
+		return trino.TopTables(records, limit)
+	})
+}
+
+// GetTopUsers handles the heaviest users report
+func (h *TrinoHandlers) GetTopUsers(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.usageReport(ctx, request, "users", func(records []trino.UsageRecord, limit int) interface{} {
+		return trino.TopUsers(records, limit)
+	})
+}
+
+// GetFailureHotspots handles the failure hotspots report
+func (h *TrinoHandlers) GetFailureHotspots(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.usageReport(ctx, request, "hotspots", func(records []trino.UsageRecord, limit int) interface{} {
+		return trino.FailureHotspots(records, limit)
+	})
+}
+
+// usageReport reads the query history for the requested window and returns
+// the entries built from it under key, along with the window it covers
+func (h *TrinoHandlers) usageReport(ctx context.Context, request mcp.CallToolRequest, key string,
+	build func(records []trino.UsageRecord, limit int) interface{}) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	var opts trino.UsageOptions
+	if windowParam, ok := args["window_hours"].(float64); ok {
+		if windowParam <= 0 {
+			mcpErr := fmt.Errorf("window_hours must be positive")
+			return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+		}
+		opts.WindowHours = int(windowParam)
+	}
+	if limitParam, ok := args["limit"].(float64); ok {
+		opts.Limit = int(limitParam)
+	}
+	if allSourcesParam, ok := args["all_sources"].(bool); ok {
+		opts.AllSources = allSourcesParam
+	}
+	opts = opts.Normalize()
+
+	records, err := h.TrinoClient.QueryHistory(ctx, opts)
+	if err != nil {
+		log.Printf("Error reading query history: %v", err)
+		mcpErr := fmt.Errorf("failed to build usage report: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	report := map[string]interface{}{
+		"window_hours":     opts.WindowHours,
+		"all_sources":      opts.AllSources,
+		"queries_analyzed": len(records),
+		key:                build(records, opts.Limit),
+	}
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal usage report to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(report, string(jsonData)), nil
+}
+
+// CheckCatalogs handles per-catalog connectivity probes
+func (h *TrinoHandlers) CheckCatalogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	timeout := trino.DefaultCatalogProbeTimeout
+	if timeoutParam, ok := args["timeout_seconds"].(float64); ok {
+		if timeoutParam <= 0 {
+			mcpErr := fmt.Errorf("timeout_seconds must be positive")
+			return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+		}
+		timeout = time.Duration(timeoutParam * float64(time.Second))
+	}
+	slowThreshold := trino.DefaultCatalogSlowThreshold
+	if slowParam, ok := args["slow_threshold_ms"].(float64); ok && slowParam > 0 {
+		slowThreshold = time.Duration(slowParam) * time.Millisecond
+	}
+
+	check, err := h.TrinoClient.CheckCatalogs(ctx, timeout, slowThreshold)
+	if err != nil {
+		log.Printf("Error checking catalogs: %v", err)
+		mcpErr := fmt.Errorf("catalog check failed: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	jsonData, err := json.MarshalIndent(check, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal catalog check to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(check, string(jsonData)), nil
+}
+
+// SampleTable handles TABLESAMPLE-based table sampling
+func (h *TrinoHandlers) SampleTable(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Table parameter is required
+	table, ok := args["table"].(string)
+	if !ok {
+		mcpErr := fmt.Errorf("table parameter is required")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	opts := trino.SampleOptions{Table: table}
+	if catalogParam, ok := args["catalog"].(string); ok {
+		opts.Catalog = catalogParam
+	}
+	if schemaParam, ok := args["schema"].(string); ok {
+		opts.Schema = schemaParam
+	}
+	if methodParam, ok := args["method"].(string); ok {
+		opts.Method = methodParam
+	}
+	if percentageParam, ok := args["percentage"].(float64); ok {
+		opts.Percentage = percentageParam
+	}
+	if maxRowsParam, ok := args["max_rows"].(float64); ok {
+		opts.MaxRows = int(maxRowsParam)
+	}
+
+	sample, err := h.TrinoClient.SampleTable(ctx, opts)
+	if err != nil {
+		log.Printf("Error sampling table: %v", err)
+		mcpErr := fmt.Errorf("failed to sample table: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	jsonData, err := json.MarshalIndent(sample, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal table sample to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(sample, string(jsonData)), nil
+}
+
+// ListSnapshots handles Iceberg snapshot listing
+func (h *TrinoHandlers) ListSnapshots(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Table parameter is required
+	table, ok := args["table"].(string)
+	if !ok {
+		mcpErr := fmt.Errorf("table parameter is required")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	var catalog, schema string
+	var limit int
+	if catalogParam, ok := args["catalog"].(string); ok {
+		catalog = catalogParam
+	}
+	if schemaParam, ok := args["schema"].(string); ok {
+		schema = schemaParam
+	}
+	if limitParam, ok := args["limit"].(float64); ok {
+		limit = int(limitParam)
+	}
+
+	snapshots, err := h.TrinoClient.ListSnapshots(ctx, catalog, schema, table, limit)
+	if err != nil {
+		log.Printf("Error listing snapshots: %v", err)
+		mcpErr := fmt.Errorf("failed to list snapshots: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	jsonData, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal snapshots to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(map[string]interface{}{"snapshots": snapshots}, string(jsonData)), nil
+}
+
+// QueryAtVersion handles point-in-time queries against Iceberg tables
+func (h *TrinoHandlers) QueryAtVersion(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Table parameter is required
+	table, ok := args["table"].(string)
+	if !ok {
+		mcpErr := fmt.Errorf("table parameter is required")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	opts := trino.VersionOptions{Table: table}
+	if catalogParam, ok := args["catalog"].(string); ok {
+		opts.Catalog = catalogParam
+	}
+	if schemaParam, ok := args["schema"].(string); ok {
+		opts.Schema = schemaParam
+	}
+	if versionParam, ok := args["version"].(string); ok {
+		opts.Version = versionParam
+	}
+	if timestampParam, ok := args["timestamp"].(string); ok {
+		opts.Timestamp = timestampParam
+	}
+	if queryParam, ok := args["query"].(string); ok {
+		opts.Query = queryParam
+	}
+
+	result, err := h.TrinoClient.QueryAtVersion(ctx, opts)
+	if err != nil {
+		log.Printf("Error running time travel query: %v", err)
+		mcpErr := fmt.Errorf("time travel query failed: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal results to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(result, string(jsonData)), nil
+}
+
+// SummarizeTable handles grouped table summaries
+func (h *TrinoHandlers) SummarizeTable(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Table parameter is required
+	table, ok := args["table"].(string)
+	if !ok {
+		mcpErr := fmt.Errorf("table parameter is required")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	opts := trino.SummaryOptions{
+		Table:      table,
+		Measures:   stringSliceArg(args, "measures"),
+		Aggregates: stringSliceArg(args, "aggregates"),
+	}
+	if catalogParam, ok := args["catalog"].(string); ok {
+		opts.Catalog = catalogParam
+	}
+	if schemaParam, ok := args["schema"].(string); ok {
+		opts.Schema = schemaParam
+	}
+	if dimensionParam, ok := args["dimension"].(string); ok {
+		opts.Dimension = dimensionParam
+	}
+	if maxGroupsParam, ok := args["max_groups"].(float64); ok {
+		opts.MaxGroups = int(maxGroupsParam)
+	}
+
+	summary, err := h.TrinoClient.SummarizeTable(ctx, opts)
+	if err != nil {
+		log.Printf("Error summarizing table: %v", err)
+		mcpErr := fmt.Errorf("failed to summarize table: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	jsonData, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal table summary to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(summary, string(jsonData)), nil
+}
+
+// stringSliceArg returns the string elements of an array argument, skipping
+// anything that is not a string
+func stringSliceArg(args map[string]interface{}, key string) []string {
+	items, ok := args[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// RunChecks handles running the configured data quality checks
+func (h *TrinoHandlers) RunChecks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	if len(h.Checks) == 0 {
+		mcpErr := fmt.Errorf("no data quality checks are configured; set MCP_CHECKS_FILE")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Optional filters select checks by name or table
+	names := make(map[string]bool)
+	for _, name := range stringSliceArg(args, "names") {
+		names[name] = true
+	}
+	tables := make(map[string]bool)
+	for _, table := range stringSliceArg(args, "tables") {
+		tables[strings.ToLower(table)] = true
+	}
+	var selected []trino.Check
+	for _, check := range h.Checks {
+		if (len(names) == 0 || names[check.Name]) && (len(tables) == 0 || tables[strings.ToLower(check.Table)]) {
+			selected = append(selected, check)
+		}
+	}
+	if len(selected) == 0 {
+		mcpErr := fmt.Errorf("no configured checks match the given names and tables")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	run, err := h.TrinoClient.RunChecks(ctx, selected)
+	if err != nil {
+		log.Printf("Error running data quality checks: %v", err)
+		mcpErr := fmt.Errorf("failed to run checks: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	jsonData, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal check results to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(run, string(jsonData)), nil
+}
+
+// RegisterTrinoTools registers all Trino-related tools with the MCP server.
+// OAuth middleware is applied server-wide via WithToolHandlerMiddleware(),
+// so no per-tool middleware application needed.
+func RegisterTrinoTools(m *server.MCPServer, h *TrinoHandlers) {
+
+	m.AddTool(mcp.NewTool("execute_query",
+		mcp.WithDescription("Execute SQL queries on Trino's fast distributed query engine for big data analytics. By default, only read-only queries (SELECT, SHOW, DESCRIBE, EXPLAIN) are allowed for security. When TRINO_ALLOW_WRITE_QUERIES=true is set, supports all SQL statements including INSERT, UPDATE, DELETE, CREATE, DROP, and other DML/DDL operations. Perfect for complex analytics, aggregations, joins, and cross-system data exploration on large datasets."),
+		mcp.WithTitleAnnotation("Execute Query"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("query", mcp.Required(), mcp.Description("SQL query to execute. By default read-only queries only; DML/DDL requires TRINO_ALLOW_WRITE_QUERIES=true")),
+		mcp.WithString("verbosity", mcp.Description("full returns every row; compact keeps the first columns, cuts long values, and summarizes large results per column (optional; defaults to the session's set_response_mode, else full)"), mcp.Enum(compact.VerbosityFull, compact.VerbosityCompact)),
+	), h.ExecuteQuery)
+
+	m.AddTool(mcp.NewTool("list_catalogs",
+		mcp.WithDescription("Discover available Trino catalogs - each catalog represents a connector to different data systems (PostgreSQL, MySQL, S3, HDFS, Kafka, etc.). Catalogs are your entry point to querying data across heterogeneous systems in a single SQL query."),
+		mcp.WithTitleAnnotation("List Catalogs"),
+		mcp.WithReadOnlyHintAnnotation(true)),
+		h.ListCatalogs)
+
+	m.AddTool(mcp.NewTool("list_schemas",
+		mcp.WithDescription("Browse schemas (databases/namespaces) within a Trino catalog. Each schema contains related tables and views. Use this to navigate the data hierarchy before querying specific datasets."),
+		mcp.WithTitleAnnotation("List Schemas"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("catalog", mcp.Description("Trino catalog name (optional; defaults to server configuration if omitted)"))),
+		h.ListSchemas)
+
+	m.AddTool(mcp.NewTool("list_tables",
+		mcp.WithDescription("Discover tables and views available for querying in Trino schemas. Essential for finding datasets to analyze. Can scope to specific catalog/schema or browse all available data across the distributed system."),
+		mcp.WithTitleAnnotation("List Tables"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("catalog", mcp.Description("Trino catalog name (optional)")),
+		mcp.WithString("schema", mcp.Description("Schema name within catalog (optional)"))),
+		h.ListTables)
+
+	m.AddTool(mcp.NewTool("get_table_schema",
+		mcp.WithDescription("Inspect table structure and column metadata from Trino's distributed data sources. Shows column names, data types, nullability, and constraints. Critical for understanding data before writing analytical queries."),
+		mcp.WithTitleAnnotation("Get Table Schema"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("catalog", mcp.Description("Trino catalog containing the table (optional)")),
+		mcp.WithString("schema", mcp.Description("Schema containing the table (optional)")),
+		mcp.WithString("table", mcp.Required(), mcp.Description("Table name to inspect"))),
+		h.GetTableSchema)
+
+	m.AddTool(mcp.NewTool("explain_query",
+		mcp.WithDescription("Analyze Trino query execution plans without running expensive queries. Shows distributed execution stages, data movement between nodes, and resource estimates. Essential for query optimization and performance tuning."),
+		mcp.WithTitleAnnotation("Explain Query"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query", mcp.Required(), mcp.Description("SQL query to analyze (SELECT, JOIN, aggregations, etc.)")),
+		mcp.WithString("format", mcp.Description("Plan type: LOGICAL, DISTRIBUTED, VALIDATE, or IO (optional)"))),
+		h.ExplainQuery)
+
+	m.AddTool(mcp.NewTool("get_query_results",
+		mcp.WithDescription("Fetch another page of a large execute_query result. When a result exceeds the configured page size, execute_query returns the first page with a query_id; use this tool to retrieve the remaining pages from any server replica."),
+		mcp.WithTitleAnnotation("Get Query Results"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query_id", mcp.Required(), mcp.Description("query_id returned by execute_query")),
+		mcp.WithNumber("page", mcp.Description("1-indexed page number to fetch (default: 1)")),
+		mcp.WithString("verbosity", mcp.Description("full or compact, as for execute_query (optional; defaults to the session's set_response_mode, else full)"), mcp.Enum(compact.VerbosityFull, compact.VerbosityCompact))),
+		h.GetQueryResults)
+
+	m.AddTool(mcp.NewTool(fetchResultChunkTool,
+		mcp.WithDescription("Fetch one chunk of a tool response that was too large to return directly. Oversized responses are replaced by a result_handle and chunk_count; fetch chunks 1 through chunk_count and concatenate their text in order."),
+		mcp.WithTitleAnnotation("Fetch Result Chunk"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("result_handle", mcp.Required(), mcp.Description("result_handle returned in place of the oversized response")),
+		mcp.WithNumber("chunk", mcp.Description("1-indexed chunk number to fetch (default: 1)"))),
+		h.FetchResultChunk)
+
+	m.AddTool(mcp.NewTool("set_response_mode",
+		mcp.WithDescription("Choose how execute_query and get_query_results return results for the rest of this session. compact suits small context windows: only the first max_columns columns are kept (the rest are listed by name), values longer than max_value_length end in an ellipsis, and results with more than max_rows rows are replaced by per-column statistics (nulls, distinct values, min, max, mean) and the first few rows. full restores complete results. A verbosity argument on a single call overrides the session's mode."),
+		mcp.WithTitleAnnotation("Set Response Mode"),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("verbosity", mcp.Required(), mcp.Description("full or compact"), mcp.Enum(compact.VerbosityFull, compact.VerbosityCompact)),
+		mcp.WithNumber("max_columns", mcp.Description(fmt.Sprintf("Columns kept in compact results, at most %d (default: %d)", compact.MaxMaxColumns, compact.DefaultMaxColumns))),
+		mcp.WithNumber("max_value_length", mcp.Description(fmt.Sprintf("Characters kept per value in compact results (default: %d)", compact.DefaultMaxValueLen))),
+		mcp.WithNumber("max_rows", mcp.Description(fmt.Sprintf("Compact results with more rows are summarized, at most %d (default: %d)", compact.MaxMaxRows, compact.DefaultMaxRows)))),
+		h.SetResponseMode)
+
+	m.AddTool(mcp.NewTool("build_query_context",
+		mcp.WithDescription("Assemble the schema context needed to write SQL for a natural-language question in one call. Selects the tables most relevant to the question (by table name, comment, and column matches, with recent query usage as a tie-breaker) and returns their columns, a few sample rows, and suggested join conditions, trimmed to fit a token budget. Call this before writing SQL against unfamiliar data."),
+		mcp.WithTitleAnnotation("Build Query Context"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("question", mcp.Required(), mcp.Description("Natural-language question the SQL should answer")),
+		mcp.WithString("catalog", mcp.Description("Catalog to search (optional; defaults to server configuration)")),
+		mcp.WithString("schema", mcp.Description("Schema to search (optional; searches the whole catalog if omitted)")),
+		mcp.WithNumber("max_tables", mcp.Description(fmt.Sprintf("Maximum tables to include (default: %d)", trino.DefaultContextMaxTables))),
+		mcp.WithNumber("sample_rows", mcp.Description(fmt.Sprintf("Sample rows per table, 0 to omit (default: %d)", trino.DefaultContextSampleRows))),
+		mcp.WithNumber("token_budget", mcp.Description(fmt.Sprintf("Approximate token budget for the response (default: %d)", trino.DefaultContextTokenBudget)))),
+		h.BuildQueryContext)
+
+	m.AddTool(mcp.NewTool("estimate_query_cost",
+		mcp.WithDescription("Estimate what a query will read before running it. Runs EXPLAIN (TYPE IO) and returns the estimated bytes and rows scanned, partitions touched, and a per-table breakdown with the filters pushed into each scan. Use it to catch full-table scans and expensive queries before calling execute_query."),
+		mcp.WithTitleAnnotation("Estimate Query Cost"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query", mcp.Required(), mcp.Description("SQL query to estimate"))),
+		h.EstimateQueryCost)
+
+	m.AddTool(mcp.NewTool("advise_query",
+		mcp.WithDescription("Suggest optimizations for a query without running it. Inspects the IO plan and table metadata to flag missing partition filters, functions on filtered columns that block pruning, COUNT(DISTINCT) that could use approx_distinct, large tables joined without a filter, and existing materialized views that already compute the result. Returns suggestions ordered by severity along with the cost estimate."),
+		mcp.WithTitleAnnotation("Advise Query"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query", mcp.Required(), mcp.Description("SQL query to analyze"))),
+		h.AdviseQuery)
+
+	m.AddTool(mcp.NewTool("translate_sql",
+		mcp.WithDescription("Rewrite a query written for another SQL dialect into Trino SQL without running it. Rewrites Postgres :: casts and type names, MySQL backtick identifiers and LIMIT offset, count, LIMIT ... OFFSET, DATE_SUB/DATE_ADD/ADDDATE/SUBDATE, DATEADD, DATEDIFF, and TIMESTAMPDIFF, unquoted MySQL and unit-in-string Postgres intervals, and functions Trino names differently (IFNULL, NVL, STR_TO_DATE). Idioms without a safe rewrite, such as ILIKE, SELECT TOP, GROUP_CONCAT, and TO_CHAR, come back as hints. Call it when a query from another dialect fails, then run the returned query."),
+		mcp.WithTitleAnnotation("Translate SQL"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query", mcp.Required(), mcp.Description("SQL query to translate"))),
+		h.TranslateSQL)
+
+	m.AddTool(mcp.NewTool("benchmark_query",
+		mcp.WithDescription("Run a read-only query several times and report how long it takes: p50 and p95 latency, mean, standard deviation, and coefficient of variation, plus bytes scanned and CPU time per run from the coordinator. Each run carries a unique comment so query text caches cannot answer it. Use it to compare formulations of the same query; every run is a full execution, so benchmark queries that are cheap enough to repeat."),
+		mcp.WithTitleAnnotation("Benchmark Query"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Read-only SQL query to benchmark")),
+		mcp.WithNumber("runs", mcp.Description(fmt.Sprintf("Measured runs, at most 20 (default: %d)", trino.DefaultBenchmarkRuns))),
+		mcp.WithNumber("warmup", mcp.Description("Unmeasured runs before the measured ones, at most 5 (default: 0)"))),
+		h.BenchmarkQuery)
+
+	m.AddTool(mcp.NewTool("diff_queries",
+		mcp.WithDescription("Compare the results of two read-only queries and report what changed: row count delta, rows only on each side, and, when key columns are given, rows whose values changed with the old and new value of each column. Compare two formulations of a query, the same query on two environment profiles (other_profile), or the same query against two versions of an Iceberg table (table with version/timestamp and other_version/other_timestamp). Use it for migration and regression checks."),
+		mcp.WithTitleAnnotation("Diff Queries"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Read-only SQL query for the left side")),
+		mcp.WithString("other_query", mcp.Description("Read-only SQL query for the right side (optional; defaults to query)")),
+		mcp.WithString("other_profile", mcp.Description("Environment profile the right side runs against (optional; requires environment profiles)")),
+		mcp.WithArray("key_columns", mcp.WithStringItems(), mcp.Description("Columns that identify a row on both sides; rows are compared whole when omitted")),
+		mcp.WithString("table", mcp.Description("Iceberg table to read at different versions, optionally qualified as schema.table or catalog.schema.table (optional)")),
+		mcp.WithString("catalog", mcp.Description("Catalog containing the table (optional)")),
+		mcp.WithString("schema", mcp.Description("Schema containing the table (optional)")),
+		mcp.WithString("version", mcp.Description("Snapshot ID, branch, or tag the left side reads the table at (optional; current version if omitted)")),
+		mcp.WithString("timestamp", mcp.Description("Point in time the left side reads the table at; UTC if no zone is given (optional)")),
+		mcp.WithString("other_version", mcp.Description("Snapshot ID, branch, or tag the right side reads the table at (optional)")),
+		mcp.WithString("other_timestamp", mcp.Description("Point in time the right side reads the table at (optional)")),
+		mcp.WithNumber("sample_size", mcp.Description(fmt.Sprintf("Differing rows to return of each kind, at most 100 (default: %d)", trino.DefaultDiffSamples)))),
+		h.DiffQueries)
+
+	m.AddTool(mcp.NewTool("suggest_create_table",
+		mcp.WithDescription("Suggest a CREATE TABLE and a CREATE TABLE AS SELECT statement that persist a read-only query's result. The result's columns and exact types are read without running the query to completion, then adapted to the target catalog's connector: types it cannot store are replaced (with the reason), unnamed and repeated columns are named, and Hive, Iceberg, and Delta Lake tables get a suggested date partitioning. Nothing is created; review the statements and run them yourself."),
+		mcp.WithTitleAnnotation("Suggest Create Table"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Read-only SQL query whose result the table should hold")),
+		mcp.WithString("table", mcp.Required(), mcp.Description("Name of the table to create, optionally qualified as schema.table or catalog.schema.table")),
+		mcp.WithString("catalog", mcp.Description("Catalog to create the table in (optional)")),
+		mcp.WithString("schema", mcp.Description("Schema to create the table in (optional)"))),
+		h.SuggestCreateTable)
+
+	m.AddTool(mcp.NewTool("subscribe_query",
+		mcp.WithDescription("Re-run a bounded read-only query on an interval and get notified when its result changes, for example to learn when yesterday's partition lands. Watch the whole result, its row count, or a numeric value in the first row, optionally only notifying for changes beyond an absolute or percent threshold. Changes arrive as log notifications on this session and, if given, are posted to a webhook the server allows. The query must return its whole result within the row limit, so aggregate it or add a LIMIT. Subscriptions expire, and end when the server restarts."),
+		mcp.WithTitleAnnotation("Subscribe to Query"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Read-only SQL query to re-run")),
+		mcp.WithString("watch", mcp.Description("What to watch: result (any change to the rows), row_count, or value (a numeric column of the first row) (default: result)"), mcp.Enum(subscription.WatchResult, subscription.WatchRowCount, subscription.WatchValue)),
+		mcp.WithString("value_column", mcp.Description("Column holding the watched value (optional; defaults to the only column)")),
+		mcp.WithNumber("threshold", mcp.Description("Smallest absolute change in the row count or value that notifies (optional)")),
+		mcp.WithNumber("threshold_percent", mcp.Description("Smallest change, in percent of the previous row count or value, that notifies (optional)")),
+		mcp.WithNumber("interval_seconds", mcp.Description("Seconds between runs; the server sets a minimum (default: the minimum)")),
+		mcp.WithNumber("expires_hours", mcp.Description("Hours until the subscription ends; the server sets a maximum (default: 24)")),
+		mcp.WithBoolean("once", mcp.Description("End the subscription after the first notification (default: false)")),
+		mcp.WithString("webhook", mcp.Description("URL to also post changes to; must match a prefix the server allows (optional)"))),
+		h.SubscribeQuery)
+
+	m.AddTool(mcp.NewTool("list_subscriptions",
+		mcp.WithDescription("List your active query subscriptions on this server instance, with each one's baseline, last run, and notification count."),
+		mcp.WithTitleAnnotation("List Subscriptions"),
+		mcp.WithReadOnlyHintAnnotation(true)),
+		h.ListSubscriptions)
+
+	m.AddTool(mcp.NewTool("unsubscribe_query",
+		mcp.WithDescription("End one of your query subscriptions."),
+		mcp.WithTitleAnnotation("Unsubscribe from Query"),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("subscription_id", mcp.Required(), mcp.Description("ID returned by subscribe_query"))),
+		h.UnsubscribeQuery)
+
+	m.AddTool(mcp.NewTool("my_query_history",
+		mcp.WithDescription("List the queries you ran with execute_query, newest first, from the server's persistent history. Entries survive sessions and server restarts and outlast the cluster's own query history. Each entry has the query text, when it ran, how long it took, whether it failed and why, the row count, the Trino query IDs, and, for results stored for paging, a query_id for get_query_results while the result is kept. Filter by text, state, or age, or pass id to get one entry; re-run an entry by passing its query to execute_query."),
+		mcp.WithTitleAnnotation("My Query History"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithNumber("id", mcp.Description("Return only this history entry (optional)")),
+		mcp.WithString("search", mcp.Description("Only entries whose query contains this text, ignoring case (optional)")),
+		mcp.WithString("state", mcp.Description("Only succeeded or failed entries (optional)"), mcp.Enum(history.StateSucceeded, history.StateFailed)),
+		mcp.WithNumber("since_hours", mcp.Description("Only entries from the last this many hours (optional)")),
+		mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Maximum entries to return, at most %d (default: %d)", history.MaxLimit, history.DefaultLimit)))),
+		h.MyQueryHistory)
+
+	usageParams := []mcp.ToolOption{
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithNumber("window_hours", mcp.Description(fmt.Sprintf("How many hours of history to analyze (default: %d)", trino.DefaultUsageWindowHours))),
+		mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Maximum entries to return (default: %d)", trino.DefaultUsageLimit))),
+		mcp.WithBoolean("all_sources", mcp.Description("Include queries from every client, not only those sent through this server (default: false)")),
+	}
+
+	m.AddTool(mcp.NewTool("get_top_tables", append([]mcp.ToolOption{
+		mcp.WithDescription("Report the most-queried tables over a recent window, from the coordinator's query history. For each table returns the number of queries that read it, distinct users, failures, and total query time. Use it to see which data agents lean on most."),
+		mcp.WithTitleAnnotation("Get Top Tables"),
+	}, usageParams...)...),
+		h.GetTopTables)
+
+	m.AddTool(mcp.NewTool("get_top_users", append([]mcp.ToolOption{
+		mcp.WithDescription("Report the heaviest users over a recent window, from the coordinator's query history, ranked by total query time. For each user returns query count, failures, and total and average query time."),
+		mcp.WithTitleAnnotation("Get Top Users"),
+	}, usageParams...)...),
+		h.GetTopUsers)
+
+	m.AddTool(mcp.NewTool("get_failure_hotspots", append([]mcp.ToolOption{
+		mcp.WithDescription("Report where queries fail over a recent window, from the coordinator's query history. Groups failed queries by error code and the table they read, with failure and user counts, when the error was last seen, and an example query ID to inspect."),
+		mcp.WithTitleAnnotation("Get Failure Hotspots"),
+	}, usageParams...)...),
+		h.GetFailureHotspots)
+
+	m.AddTool(mcp.NewTool("check_catalogs",
+		mcp.WithDescription("Check connectivity to every allowed catalog. Runs SHOW SCHEMAS against each catalog with a short timeout and reports it as reachable, slow, or broken, with latency and the error for broken ones. Use it when queries against one data source start failing, to find the flaky connector."),
+		mcp.WithTitleAnnotation("Check Catalogs"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithNumber("timeout_seconds", mcp.Description(fmt.Sprintf("Per-catalog probe timeout in seconds (default: %d)", int(trino.DefaultCatalogProbeTimeout.Seconds())))),
+		mcp.WithNumber("slow_threshold_ms", mcp.Description(fmt.Sprintf("Latency in milliseconds above which a catalog is reported slow (default: %d)", trino.DefaultCatalogSlowThreshold.Milliseconds())))),
+		h.CheckCatalogs)
+
+	m.AddTool(mcp.NewTool("sample_table",
+		mcp.WithDescription("Look at a random sample of a large table without a full scan. Uses TABLESAMPLE so rows come from across the table, unlike a LIMIT preview that only shows whichever data is read first. BERNOULLI samples individual rows; SYSTEM samples whole splits and reads far less data, at the cost of clumpier samples."),
+		mcp.WithTitleAnnotation("Sample Table"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("table", mcp.Required(), mcp.Description("Table to sample, optionally qualified as schema.table or catalog.schema.table")),
+		mcp.WithString("catalog", mcp.Description("Catalog containing the table (optional)")),
+		mcp.WithString("schema", mcp.Description("Schema containing the table (optional)")),
+		mcp.WithString("method", mcp.Description("Sampling method: BERNOULLI or SYSTEM (default: BERNOULLI)"), mcp.Enum(trino.SampleBernoulli, trino.SampleSystem)),
+		mcp.WithNumber("percentage", mcp.Description(fmt.Sprintf("Percentage of rows or splits to sample, greater than 0 and at most 100 (default: %g)", trino.DefaultSamplePercentage))),
+		mcp.WithNumber("max_rows", mcp.Description(fmt.Sprintf("Maximum rows to return (default: %d)", trino.DefaultSampleRows)))),
+		h.SampleTable)
+
+	m.AddTool(mcp.NewTool("list_snapshots",
+		mcp.WithDescription("List the snapshots of an Iceberg table, newest first, with commit time, operation (append, overwrite, delete), and summary counts. Use it to find the snapshot ID or time to pass to query_at_version."),
+		mcp.WithTitleAnnotation("List Snapshots"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("table", mcp.Required(), mcp.Description("Iceberg table, optionally qualified as schema.table or catalog.schema.table")),
+		mcp.WithString("catalog", mcp.Description("Catalog containing the table (optional)")),
+		mcp.WithString("schema", mcp.Description("Schema containing the table (optional)")),
+		mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Maximum snapshots to return (default: %d)", trino.DefaultSnapshotLimit)))),
+		h.ListSnapshots)
+
+	m.AddTool(mcp.NewTool("query_at_version",
+		mcp.WithDescription("Query an Iceberg table as it was at an earlier snapshot, branch, tag, or point in time, for point-in-time debugging. Adds FOR VERSION AS OF or FOR TIMESTAMP AS OF to every reference to the table in the query; other tables are read at their current version. Without a query, returns rows from the table at that version."),
+		mcp.WithTitleAnnotation("Query At Version"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("table", mcp.Required(), mcp.Description("Iceberg table to read at an earlier version, optionally qualified as schema.table or catalog.schema.table")),
+		mcp.WithString("catalog", mcp.Description("Catalog containing the table (optional)")),
+		mcp.WithString("schema", mcp.Description("Schema containing the table (optional)")),
+		mcp.WithString("version", mcp.Description("Snapshot ID, branch, or tag to read (use this or timestamp)")),
+		mcp.WithString("timestamp", mcp.Description("Point in time to read, e.g. '2024-05-01 12:00:00 UTC'; UTC if no zone is given (use this or version)")),
+		mcp.WithString("query", mcp.Description(fmt.Sprintf("Read-only query that references the table in a FROM or JOIN clause, without a time travel clause (optional; defaults to SELECT * with LIMIT %d)", trino.DefaultVersionRows)))),
+		h.QueryAtVersion)
+
+	m.AddTool(mcp.NewTool("summarize_table",
+		mcp.WithDescription("Get a one-call overview of a table: rows grouped by one dimension with a row count and aggregates of numeric measures per group, largest groups first. Defaults pick the first text, boolean, or date column as the dimension and up to three numeric non-ID columns as measures, so it works without knowing the schema."),
+		mcp.WithTitleAnnotation("Summarize Table"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("table", mcp.Required(), mcp.Description("Table to summarize, optionally qualified as schema.table or catalog.schema.table")),
+		mcp.WithString("catalog", mcp.Description("Catalog containing the table (optional)")),
+		mcp.WithString("schema", mcp.Description("Schema containing the table (optional)")),
+		mcp.WithString("dimension", mcp.Description("Column to group by (optional; defaults to the first text, boolean, or date column)")),
+		mcp.WithArray("measures", mcp.WithStringItems(), mcp.Description(fmt.Sprintf("Numeric columns to aggregate (optional; defaults to up to %d numeric non-ID columns)", trino.DefaultSummaryMeasures))),
+		mcp.WithArray("aggregates", mcp.WithStringItems(mcp.Enum(trino.AggregateSum, trino.AggregateAvg, trino.AggregateMin, trino.AggregateMax)), mcp.Description("Aggregates computed for each measure (default: sum, avg)")),
+		mcp.WithNumber("max_groups", mcp.Description(fmt.Sprintf("Maximum groups to return, largest first (default: %d)", trino.DefaultSummaryGroups)))),
+		h.SummarizeTable)
+
+	m.AddTool(mcp.NewTool("run_checks",
+		mcp.WithDescription("Run the data quality checks configured by the operator (NULL rate, uniqueness, freshness, and referential integrity) and return pass or fail for each, with the measured metrics. Run all checks, or filter by check name or table."),
+		mcp.WithTitleAnnotation("Run Checks"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithArray("names", mcp.WithStringItems(), mcp.Description("Check names to run (optional; runs all checks if omitted)")),
+		mcp.WithArray("tables", mcp.WithStringItems(), mcp.Description("Run only checks on these catalog.schema.table names (optional)"))),
+		h.RunChecks)
+
+	m.AddTool(mcp.NewTool("generate_access_report",
+		mcp.WithDescription("Summarize which users accessed which tables over a date range, from the server's audit log: call counts, successful writes, failures (including blocked writes), and first and last access for each user and table. Return JSON, or CSV for export. Restricted to the report admins configured by the operator."),
+		mcp.WithTitleAnnotation("Generate Access Report"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("from", mcp.Description(fmt.Sprintf("Start of the range, as YYYY-MM-DD or an RFC 3339 timestamp (default: %d days before to)", defaultReportDays))),
+		mcp.WithString("to", mcp.Description("End of the range; a date includes that whole day (default: now)")),
+		mcp.WithString("user", mcp.Description("Only report this user's access (optional)")),
+		mcp.WithString("format", mcp.Enum("json", "csv"), mcp.Description("Output format (default: json)"))),
+		h.GenerateAccessReport)
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
 	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/resultstore"
+	"github.com/tuannvm/mcp-trino/internal/trino"
 )
 
 // expectedTools lists all tool names that RegisterTrinoTools must register.
@@ -21,6 +23,30 @@ var expectedTools = []string{
 	"list_tables",
 	"get_table_schema",
 	"explain_query",
+	"get_query_results",
+	"fetch_result_chunk",
+	"set_response_mode",
+	"build_query_context",
+	"estimate_query_cost",
+	"advise_query",
+	"translate_sql",
+	"benchmark_query",
+	"diff_queries",
+	"suggest_create_table",
+	"subscribe_query",
+	"list_subscriptions",
+	"unsubscribe_query",
+	"my_query_history",
+	"get_top_tables",
+	"get_top_users",
+	"get_failure_hotspots",
+	"check_catalogs",
+	"sample_table",
+	"list_snapshots",
+	"query_at_version",
+	"summarize_table",
+	"run_checks",
+	"generate_access_report",
 }
 
 // newTestHandlers creates a TrinoHandlers with no real Trino client, suitable
@@ -32,7 +58,7 @@ func newTestHandlers(cfg *config.TrinoConfig) *TrinoHandlers {
 	}
 }
 
-// TestRegisterTrinoTools_AllToolsRegistered verifies that all 6 tools are
+// TestRegisterTrinoTools_AllToolsRegistered verifies that all tools are
 // registered on the MCP server and can be listed via the JSON-RPC protocol.
 func TestRegisterTrinoTools_AllToolsRegistered(t *testing.T) {
 	srv := mcpserver.NewMCPServer("test-server", "0.0.1", mcpserver.WithToolCapabilities(true))
@@ -454,3 +480,433 @@ func assertContentContains(t *testing.T, result *mcp.CallToolResult, want string
 	}
 	t.Errorf("result content does not contain %q", want)
 }
+
+// newTestPager creates an in-memory result pager for pagination tests.
+func newTestPager(t *testing.T, pageSize int) *resultstore.Pager {
+	t.Helper()
+	store, err := resultstore.NewBlobStore(context.Background(), resultstore.Options{Backend: resultstore.BackendMemory})
+	if err != nil {
+		t.Fatalf("failed to create result store: %v", err)
+	}
+	return resultstore.NewPager(store, pageSize, time.Hour)
+}
+
+// TestGetQueryResults_Disabled verifies that GetQueryResults reports that
+// pagination is disabled when no pager is configured.
+func TestGetQueryResults_Disabled(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "get_query_results"
+	req.Params.Arguments = map[string]interface{}{"query_id": "x"}
+
+	result, err := handlers.GetQueryResults(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetQueryResults returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true when pagination is disabled")
+	}
+	assertContentContains(t, result, "result pagination is disabled")
+}
+
+// TestGetQueryResults_MissingQueryID verifies that GetQueryResults rejects
+// requests without a query_id argument.
+func TestGetQueryResults_MissingQueryID(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+	handlers.ResultPager = newTestPager(t, 2)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "get_query_results"
+	req.Params.Arguments = map[string]interface{}{}
+
+	result, err := handlers.GetQueryResults(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetQueryResults returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for missing query_id")
+	}
+	assertContentContains(t, result, "query_id parameter is required")
+}
+
+// TestPaginatedResultFlow verifies that a large result returns its first page
+// with a query_id and that later pages are served by GetQueryResults.
+func TestPaginatedResultFlow(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+	handlers.ResultPager = newTestPager(t, 2)
+
+	rows := make([]map[string]interface{}, 5)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"row": i + 1}
+	}
+
+	first, err := handlers.paginatedResult(context.Background(), &trino.QueryResult{Rows: rows, MaxRows: 100})
+	if err != nil {
+		t.Fatalf("paginatedResult returned unexpected Go error: %v", err)
+	}
+	if first.IsError {
+		t.Fatalf("expected success, got error result: %+v", first.Content)
+	}
+
+	sc := structuredMap(t, first)
+	if sc["page_count"].(float64) != 3 || sc["total_rows"].(float64) != 5 {
+		t.Errorf("unexpected pagination metadata: %v", sc)
+	}
+	if sc["next_page"].(float64) != 2 {
+		t.Errorf("next_page = %v, want 2", sc["next_page"])
+	}
+	queryID, _ := sc["query_id"].(string)
+	if queryID == "" {
+		t.Fatal("expected query_id in structuredContent")
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "get_query_results"
+	req.Params.Arguments = map[string]interface{}{"query_id": queryID, "page": float64(3)}
+
+	last, err := handlers.GetQueryResults(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetQueryResults returned unexpected Go error: %v", err)
+	}
+	if last.IsError {
+		t.Fatalf("expected success, got error result: %+v", last.Content)
+	}
+	tc, ok := last.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatal("expected TextContent in content[0]")
+	}
+	var arr []map[string]interface{}
+	if err := json.Unmarshal([]byte(tc.Text), &arr); err != nil {
+		t.Fatalf("text content is not a bare JSON array: %v", err)
+	}
+	if len(arr) != 1 || arr[0]["row"].(float64) != 5 {
+		t.Errorf("unexpected final page rows: %v", arr)
+	}
+	if _, ok := structuredMap(t, last)["next_page"]; ok {
+		t.Error("expected no next_page on the final page")
+	}
+}
+
+// structuredMap round-trips a result's structuredContent through JSON.
+func structuredMap(t *testing.T, result *mcp.CallToolResult) map[string]interface{} {
+	t.Helper()
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("failed to marshal structuredContent: %v", err)
+	}
+	var sc map[string]interface{}
+	if err := json.Unmarshal(data, &sc); err != nil {
+		t.Fatalf("structuredContent is not a JSON object: %v", err)
+	}
+	return sc
+}
+
+// TestBuildQueryContext_MissingQuestion verifies that BuildQueryContext rejects
+// requests without a question argument.
+func TestBuildQueryContext_MissingQuestion(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "build_query_context"
+	req.Params.Arguments = map[string]interface{}{"schema": "sales"}
+
+	result, err := handlers.BuildQueryContext(context.Background(), req)
+	if err != nil {
+		t.Fatalf("BuildQueryContext returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for missing question")
+	}
+	assertContentContains(t, result, "question parameter is required")
+}
+
+// TestEstimateQueryCost_MissingQueryParam verifies that EstimateQueryCost
+// rejects requests without a query argument.
+func TestEstimateQueryCost_MissingQueryParam(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "estimate_query_cost"
+	req.Params.Arguments = map[string]interface{}{}
+
+	result, err := handlers.EstimateQueryCost(context.Background(), req)
+	if err != nil {
+		t.Fatalf("EstimateQueryCost returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for missing query parameter")
+	}
+	assertContentContains(t, result, "query parameter must be a string")
+}
+
+// TestAdviseQuery_MissingQueryParam verifies that AdviseQuery rejects requests
+// without a query argument.
+func TestAdviseQuery_MissingQueryParam(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "advise_query"
+	req.Params.Arguments = map[string]interface{}{}
+
+	result, err := handlers.AdviseQuery(context.Background(), req)
+	if err != nil {
+		t.Fatalf("AdviseQuery returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for missing query parameter")
+	}
+	assertContentContains(t, result, "query parameter must be a string")
+}
+
+// TestTranslateSQL verifies that TranslateSQL returns the rewritten query
+// without a Trino client and rejects requests without a query argument.
+func TestTranslateSQL(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "translate_sql"
+	req.Params.Arguments = map[string]interface{}{}
+
+	result, err := handlers.TranslateSQL(context.Background(), req)
+	if err != nil {
+		t.Fatalf("TranslateSQL returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for missing query parameter")
+	}
+	assertContentContains(t, result, "query parameter must be a string")
+
+	req.Params.Arguments = map[string]interface{}{"query": "SELECT `id`::text FROM t LIMIT 10, 5"}
+	result, err = handlers.TranslateSQL(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("TranslateSQL() = %+v, %v", result, err)
+	}
+	translation, ok := result.StructuredContent.(*trino.Translation)
+	if !ok || translation.Query != `SELECT CAST("id" AS varchar) FROM t OFFSET 10 LIMIT 5` {
+		t.Errorf("StructuredContent = %#v, want the rewritten query", result.StructuredContent)
+	}
+}
+
+// TestBenchmarkQuery_MissingQueryParam verifies that BenchmarkQuery rejects
+// requests without a query argument.
+func TestBenchmarkQuery_MissingQueryParam(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "benchmark_query"
+	req.Params.Arguments = map[string]interface{}{"runs": float64(3)}
+
+	result, err := handlers.BenchmarkQuery(context.Background(), req)
+	if err != nil {
+		t.Fatalf("BenchmarkQuery returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for missing query parameter")
+	}
+	assertContentContains(t, result, "query parameter must be a string")
+}
+
+// TestDiffQueries_Validation verifies that DiffQueries rejects requests
+// without a query, and other_profile when profiles are disabled.
+func TestDiffQueries_Validation(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "diff_queries"
+	req.Params.Arguments = map[string]interface{}{"other_query": "SELECT 1"}
+	result, err := handlers.DiffQueries(context.Background(), req)
+	if err != nil {
+		t.Fatalf("DiffQueries returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for missing query parameter")
+	}
+	assertContentContains(t, result, "query parameter must be a string")
+
+	req.Params.Arguments = map[string]interface{}{"query": "SELECT 1", "other_profile": "staging"}
+	result, err = handlers.DiffQueries(context.Background(), req)
+	if err != nil {
+		t.Fatalf("DiffQueries returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for other_profile without profiles")
+	}
+	assertContentContains(t, result, "MCP_PROFILES_FILE")
+}
+
+// TestSuggestCreateTable_MissingQuery verifies that SuggestCreateTable
+// rejects requests without a query.
+func TestSuggestCreateTable_MissingQuery(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "suggest_create_table"
+	req.Params.Arguments = map[string]interface{}{"table": "daily_orders"}
+	result, err := handlers.SuggestCreateTable(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SuggestCreateTable returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for missing query parameter")
+	}
+	assertContentContains(t, result, "query parameter must be a string")
+}
+
+// TestUsageReports_InvalidWindow verifies that the usage analytics tools
+// reject a non-positive window before querying Trino.
+func TestUsageReports_InvalidWindow(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{})
+
+	tools := map[string]func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error){
+		"get_top_tables":       handlers.GetTopTables,
+		"get_top_users":        handlers.GetTopUsers,
+		"get_failure_hotspots": handlers.GetFailureHotspots,
+	}
+	for name, handler := range tools {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = map[string]interface{}{"window_hours": float64(0)}
+
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s returned unexpected Go error: %v", name, err)
+		}
+		if !result.IsError {
+			t.Errorf("%s: expected IsError=true for a zero window", name)
+		}
+		assertContentContains(t, result, "window_hours must be positive")
+	}
+}
+
+// TestCheckCatalogs_InvalidTimeout verifies that CheckCatalogs rejects a
+// non-positive probe timeout.
+func TestCheckCatalogs_InvalidTimeout(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "check_catalogs"
+	req.Params.Arguments = map[string]interface{}{"timeout_seconds": float64(-1)}
+
+	result, err := handlers.CheckCatalogs(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CheckCatalogs returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for a negative timeout")
+	}
+	assertContentContains(t, result, "timeout_seconds must be positive")
+}
+
+// TestSampleTable_MissingTableParam verifies that SampleTable rejects
+// requests without a table argument.
+func TestSampleTable_MissingTableParam(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "sample_table"
+	req.Params.Arguments = map[string]interface{}{"percentage": float64(5)}
+
+	result, err := handlers.SampleTable(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SampleTable returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for missing table parameter")
+	}
+	assertContentContains(t, result, "table parameter is required")
+}
+
+// TestTimeTravel_MissingTableParam verifies that the time travel tools reject
+// requests without a table argument.
+func TestTimeTravel_MissingTableParam(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{})
+
+	tools := map[string]func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error){
+		"list_snapshots":   handlers.ListSnapshots,
+		"query_at_version": handlers.QueryAtVersion,
+	}
+	for name, handler := range tools {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = map[string]interface{}{"version": "1"}
+
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s returned unexpected Go error: %v", name, err)
+		}
+		if !result.IsError {
+			t.Errorf("%s: expected IsError=true for missing table parameter", name)
+		}
+		assertContentContains(t, result, "table parameter is required")
+	}
+}
+
+// TestSummarizeTable_MissingTableParam verifies that SummarizeTable rejects
+// requests without a table argument.
+func TestSummarizeTable_MissingTableParam(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "summarize_table"
+	req.Params.Arguments = map[string]interface{}{"dimension": "status"}
+
+	result, err := handlers.SummarizeTable(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SummarizeTable returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for missing table parameter")
+	}
+	assertContentContains(t, result, "table parameter is required")
+}
+
+// TestStringSliceArg verifies that array arguments are read as strings.
+func TestStringSliceArg(t *testing.T) {
+	args := map[string]interface{}{"measures": []interface{}{"amount", 3.0, "quantity"}, "other": "amount"}
+	if got := stringSliceArg(args, "measures"); len(got) != 2 || got[0] != "amount" || got[1] != "quantity" {
+		t.Errorf("stringSliceArg() = %v, want [amount quantity]", got)
+	}
+	if got := stringSliceArg(args, "other"); got != nil {
+		t.Errorf("stringSliceArg() = %v, want nil for a non-array", got)
+	}
+}
+
+// TestRunChecks_NoChecksConfigured verifies that RunChecks explains how to
+// configure checks when none are loaded.
+func TestRunChecks_NoChecksConfigured(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "run_checks"
+	req.Params.Arguments = map[string]interface{}{}
+
+	result, err := handlers.RunChecks(context.Background(), req)
+	if err != nil {
+		t.Fatalf("RunChecks returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true when no checks are configured")
+	}
+	assertContentContains(t, result, "MCP_CHECKS_FILE")
+}
+
+// TestRunChecks_NoMatchingChecks verifies that filters matching no
+// configured check are reported instead of running nothing.
+func TestRunChecks_NoMatchingChecks(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{})
+	handlers.Checks = []trino.Check{{Name: "orders_id_unique", Type: trino.CheckUnique, Table: "hive.sales.orders", Columns: []string{"id"}}}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "run_checks"
+	req.Params.Arguments = map[string]interface{}{"tables": []interface{}{"hive.sales.customers"}}
+
+	result, err := handlers.RunChecks(context.Background(), req)
+	if err != nil {
+		t.Fatalf("RunChecks returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true when no checks match")
+	}
+	assertContentContains(t, result, "no configured checks match")
+}
@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// readinessTimeout bounds the Trino check behind /readyz, so probes fail
+// fast instead of piling up behind a hung coordinator
+const readinessTimeout = 3 * time.Second
+
+// handleReadyz reports whether the server can serve tool calls. It is ready
+// once the Trino coordinator answers and has finished starting; otherwise it
+// returns 503 so load balancers and Kubernetes stop routing to this replica.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := s.trinoClient.Ping(ctx); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "unavailable", "error": "trino: " + err.Error()})
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ready", "version": s.version})
+}
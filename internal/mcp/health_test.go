@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+// newReadinessTestServer returns a Server whose Trino client points at a fake
+// coordinator answering /v1/info with the given body and status.
+func newReadinessTestServer(t *testing.T, status int, body string) *Server {
+	t.Helper()
+	coordinator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(coordinator.Close)
+
+	u, _ := url.Parse(coordinator.URL)
+	port, _ := strconv.Atoi(u.Port())
+	client, err := trino.NewClient(&config.TrinoConfig{
+		Host: u.Hostname(), Port: port, Scheme: "http", User: "svc",
+		Catalog: "memory", Schema: "default",
+	})
+	if err != nil {
+		t.Fatalf("trino.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return &Server{trinoClient: client, version: "test"}
+}
+
+func TestHandleReadyz(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"ready", http.StatusOK, `{"starting":false}`, http.StatusOK, `"status":"ready"`},
+		{"starting", http.StatusOK, `{"starting":true}`, http.StatusServiceUnavailable, "still starting"},
+		{"coordinator error", http.StatusInternalServerError, "", http.StatusServiceUnavailable, "500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newReadinessTestServer(t, tt.status, tt.body)
+			rec := httptest.NewRecorder()
+			s.handleReadyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/history"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+// newQueryHistory opens the query history database, or returns nil when
+// history is disabled or the database cannot be opened
+func newQueryHistory(cfg *config.TrinoConfig) *history.Store {
+	if cfg.HistoryFile == "" {
+		return nil
+	}
+	store, err := history.Open(cfg.HistoryFile)
+	if err != nil {
+		log.Printf("ERROR: Failed to open query history, my_query_history is disabled: %v", err)
+		return nil
+	}
+	return store
+}
+
+// errHistoryDisabled is returned by my_query_history when the server keeps
+// no query history
+var errHistoryDisabled = errors.New("query history is not enabled on this server (set MCP_QUERY_HISTORY_FILE)")
+
+// recordHistory records a finished execute_query call in the caller's
+// history. Values of redacted columns are scrubbed from the query and error
+// text, as in the audit log. Failing to record it is logged, not reported to
+// the caller.
+func (h *TrinoHandlers) recordHistory(entry *history.Entry, queries *trino.QueryLog, result *mcp.CallToolResult) {
+	scrubValues := h.Redactor.References(entry.Query)
+	entry.Query = h.Redactor.Query(entry.Query)
+	entry.DurationMs = time.Since(entry.Time).Milliseconds()
+	entry.TrinoQueryIDs = queries.IDs()
+	entry.State = history.StateSucceeded
+	if result == nil || result.IsError {
+		entry.State = history.StateFailed
+		entry.Rows, entry.Truncated = 0, false
+		if result != nil && len(result.Content) > 0 {
+			if text, ok := result.Content[0].(mcp.TextContent); ok {
+				entry.Error = h.Redactor.Message(text.Text, scrubValues)
+			}
+		}
+	}
+	// Paged results are kept in the result store under a query_id
+	if result != nil {
+		if structured, ok := result.StructuredContent.(map[string]interface{}); ok {
+			entry.ResultRef, _ = structured["query_id"].(string)
+		}
+	}
+	if _, err := h.History.Add(*entry); err != nil {
+		log.Printf("WARNING: Query by %s was not recorded in the query history: %v", entry.User, err)
+	}
+}
+
+// MyQueryHistory handles listing the caller's earlier queries
+func (h *TrinoHandlers) MyQueryHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.History == nil {
+		return mcp.NewToolResultErrorFromErr(errHistoryDisabled.Error(), errHistoryDisabled), nil
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	user := trino.UserIdentity(ctx)
+
+	// A single entry is returned whole, for re-running or citing it
+	if idParam, ok := args["id"].(float64); ok {
+		if idParam < 1 {
+			mcpErr := fmt.Errorf("id must be a positive history entry id")
+			return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+		}
+		entry, err := h.History.Get(user, uint64(idParam))
+		if err != nil {
+			mcpErr := fmt.Errorf("failed to read query history: %w", err)
+			return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+		}
+		jsonData, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			mcpErr := fmt.Errorf("failed to marshal query history to JSON: %w", err)
+			return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+		}
+		return mcp.NewToolResultStructured(entry, string(jsonData)), nil
+	}
+
+	var filter history.Filter
+	filter.Search, _ = args["search"].(string)
+	filter.State, _ = args["state"].(string)
+	if filter.State != "" && filter.State != history.StateSucceeded && filter.State != history.StateFailed {
+		mcpErr := fmt.Errorf("state must be %s or %s", history.StateSucceeded, history.StateFailed)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	if hoursParam, ok := args["since_hours"].(float64); ok {
+		if hoursParam <= 0 {
+			mcpErr := fmt.Errorf("since_hours must be positive")
+			return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+		}
+		filter.Since = time.Now().Add(-time.Duration(hoursParam * float64(time.Hour)))
+	}
+	if limitParam, ok := args["limit"].(float64); ok {
+		filter.Limit = int(limitParam)
+	}
+
+	entries, err := h.History.List(user, filter)
+	if err != nil {
+		log.Printf("Error reading query history: %v", err)
+		mcpErr := fmt.Errorf("failed to read query history: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	if entries == nil {
+		entries = []history.Entry{}
+	}
+	result := map[string]interface{}{"user": user, "entries": entries, "count": len(entries)}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal query history to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(result, string(jsonData)), nil
+}
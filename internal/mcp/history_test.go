@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/history"
+	"github.com/tuannvm/mcp-trino/internal/redact"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+func TestMyQueryHistory_Disabled(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{})
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "my_query_history"
+	req.Params.Arguments = map[string]interface{}{}
+	result, err := handlers.MyQueryHistory(context.Background(), req)
+	if err != nil {
+		t.Fatalf("MyQueryHistory returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true when query history is disabled")
+	}
+	assertContentContains(t, result, "MCP_QUERY_HISTORY_FILE")
+}
+
+func TestExecuteQueryRecordsHistory(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("history.Open() error = %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	handlers := NewTrinoHandlers(newUsageTestClient(t), &config.TrinoConfig{MaxRows: 100})
+	handlers.History = store
+
+	alice := oauth.WithUser(context.Background(), &oauth.User{Username: "alice"})
+	call := func(name string, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = args
+		result, err := handler(alice, req)
+		if err != nil {
+			t.Fatalf("%s returned unexpected Go error: %v", name, err)
+		}
+		return result
+	}
+	call("execute_query", handlers.ExecuteQuery, map[string]interface{}{"query": "SELECT 1 AS n"})
+	call("execute_query", handlers.ExecuteQuery, map[string]interface{}{"query": "DELETE FROM orders"})
+
+	entries, err := store.List("alice", history.Filter{})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("history = %+v, %v; want both calls recorded for the caller", entries, err)
+	}
+	failed, succeeded := entries[0], entries[1]
+	if succeeded.Query != "SELECT 1 AS n" || succeeded.State != history.StateSucceeded || len(succeeded.TrinoQueryIDs) != 1 {
+		t.Errorf("succeeded entry = %+v", succeeded)
+	}
+	if failed.State != history.StateFailed || failed.Error == "" || len(failed.TrinoQueryIDs) != 0 {
+		t.Errorf("failed entry = %+v, want the rejected write recorded with its error", failed)
+	}
+
+	result := call("my_query_history", handlers.MyQueryHistory, map[string]interface{}{"state": "failed"})
+	if result.IsError {
+		t.Fatalf("my_query_history failed: %v", result.Content)
+	}
+	assertContentContains(t, result, "DELETE FROM orders")
+
+	result = call("my_query_history", handlers.MyQueryHistory, map[string]interface{}{"id": float64(succeeded.ID)})
+	assertContentContains(t, result, "SELECT 1 AS n")
+
+	result = call("my_query_history", handlers.MyQueryHistory, map[string]interface{}{"state": "running"})
+	if !result.IsError {
+		t.Error("expected IsError=true for an unknown state")
+	}
+}
+
+func TestRecordHistoryRedactsColumnValues(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("history.Open() error = %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	redactor, err := redact.New([]string{"ssn"}, "")
+	if err != nil {
+		t.Fatalf("redact.New() error = %v", err)
+	}
+	handlers := NewTrinoHandlers(nil, &config.TrinoConfig{})
+	handlers.History = store
+	handlers.Redactor = redactor
+
+	_, queries := trino.WithQueryLog(context.Background())
+	entry := history.Entry{Time: time.Now().UTC(), User: "alice", Query: "SELECT name FROM people WHERE ssn = '123-45-6789'"}
+	handlers.recordHistory(&entry, queries, mcp.NewToolResultError("query execution failed: Cannot cast '123-45-6789' to INT"))
+
+	entries, err := store.List("alice", history.Filter{})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("history = %+v, %v; want the call recorded", entries, err)
+	}
+	if got := entries[0]; strings.Contains(got.Query, "123-45-6789") || strings.Contains(got.Error, "123-45-6789") {
+		t.Errorf("entry = %+v, want the ssn value scrubbed from the query and error", got)
+	}
+	if !strings.Contains(entries[0].Query, "ssn = '"+redact.Marker+"'") {
+		t.Errorf("Query = %q, want the ssn comparison kept with its value redacted", entries[0].Query)
+	}
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolHook lets a deployment apply its own policy, transformation, or
+// logging to every tool call without forking the server. Hooks run after
+// authentication, so trino.UserIdentity(ctx) names the caller.
+type ToolHook interface {
+	// PreExecute runs before the tool. It may rewrite the request's
+	// arguments and return a derived context. Returning an error rejects the
+	// call with that error and skips the tool.
+	PreExecute(ctx context.Context, request *mcp.CallToolRequest) (context.Context, error)
+
+	// PostExecute runs after the tool succeeds and may replace its result.
+	// Returning an error turns the call into a tool error.
+	PostExecute(ctx context.Context, request mcp.CallToolRequest, result *mcp.CallToolResult) (*mcp.CallToolResult, error)
+
+	// OnError is notified when the call fails: the tool returned an error
+	// result or a Go error, or a hook rejected it.
+	OnError(ctx context.Context, request mcp.CallToolRequest, err error)
+}
+
+// ToolHookFuncs adapts plain functions to ToolHook. Nil functions do nothing.
+type ToolHookFuncs struct {
+	Pre   func(ctx context.Context, request *mcp.CallToolRequest) (context.Context, error)
+	Post  func(ctx context.Context, request mcp.CallToolRequest, result *mcp.CallToolResult) (*mcp.CallToolResult, error)
+	OnErr func(ctx context.Context, request mcp.CallToolRequest, err error)
+}
+
+// PreExecute implements ToolHook
+func (f ToolHookFuncs) PreExecute(ctx context.Context, request *mcp.CallToolRequest) (context.Context, error) {
+	if f.Pre == nil {
+		return ctx, nil
+	}
+	return f.Pre(ctx, request)
+}
+
+// PostExecute implements ToolHook
+func (f ToolHookFuncs) PostExecute(ctx context.Context, request mcp.CallToolRequest, result *mcp.CallToolResult) (*mcp.CallToolResult, error) {
+	if f.Post == nil {
+		return result, nil
+	}
+	return f.Post(ctx, request, result)
+}
+
+// OnError implements ToolHook
+func (f ToolHookFuncs) OnError(ctx context.Context, request mcp.CallToolRequest, err error) {
+	if f.OnErr != nil {
+		f.OnErr(ctx, request, err)
+	}
+}
+
+// Hooks registered before the server starts, typically from an init function
+// in a file added to a custom build
+var (
+	toolHooksMu sync.Mutex
+	toolHooks   []ToolHook
+)
+
+// RegisterToolHook adds a hook to every server created afterwards. Hooks run
+// in registration order before the tool and in reverse order after it.
+func RegisterToolHook(hook ToolHook) {
+	toolHooksMu.Lock()
+	defer toolHooksMu.Unlock()
+	toolHooks = append(toolHooks, hook)
+}
+
+// registeredToolHooks returns the hooks registered so far
+func registeredToolHooks() []ToolHook {
+	toolHooksMu.Lock()
+	defer toolHooksMu.Unlock()
+	return append([]ToolHook(nil), toolHooks...)
+}
+
+// ToolHookMiddleware runs hooks around each tool call. Errors from hooks are
+// returned to the client as tool errors.
+func ToolHookMiddleware(hooks ...ToolHook) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			notify := func(err error) {
+				for _, hook := range hooks {
+					hook.OnError(ctx, request, err)
+				}
+			}
+
+			for _, hook := range hooks {
+				hookCtx, err := hook.PreExecute(ctx, &request)
+				if err != nil {
+					log.Printf("Tool hook rejected %s: %v", request.Params.Name, err)
+					notify(err)
+					mcpErr := fmt.Errorf("request rejected: %w", err)
+					return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+				}
+				if hookCtx != nil {
+					ctx = hookCtx
+				}
+			}
+
+			result, err := next(ctx, request)
+			if err != nil {
+				notify(err)
+				return result, err
+			}
+			if result != nil && result.IsError {
+				notify(errors.New(resultText(result)))
+				return result, nil
+			}
+
+			for i := len(hooks) - 1; i >= 0; i-- {
+				if result, err = hooks[i].PostExecute(ctx, request, result); err != nil {
+					notify(err)
+					return mcp.NewToolResultErrorFromErr(err.Error(), err), nil
+				}
+			}
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type hookKey struct{}
+
+func TestToolHookMiddleware(t *testing.T) {
+	var order []string
+	var failures []error
+	recorder := func(name string) ToolHook {
+		return ToolHookFuncs{
+			Pre: func(ctx context.Context, request *mcp.CallToolRequest) (context.Context, error) {
+				order = append(order, "pre "+name)
+				return ctx, nil
+			},
+			Post: func(ctx context.Context, request mcp.CallToolRequest, result *mcp.CallToolResult) (*mcp.CallToolResult, error) {
+				order = append(order, "post "+name)
+				return result, nil
+			},
+			OnErr: func(ctx context.Context, request mcp.CallToolRequest, err error) {
+				failures = append(failures, err)
+			},
+		}
+	}
+	rewrite := ToolHookFuncs{
+		Pre: func(ctx context.Context, request *mcp.CallToolRequest) (context.Context, error) {
+			args := request.GetArguments()
+			args["query"] = strings.TrimSuffix(args["query"].(string), ";")
+			return context.WithValue(ctx, hookKey{}, "tagged"), nil
+		},
+		Post: func(ctx context.Context, request mcp.CallToolRequest, result *mcp.CallToolResult) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(resultText(result) + " (reviewed)"), nil
+		},
+	}
+
+	var gotQuery, gotTag interface{}
+	next := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		gotQuery, gotTag = request.GetArguments()["query"], ctx.Value(hookKey{})
+		if gotQuery == "fail" {
+			return mcp.NewToolResultError("query failed"), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	}
+	handler := ToolHookMiddleware(recorder("a"), rewrite, recorder("b"))(next)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "execute_query"
+	req.Params.Arguments = map[string]interface{}{"query": "SELECT 1;"}
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if gotQuery != "SELECT 1" || gotTag != "tagged" {
+		t.Errorf("tool saw query %v and tag %v, want the rewritten request and context", gotQuery, gotTag)
+	}
+	assertContentContains(t, result, "ok (reviewed)")
+	if want := "pre a,pre b,post b,post a"; strings.Join(order, ",") != want {
+		t.Errorf("hook order = %v, want %s", order, want)
+	}
+
+	req.Params.Arguments = map[string]interface{}{"query": "fail"}
+	if result, _ := handler(context.Background(), req); !result.IsError {
+		t.Fatal("expected the tool error to be returned")
+	}
+	if len(failures) != 2 || failures[0].Error() != "query failed" {
+		t.Errorf("OnError calls = %v, want each recorder notified of the tool error", failures)
+	}
+}
+
+func TestToolHookMiddlewareRejects(t *testing.T) {
+	called := false
+	next := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	}
+	var notified error
+	deny := ToolHookFuncs{
+		Pre: func(ctx context.Context, request *mcp.CallToolRequest) (context.Context, error) {
+			return nil, errors.New("tool disabled by policy")
+		},
+		OnErr: func(ctx context.Context, request mcp.CallToolRequest, err error) { notified = err },
+	}
+
+	result, err := ToolHookMiddleware(deny)(next)(context.Background(), mcp.CallToolRequest{})
+	if err != nil || !result.IsError || called {
+		t.Fatalf("result = %+v, err = %v, called = %v; want a rejection without calling the tool", result, err, called)
+	}
+	assertContentContains(t, result, "request rejected: tool disabled by policy")
+	if notified == nil {
+		t.Error("expected OnError to be notified of the rejection")
+	}
+}
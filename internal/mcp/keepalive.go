@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	methodNotificationProgress = "notifications/progress"
+	methodNotificationMessage  = "notifications/message"
+)
+
+// keepaliveLogger names the logger on keepalive log notifications
+const keepaliveLogger = "mcp-trino"
+
+// keepaliveMiddleware sends a notification every interval while a tool call
+// runs. Over streamable HTTP the first notification switches the response to
+// an SSE stream, so proxies and clients with idle timeouts keep seeing traffic
+// while a long query runs. Calls that carry a progress token get progress
+// notifications; other calls get debug-level log notifications, since the spec
+// only allows progress for requests that asked for it.
+func keepaliveMiddleware(interval time.Duration) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			srv := server.ServerFromContext(ctx)
+			if srv == nil {
+				return next(ctx, request)
+			}
+			var progressToken mcp.ProgressToken
+			if request.Params.Meta != nil {
+				progressToken = request.Params.Meta.ProgressToken
+			}
+
+			done := make(chan struct{})
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				start := time.Now()
+				for beat := 1; ; beat++ {
+					select {
+					case <-done:
+						return
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						sendKeepalive(ctx, srv, progressToken, beat, request.Params.Name, time.Since(start))
+					}
+				}
+			}()
+
+			result, err := next(ctx, request)
+			// Stop the ticker before returning so no notification trails the response
+			close(done)
+			wg.Wait()
+			return result, err
+		}
+	}
+}
+
+// sendKeepalive emits one keepalive notification. Failures are ignored: an
+// uninitialized session has nowhere to send them, and a full notification
+// channel already means traffic is flowing.
+func sendKeepalive(ctx context.Context, srv *server.MCPServer, progressToken mcp.ProgressToken, beat int, tool string, elapsed time.Duration) {
+	message := fmt.Sprintf("%s still running (%s elapsed)", tool, elapsed.Round(time.Second))
+	if progressToken != nil {
+		_ = srv.SendNotificationToClient(ctx, methodNotificationProgress, map[string]any{
+			"progressToken": progressToken,
+			"progress":      beat,
+			"message":       message,
+		})
+		return
+	}
+	_ = srv.SendNotificationToClient(ctx, methodNotificationMessage, map[string]any{
+		"level":  mcp.LoggingLevelDebug,
+		"logger": keepaliveLogger,
+		"data":   message,
+	})
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// fakeSession is an initialized client session that records notifications
+type fakeSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *fakeSession) Initialize()       {}
+func (s *fakeSession) Initialized() bool { return true }
+func (s *fakeSession) SessionID() string { return "keepalive-test" }
+func (s *fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+// callSlowTool runs a tool that takes duration through a server with
+// keepalives every interval, returning the notifications sent meanwhile.
+func callSlowTool(t *testing.T, duration, interval time.Duration, params string) []mcp.JSONRPCNotification {
+	t.Helper()
+	srv := mcpserver.NewMCPServer("test-server", "0.0.1",
+		mcpserver.WithToolCapabilities(true),
+		mcpserver.WithToolHandlerMiddleware(keepaliveMiddleware(interval)),
+	)
+	srv.AddTool(mcp.NewTool("slow"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		time.Sleep(duration)
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	session := &fakeSession{notifications: make(chan mcp.JSONRPCNotification, 100)}
+	ctx := srv.WithContext(context.Background(), session)
+	srv.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":`+params+`}`))
+
+	close(session.notifications)
+	var sent []mcp.JSONRPCNotification
+	for n := range session.notifications {
+		sent = append(sent, n)
+	}
+	return sent
+}
+
+func TestKeepaliveSendsProgressWithToken(t *testing.T) {
+	sent := callSlowTool(t, 120*time.Millisecond, 20*time.Millisecond,
+		`{"name":"slow","arguments":{},"_meta":{"progressToken":"tok-1"}}`)
+	if len(sent) < 2 {
+		t.Fatalf("expected several keepalives during a slow call, got %d", len(sent))
+	}
+	for i, n := range sent {
+		if n.Method != methodNotificationProgress {
+			t.Fatalf("notification %d method = %q, want %q", i, n.Method, methodNotificationProgress)
+		}
+		if token := n.Params.AdditionalFields["progressToken"]; token != "tok-1" {
+			t.Errorf("notification %d progressToken = %v, want tok-1", i, token)
+		}
+		if progress := n.Params.AdditionalFields["progress"]; progress != i+1 {
+			t.Errorf("notification %d progress = %v, want %d", i, progress, i+1)
+		}
+	}
+}
+
+func TestKeepaliveSendsLogWithoutToken(t *testing.T) {
+	sent := callSlowTool(t, 80*time.Millisecond, 20*time.Millisecond, `{"name":"slow","arguments":{}}`)
+	if len(sent) == 0 {
+		t.Fatal("expected keepalives during a slow call")
+	}
+	if sent[0].Method != methodNotificationMessage {
+		t.Fatalf("method = %q, want %q", sent[0].Method, methodNotificationMessage)
+	}
+	if level := sent[0].Params.AdditionalFields["level"]; level != mcp.LoggingLevelDebug {
+		t.Errorf("level = %v, want debug", level)
+	}
+}
+
+func TestKeepaliveQuietForFastCalls(t *testing.T) {
+	sent := callSlowTool(t, 0, time.Second, `{"name":"slow","arguments":{}}`)
+	if len(sent) != 0 {
+		t.Errorf("expected no keepalives for a fast call, got %d", len(sent))
+	}
+}
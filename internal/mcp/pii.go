@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/pii"
+)
+
+// newPIIScanner creates the scanner for MCP_PII_MODE, or returns nil when
+// scanning is off
+func newPIIScanner(cfg *config.TrinoConfig) *pii.Scanner {
+	switch cfg.PIIMode {
+	case "warn":
+		return pii.NewScanner(cfg.PIIColumns, false)
+	case "mask":
+		return pii.NewScanner(cfg.PIIColumns, true)
+	}
+	return nil
+}
+
+// piiMiddleware scans tool responses for likely personal data. Findings are
+// reported in a warning appended to the response; in mask mode the values
+// are redacted first. Chunks are skipped, since the full response was
+// scanned before it was split.
+func piiMiddleware(scanner *pii.Scanner) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || result.IsError || request.Params.Name == fetchResultChunkTool {
+				return result, err
+			}
+
+			findings := scanResult(scanner, result)
+			if len(findings) == 0 {
+				return result, nil
+			}
+			log.Printf("WARNING: %s response contains likely personal data (%d findings, masked: %t)", request.Params.Name, len(findings), scanner.Masks())
+
+			descriptions := make([]string, len(findings))
+			for i, finding := range findings {
+				descriptions[i] = finding.String()
+			}
+			warning := "PII warning: this response likely contains personal data: " + strings.Join(descriptions, "; ") + "."
+			if scanner.Masks() {
+				warning += " The values were masked."
+			} else {
+				warning += " Handle it according to your data policy and avoid repeating it."
+			}
+			result.Content = append(result.Content, mcp.NewTextContent(warning))
+			if structured, ok := result.StructuredContent.(map[string]interface{}); ok {
+				structured["pii_warnings"] = findings
+			}
+			return result, nil
+		}
+	}
+}
+
+// scanResult scans and, in mask mode, redacts a tool result in place. Text
+// content that holds JSON is scanned value by value, so object keys count as
+// column names. Structured content duplicates the text for our tools, so its
+// findings are only reported when there is no text.
+func scanResult(scanner *pii.Scanner, result *mcp.CallToolResult) []pii.Finding {
+	var findings []pii.Finding
+	for i, content := range result.Content {
+		tc, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		var textFindings []pii.Finding
+		if value, ok := decodeJSON([]byte(tc.Text)); ok {
+			var scanned interface{}
+			scanned, textFindings = scanner.Scan(value)
+			if scanner.Masks() && len(textFindings) > 0 {
+				if data, err := json.MarshalIndent(scanned, "", "  "); err == nil {
+					tc.Text = string(data)
+				}
+			}
+		} else {
+			tc.Text, textFindings = scanner.ScanText(tc.Text)
+		}
+		result.Content[i] = tc
+		findings = append(findings, textFindings...)
+	}
+
+	if result.StructuredContent != nil {
+		data, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			return findings
+		}
+		value, ok := decodeJSON(data)
+		if !ok {
+			return findings
+		}
+		scanned, structuredFindings := scanner.Scan(value)
+		if scanner.Masks() && len(structuredFindings) > 0 {
+			result.StructuredContent = scanned
+		}
+		if len(findings) == 0 {
+			findings = structuredFindings
+		}
+	}
+	return findings
+}
+
+// decodeJSON decodes a complete JSON document, keeping numbers exact
+func decodeJSON(data []byte) (interface{}, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return nil, false
+	}
+	return value, true
+}
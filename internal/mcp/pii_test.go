@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/pii"
+)
+
+const piiRows = `[
+  {
+    "id": 12345678901234567890,
+    "email": "alice@example.com"
+  }
+]`
+
+func TestPIIMiddlewareWarns(t *testing.T) {
+	handler := piiMiddleware(pii.NewScanner(nil, false))(textHandler(piiRows))
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != piiRows {
+		t.Errorf("warn mode changed the response: %s", text)
+	}
+	assertContentContains(t, result, `PII warning: this response likely contains personal data: 1 values in column "email" look like email addresses`)
+}
+
+func TestPIIMiddlewareMasks(t *testing.T) {
+	structured := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		rows := map[string]interface{}{"results": []interface{}{map[string]interface{}{"ssn": "123-45-6789"}}}
+		return mcp.NewToolResultStructured(rows, piiRows), nil
+	}
+	handler := piiMiddleware(pii.NewScanner([]string{"ssn"}, true))(structured)
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if strings.Contains(text, "alice@example.com") || !strings.Contains(text, "[REDACTED:email]") {
+		t.Errorf("text not masked: %s", text)
+	}
+	if !strings.Contains(text, "12345678901234567890") {
+		t.Errorf("masking changed a number: %s", text)
+	}
+	sc := structuredMap(t, result)
+	row := sc["results"].([]interface{})[0].(map[string]interface{})
+	if row["ssn"] != "[REDACTED:ssn]" {
+		t.Errorf("structured content not masked: %v", row)
+	}
+	if sc["pii_warnings"] == nil {
+		t.Error("expected pii_warnings in structured content")
+	}
+	assertContentContains(t, result, "The values were masked.")
+}
+
+func TestPIIMiddlewareSkipsCleanAndChunkResponses(t *testing.T) {
+	handler := piiMiddleware(pii.NewScanner(nil, true))(textHandler(`[{"id": 1}]`))
+	result, _ := handler(context.Background(), mcp.CallToolRequest{})
+	if len(result.Content) != 1 {
+		t.Errorf("clean response got %d content items, want it unchanged", len(result.Content))
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = fetchResultChunkTool
+	handler = piiMiddleware(pii.NewScanner(nil, true))(textHandler(`"alice@exa`))
+	result, _ = handler(context.Background(), req)
+	if len(result.Content) != 1 {
+		t.Error("chunk responses must not be rescanned")
+	}
+}
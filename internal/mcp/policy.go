@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/state"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+const (
+	// policyAuthorizationTTL bounds how long the policy page and an accepted
+	// acknowledgment stay valid, which covers a login at the identity provider
+	policyAuthorizationTTL = 10 * time.Minute
+
+	policyNonceKeyPrefix   = "policy:nonce:"   // policy page nonce -> original authorize query
+	policyPendingKeyPrefix = "policy:pending:" // PKCE code challenge -> accepted policy version
+	policyAckKeyPrefix     = "policy:ack:"     // user -> latest acknowledgment
+)
+
+// usagePolicy is the data-usage policy users acknowledge before tokens are
+// issued
+type usagePolicy struct {
+	text    string // empty when the policy file could not be read
+	version string
+}
+
+// policyAcknowledgment is the record kept for each user
+type policyAcknowledgment struct {
+	Version    string    `json:"version"`
+	Time       time.Time `json:"time"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+}
+
+// newUsagePolicy loads the configured policy, or returns nil when no
+// acknowledgment is required. A policy that fails to load is still returned,
+// so authorization fails closed instead of skipping the acknowledgment.
+func newUsagePolicy(cfg *config.TrinoConfig) *usagePolicy {
+	if cfg.UsagePolicyFile == "" || !cfg.OAuthEnabled || cfg.OAuthMode != "proxy" {
+		return nil
+	}
+	data, err := os.ReadFile(cfg.UsagePolicyFile) // #nosec G304 -- operator-configured policy file
+	if err == nil && len(bytes.TrimSpace(data)) == 0 {
+		err = errors.New("policy file is empty")
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to load usage policy from %s, authorization is disabled until it loads: %v", cfg.UsagePolicyFile, err)
+		return &usagePolicy{}
+	}
+	version := cfg.UsagePolicyVersion
+	if version == "" {
+		sum := sha256.Sum256(data)
+		version = hex.EncodeToString(sum[:6])
+	}
+	log.Printf("INFO: Usage policy version %s loaded from %s", version, cfg.UsagePolicyFile)
+	return &usagePolicy{text: string(data), version: version}
+}
+
+// policyGate puts the usage policy acknowledgment in front of the OAuth
+// proxy endpoints. The authorize endpoint shows the policy first; accepting
+// it records a pending acknowledgment under the client's PKCE code
+// challenge. The token endpoint issues tokens only for an authorization with
+// a pending acknowledgment, then records it for the authenticated user.
+// State lives in the state store, so each step may reach a different replica.
+type policyGate struct {
+	policy *usagePolicy
+	store  state.Store
+	oauth  *oauthHandle
+}
+
+// ServeHTTP implements http.Handler
+func (g *policyGate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/oauth/authorize":
+		g.handleAuthorize(w, r)
+	case "/oauth/token":
+		g.handleToken(w, r)
+	default:
+		g.oauth.ServeHTTP(w, r)
+	}
+}
+
+// handleAuthorize shows the policy page, and on acceptance continues the
+// authorization with the original request
+func (g *policyGate) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	if g.policy.text == "" {
+		http.Error(w, "usage policy unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), stateStoreTimeout)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		challenge := r.URL.Query().Get("code_challenge")
+		if challenge == "" {
+			http.Error(w, "usage policy acknowledgment requires PKCE (code_challenge)", http.StatusBadRequest)
+			return
+		}
+		nonce, err := newPolicyNonce()
+		if err == nil {
+			err = g.store.Set(ctx, policyNonceKeyPrefix+nonce, []byte(r.URL.RawQuery), policyAuthorizationTTL)
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to start usage policy acknowledgment: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		g.renderPolicy(w, nonce)
+
+	case http.MethodPost:
+		nonce := r.PostFormValue("nonce")
+		rawQuery, err := g.store.Get(ctx, policyNonceKeyPrefix+nonce)
+		if nonce == "" || errors.Is(err, state.ErrNotFound) {
+			http.Error(w, "the usage policy page has expired; restart the login from your client", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to look up usage policy acknowledgment: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		_ = g.store.Delete(ctx, policyNonceKeyPrefix+nonce)
+		query, err := url.ParseQuery(string(rawQuery))
+		if err != nil {
+			http.Error(w, "invalid authorization request", http.StatusBadRequest)
+			return
+		}
+		if err := g.store.Set(ctx, policyPendingKeyPrefix+query.Get("code_challenge"), []byte(g.policy.version), policyAuthorizationTTL); err != nil {
+			log.Printf("ERROR: Failed to record usage policy acknowledgment: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		// Continue with the original authorization request
+		authorize := r.Clone(r.Context())
+		authorize.Method = http.MethodGet
+		authorize.URL.RawQuery = string(rawQuery)
+		authorize.Body = http.NoBody
+		authorize.ContentLength = 0
+		captured := &capturedResponse{header: w.Header(), status: http.StatusOK}
+		g.oauth.ServeHTTP(captured, authorize)
+		if captured.status == http.StatusTemporaryRedirect || captured.status == http.StatusPermanentRedirect {
+			// The browser must follow with a GET, not repeat the form POST
+			captured.status = http.StatusSeeOther
+		}
+		captured.writeTo(w)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleToken issues tokens only for authorizations whose policy was
+// accepted, then records the acknowledgment for the user
+func (g *policyGate) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		g.oauth.ServeHTTP(w, r)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), stateStoreTimeout)
+	defer cancel()
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	verifier := r.PostFormValue("code_verifier")
+	pendingKey, version := "", ""
+	if verifier != "" {
+		// S256 challenges are checked first; plain challenges equal the verifier
+		sum := sha256.Sum256([]byte(verifier))
+		for _, challenge := range []string{base64.RawURLEncoding.EncodeToString(sum[:]), verifier} {
+			if value, err := g.store.Get(ctx, policyPendingKeyPrefix+challenge); err == nil {
+				pendingKey, version = policyPendingKeyPrefix+challenge, string(value)
+				break
+			}
+		}
+	}
+	if pendingKey == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error":             "invalid_grant",
+			"error_description": "the usage policy was not acknowledged for this authorization",
+		})
+		return
+	}
+	_ = g.store.Delete(ctx, pendingKey)
+
+	// Capture the token response to identify the user it was issued to
+	captured := &capturedResponse{header: w.Header(), status: http.StatusOK}
+	g.oauth.ServeHTTP(captured, r)
+	if captured.status == http.StatusOK {
+		g.recordAcknowledgment(r, captured.body.Bytes(), version)
+	}
+	captured.writeTo(w)
+}
+
+// recordAcknowledgment stores the acknowledgment under the user the token
+// response was issued to
+func (g *policyGate) recordAcknowledgment(r *http.Request, tokenResponse []byte, version string) {
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(tokenResponse, &token); err != nil || token.AccessToken == "" {
+		log.Printf("WARNING: Usage policy %s was acknowledged, but the token response could not be read", version)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), stateStoreTimeout)
+	defer cancel()
+	user, err := g.oauth.current.Load().server.ValidateTokenCached(ctx, token.AccessToken)
+	if err != nil {
+		log.Printf("WARNING: Usage policy %s was acknowledged, but the issued token could not be attributed: %v", version, err)
+		return
+	}
+	username := trino.UserIdentity(oauth.WithUser(ctx, user))
+	ack := policyAcknowledgment{Version: version, Time: time.Now().UTC(), RemoteAddr: r.RemoteAddr}
+	data, _ := json.Marshal(ack)
+	if err := g.store.Set(ctx, policyAckKeyPrefix+username, data, 0); err != nil {
+		log.Printf("ERROR: Failed to record usage policy acknowledgment for %s: %v", username, err)
+	}
+	log.Printf("INFO: %s acknowledged usage policy version %s", username, version)
+}
+
+// capturedResponse buffers a response so it can be inspected before it is
+// written
+type capturedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (c *capturedResponse) Header() http.Header         { return c.header }
+func (c *capturedResponse) Write(p []byte) (int, error) { return c.body.Write(p) }
+func (c *capturedResponse) WriteHeader(status int)      { c.status = status }
+
+// writeTo sends the buffered status and body; headers were written through
+func (c *capturedResponse) writeTo(w http.ResponseWriter) {
+	w.WriteHeader(c.status)
+	_, _ = w.Write(c.body.Bytes())
+}
+
+// newPolicyNonce returns a random identifier for one showing of the policy
+func newPolicyNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+var policyPage = template.Must(template.New("policy").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Data usage policy</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #1f2328; }
+.policy { white-space: pre-wrap; border: 1px solid #d0d7de; border-radius: 6px; padding: 1rem; max-height: 60vh; overflow-y: auto; }
+.version { color: #59636e; font-size: 0.875rem; }
+button { margin-top: 1rem; padding: 0.5rem 1.25rem; font-size: 1rem; }
+</style>
+</head>
+<body>
+<h1>Data usage policy</h1>
+<p>Read and accept the policy below to give your AI client access to warehouse data.</p>
+<div class="policy">{{.Text}}</div>
+<p class="version">Policy version {{.Version}}</p>
+<form method="post" action="/oauth/authorize">
+<input type="hidden" name="nonce" value="{{.Nonce}}">
+<button type="submit">I accept</button>
+</form>
+<p>If you do not accept, close this window.</p>
+</body>
+</html>
+`))
+
+// renderPolicy writes the policy page
+func (g *policyGate) renderPolicy(w http.ResponseWriter, nonce string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+	data := struct{ Text, Version, Nonce string }{strings.TrimSpace(g.policy.text), g.policy.version, nonce}
+	if err := policyPage.Execute(w, data); err != nil {
+		log.Printf("Error rendering usage policy page: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package mcp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/state"
+)
+
+// hmacToken signs a JWT the hmac provider accepts
+func hmacToken(t *testing.T, secret, audience, username string) string {
+	t.Helper()
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signingInput := encode(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encode(map[string]interface{}{
+		"sub": username, "preferred_username": username, "aud": audience, "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newTestPolicyGate(t *testing.T, store state.Store) *policyGate {
+	t.Helper()
+	secret := strings.Repeat("k", 32)
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": hmacToken(t, secret, "trino", "alice"), "token_type": "Bearer", "expires_in": 3600,
+		})
+	}))
+	t.Cleanup(idp.Close)
+
+	cfg := &config.TrinoConfig{
+		OAuthEnabled: true, OAuthMode: "proxy", OAuthProvider: "hmac", OIDCIssuer: idp.URL, OIDCAudience: "trino",
+		OIDCClientID: "mcp-trino", OIDCClientSecret: "client-secret",
+		OAuthRedirectURIs: "https://mcp.example.com/oauth/callback", JWTSecret: secret,
+		UsagePolicyFile: filepath.Join(t.TempDir(), "policy.txt"), UsagePolicyVersion: "2026-10",
+	}
+	if err := os.WriteFile(cfg.UsagePolicyFile, []byte("Do not export <customer> data.\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	server, err := newOAuthServer(cfg, store)
+	if err != nil {
+		t.Fatalf("newOAuthServer() error = %v", err)
+	}
+	return &policyGate{policy: newUsagePolicy(cfg), store: store, oauth: newOAuthHandle(server)}
+}
+
+func TestPolicyGateFlow(t *testing.T) {
+	store := state.NewMemoryStore()
+	gate := newTestPolicyGate(t, store)
+
+	verifier := strings.Repeat("v", 43)
+	sum := sha256.Sum256([]byte(verifier))
+	authorize := url.Values{
+		"client_id":             {"client"},
+		"redirect_uri":          {"http://localhost:3000/callback"},
+		"state":                 {"xyz"},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(sum[:])},
+		"code_challenge_method": {"S256"},
+	}
+	token := func() *httptest.ResponseRecorder {
+		form := url.Values{"grant_type": {"authorization_code"}, "code": {"abc"}, "code_verifier": {verifier}}
+		req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		gate.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Tokens are refused until the policy is accepted
+	if rec := token(); rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "usage policy") {
+		t.Fatalf("token before acknowledgment = %d %s", rec.Code, rec.Body.String())
+	}
+
+	// The authorize endpoint shows the policy instead of redirecting
+	rec := httptest.NewRecorder()
+	gate.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+authorize.Encode(), nil))
+	page := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(page, "Do not export &lt;customer&gt; data.") || !strings.Contains(page, "2026-10") {
+		t.Fatalf("policy page = %d %s", rec.Code, page)
+	}
+	nonce := regexp.MustCompile(`name="nonce" value="([0-9a-f]+)"`).FindStringSubmatch(page)
+	if nonce == nil {
+		t.Fatalf("policy page has no nonce: %s", page)
+	}
+
+	// Accepting continues to the identity provider
+	accept := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(url.Values{"nonce": {nonce[1]}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		gate.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := accept(); rec.Code != http.StatusSeeOther || !strings.Contains(rec.Header().Get("Location"), "/oauth2/v1/authorize") {
+		t.Fatalf("accept = %d %s (Location %q)", rec.Code, rec.Body.String(), rec.Header().Get("Location"))
+	}
+	if rec := accept(); rec.Code != http.StatusBadRequest {
+		t.Errorf("reusing the policy page = %d, want 400", rec.Code)
+	}
+
+	// The token exchange now succeeds and records the acknowledgment
+	if rec := token(); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "access_token") {
+		t.Fatalf("token after acknowledgment = %d %s", rec.Code, rec.Body.String())
+	}
+	data, err := store.Get(t.Context(), policyAckKeyPrefix+"alice")
+	if err != nil {
+		t.Fatalf("acknowledgment not recorded: %v", err)
+	}
+	var ack policyAcknowledgment
+	if err := json.Unmarshal(data, &ack); err != nil || ack.Version != "2026-10" {
+		t.Errorf("acknowledgment = %s, %v", data, err)
+	}
+
+	// Each acknowledgment covers one authorization
+	if rec := token(); rec.Code != http.StatusBadRequest {
+		t.Errorf("second token exchange = %d, want 400", rec.Code)
+	}
+}
+
+func TestPolicyGateRequiresPKCE(t *testing.T) {
+	gate := newTestPolicyGate(t, state.NewMemoryStore())
+	rec := httptest.NewRecorder()
+	gate.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth/authorize?client_id=client&redirect_uri=http://localhost:3000/callback", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "PKCE") {
+		t.Errorf("authorize without PKCE = %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestNewUsagePolicyFailsClosed(t *testing.T) {
+	cfg := &config.TrinoConfig{OAuthEnabled: true, OAuthMode: "proxy", UsagePolicyFile: filepath.Join(t.TempDir(), "missing.txt")}
+	policy := newUsagePolicy(cfg)
+	if policy == nil {
+		t.Fatal("newUsagePolicy() = nil for an unreadable policy")
+	}
+	gate := &policyGate{policy: policy, store: state.NewMemoryStore()}
+	rec := httptest.NewRecorder()
+	gate.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth/authorize?code_challenge=x", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("authorize with unreadable policy = %d, want 503", rec.Code)
+	}
+
+	cfg.OAuthMode = "native"
+	if newUsagePolicy(cfg) != nil {
+		t.Error("newUsagePolicy() in native mode != nil")
+	}
+}
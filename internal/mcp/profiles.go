@@ -0,0 +1,161 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/profiles"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+// profileArgument is the tool argument that selects an environment profile
+const profileArgument = "profile"
+
+// profileRouter runs tool calls that name a profile against that profile's
+// cluster and allowlists. Calls without a profile use the server's own.
+type profileRouter struct {
+	names    []string // in file order, for the argument's enum
+	profiles map[string]*boundTools
+}
+
+// newProfileRouter loads the configured profiles, or returns nil when
+// profiles are disabled. Profiles that fail to load are left out, so calls
+// that name them are rejected rather than run elsewhere.
+func newProfileRouter(cfg *config.TrinoConfig, components serverComponents) *profileRouter {
+	if cfg.ProfilesFile == "" {
+		return nil
+	}
+	router := &profileRouter{profiles: make(map[string]*boundTools)}
+	loaded, err := profiles.Load(cfg.ProfilesFile)
+	if err != nil {
+		log.Printf("ERROR: Failed to load profiles from %s, tool calls naming a profile will be rejected: %v", cfg.ProfilesFile, err)
+		return router
+	}
+
+	for _, profile := range loaded {
+		router.names = append(router.names, profile.Name)
+		profileCfg, err := profile.Config(cfg)
+		if err != nil {
+			log.Printf("ERROR: Failed to configure profile %s, its tool calls will be rejected: %v", profile.Name, err)
+			continue
+		}
+		client, err := trino.NewClientWithLimits(profileCfg, components.limits)
+		if err != nil {
+			log.Printf("ERROR: Failed to create Trino client for profile %s, its tool calls will be rejected: %v", profile.Name, err)
+			continue
+		}
+
+		handlers := NewTrinoHandlers(client, profileCfg)
+		handlers.ResultPager = components.resultPager
+		handlers.ResultChunker = components.resultChunker
+		handlers.Checks = components.checks
+		handlers.Subscriptions = components.subscriptions
+		handlers.History = components.history
+		handlers.Redactor = components.redactor
+		handlers.Sessions = components.stateStore
+		handlers.ProfileClient = router.client
+		tools := mcpserver.NewMCPServer("profile "+profile.Name, "", mcpserver.WithToolCapabilities(true))
+		RegisterTrinoTools(tools, handlers)
+		router.profiles[profile.Name] = &boundTools{client: client, tools: tools}
+	}
+	log.Printf("INFO: Environment profiles available: %s", strings.Join(router.names, ", "))
+	return router
+}
+
+// addArgument adds the profile argument to every tool registered on s
+func (r *profileRouter) addArgument(s *mcpserver.MCPServer) {
+	if len(r.names) == 0 {
+		return
+	}
+	enum := make([]any, len(r.names))
+	for i, name := range r.names {
+		enum[i] = name
+	}
+	for _, tool := range s.ListTools() {
+		updated := tool.Tool
+		properties := make(map[string]any, len(updated.InputSchema.Properties)+1)
+		for key, value := range updated.InputSchema.Properties {
+			properties[key] = value
+		}
+		properties[profileArgument] = map[string]any{
+			"type":        "string",
+			"description": "Environment profile to run against, such as staging to validate a query before prod. Omit to use the server's default cluster.",
+			"enum":        enum,
+		}
+		updated.InputSchema.Properties = properties
+		s.AddTool(updated, tool.Handler)
+	}
+}
+
+// middleware replaces the server's tool handlers with the named profile's
+func (r *profileRouter) middleware() mcpserver.ToolHandlerMiddleware {
+	return func(next mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			name, _ := request.GetArguments()[profileArgument].(string)
+			if name == "" {
+				return next(ctx, request)
+			}
+			tool, err := r.route(name, request)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr(err.Error(), err), nil
+			}
+			return tool.Handler(ctx, request)
+		}
+	}
+}
+
+// route finds the profile's handler for a tool call
+func (r *profileRouter) route(name string, request mcp.CallToolRequest) (*mcpserver.ServerTool, error) {
+	profile, err := r.profile(name)
+	if err != nil {
+		return nil, err
+	}
+	tool := profile.tools.GetTool(request.Params.Name)
+	if tool == nil {
+		return nil, fmt.Errorf("tool %s is not available for profile %s", request.Params.Name, name)
+	}
+	return tool, nil
+}
+
+// profile finds a profile that loaded
+func (r *profileRouter) profile(name string) (*boundTools, error) {
+	profile, ok := r.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q is not available (available: %s)", name, strings.Join(r.available(), ", "))
+	}
+	return profile, nil
+}
+
+// client returns the Trino client of a profile that loaded
+func (r *profileRouter) client(name string) (*trino.Client, error) {
+	profile, err := r.profile(name)
+	if err != nil {
+		return nil, err
+	}
+	return profile.client, nil
+}
+
+// available lists the profiles that loaded, in file order
+func (r *profileRouter) available() []string {
+	var names []string
+	for _, name := range r.names {
+		if _, ok := r.profiles[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Close closes the profiles' Trino clients
+func (r *profileRouter) Close() {
+	for name, profile := range r.profiles {
+		if err := profile.client.Close(); err != nil {
+			log.Printf("Error closing Trino client for profile %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func newTestProfileRouter(t *testing.T) *profileRouter {
+	t.Helper()
+	profilesFile := filepath.Join(t.TempDir(), "profiles.yaml")
+	if err := os.WriteFile(profilesFile, []byte("profiles:\n  - name: staging\n    trino: {catalog: iceberg_staging}\n    allowed_catalogs: [iceberg_staging]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.TrinoConfig{
+		Host: "localhost", Port: 1, Scheme: "http", User: "svc", Catalog: "hive", Schema: "default",
+		ProfilesFile: profilesFile,
+	}
+	router := newProfileRouter(cfg, serverComponents{})
+	t.Cleanup(router.Close)
+	return router
+}
+
+func TestProfileRouterRoute(t *testing.T) {
+	router := newTestProfileRouter(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "list_catalogs"
+	if tool, err := router.route("staging", request); err != nil || tool == nil {
+		t.Fatalf("route() = %v, %v; want the staging tool", tool, err)
+	}
+	if _, err := router.route("prod", request); err == nil || !strings.Contains(err.Error(), "available: staging") {
+		t.Errorf("route() error = %v, want the unknown profile rejected", err)
+	}
+	request.Params.Name = "drop_everything"
+	if _, err := router.route("staging", request); err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Errorf("route() error = %v, want the unknown tool rejected", err)
+	}
+}
+
+func TestProfileRouterClient(t *testing.T) {
+	router := newTestProfileRouter(t)
+
+	if client, err := router.client("staging"); err != nil || client == nil || client != router.profiles["staging"].client {
+		t.Errorf("client() = %v, %v; want the staging client", client, err)
+	}
+	if _, err := router.client("prod"); err == nil {
+		t.Error("client() succeeded for an unknown profile")
+	}
+}
+
+func TestProfileRouterMiddlewareDefaultsToServer(t *testing.T) {
+	router := newTestProfileRouter(t)
+
+	called := false
+	next := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("default"), nil
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "list_catalogs"
+	request.Params.Arguments = map[string]interface{}{}
+	if _, err := router.middleware()(next)(context.Background(), request); err != nil || !called {
+		t.Fatalf("middleware() called default handler = %t, err = %v", called, err)
+	}
+
+	called = false
+	request.Params.Arguments = map[string]interface{}{"profile": "prod"}
+	result, err := router.middleware()(next)(context.Background(), request)
+	if err != nil || called || result == nil || !result.IsError {
+		t.Errorf("middleware() = %+v, %v; want an error result for an unknown profile", result, err)
+	}
+}
+
+func TestProfileRouterAddArgument(t *testing.T) {
+	router := newTestProfileRouter(t)
+	s := mcpserver.NewMCPServer("test", "1.0.0", mcpserver.WithToolCapabilities(true))
+	RegisterTrinoTools(s, NewTrinoHandlers(nil, &config.TrinoConfig{}))
+
+	router.addArgument(s)
+	for name, tool := range s.ListTools() {
+		property, ok := tool.Tool.InputSchema.Properties["profile"].(map[string]any)
+		if !ok {
+			t.Errorf("tool %s has no profile argument", name)
+			continue
+		}
+		if enum, _ := property["enum"].([]any); len(enum) != 1 || enum[0] != "staging" {
+			t.Errorf("tool %s profile enum = %v, want [staging]", name, property["enum"])
+		}
+	}
+	if query := s.GetTool("execute_query").Tool.InputSchema.Properties["query"]; query == nil {
+		t.Error("addArgument() dropped the existing arguments")
+	}
+}
@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/ratelimit"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+// rateLimitMiddleware rejects tool calls once the caller's identity exceeds
+// its quota. It must run after the OAuth middleware so the user is in context.
+func rateLimitMiddleware(limiter ratelimit.Limiter) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			identity := trino.UserIdentity(ctx)
+
+			decision, err := limiter.Allow(ctx, identity)
+			if err != nil {
+				// Limiter failures must not take the server down; let the call through
+				log.Printf("WARNING: Rate limit check failed for %s: %v", identity, err)
+				return next(ctx, request)
+			}
+			if !decision.Allowed {
+				log.Printf("Rate limit exceeded for %s on tool %s", identity, request.Params.Name)
+				mcpErr := fmt.Errorf("rate limit exceeded: %d requests per minute allowed; retry in %d seconds",
+					decision.Limit, int(math.Ceil(decision.RetryAfter.Seconds())))
+				return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
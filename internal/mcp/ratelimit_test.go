@@ -0,0 +1,41 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/ratelimit"
+)
+
+// TestRateLimitMiddleware verifies that calls beyond the quota return a tool
+// error without invoking the wrapped handler.
+func TestRateLimitMiddleware(t *testing.T) {
+	calls := 0
+	next := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("ok"), nil
+	}
+	handler := rateLimitMiddleware(ratelimit.NewLocalLimiter(60, 1))(next)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "execute_query"
+
+	result, err := handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("first call = (%+v, %v), want success", result, err)
+	}
+
+	result, err = handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("second call returned error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected second call to be rate limited")
+	}
+	assertContentContains(t, result, "rate limit exceeded")
+
+	if calls != 1 {
+		t.Errorf("wrapped handler called %d times, want 1", calls)
+	}
+}
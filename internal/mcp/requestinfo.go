@@ -0,0 +1,39 @@
+package mcp
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+// hasClientHeaderTemplates reports whether any client identification header
+// template is configured
+func hasClientHeaderTemplates(cfg *config.TrinoConfig) bool {
+	return cfg.TrinoClientInfo != "" || cfg.TrinoTraceToken != "" || len(cfg.TrinoHeaders) > 0
+}
+
+// requestInfoMiddleware tags each tool call's context with its session,
+// JSON-RPC request ID, and tool name, which fill the client identification
+// header templates of the Trino requests the call makes
+func requestInfoMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			info := trino.RequestInfo{SessionID: sessionIDFromContext(ctx), Tool: request.Params.Name}
+			if request.Params.Meta != nil {
+				// Tagged by the cancellation hook as the session ID, "|", and the JSON ID
+				if key, ok := request.Params.Meta.AdditionalFields[requestIDMetaKey].(string); ok {
+					info.RequestID = strings.TrimPrefix(key, info.SessionID+"|")
+					if unquoted, err := strconv.Unquote(info.RequestID); err == nil {
+						info.RequestID = unquoted
+					}
+				}
+			}
+			return next(trino.WithRequestInfo(ctx, info), request)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+func TestRequestInfoMiddleware(t *testing.T) {
+	ctx := mcpserver.NewMCPServer("test-server", "0.0.1").WithContext(context.Background(), &fakeSession{})
+	tests := []struct {
+		name string
+		id   any
+		want trino.RequestInfo
+	}{
+		{"numeric ID", 7, trino.RequestInfo{SessionID: "keepalive-test", RequestID: "7", Tool: "execute_query"}},
+		{"string ID", "req-7", trino.RequestInfo{SessionID: "keepalive-test", RequestID: "req-7", Tool: "execute_query"}},
+		{"untagged call", nil, trino.RequestInfo{SessionID: "keepalive-test", Tool: "execute_query"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Name = "execute_query"
+			if key, ok := requestKey(ctx, tt.id); ok {
+				req.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{requestIDMetaKey: key}}
+			}
+
+			var got trino.RequestInfo
+			handler := requestInfoMiddleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				got, _ = trino.GetRequestInfo(ctx)
+				return mcp.NewToolResultText("ok"), nil
+			})
+			if _, err := handler(ctx, req); err != nil {
+				t.Fatalf("handler returned unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("request info = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/compact"
+	"github.com/tuannvm/mcp-trino/internal/resultstore"
+	"github.com/tuannvm/mcp-trino/internal/state"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+// responseModeKeyPrefix keys a session's response mode in the state store
+const responseModeKeyPrefix = "response-mode:"
+
+// responseMode is how a caller wants query results returned
+type responseMode struct {
+	Verbosity string          `json:"verbosity"`
+	Options   compact.Options `json:"options"`
+}
+
+// responseMode resolves the caller's response mode: the call's verbosity
+// argument, over the session's mode set with set_response_mode, over full
+func (h *TrinoHandlers) responseMode(ctx context.Context, args map[string]interface{}) (responseMode, error) {
+	mode := h.sessionResponseMode(ctx)
+	if verbosity, ok := args["verbosity"].(string); ok && verbosity != "" {
+		if verbosity != compact.VerbosityFull && verbosity != compact.VerbosityCompact {
+			return mode, fmt.Errorf("verbosity must be %s or %s", compact.VerbosityFull, compact.VerbosityCompact)
+		}
+		mode.Verbosity = verbosity
+	}
+	return mode, nil
+}
+
+// sessionResponseMode returns the mode stored for the caller's session, or
+// full when none is stored or it cannot be read
+func (h *TrinoHandlers) sessionResponseMode(ctx context.Context) responseMode {
+	mode := responseMode{Verbosity: compact.VerbosityFull}
+	sessionID := sessionIDFromContext(ctx)
+	if h.Sessions == nil || sessionID == "" {
+		return mode
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, stateStoreTimeout)
+	defer cancel()
+	data, err := h.Sessions.Get(ctx, responseModeKeyPrefix+sessionID)
+	if errors.Is(err, state.ErrNotFound) {
+		return mode
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &mode)
+	}
+	if err != nil {
+		log.Printf("WARNING: Failed to read the response mode of session %s, returning full results: %v", sessionID, err)
+		return responseMode{Verbosity: compact.VerbosityFull}
+	}
+	return mode
+}
+
+// sessionIDFromContext returns the caller's MCP session ID, or ""
+func sessionIDFromContext(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// SetResponseMode handles choosing how the session's query results are returned
+func (h *TrinoHandlers) SetResponseMode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	mode := responseMode{}
+	mode.Verbosity, _ = args["verbosity"].(string)
+	if mode.Verbosity != compact.VerbosityFull && mode.Verbosity != compact.VerbosityCompact {
+		mcpErr := fmt.Errorf("verbosity must be %s or %s", compact.VerbosityFull, compact.VerbosityCompact)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	if v, ok := args["max_columns"].(float64); ok {
+		mode.Options.MaxColumns = int(v)
+	}
+	if v, ok := args["max_value_length"].(float64); ok {
+		mode.Options.MaxValueLen = int(v)
+	}
+	if v, ok := args["max_rows"].(float64); ok {
+		mode.Options.MaxRows = int(v)
+	}
+	if err := mode.Options.Validate(); err != nil {
+		return mcp.NewToolResultErrorFromErr(err.Error(), err), nil
+	}
+
+	sessionID := sessionIDFromContext(ctx)
+	if h.Sessions == nil || sessionID == "" {
+		mcpErr := fmt.Errorf("this connection has no session to keep a response mode for; pass verbosity to execute_query on each call instead")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	storeCtx, cancel := context.WithTimeout(ctx, stateStoreTimeout)
+	defer cancel()
+	var err error
+	if mode.Verbosity == compact.VerbosityFull {
+		mode.Options = compact.Options{}
+		err = h.Sessions.Delete(storeCtx, responseModeKeyPrefix+sessionID)
+	} else {
+		var data []byte
+		if data, err = json.Marshal(mode); err == nil {
+			err = h.Sessions.Set(storeCtx, responseModeKeyPrefix+sessionID, data, sessionTTL)
+		}
+	}
+	if err != nil {
+		log.Printf("Error storing response mode: %v", err)
+		mcpErr := fmt.Errorf("failed to store response mode: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	result := map[string]interface{}{"verbosity": mode.Verbosity, "options": mode.Options}
+	if mode.Verbosity == compact.VerbosityCompact {
+		result["message"] = `Query results in this session are now compact. Pass verbosity "full" to execute_query for a complete result.`
+	} else {
+		result["message"] = "Query results in this session are now returned in full."
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal response mode to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(result, string(jsonData)), nil
+}
+
+// compactQueryResult returns a compact view of an execute_query result. Paged
+// and spilled results are still stored in full, so their pages stay
+// available through get_query_results.
+func (h *TrinoHandlers) compactQueryResult(ctx context.Context, qr *trino.QueryResult, opts compact.Options) (*mcp.CallToolResult, error) {
+	columns := make([]string, len(qr.Columns))
+	for i, column := range qr.Columns {
+		columns[i] = column.Name
+	}
+
+	var meta *resultstore.Meta
+	var view *compact.Result
+	var err error
+	switch {
+	case qr.Spill != nil:
+		if h.ResultPager != nil {
+			if meta, err = h.ResultPager.SaveFrom(ctx, trino.UserIdentity(ctx), qr.Spill.Iterate, qr.Truncated); err != nil {
+				break
+			}
+		}
+		view, err = compact.CompactFrom(columns, qr.Spill.Iterate, opts)
+	case h.ResultPager != nil && len(qr.Rows) > h.ResultPager.PageSize():
+		if meta, err = h.ResultPager.Save(ctx, trino.UserIdentity(ctx), qr.Rows, qr.Truncated); err != nil {
+			break
+		}
+		view = compact.Compact(columns, qr.Rows, opts)
+	default:
+		view = compact.Compact(columns, qr.Rows, opts)
+	}
+	if err != nil {
+		log.Printf("Error compacting query result: %v", err)
+		mcpErr := fmt.Errorf("failed to store query results: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	if qr.Truncated {
+		view.Message += fmt.Sprintf(" The result was truncated to %d rows before compacting.", qr.MaxRows)
+	}
+	return compactResult(view, meta, 0, qr.Truncated)
+}
+
+// compactResult builds a compact tool result. Paging metadata is added when
+// the full result is stored; page is the page compacted, or 0 for all of it.
+// The text is unindented JSON to save tokens.
+func compactResult(view *compact.Result, meta *resultstore.Meta, page int, truncated bool) (*mcp.CallToolResult, error) {
+	if meta != nil && page == 0 {
+		view.Message += fmt.Sprintf(" The full result is stored as query_id %q in %d pages; call get_query_results to read them.", meta.QueryID, meta.PageCount)
+	}
+	data, err := json.Marshal(view)
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal results to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Structured content is a map, like the other result envelopes, so the
+	// PII and history middleware can annotate it. Numbers are kept exact.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var structured map[string]interface{}
+	if err := decoder.Decode(&structured); err != nil {
+		mcpErr := fmt.Errorf("failed to marshal results to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	structured["verbosity"] = compact.VerbosityCompact
+	structured["truncated"] = truncated
+	if meta != nil {
+		structured["query_id"] = meta.QueryID
+		structured["page_count"] = meta.PageCount
+		structured["total_rows"] = meta.TotalRows
+		if page > 0 {
+			structured["page"] = page
+		}
+	}
+
+	data, err = json.Marshal(structured)
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal results to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	return mcp.NewToolResultStructured(structured, string(data)), nil
+}
@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/compact"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/state"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+func TestSetResponseMode(t *testing.T) {
+	handlers := NewTrinoHandlers(newUsageTestClient(t), &config.TrinoConfig{MaxRows: 100})
+	handlers.Sessions = state.NewMemoryStore()
+	srv := mcpserver.NewMCPServer("test-server", "0.0.1")
+	ctx := srv.WithContext(context.Background(), &fakeSession{})
+
+	call := func(ctx context.Context, name string, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = args
+		result, err := handler(ctx, req)
+		if err != nil {
+			t.Fatalf("%s returned unexpected Go error: %v", name, err)
+		}
+		return result
+	}
+	verbosity := func(result *mcp.CallToolResult) string {
+		t.Helper()
+		if result.IsError {
+			t.Fatalf("call failed: %v", result.Content)
+		}
+		structured, _ := result.StructuredContent.(map[string]interface{})
+		if v, ok := structured["verbosity"].(string); ok {
+			return v
+		}
+		return "full"
+	}
+	query := map[string]interface{}{"query": "SELECT 1 AS n"}
+
+	if got := verbosity(call(ctx, "execute_query", handlers.ExecuteQuery, query)); got != "full" {
+		t.Errorf("default verbosity = %s, want full", got)
+	}
+
+	result := call(ctx, "set_response_mode", handlers.SetResponseMode, map[string]interface{}{"verbosity": "compact", "max_columns": float64(3)})
+	if result.IsError {
+		t.Fatalf("set_response_mode failed: %v", result.Content)
+	}
+	result = call(ctx, "execute_query", handlers.ExecuteQuery, query)
+	if got := verbosity(result); got != "compact" {
+		t.Errorf("verbosity after set_response_mode = %s, want compact", got)
+	}
+	assertContentContains(t, result, "Compact result")
+
+	if got := verbosity(call(ctx, "execute_query", handlers.ExecuteQuery, map[string]interface{}{"query": "SELECT 1 AS n", "verbosity": "full"})); got != "full" {
+		t.Errorf("verbosity with a full override = %s, want full", got)
+	}
+	if got := verbosity(call(context.Background(), "execute_query", handlers.ExecuteQuery, query)); got != "full" {
+		t.Errorf("verbosity of another connection = %s, want full", got)
+	}
+
+	call(ctx, "set_response_mode", handlers.SetResponseMode, map[string]interface{}{"verbosity": "full"})
+	if got := verbosity(call(ctx, "execute_query", handlers.ExecuteQuery, query)); got != "full" {
+		t.Errorf("verbosity after resetting = %s, want full", got)
+	}
+}
+
+func TestSetResponseMode_Validation(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+	handlers.Sessions = state.NewMemoryStore()
+	ctx := mcpserver.NewMCPServer("test-server", "0.0.1").WithContext(context.Background(), &fakeSession{})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		args map[string]interface{}
+		want string
+	}{
+		{"unknown verbosity", ctx, map[string]interface{}{"verbosity": "terse"}, "verbosity must be full or compact"},
+		{"option out of range", ctx, map[string]interface{}{"verbosity": "compact", "max_rows": float64(5000)}, "max_rows must be between"},
+		{"no session", context.Background(), map[string]interface{}{"verbosity": "compact"}, "no session"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Name = "set_response_mode"
+			req.Params.Arguments = tt.args
+			result, err := handlers.SetResponseMode(tt.ctx, req)
+			if err != nil {
+				t.Fatalf("SetResponseMode returned unexpected Go error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected IsError=true")
+			}
+			assertContentContains(t, result, tt.want)
+		})
+	}
+
+	// An unknown verbosity is rejected before the query runs
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "execute_query"
+	req.Params.Arguments = map[string]interface{}{"query": "SELECT 1", "verbosity": "terse"}
+	result, err := handlers.ExecuteQuery(ctx, req)
+	if err != nil || !result.IsError {
+		t.Fatalf("ExecuteQuery() = %+v, %v; want a verbosity error", result, err)
+	}
+	assertContentContains(t, result, "verbosity must be full or compact")
+}
+
+func TestCompactQueryResult(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{MaxRows: 100})
+	handlers.ResultPager = newTestPager(t, 10)
+
+	rows := make([]map[string]interface{}, 30)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"id": int64(i + 1), "email": "user@example.com", "note": "x"}
+	}
+	qr := &trino.QueryResult{Rows: rows, Columns: []trino.Column{{Name: "id", Type: "bigint"}, {Name: "note", Type: "varchar"}, {Name: "email", Type: "varchar"}}, MaxRows: 100}
+	result, err := handlers.compactQueryResult(context.Background(), qr, compact.Options{MaxColumns: 2})
+	if err != nil || result.IsError {
+		t.Fatalf("compactQueryResult() = %+v, %v", result, err)
+	}
+
+	sc := structuredMap(t, result)
+	if sc["row_count"].(float64) != 30 || sc["page_count"].(float64) != 3 || sc["rows"] != nil {
+		t.Errorf("structured content = %v, want 30 rows summarized and stored in 3 pages", sc)
+	}
+	if omitted := sc["omitted_columns"].([]interface{}); len(omitted) != 1 || omitted[0] != "email" {
+		t.Errorf("omitted_columns = %v, want the columns after the first 2 in result order", omitted)
+	}
+	summary := sc["summary"].(map[string]interface{})
+	if id := summary["id"].(map[string]interface{}); id["max"].(float64) != 30 || id["mean"].(float64) != 15.5 {
+		t.Errorf("summary of id = %v", id)
+	}
+	assertContentContains(t, result, "call get_query_results")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "get_query_results"
+	req.Params.Arguments = map[string]interface{}{"query_id": sc["query_id"], "page": float64(2), "verbosity": "compact"}
+	page, err := handlers.GetQueryResults(context.Background(), req)
+	if err != nil || page.IsError {
+		t.Fatalf("GetQueryResults() = %+v, %v", page, err)
+	}
+	sc = structuredMap(t, page)
+	if sc["page"].(float64) != 2 || len(sc["rows"].([]interface{})) != 10 || sc["summary"] != nil || sc["verbosity"] != "compact" {
+		t.Errorf("compact page = %v", sc)
+	}
+	assertContentContains(t, page, "This is page 2 of 3")
+}
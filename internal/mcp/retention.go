@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"log"
+	"strings"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/history"
+	"github.com/tuannvm/mcp-trino/internal/resultstore"
+	"github.com/tuannvm/mcp-trino/internal/retention"
+	"github.com/tuannvm/mcp-trino/internal/spill"
+)
+
+// newJanitor creates a retention janitor for the data this server keeps on
+// local disk, or returns nil when the janitor is disabled or has nothing to
+// enforce
+func newJanitor(cfg *config.TrinoConfig, components serverComponents) *retention.Janitor {
+	if cfg.RetentionInterval <= 0 {
+		return nil
+	}
+	janitor := retention.New(cfg.RetentionInterval)
+
+	auditPolicy := retention.Policy{MaxAge: cfg.AuditMaxAge, MaxBytes: int64(cfg.AuditMaxTotalMB) << 20}
+	if components.audit != nil && (cfg.AuditRotateMB > 0 || auditPolicy.Enabled()) {
+		janitor.Add(&retention.RotatedLog{
+			Label:       "audit log",
+			Log:         components.audit,
+			RotateBytes: int64(cfg.AuditRotateMB) << 20,
+			Policy:      auditPolicy,
+		})
+	}
+	// The usage log follows the audit log's rotation and retention
+	if components.usage != nil && (cfg.AuditRotateMB > 0 || auditPolicy.Enabled()) {
+		janitor.Add(&retention.RotatedLog{
+			Label:       "usage log",
+			Log:         components.usage.store,
+			RotateBytes: int64(cfg.AuditRotateMB) << 20,
+			Policy:      auditPolicy,
+		})
+	}
+	resultPolicy := retention.Policy{MaxAge: cfg.ResultMaxAge, MaxBytes: int64(cfg.ResultMaxTotalMB) << 20}
+	if components.resultStore != nil && components.resultStore.Backend() == resultstore.BackendDisk && resultPolicy.Enabled() {
+		janitor.Add(&retention.Files{
+			Label:  "stored result",
+			Dir:    resultstore.DiskResultDir(cfg.SpillDir),
+			Match:  resultstore.IsDiskBlob,
+			Policy: resultPolicy,
+		})
+	}
+	spillPolicy := retention.Policy{MaxAge: cfg.SpillMaxAge, MaxBytes: int64(cfg.SpillMaxTotalMB) << 20}
+	if cfg.SpillThresholdRows > 0 && spillPolicy.Enabled() {
+		janitor.Add(&retention.Files{
+			Label:  "spill",
+			Dir:    cfg.SpillDir,
+			Match:  spill.IsSpillFile,
+			Policy: spillPolicy,
+		})
+	}
+
+	if components.history != nil && (cfg.HistoryMaxAge > 0 || cfg.HistoryMaxPerUser > 0) {
+		janitor.Add(&history.Retention{
+			Store:      components.history,
+			MaxAge:     cfg.HistoryMaxAge,
+			MaxPerUser: cfg.HistoryMaxPerUser,
+		})
+	}
+
+	targets := janitor.Targets()
+	if len(targets) == 0 {
+		return nil
+	}
+	log.Printf("INFO: Retention janitor enforcing limits on %s every %s", strings.Join(targets, ", "), cfg.RetentionInterval)
+	return janitor
+}
@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestNewJanitor(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.TrinoConfig{
+		AuditLogFile:       filepath.Join(dir, "audit.jsonl"),
+		UsageLogFile:       filepath.Join(dir, "usage.jsonl"),
+		RetentionInterval:  time.Minute,
+		AuditRotateMB:      100,
+		SpillThresholdRows: 1000,
+		SpillDir:           dir,
+		SpillMaxAge:        24 * time.Hour,
+		HistoryFile:        filepath.Join(dir, "history.db"),
+		HistoryMaxPerUser:  1000,
+	}
+	components := serverComponents{audit: newAuditLogger(cfg), usage: newUsageRecorder(cfg), history: newQueryHistory(cfg)}
+	defer func() { _ = components.audit.Close() }()
+	defer func() { _ = components.usage.Close() }()
+	defer func() { _ = components.history.Close() }()
+
+	janitor := newJanitor(cfg, components)
+	if janitor == nil {
+		t.Fatal("newJanitor() = nil")
+	}
+	if got := strings.Join(janitor.Targets(), ","); got != "audit log,usage log,spill,query history" {
+		t.Errorf("Targets() = %s, want audit log,usage log,spill,query history", got)
+	}
+
+	// Nothing to enforce, or the janitor disabled
+	if janitor := newJanitor(&config.TrinoConfig{RetentionInterval: time.Minute}, serverComponents{}); janitor != nil {
+		t.Error("newJanitor() without targets should be nil")
+	}
+	cfg.RetentionInterval = 0
+	if janitor := newJanitor(cfg, components); janitor != nil {
+		t.Error("newJanitor() with a zero interval should be nil")
+	}
+}
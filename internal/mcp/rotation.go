@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/filewatch"
+	"github.com/tuannvm/mcp-trino/internal/state"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+// oauthHandle serves the current OAuth server, which is replaced when the
+// OIDC client secret rotates. The tool middleware and HTTP endpoints look up
+// the current server per request, so a rebuild takes effect without
+// re-registering either.
+type oauthHandle struct {
+	current atomic.Pointer[oauthInstance]
+}
+
+// oauthInstance is an OAuth server with its endpoints registered on a mux
+type oauthInstance struct {
+	server *oauth.Server
+	mux    *http.ServeMux
+}
+
+// newOAuthHandle wraps an OAuth server for in-place replacement
+func newOAuthHandle(server *oauth.Server) *oauthHandle {
+	h := &oauthHandle{}
+	h.swap(server)
+	return h
+}
+
+// swap makes server handle all later requests
+func (h *oauthHandle) swap(server *oauth.Server) {
+	mux := http.NewServeMux()
+	server.RegisterHandlers(mux)
+	h.current.Store(&oauthInstance{server: server, mux: mux})
+}
+
+// middleware authenticates tool calls with the current OAuth server
+func (h *oauthHandle) middleware() mcpserver.ToolHandlerMiddleware {
+	return func(next mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return h.current.Load().server.Middleware()(next)(ctx, request)
+		}
+	}
+}
+
+// ServeHTTP routes OAuth endpoints to the current OAuth server
+func (h *oauthHandle) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.current.Load().mux.ServeHTTP(w, r)
+}
+
+// newOAuthServer creates the OAuth server for cfg. Without JWT_SECRET every
+// replica would sign OAuth state with its own random key, so replicas that
+// share a state store derive one from the client secret they share instead.
+func newOAuthServer(cfg *config.TrinoConfig, store state.Store) (*oauth.Server, error) {
+	oauthCfg := trinoConfigToOAuthConfig(cfg)
+	if len(oauthCfg.JWTSecret) == 0 && store.Backend() != state.BackendMemory {
+		if oauthCfg.ClientSecret != "" {
+			oauthCfg.JWTSecret = derivedStateSigningKey(oauthCfg.ClientSecret)
+		} else {
+			log.Println("WARNING: JWT_SECRET is not set and the OAuth client has no secret, so each replica signs OAuth state with its own key. Set JWT_SECRET for the OAuth proxy flow to work across replicas.")
+		}
+	}
+	return oauth.NewServer(oauthCfg)
+}
+
+// certReloader serves the TLS certificate most recently loaded from disk
+type certReloader struct {
+	certFile, keyFile string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// newCertReloader loads the initial certificate
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload reads the certificate and key again. A mismatched pair, as seen
+// halfway through a rotation, leaves the current certificate in place.
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+// getCertificate implements tls.Config.GetCertificate, so each handshake
+// uses the current certificate without restarting the listener
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// newSecretWatcher watches the configured secret files and rebuilds only the
+// component each one feeds: the Trino connection pool for the password and
+// the OAuth server for the client secret. Returns nil when rotation is
+// disabled. TLS certificates are added by ServeHTTP.
+func newSecretWatcher(cfg *config.TrinoConfig, trinoClient *trino.Client, oauthHandle *oauthHandle, store state.Store) *filewatch.Watcher {
+	if cfg.SecretPollInterval <= 0 {
+		return nil
+	}
+	watcher := filewatch.New(cfg.SecretPollInterval)
+	if cfg.PasswordFile != "" && trinoClient != nil {
+		watcher.Watch("Trino password", func() error {
+			password, err := config.ReadSecretFile(cfg.PasswordFile)
+			if err != nil {
+				return err
+			}
+			return trinoClient.UpdatePassword(password)
+		}, cfg.PasswordFile)
+	}
+	if cfg.OIDCClientSecretFile != "" && oauthHandle != nil {
+		watcher.Watch("OAuth client secret", func() error {
+			secret, err := config.ReadSecretFile(cfg.OIDCClientSecretFile)
+			if err != nil {
+				return err
+			}
+			rotated := *cfg
+			rotated.OIDCClientSecret = secret
+			server, err := newOAuthServer(&rotated, store)
+			if err != nil {
+				return err
+			}
+			oauthHandle.swap(server)
+			return nil
+		}, cfg.OIDCClientSecretFile)
+	}
+	return watcher
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/state"
+)
+
+// writeTestCertificate writes a self-signed certificate for commonName
+func writeTestCertificate(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeTestCertificate(t, certFile, keyFile, "first")
+
+	certs, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("newCertReloader() error = %v", err)
+	}
+	commonName := func() string {
+		cert, _ := certs.getCertificate(nil)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return leaf.Subject.CommonName
+	}
+	if got := commonName(); got != "first" {
+		t.Fatalf("certificate = %q, want first", got)
+	}
+
+	// A certificate without its matching key is rejected and the old one kept
+	otherDir := t.TempDir()
+	writeTestCertificate(t, certFile, filepath.Join(otherDir, "tls.key"), "second")
+	if err := certs.reload(); err == nil {
+		t.Error("expected reload to fail for a mismatched key")
+	}
+	if got := commonName(); got != "first" {
+		t.Errorf("certificate = %q after a failed reload, want first", got)
+	}
+
+	writeTestCertificate(t, certFile, keyFile, "second")
+	if err := certs.reload(); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	if got := commonName(); got != "second" {
+		t.Errorf("certificate = %q after rotation, want second", got)
+	}
+}
+
+func TestOAuthHandleSwap(t *testing.T) {
+	newServer := func(serverURL string) *oauthHandle {
+		t.Setenv("MCP_URL", serverURL)
+		server, err := newOAuthServer(&config.TrinoConfig{OAuthMode: "native", OAuthProvider: "hmac", OIDCAudience: "trino", JWTSecret: strings.Repeat("k", 32)}, state.NewMemoryStore())
+		if err != nil {
+			t.Fatalf("newOAuthServer() error = %v", err)
+		}
+		return newOAuthHandle(server)
+	}
+	metadata := func(h *oauthHandle) string {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/oauth-protected-resource", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("metadata status = %d", rec.Code)
+		}
+		return rec.Body.String()
+	}
+
+	handle := newServer("https://old.example.com")
+	if !strings.Contains(metadata(handle), "old.example.com") {
+		t.Fatalf("metadata = %s, want the first server", metadata(handle))
+	}
+
+	replacement := newServer("https://new.example.com")
+	handle.swap(replacement.current.Load().server)
+	if body := metadata(handle); !strings.Contains(body, "new.example.com") {
+		t.Errorf("metadata = %s, want the replacement server after swap", body)
+	}
+}
+
+func TestNewSecretWatcherDisabled(t *testing.T) {
+	if w := newSecretWatcher(&config.TrinoConfig{PasswordFile: "/run/secrets/password"}, nil, nil, nil); w != nil {
+		t.Error("expected no watcher when MCP_SECRET_POLL_INTERVAL is 0")
+	}
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/secevents"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+// newSecuritySink creates the configured security event sink, or returns
+// nil when forwarding is disabled or misconfigured
+func newSecuritySink(cfg *config.TrinoConfig) secevents.Sink {
+	if cfg.SyslogAddress == "" {
+		return nil
+	}
+	sink, err := secevents.NewSyslog(secevents.SyslogOptions{
+		Address:  cfg.SyslogAddress,
+		Protocol: cfg.SyslogProtocol,
+		CAFile:   cfg.SyslogCAFile,
+		Facility: cfg.SyslogFacility,
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to configure syslog forwarding, security events will not be forwarded: %v", err)
+		return nil
+	}
+	return sink
+}
+
+// securityEventType classifies a tool error as a security event, or returns
+// "" for ordinary failures. The phrases are the ones the OAuth middleware
+// and the Trino client use for these rejections.
+func securityEventType(message string) string {
+	switch {
+	case strings.Contains(message, "authentication required") || strings.Contains(message, "authentication failed"):
+		return secevents.TypeAuthFailure
+	case strings.Contains(message, "security restriction:"):
+		return secevents.TypeBlockedWrite
+	case strings.Contains(message, "access denied"):
+		return secevents.TypeAllowlistViolation
+	default:
+		return ""
+	}
+}
+
+// authFailureMiddleware reports tool calls rejected by OAuth. It runs
+// outside OAuth, so the caller is unknown.
+func authFailureMiddleware(sink secevents.Sink) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil && securityEventType(err.Error()) == secevents.TypeAuthFailure {
+				sink.Emit(secevents.Event{Time: time.Now(), Type: secevents.TypeAuthFailure, Tool: request.Params.Name, Detail: err.Error()})
+			}
+			return result, err
+		}
+	}
+}
+
+// securityEventMiddleware reports blocked writes and allowlist violations
+// with the authenticated caller
+func securityEventMiddleware(sink secevents.Sink) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			var message string
+			switch {
+			case err != nil:
+				message = err.Error()
+			case result != nil && result.IsError:
+				message = resultText(result)
+			}
+			if eventType := securityEventType(message); eventType == secevents.TypeBlockedWrite || eventType == secevents.TypeAllowlistViolation {
+				sink.Emit(secevents.Event{
+					Time:   time.Now(),
+					Type:   eventType,
+					User:   trino.UserIdentity(ctx),
+					Tool:   request.Params.Name,
+					Detail: truncate(message, maxAuditErrorBytes),
+				})
+			}
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/secevents"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+type recordingSink struct {
+	mu     sync.Mutex
+	events []secevents.Event
+}
+
+func (s *recordingSink) Emit(event secevents.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func TestSecurityEventType(t *testing.T) {
+	tests := map[string]string{
+		"authentication required: missing OAuth token":                     secevents.TypeAuthFailure,
+		"authentication failed: token expired":                             secevents.TypeAuthFailure,
+		"query execution failed: security restriction: only SELECT ...":    secevents.TypeBlockedWrite,
+		"query execution failed: table access denied: hive.hr.salaries...": secevents.TypeAllowlistViolation,
+		"catalog access denied: system not in allowlist":                   secevents.TypeAllowlistViolation,
+		"query execution failed: line 1:8: Column 'x' cannot be resolved":  "",
+	}
+	for message, want := range tests {
+		if got := securityEventType(message); got != want {
+			t.Errorf("securityEventType(%q) = %q, want %q", message, got, want)
+		}
+	}
+}
+
+func TestSecurityEventMiddleware(t *testing.T) {
+	sink := &recordingSink{}
+	ctx := oauth.WithUser(context.Background(), &oauth.User{Username: "alice"})
+	call := func(handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "execute_query"
+		req.Params.Arguments = map[string]interface{}{"query": "DELETE FROM hr.salaries"}
+		_, _ = securityEventMiddleware(sink)(handler)(ctx, req)
+	}
+	call(textHandler("[]"))
+	call(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("query execution failed: security restriction: only SELECT queries are allowed"), nil
+	})
+	call(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("table access denied: hive.hr.salaries not in allowlist")
+	})
+
+	if len(sink.events) != 2 {
+		t.Fatalf("emitted %d events, want 2: %+v", len(sink.events), sink.events)
+	}
+	if event := sink.events[0]; event.Type != secevents.TypeBlockedWrite || event.User != trino.UserIdentity(ctx) || event.Tool != "execute_query" {
+		t.Errorf("blocked write event = %+v", event)
+	}
+	if event := sink.events[1]; event.Type != secevents.TypeAllowlistViolation || event.User != "alice" {
+		t.Errorf("allowlist event = %+v", event)
+	}
+}
+
+func TestAuthFailureMiddleware(t *testing.T) {
+	sink := &recordingSink{}
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "list_catalogs"
+	_, _ = authFailureMiddleware(sink)(textHandler("[]"))(context.Background(), req)
+	_, err := authFailureMiddleware(sink)(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("authentication failed: token expired")
+	})(context.Background(), req)
+	if err == nil {
+		t.Error("middleware swallowed the authentication error")
+	}
+	if len(sink.events) != 1 || sink.events[0].Type != secevents.TypeAuthFailure || sink.events[0].Tool != "list_catalogs" || sink.events[0].User != "" {
+		t.Errorf("events = %+v", sink.events)
+	}
+}
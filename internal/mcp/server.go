@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -13,53 +14,329 @@ import (
 	"time"
 
 	mcpserver "github.com/mark3labs/mcp-go/server"
-	oauth "github.com/tuannvm/oauth-mcp-proxy"
+	"github.com/tuannvm/mcp-trino/internal/audit"
 	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/filewatch"
+	"github.com/tuannvm/mcp-trino/internal/history"
+	"github.com/tuannvm/mcp-trino/internal/pii"
+	"github.com/tuannvm/mcp-trino/internal/ratelimit"
+	"github.com/tuannvm/mcp-trino/internal/redact"
+	"github.com/tuannvm/mcp-trino/internal/resultstore"
+	"github.com/tuannvm/mcp-trino/internal/retention"
+	"github.com/tuannvm/mcp-trino/internal/secevents"
+	"github.com/tuannvm/mcp-trino/internal/state"
+	"github.com/tuannvm/mcp-trino/internal/subscription"
 	"github.com/tuannvm/mcp-trino/internal/trino"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
 )
 
 // Server represents the MCP server with all components
 type Server struct {
 	mcpServer   *mcpserver.MCPServer
+	trinoClient *trino.Client
 	config      *config.TrinoConfig
 	version     string
-	oauthServer *oauth.Server // oauth-mcp-proxy Server (nil if OAuth disabled)
+	oauth       *oauthHandle       // current oauth-mcp-proxy Server (nil if OAuth disabled)
+	watcher     *filewatch.Watcher // reloads rotated secret files (nil if disabled)
+	stopWatcher context.CancelFunc
+	janitor     *retention.Janitor // enforces retention on local disk data (nil if disabled)
+	stopJanitor context.CancelFunc
+	warmer      *metadataWarmer // warms metadata caches (nil if disabled)
+	stopWarmer  context.CancelFunc
+	serverComponents
+}
+
+// serverComponents holds the optional subsystems shared by the MCP server,
+// its middleware, and the tool handlers
+type serverComponents struct {
+	stateStore    state.Store           // shared MCP session state (memory or redis)
+	limiter       ratelimit.Limiter     // per-user tool call limiter (nil if disabled)
+	audit         *audit.Logger         // records every tool call (nil if disabled)
+	security      secevents.Sink        // forwards security events to syslog (nil if disabled)
+	redactor      *redact.Redactor      // scrubs redacted column values from audit records and query history (nil if disabled)
+	usage         *usageRecorder        // records the Trino resources each call used (nil if disabled)
+	anomalies     *anomalyMonitor       // flags unusual behavior per identity (nil if disabled)
+	resultStore   resultstore.BlobStore // backs pagination and chunking (nil if both disabled)
+	resultPager   *resultstore.Pager    // paginated result storage (nil if disabled)
+	resultChunker *resultstore.Chunker  // oversized response storage (nil if disabled)
+	checks        []trino.Check         // data quality checks for run_checks (nil if none configured)
+	hooks         []ToolHook            // deployment hooks around tool calls (nil if none registered)
+	pii           *pii.Scanner          // flags or masks personal data in responses (nil if disabled)
+	tenants       *tenantRouter         // routes tool calls to the caller's tenant (nil if single tenant)
+	profiles      *profileRouter        // routes tool calls to the named environment profile (nil if disabled)
+	subscriptions *subscription.Manager // re-runs subscribed queries (nil if disabled)
+	history       *history.Store        // records execute_query calls (nil if disabled)
+	limits        *trino.Limits         // memory budget and query slots shared by every Trino client
 }
 
 // NewServer creates a new MCP server instance with all components
 func NewServer(trinoClient *trino.Client, trinoConfig *config.TrinoConfig, version string) *Server {
-	mcpServer, oauthServer := createMCPServer(trinoClient, trinoConfig, version)
+	components := newServerComponents(trinoConfig, trinoClient.Limits())
+	mcpServer, oauthHandle := createMCPServer(trinoClient, trinoConfig, version, components)
+
+	s := &Server{
+		mcpServer:        mcpServer,
+		trinoClient:      trinoClient,
+		config:           trinoConfig,
+		version:          version,
+		oauth:            oauthHandle,
+		watcher:          newSecretWatcher(trinoConfig, trinoClient, oauthHandle, components.stateStore),
+		janitor:          newJanitor(trinoConfig, components),
+		warmer:           newMetadataWarmer(trinoConfig, trinoClient, components),
+		serverComponents: components,
+	}
+	if s.watcher != nil {
+		var ctx context.Context
+		ctx, s.stopWatcher = context.WithCancel(context.Background())
+		go s.watcher.Run(ctx)
+	}
+	if s.janitor != nil {
+		var ctx context.Context
+		ctx, s.stopJanitor = context.WithCancel(context.Background())
+		go s.janitor.Run(ctx)
+	}
+	if s.warmer != nil {
+		var ctx context.Context
+		ctx, s.stopWarmer = context.WithCancel(context.Background())
+		go s.warmer.Run(ctx)
+	}
+	return s
+}
 
-	return &Server{
-		mcpServer:   mcpServer,
-		config:      trinoConfig,
-		version:     version,
-		oauthServer: oauthServer,
+// newServerComponents creates the configured optional subsystems. Tenant and
+// profile clients share limits with the server's client.
+func newServerComponents(cfg *config.TrinoConfig, limits *trino.Limits) serverComponents {
+	components := serverComponents{
+		limits:      limits,
+		stateStore:  newStateStore(cfg),
+		limiter:     newRateLimiter(cfg),
+		audit:       newAuditLogger(cfg),
+		security:    newSecuritySink(cfg),
+		redactor:    newRedactor(cfg),
+		usage:       newUsageRecorder(cfg),
+		resultStore: newResultStore(cfg),
+		checks:      loadChecks(cfg),
+		hooks:       registeredToolHooks(),
+		pii:         newPIIScanner(cfg),
+	}
+	components.subscriptions = newSubscriptionManager(cfg)
+	components.history = newQueryHistory(cfg)
+	components.anomalies = newAnomalyMonitor(cfg, components.security)
+	if components.anomalies != nil && components.usage != nil {
+		components.usage.onRecord = components.anomalies.observeUsage
+	}
+	if components.resultStore != nil && cfg.ResultPageSize > 0 {
+		log.Printf("INFO: Paginating results larger than %d rows using %s result store", cfg.ResultPageSize, components.resultStore.Backend())
+		components.resultPager = resultstore.NewPager(components.resultStore, cfg.ResultPageSize, cfg.ResultTTL)
 	}
+	if components.resultStore != nil && cfg.MaxResponseBytes > 0 {
+		chunkSize := cfg.MaxResponseBytes - chunkEnvelopeBytes
+		if chunkSize < chunkEnvelopeBytes {
+			chunkSize = chunkEnvelopeBytes
+		}
+		log.Printf("INFO: Chunking tool responses larger than %d bytes using %s result store", cfg.MaxResponseBytes, components.resultStore.Backend())
+		components.resultChunker = resultstore.NewChunker(components.resultStore, chunkSize, cfg.ResultTTL)
+	}
+	components.tenants = newTenantRouter(cfg, components)
+	components.profiles = newProfileRouter(cfg, components)
+	return components
 }
 
-func createMCPServer(trinoClient *trino.Client, trinoConfig *config.TrinoConfig, version string) (*mcpserver.MCPServer, *oauth.Server) {
-	options := []mcpserver.ServerOption{mcpserver.WithToolCapabilities(true)}
+// newStateStore creates the configured shared state store, falling back to
+// in-memory state if the backend cannot be initialized
+func newStateStore(cfg *config.TrinoConfig) state.Store {
+	store, err := state.NewStore(state.Options{
+		Backend:  cfg.StateStore,
+		RedisURL: cfg.RedisURL,
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to create %s state store, falling back to in-memory state (multi-replica deployments will not share sessions): %v", cfg.StateStore, err)
+		return state.NewMemoryStore()
+	}
+	log.Printf("INFO: Using %s state store for MCP session state", store.Backend())
+	return store
+}
 
-	var oauthServer *oauth.Server
+// newRateLimiter creates the configured per-user limiter, or nil when rate
+// limiting is disabled
+func newRateLimiter(cfg *config.TrinoConfig) ratelimit.Limiter {
+	if cfg.RateLimitPerMinute <= 0 {
+		return nil
+	}
+	limiter, err := ratelimit.NewLimiter(ratelimit.Options{
+		Backend:   cfg.RateLimitBackend,
+		PerMinute: cfg.RateLimitPerMinute,
+		Burst:     cfg.RateLimitBurst,
+		RedisURL:  cfg.RedisURL,
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to create %s rate limiter, falling back to local limits: %v", cfg.RateLimitBackend, err)
+		return ratelimit.NewLocalLimiter(cfg.RateLimitPerMinute, cfg.RateLimitBurst)
+	}
+	return limiter
+}
+
+// newResultStore creates the blob store behind result pagination and response
+// chunking, or nil when both are disabled. Falls back to in-memory storage if
+// the backend cannot be initialized.
+func newResultStore(cfg *config.TrinoConfig) resultstore.BlobStore {
+	if cfg.ResultPageSize <= 0 && cfg.MaxResponseBytes <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	store, err := resultstore.NewBlobStore(ctx, resultstore.Options{
+		Backend:      cfg.ResultStore,
+		RedisURL:     cfg.RedisURL,
+		S3Bucket:     cfg.ResultS3Bucket,
+		S3Prefix:     cfg.ResultS3Prefix,
+		SpillDir:     cfg.SpillDir,
+		SpillEncrypt: cfg.SpillEncrypt,
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to create %s result store, falling back to in-memory results (results will not be shared between replicas): %v", cfg.ResultStore, err)
+		store, _ = resultstore.NewBlobStore(ctx, resultstore.Options{Backend: resultstore.BackendMemory})
+	}
+	return store
+}
+
+// loadChecks reads the configured data quality checks, or returns nil when
+// none are configured or the file is invalid
+func loadChecks(cfg *config.TrinoConfig) []trino.Check {
+	if cfg.ChecksFile == "" {
+		return nil
+	}
+	checks, err := trino.LoadChecks(cfg.ChecksFile)
+	if err != nil {
+		log.Printf("ERROR: Failed to load data quality checks from %s, run_checks is disabled: %v", cfg.ChecksFile, err)
+		return nil
+	}
+	log.Printf("INFO: Loaded %d data quality checks from %s", len(checks), cfg.ChecksFile)
+	return checks
+}
+
+func createMCPServer(trinoClient *trino.Client, trinoConfig *config.TrinoConfig, version string, components serverComponents) (*mcpserver.MCPServer, *oauthHandle) {
+	// Cancellation runs outermost so a cancelled call's context reaches every
+	// layer, down to the Trino query
+	cancellations := newCancellationRegistry()
+	options := []mcpserver.ServerOption{
+		mcpserver.WithToolCapabilities(true),
+		mcpserver.WithHooks(cancellations.hooks()),
+		mcpserver.WithToolHandlerMiddleware(cancellations.middleware()),
+	}
+
+	// Tool calls are identified to Trino as soon as they are tagged
+	if hasClientHeaderTemplates(trinoConfig) {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(requestInfoMiddleware()))
+	}
+
+	// Keepalives cover the whole call, including time spent queued or rate limited
+	if trinoConfig.KeepaliveInterval > 0 {
+		options = append(options,
+			mcpserver.WithLogging(),
+			mcpserver.WithToolHandlerMiddleware(keepaliveMiddleware(trinoConfig.KeepaliveInterval)),
+		)
+	}
+
+	var oauthHandle *oauthHandle
 	if trinoConfig.OAuthEnabled {
-		oauthCfg := trinoConfigToOAuthConfig(trinoConfig)
-		var err error
-		oauthServer, err = oauth.NewServer(oauthCfg)
+		oauthServer, err := newOAuthServer(trinoConfig, components.stateStore)
 		if err != nil {
 			log.Printf("ERROR: Failed to create OAuth server: %v", err)
 		} else {
-			options = append(options, mcpserver.WithToolHandlerMiddleware(oauthServer.Middleware()))
+			oauthHandle = newOAuthHandle(oauthServer)
 			log.Printf("INFO: OAuth enabled with provider: %s, mode: %s", trinoConfig.OAuthProvider, trinoConfig.OAuthMode)
 		}
 	}
+	options = applyMiddleware(options, trinoConfig, oauthHandle, components)
 
 	mcpServer := mcpserver.NewMCPServer("Trino MCP Server", version, options...)
+	mcpServer.AddNotificationHandler(methodNotificationCancelled, cancellations.handleCancelled)
 
 	trinoHandlers := NewTrinoHandlers(trinoClient, trinoConfig)
+	trinoHandlers.ResultPager = components.resultPager
+	trinoHandlers.ResultChunker = components.resultChunker
+	trinoHandlers.Checks = components.checks
+	trinoHandlers.Subscriptions = components.subscriptions
+	trinoHandlers.History = components.history
+	trinoHandlers.Redactor = components.redactor
+	trinoHandlers.Sessions = components.stateStore
 	RegisterTrinoTools(mcpServer, trinoHandlers)
+	if components.profiles != nil {
+		trinoHandlers.ProfileClient = components.profiles.client
+		components.profiles.addArgument(mcpServer)
+	}
+
+	return mcpServer, oauthHandle
+}
+
+// applyMiddleware adds the tool middleware that runs inside cancellation and
+// keepalives, outermost first: OAuth, auditing, security events, anomaly
+// detection, usage accounting, rate limiting, deployment hooks, response chunking, PII
+// scanning, then tenant or profile routing
+func applyMiddleware(options []mcpserver.ServerOption, cfg *config.TrinoConfig, oauthHandle *oauthHandle, components serverComponents) []mcpserver.ServerOption {
+	// Authentication failures are only visible from outside OAuth
+	if components.security != nil && oauthHandle != nil {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(authFailureMiddleware(components.security)))
+	}
+	if oauthHandle != nil {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(oauthHandle.middleware()))
+	}
+
+	// Auditing runs after OAuth so records name the caller, and before
+	// every other layer so rejected calls are recorded too
+	if components.audit != nil {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(auditMiddleware(components.audit, components.redactor)))
+	}
+
+	if components.security != nil {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(securityEventMiddleware(components.security)))
+	}
+
+	// Anomaly detection sees every authenticated call, including those
+	// rejected by rate limits or routing
+	if components.anomalies != nil {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(components.anomalies.middleware()))
+	}
+
+	// Usage is attributed to the authenticated caller, and covers the
+	// queries of routed tenants and profiles, which run on their own clusters
+	if components.usage != nil {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(components.usage.middleware()))
+	}
+
+	// Rate limiting runs after OAuth so limits are keyed by authenticated identity
+	if components.limiter != nil {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(rateLimitMiddleware(components.limiter)))
+	}
+
+	// Hooks see the authenticated caller and only calls within quota
+	if len(components.hooks) > 0 {
+		log.Printf("INFO: Applying %d tool hooks", len(components.hooks))
+		options = append(options, mcpserver.WithToolHandlerMiddleware(ToolHookMiddleware(components.hooks...)))
+	}
 
-	return mcpServer, oauthServer
+	// Chunking wraps the handlers directly so it sees their final responses
+	if components.resultChunker != nil {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(responseChunkingMiddleware(components.resultChunker, cfg.MaxResponseBytes)))
+	}
+
+	// PII scanning runs inside chunking so the full response is scanned
+	// before it is split
+	if components.pii != nil {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(piiMiddleware(components.pii)))
+	}
+
+	// Tenant and profile routing replace the shared handlers, so they run
+	// innermost. Tenant routing does not call the rest of the chain; the
+	// two are never enabled together.
+	if components.tenants != nil {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(components.tenants.middleware()))
+	}
+	if components.profiles != nil {
+		options = append(options, mcpserver.WithToolHandlerMiddleware(components.profiles.middleware()))
+	}
+	return options
 }
 
 // ServeStdio starts the MCP server with STDIO transport
@@ -73,27 +350,40 @@ func (s *Server) ServeHTTP(port string) error {
 
 	log.Println("Setting up StreamableHTTP server...")
 
-	var streamableServer *mcpserver.StreamableHTTPServer
+	streamableOptions := []mcpserver.StreamableHTTPOption{
+		mcpserver.WithEndpointPath("/mcp"),
+		mcpserver.WithStateLess(false),
+	}
+	// With a shared store, session IDs are validated and terminated across replicas
+	if s.stateStore != nil && s.stateStore.Backend() != state.BackendMemory {
+		streamableOptions = append(streamableOptions, mcpserver.WithSessionIdManager(newStoreSessionIdManager(s.stateStore)))
+	}
 	if s.config.OAuthEnabled {
-		streamableServer = mcpserver.NewStreamableHTTPServer(
-			s.mcpServer,
-			mcpserver.WithEndpointPath("/mcp"),
-			mcpserver.WithHTTPContextFunc(oauth.CreateHTTPContextFunc()),
-			mcpserver.WithStateLess(false),
-		)
-	} else {
-		streamableServer = mcpserver.NewStreamableHTTPServer(
-			s.mcpServer,
-			mcpserver.WithEndpointPath("/mcp"),
-			mcpserver.WithStateLess(false),
-		)
+		streamableOptions = append(streamableOptions, mcpserver.WithHTTPContextFunc(oauth.CreateHTTPContextFunc()))
+	}
+	// Idle GET listening streams get the same keepalive as tool calls
+	if s.config.KeepaliveInterval > 0 {
+		streamableOptions = append(streamableOptions, mcpserver.WithHeartbeatInterval(s.config.KeepaliveInterval))
 	}
+	streamableServer := mcpserver.NewStreamableHTTPServer(s.mcpServer, streamableOptions...)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/status", s.handleStatus)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	if s.config.AdminToken != "" {
+		mux.HandleFunc("/admin/access-report", s.handleAccessReport)
+		if s.config.UsageLogFile != "" {
+			mux.HandleFunc("/admin/chargeback", s.handleChargeback)
+		}
+	}
 
-	if s.config.OAuthEnabled && s.oauthServer != nil {
-		s.oauthServer.RegisterHandlers(mux)
+	if s.config.OAuthEnabled && s.oauth != nil {
+		// More specific routes above win; everything else is an OAuth endpoint
+		if policy := newUsagePolicy(s.config); policy != nil {
+			mux.Handle("/", &policyGate{policy: policy, store: s.stateStore, oauth: s.oauth})
+		} else {
+			mux.Handle("/", s.oauth)
+		}
 		log.Printf("INFO: OAuth enabled - mode: %s, provider: %s", s.config.OAuthMode, s.config.OAuthProvider)
 	}
 
@@ -103,13 +393,25 @@ func (s *Server) ServeHTTP(port string) error {
 
 	httpServer := &http.Server{Addr: addr, Handler: mux}
 
+	// Certificates are served through GetCertificate so rotated files take
+	// effect on the next handshake without restarting the listener
+	certFile := getEnv("HTTPS_CERT_FILE", "")
+	keyFile := getEnv("HTTPS_KEY_FILE", "")
+	if certFile != "" && keyFile != "" {
+		certs, err := newCertReloader(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		httpServer.TLSConfig = &tls.Config{GetCertificate: certs.getCertificate, MinVersion: tls.VersionTLS12}
+		if s.watcher != nil {
+			s.watcher.Watch("TLS certificate", certs.reload, certFile, keyFile)
+		}
+	}
+
 	done := make(chan bool, 1)
 	go s.handleSignals(done)
 
 	go func() {
-		certFile := getEnv("HTTPS_CERT_FILE", "")
-		keyFile := getEnv("HTTPS_KEY_FILE", "")
-
 		mcpHost := getEnv("MCP_HOST", "localhost")
 		mcpPort := getEnv("MCP_PORT", "8080")
 		scheme := s.getScheme()
@@ -128,7 +430,7 @@ func (s *Server) ServeHTTP(port string) error {
 				log.Printf("  - OAuth callback (Claude Code): %s/callback (redirects to /oauth/callback)", mcpURL)
 			}
 
-			if err := httpServer.ListenAndServeTLS(certFile, keyFile); err != nil && err != http.ErrServerClosed {
+			if err := httpServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
 				log.Fatalf("HTTPS server error: %v", err)
 			}
 		} else {
@@ -166,6 +468,71 @@ func (s *Server) ServeHTTP(port string) error {
 	return nil
 }
 
+// Close releases resources held by the server
+func (s *Server) Close() error {
+	if s.stopWatcher != nil {
+		s.stopWatcher()
+	}
+	if s.stopJanitor != nil {
+		s.stopJanitor()
+	}
+	if s.stopWarmer != nil {
+		s.stopWarmer()
+	}
+	if s.subscriptions != nil {
+		if err := s.subscriptions.Close(); err != nil {
+			log.Printf("Error closing subscriptions: %v", err)
+		}
+	}
+	if s.limiter != nil {
+		if err := s.limiter.Close(); err != nil {
+			log.Printf("Error closing rate limiter: %v", err)
+		}
+	}
+	if s.tenants != nil {
+		s.tenants.Close()
+	}
+	if s.audit != nil {
+		if err := s.audit.Close(); err != nil {
+			log.Printf("Error closing audit log: %v", err)
+		}
+	}
+	// Usage is recorded before the anomaly and security event senders stop,
+	// so spikes found in queued usage are still reported
+	if s.usage != nil {
+		if err := s.usage.Close(); err != nil {
+			log.Printf("Error closing usage log: %v", err)
+		}
+	}
+	if s.anomalies != nil {
+		if err := s.anomalies.Close(); err != nil {
+			log.Printf("Error closing anomaly webhook: %v", err)
+		}
+	}
+	if s.security != nil {
+		if err := s.security.Close(); err != nil {
+			log.Printf("Error closing security event sink: %v", err)
+		}
+	}
+	if s.profiles != nil {
+		s.profiles.Close()
+	}
+	if s.history != nil {
+		if err := s.history.Close(); err != nil {
+			log.Printf("Error closing query history: %v", err)
+		}
+	}
+	if s.resultStore != nil {
+		if err := s.resultStore.Close(); err != nil {
+			log.Printf("Error closing result store: %v", err)
+		}
+	}
+	if s.stateStore != nil {
+		return s.stateStore.Close()
+	}
+	return nil
+}
+
 // createMCPHandler creates the shared MCP handler function
 func (s *Server) createMCPHandler(streamableServer *mcpserver.StreamableHTTPServer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -184,6 +551,9 @@ func (s *Server) createMCPHandler(streamableServer *mcpserver.StreamableHTTPServ
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
 				log.Printf("OAuth: No bearer token provided, returning 401 with discovery info")
+				if s.security != nil {
+					s.security.Emit(secevents.Event{Time: time.Now(), Type: secevents.TypeAuthFailure, RemoteAddr: r.RemoteAddr, Detail: "missing bearer token"})
+				}
 
 				mcpHost := getEnv("MCP_HOST", "localhost")
 				mcpPort := getEnv("MCP_PORT", "8080")
@@ -254,7 +624,6 @@ func (s *Server) getOAuthStatusWithWarning() string {
 	return " (OAuth disabled)"
 }
 
-
 func trinoConfigToOAuthConfig(cfg *config.TrinoConfig) *oauth.Config {
 	serverURL := getEnv("MCP_URL", "")
 	if serverURL == "" {
@@ -280,6 +649,12 @@ func trinoConfigToOAuthConfig(cfg *config.TrinoConfig) *oauth.Config {
 	}
 }
 
+// NewOAuthServer creates an OAuth server from the Trino configuration, for
+// embedders serving the tools from their own MCP server
+func NewOAuthServer(cfg *config.TrinoConfig) (*oauth.Server, error) {
+	return newOAuthServer(cfg, state.NewMemoryStore())
+}
+
 // getEnv gets environment variable with default value
 func getEnv(key, def string) string {
 	if v, ok := os.LookupEnv(key); ok {
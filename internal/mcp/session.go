@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/state"
+)
+
+const (
+	// sessionIDPrefix matches the prefix used by mcp-go generated session IDs
+	sessionIDPrefix = "mcp-session-"
+
+	// sessionTTL bounds how long an idle session ID remains valid in the store.
+	// Each validation refreshes the TTL, so active sessions never expire.
+	sessionTTL = 24 * time.Hour
+
+	// stateStoreTimeout bounds each state store round-trip
+	stateStoreTimeout = 2 * time.Second
+
+	sessionKeyPrefix  = "session:"
+	sessionActive     = "active"
+	sessionTerminated = "terminated"
+
+	// stateSigningKeyLabel separates the state signing key derived from the
+	// client secret from any other use of the secret
+	stateSigningKeyLabel = "mcp-trino oauth state signing key"
+)
+
+// storeSessionIdManager tracks MCP session IDs in the shared state store so
+// that any replica behind a load balancer can validate or terminate a session
+// created by another replica.
+type storeSessionIdManager struct {
+	store state.Store
+}
+
+var _ mcpserver.SessionIdManager = (*storeSessionIdManager)(nil)
+
+func newStoreSessionIdManager(store state.Store) *storeSessionIdManager {
+	return &storeSessionIdManager{store: store}
+}
+
+// Generate creates a new session ID and records it as active
+func (m *storeSessionIdManager) Generate() string {
+	sessionID := sessionIDPrefix + uuid.New().String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), stateStoreTimeout)
+	defer cancel()
+	if err := m.store.Set(ctx, sessionKeyPrefix+sessionID, []byte(sessionActive), sessionTTL); err != nil {
+		log.Printf("ERROR: Failed to record MCP session %s in state store: %v", sessionID, err)
+	}
+	return sessionID
+}
+
+// Validate checks that the session ID exists in the store and is not terminated
+func (m *storeSessionIdManager) Validate(sessionID string) (bool, error) {
+	if !strings.HasPrefix(sessionID, sessionIDPrefix) {
+		return false, fmt.Errorf("invalid session id: %s", sessionID)
+	}
+	if _, err := uuid.Parse(strings.TrimPrefix(sessionID, sessionIDPrefix)); err != nil {
+		return false, fmt.Errorf("invalid session id: %s", sessionID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stateStoreTimeout)
+	defer cancel()
+
+	value, err := m.store.Get(ctx, sessionKeyPrefix+sessionID)
+	if errors.Is(err, state.ErrNotFound) {
+		return false, fmt.Errorf("unknown session id: %s", sessionID)
+	}
+	if err != nil {
+		return false, fmt.Errorf("session lookup failed: %w", err)
+	}
+	if string(value) == sessionTerminated {
+		return true, nil
+	}
+
+	// Refresh TTL for active sessions
+	if err := m.store.Set(ctx, sessionKeyPrefix+sessionID, []byte(sessionActive), sessionTTL); err != nil {
+		log.Printf("WARNING: Failed to refresh MCP session %s TTL: %v", sessionID, err)
+	}
+	return false, nil
+}
+
+// Terminate marks the session as terminated for all replicas
+func (m *storeSessionIdManager) Terminate(sessionID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), stateStoreTimeout)
+	defer cancel()
+
+	if err := m.store.Set(ctx, sessionKeyPrefix+sessionID, []byte(sessionTerminated), sessionTTL); err != nil {
+		return false, fmt.Errorf("failed to terminate session: %w", err)
+	}
+	return false, nil
+}
+
+// derivedStateSigningKey derives the OAuth state signing key from the OIDC
+// client secret, which every replica already shares. This keeps the
+// proxy-mode authorize/callback round-trip valid when the two requests land
+// on different pods and JWT_SECRET is not configured, without the key ever
+// leaving the process. The key changes when the client secret rotates.
+func derivedStateSigningKey(clientSecret string) []byte {
+	mac := hmac.New(sha256.New, []byte(clientSecret))
+	mac.Write([]byte(stateSigningKeyLabel))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/state"
+)
+
+// TestStoreSessionIdManager_SharedAcrossReplicas verifies that a session
+// generated by one manager is valid for, and can be terminated by, another
+// manager sharing the same store.
+func TestStoreSessionIdManager_SharedAcrossReplicas(t *testing.T) {
+	store := state.NewMemoryStore()
+	replicaA := newStoreSessionIdManager(store)
+	replicaB := newStoreSessionIdManager(store)
+
+	sessionID := replicaA.Generate()
+	if !strings.HasPrefix(sessionID, sessionIDPrefix) {
+		t.Fatalf("Generate() = %q, want prefix %q", sessionID, sessionIDPrefix)
+	}
+
+	terminated, err := replicaB.Validate(sessionID)
+	if err != nil || terminated {
+		t.Fatalf("Validate() on other replica = (%v, %v), want (false, nil)", terminated, err)
+	}
+
+	if _, err := replicaB.Terminate(sessionID); err != nil {
+		t.Fatalf("Terminate() error = %v", err)
+	}
+	terminated, err = replicaA.Validate(sessionID)
+	if err != nil || !terminated {
+		t.Fatalf("Validate() after terminate = (%v, %v), want (true, nil)", terminated, err)
+	}
+}
+
+func TestStoreSessionIdManager_RejectsUnknownAndMalformed(t *testing.T) {
+	manager := newStoreSessionIdManager(state.NewMemoryStore())
+
+	if _, err := manager.Validate("not-a-session"); err == nil {
+		t.Error("expected error for malformed session id")
+	}
+	if _, err := manager.Validate(sessionIDPrefix + "00000000-0000-0000-0000-000000000000"); err == nil {
+		t.Error("expected error for unknown session id")
+	}
+}
+
+func TestDerivedStateSigningKey(t *testing.T) {
+	first := derivedStateSigningKey("client-secret")
+	if len(first) != sha256.Size || string(first) == "client-secret" {
+		t.Fatalf("derivedStateSigningKey() = %x, want a 32-byte key distinct from the secret", first)
+	}
+	if second := derivedStateSigningKey("client-secret"); !bytes.Equal(first, second) {
+		t.Error("replicas sharing a client secret must derive the same key")
+	}
+	if rotated := derivedStateSigningKey("rotated-secret"); bytes.Equal(first, rotated) {
+		t.Error("the key must change when the client secret rotates")
+	}
+}
@@ -0,0 +1,282 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/secevents"
+	"github.com/tuannvm/mcp-trino/internal/subscription"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+// subscriptionLogger names the logger on subscription log notifications
+const subscriptionLogger = "mcp-trino.subscriptions"
+
+// subscriptionWebhookTimeout bounds posting one change to a webhook
+const subscriptionWebhookTimeout = 10 * time.Second
+
+// newSubscriptionManager creates the query subscription manager, or returns
+// nil when subscriptions are disabled
+func newSubscriptionManager(cfg *config.TrinoConfig) *subscription.Manager {
+	if !cfg.Subscriptions {
+		return nil
+	}
+	return subscription.NewManager(subscription.Limits{
+		MinInterval: cfg.SubMinInterval,
+		MaxLifetime: cfg.SubMaxLifetime,
+		MaxPerOwner: cfg.SubMaxPerUser,
+	})
+}
+
+// errSubscriptionsDisabled is returned by the subscription tools when the
+// server does not run subscriptions
+var errSubscriptionsDisabled = errors.New("query subscriptions are not enabled on this server (set MCP_SUBSCRIPTIONS=true)")
+
+// SubscribeQuery handles subscribing to changes in a query's result
+func (h *TrinoHandlers) SubscribeQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Subscriptions == nil {
+		return mcp.NewToolResultErrorFromErr(errSubscriptionsDisabled.Error(), errSubscriptionsDisabled), nil
+	}
+	if h.Config.EnableImpersonation {
+		ctx = h.prepareImpersonationContext(ctx)
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	// Extract the query parameter
+	query, ok := args["query"].(string)
+	if !ok {
+		mcpErr := fmt.Errorf("query parameter must be a string")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	spec := subscription.Spec{Owner: trino.UserIdentity(ctx), Query: query}
+	spec.Watch, _ = args["watch"].(string)
+	spec.ValueColumn, _ = args["value_column"].(string)
+	spec.Threshold, _ = args["threshold"].(float64)
+	spec.ThresholdPercent, _ = args["threshold_percent"].(float64)
+	spec.Once, _ = args["once"].(bool)
+	spec.Webhook, _ = args["webhook"].(string)
+	if seconds, ok := args["interval_seconds"].(float64); ok {
+		spec.Interval = time.Duration(seconds) * time.Second
+	}
+	if hours, ok := args["expires_hours"].(float64); ok {
+		spec.Lifetime = time.Duration(hours * float64(time.Hour))
+	}
+
+	if spec.Webhook != "" && !webhookAllowed(spec.Webhook, h.Config.SubWebhooks) {
+		mcpErr := fmt.Errorf("webhook %q is not allowed; it must start with a prefix in MCP_SUBSCRIPTION_WEBHOOKS", spec.Webhook)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	notify, err := newSubscriptionNotifier(ctx, spec.Webhook, h.Config.SubWebhookSecret)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(err.Error(), err), nil
+	}
+
+	info, err := h.Subscriptions.Add(ctx, spec, h.subscriptionRunner(ctx, spec), notify)
+	if err != nil {
+		log.Printf("Error subscribing to query: %v", err)
+		mcpErr := fmt.Errorf("subscription failed: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	log.Printf("INFO: %s subscribed to query %s every %ds", info.Owner, info.ID, info.IntervalSeconds)
+
+	jsonData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal subscription to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(info, string(jsonData)), nil
+}
+
+// ListSubscriptions handles listing the caller's query subscriptions
+func (h *TrinoHandlers) ListSubscriptions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Subscriptions == nil {
+		return mcp.NewToolResultErrorFromErr(errSubscriptionsDisabled.Error(), errSubscriptionsDisabled), nil
+	}
+
+	subscriptions := h.Subscriptions.List(trino.UserIdentity(ctx))
+	if subscriptions == nil {
+		subscriptions = []subscription.Info{}
+	}
+	result := map[string]interface{}{"subscriptions": subscriptions}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		mcpErr := fmt.Errorf("failed to marshal subscriptions to JSON: %w", err)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	return mcp.NewToolResultStructured(result, string(jsonData)), nil
+}
+
+// UnsubscribeQuery handles ending one of the caller's query subscriptions
+func (h *TrinoHandlers) UnsubscribeQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.Subscriptions == nil {
+		return mcp.NewToolResultErrorFromErr(errSubscriptionsDisabled.Error(), errSubscriptionsDisabled), nil
+	}
+
+	// Type assert Arguments to map[string]interface{}
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		mcpErr := fmt.Errorf("invalid arguments format")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	id, ok := args["subscription_id"].(string)
+	if !ok || id == "" {
+		mcpErr := fmt.Errorf("subscription_id parameter must be a non-empty string")
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+
+	if !h.Subscriptions.Remove(trino.UserIdentity(ctx), id) {
+		mcpErr := fmt.Errorf("no active subscription %q; it may have expired or been created on another server instance", id)
+		return mcp.NewToolResultErrorFromErr(mcpErr.Error(), mcpErr), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Subscription %s ended", id)), nil
+}
+
+// subscriptionRunner runs a subscribed query as the caller. Background runs
+// happen after the call returns, so the caller's identity is carried over
+// from ctx rather than read from the run's context.
+func (h *TrinoHandlers) subscriptionRunner(ctx context.Context, spec subscription.Spec) subscription.Runner {
+	user, _ := oauth.GetUserFromContext(ctx)
+	impersonated, _ := trino.GetImpersonatedUser(ctx)
+	client := h.TrinoClient
+	return func(runCtx context.Context) (subscription.Observation, error) {
+		if user != nil {
+			runCtx = oauth.WithUser(runCtx, user)
+		}
+		if impersonated != "" {
+			runCtx = trino.WithImpersonatedUser(runCtx, impersonated)
+		}
+		rows, err := client.SnapshotQuery(runCtx, spec.Query)
+		if err != nil {
+			return subscription.Observation{}, err
+		}
+		return subscription.Observe(rows, spec.Watch, spec.ValueColumn)
+	}
+}
+
+// newSubscriptionNotifier delivers changes as log notifications to the
+// caller's MCP session and, when a webhook is given, posts them to it. Once
+// the session ends, a subscription without a webhook has nowhere to report
+// and is removed.
+func newSubscriptionNotifier(ctx context.Context, webhook, secret string) (subscription.Notifier, error) {
+	srv := server.ServerFromContext(ctx)
+	session := server.ClientSessionFromContext(ctx)
+	var sessionID string
+	if srv != nil && session != nil {
+		sessionID = session.SessionID()
+	}
+	if sessionID == "" && webhook == "" {
+		return nil, fmt.Errorf("this connection cannot receive notifications; pass a webhook to be notified of changes")
+	}
+	client := &http.Client{Timeout: subscriptionWebhookTimeout}
+
+	return func(ctx context.Context, change subscription.Change) error {
+		var errs []error
+		if sessionID != "" {
+			err := srv.SendNotificationToSpecificClient(sessionID, methodNotificationMessage, map[string]any{
+				"level":  mcp.LoggingLevelNotice,
+				"logger": subscriptionLogger,
+				"data":   change,
+			})
+			switch {
+			case errors.Is(err, server.ErrSessionNotFound) && webhook == "":
+				return fmt.Errorf("%w: session %s ended", subscription.ErrUndeliverable, sessionID)
+			case errors.Is(err, server.ErrSessionNotFound):
+				sessionID = "" // keep posting to the webhook
+			case err != nil:
+				errs = append(errs, err)
+			}
+		}
+		if webhook != "" {
+			if err := postSubscriptionChange(ctx, client, webhook, secret, change); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+// subscriptionPayload is the JSON body posted to a subscription webhook.
+// Text summarizes the change for chat webhooks that display a text field.
+type subscriptionPayload struct {
+	subscription.Change
+	Text string `json:"text"`
+}
+
+// postSubscriptionChange posts a change to a webhook, signing the body in
+// secevents.SignatureHeader when a secret is configured
+func postSubscriptionChange(ctx context.Context, client *http.Client, webhook, secret string, change subscription.Change) error {
+	body, err := json.Marshal(subscriptionPayload{
+		Change: change,
+		Text:   fmt.Sprintf("mcp-trino: subscription %s: %s", change.Subscription.ID, change.Detail),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode subscription change: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mcp-trino")
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		req.Header.Set(secevents.SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// webhookAllowed reports whether a webhook URL starts with one of the
+// operator's allowed prefixes. A prefix ending mid-host or mid-segment only
+// matches at a boundary, so https://hooks.example.com does not admit
+// https://hooks.example.com.attacker.net.
+func webhookAllowed(webhook string, prefixes []string) bool {
+	u, err := url.Parse(webhook)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.User != nil {
+		return false
+	}
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(webhook, prefix) {
+			continue
+		}
+		rest := webhook[len(prefix):]
+		if strings.HasSuffix(prefix, "/") || rest == "" || strings.ContainsAny(rest[:1], "/?#") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/secevents"
+	"github.com/tuannvm/mcp-trino/internal/subscription"
+)
+
+func TestWebhookAllowed(t *testing.T) {
+	prefixes := []string{"https://hooks.example.com/data/", "https://chat.example.com"}
+	tests := map[string]bool{
+		"https://hooks.example.com/data/partitions": true,
+		"https://hooks.example.com/other":           false,
+		"https://chat.example.com/hooks/abc":        true,
+		"https://chat.example.com":                  true,
+		"https://chat.example.com.attacker.net/x":   false,
+		"https://chat.example.com@attacker.net/x":   false,
+		"http://hooks.example.com/data/partitions":  false,
+		"file:///etc/passwd":                        false,
+	}
+	for webhook, want := range tests {
+		if got := webhookAllowed(webhook, prefixes); got != want {
+			t.Errorf("webhookAllowed(%q) = %v, want %v", webhook, got, want)
+		}
+	}
+	if webhookAllowed("https://hooks.example.com/data/x", nil) {
+		t.Error("webhookAllowed() with no prefixes = true")
+	}
+}
+
+func TestSubscriptionTools_Disabled(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{})
+	for name, handler := range map[string]func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error){
+		"subscribe_query":    handlers.SubscribeQuery,
+		"list_subscriptions": handlers.ListSubscriptions,
+		"unsubscribe_query":  handlers.UnsubscribeQuery,
+	} {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = map[string]interface{}{"query": "SELECT 1", "subscription_id": "sub_1"}
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s returned unexpected Go error: %v", name, err)
+		}
+		if !result.IsError {
+			t.Errorf("%s: expected IsError=true when subscriptions are disabled", name)
+		}
+		assertContentContains(t, result, "MCP_SUBSCRIPTIONS")
+	}
+}
+
+func TestSubscribeQuery_Validation(t *testing.T) {
+	handlers := newTestHandlers(&config.TrinoConfig{Subscriptions: true, SubWebhooks: []string{"https://hooks.example.com/"}})
+	handlers.Subscriptions = subscription.NewManager(subscription.Limits{})
+	defer func() { _ = handlers.Subscriptions.Close() }()
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"missing query", map[string]interface{}{}, "query parameter must be a string"},
+		{"webhook not allowed", map[string]interface{}{"query": "SELECT 1", "webhook": "https://attacker.example.net/"}, "not allowed"},
+		{"no way to notify", map[string]interface{}{"query": "SELECT 1"}, "cannot receive notifications"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Name = "subscribe_query"
+			req.Params.Arguments = tt.args
+			result, err := handlers.SubscribeQuery(context.Background(), req)
+			if err != nil {
+				t.Fatalf("SubscribeQuery returned unexpected Go error: %v", err)
+			}
+			if !result.IsError {
+				t.Error("expected IsError=true")
+			}
+			assertContentContains(t, result, tt.want)
+		})
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "unsubscribe_query"
+	req.Params.Arguments = map[string]interface{}{"subscription_id": "sub_unknown"}
+	result, err := handlers.UnsubscribeQuery(context.Background(), req)
+	if err != nil {
+		t.Fatalf("UnsubscribeQuery returned unexpected Go error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError=true for an unknown subscription")
+	}
+	assertContentContains(t, result, "no active subscription")
+}
+
+func TestSubscriptionNotifierSession(t *testing.T) {
+	srv := mcpserver.NewMCPServer("test-server", "0.0.1", mcpserver.WithToolCapabilities(true))
+	var notify subscription.Notifier
+	srv.AddTool(mcp.NewTool("subscribe"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var err error
+		notify, err = newSubscriptionNotifier(ctx, "", "")
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("subscribed"), nil
+	})
+
+	session := &fakeSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	ctx := srv.WithContext(context.Background(), session)
+	if err := srv.RegisterSession(ctx, session); err != nil {
+		t.Fatalf("RegisterSession() error = %v", err)
+	}
+	srv.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"subscribe","arguments":{}}}`))
+	if notify == nil {
+		t.Fatal("newSubscriptionNotifier() failed inside a session")
+	}
+
+	change := subscription.Change{Subscription: subscription.Info{ID: "sub_1"}, Reason: subscription.ReasonChanged, Detail: "row count changed from 0 to 24"}
+	if err := notify(context.Background(), change); err != nil {
+		t.Fatalf("notify() error = %v", err)
+	}
+	n := <-session.notifications
+	if n.Method != methodNotificationMessage {
+		t.Errorf("method = %q, want %q", n.Method, methodNotificationMessage)
+	}
+	if level := fmt.Sprint(n.Params.AdditionalFields["level"]); level != "notice" || n.Params.AdditionalFields["logger"] != subscriptionLogger {
+		t.Errorf("params = %+v", n.Params.AdditionalFields)
+	}
+	if data, ok := n.Params.AdditionalFields["data"].(subscription.Change); !ok || data.Subscription.ID != "sub_1" {
+		t.Errorf("data = %+v, want the change", n.Params.AdditionalFields["data"])
+	}
+
+	srv.UnregisterSession(ctx, session.SessionID())
+	if err := notify(context.Background(), change); !errors.Is(err, subscription.ErrUndeliverable) {
+		t.Errorf("notify() after the session ended error = %v, want ErrUndeliverable", err)
+	}
+}
+
+func TestSubscriptionNotifierWebhook(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write(body)
+		if r.Header.Get(secevents.SignatureHeader) != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		bodies <- body
+	}))
+	defer hook.Close()
+
+	notify, err := newSubscriptionNotifier(context.Background(), hook.URL+"/partitions", "s3cret")
+	if err != nil {
+		t.Fatalf("newSubscriptionNotifier() error = %v", err)
+	}
+	change := subscription.Change{Subscription: subscription.Info{ID: "sub_1"}, Reason: subscription.ReasonChanged, Detail: "result changed (0 rows before, 24 now)"}
+	if err := notify(context.Background(), change); err != nil {
+		t.Fatalf("notify() error = %v", err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(<-bodies, &payload); err != nil {
+		t.Fatalf("webhook body is not JSON: %v", err)
+	}
+	if payload["reason"] != "changed" || payload["text"] != "mcp-trino: subscription sub_1: result changed (0 rows before, 24 now)" {
+		t.Errorf("payload = %v", payload)
+	}
+}
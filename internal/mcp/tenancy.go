@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/tenancy"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+// tenantRouter sends each tool call to the Trino cluster and allowlists of
+// the caller's tenant
+type tenantRouter struct {
+	claim   string
+	tenants map[string]*boundTools
+}
+
+// boundTools are the tool handlers bound to one tenant's or profile's client
+type boundTools struct {
+	client *trino.Client
+	tools  *mcpserver.MCPServer // registry only; never served
+}
+
+// newTenantRouter loads the configured tenants, or returns nil when
+// multi-tenant mode is disabled. Tenants that fail to load are left out, so
+// their calls are rejected rather than served from the shared cluster.
+func newTenantRouter(cfg *config.TrinoConfig, components serverComponents) *tenantRouter {
+	if cfg.TenantsFile == "" {
+		return nil
+	}
+	router := &tenantRouter{claim: cfg.TenantClaim, tenants: make(map[string]*boundTools)}
+	tenants, err := tenancy.Load(cfg.TenantsFile)
+	if err != nil {
+		log.Printf("ERROR: Failed to load tenants from %s, all tool calls will be rejected: %v", cfg.TenantsFile, err)
+		return router
+	}
+
+	for _, tenant := range tenants {
+		tenantCfg, err := tenant.Config(cfg)
+		if err != nil {
+			log.Printf("ERROR: Failed to configure tenant %s, its tool calls will be rejected: %v", tenant.ID, err)
+			continue
+		}
+		client, err := trino.NewClientWithLimits(tenantCfg, components.limits)
+		if err != nil {
+			log.Printf("ERROR: Failed to create Trino client for tenant %s, its tool calls will be rejected: %v", tenant.ID, err)
+			continue
+		}
+
+		handlers := NewTrinoHandlers(client, tenantCfg)
+		handlers.ResultPager = components.resultPager
+		handlers.ResultChunker = components.resultChunker
+		handlers.Subscriptions = components.subscriptions
+		handlers.History = components.history
+		handlers.Redactor = components.redactor
+		handlers.Sessions = components.stateStore
+		tools := mcpserver.NewMCPServer("tenant "+tenant.ID, "", mcpserver.WithToolCapabilities(true))
+		RegisterTrinoTools(tools, handlers)
+		router.tenants[tenant.ID] = &boundTools{client: client, tools: tools}
+	}
+	log.Printf("INFO: Multi-tenant mode enabled for %d tenants", len(router.tenants))
+	return router
+}
+
+// queryArguments are the tool arguments that carry SQL
+var queryArguments = []string{"query", "other_query"}
+
+// middleware replaces the shared tool handlers with the tenant's. Queries
+// are checked against the tenant's allowlists before they run. It calls the
+// tenant's handler instead of next, so it must be the innermost middleware.
+func (r *tenantRouter) middleware() mcpserver.ToolHandlerMiddleware {
+	return func(mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			tool, err := r.route(ctx, request)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr(err.Error(), err), nil
+			}
+			return tool.Handler(ctx, request)
+		}
+	}
+}
+
+// route finds the tenant's handler for a tool call
+func (r *tenantRouter) route(ctx context.Context, request mcp.CallToolRequest) (*mcpserver.ServerTool, error) {
+	token, ok := oauth.GetOAuthToken(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant not identified: missing OAuth token")
+	}
+	tenantID, err := tenancy.ClaimFromToken(token, r.claim)
+	if err != nil {
+		return nil, fmt.Errorf("tenant not identified: %w", err)
+	}
+	tenant, ok := r.tenants[tenantID]
+	if !ok {
+		return nil, fmt.Errorf("no workspace is configured for tenant %q", tenantID)
+	}
+
+	// Profiles cannot be combined with tenants, so a tenant cannot select one
+	for _, name := range []string{profileArgument, "other_profile"} {
+		if value, ok := request.GetArguments()[name].(string); ok && value != "" {
+			return nil, fmt.Errorf("the %s argument is not available in multi-tenant mode; each tenant queries only its own cluster", name)
+		}
+	}
+	for _, name := range queryArguments {
+		if query, ok := request.GetArguments()[name].(string); ok {
+			if err := tenant.client.CheckQueryAccess(query); err != nil {
+				return nil, err
+			}
+		}
+	}
+	tool := tenant.tools.GetTool(request.Params.Name)
+	if tool == nil {
+		return nil, fmt.Errorf("tool %s is not available in multi-tenant mode", request.Params.Name)
+	}
+	return tool, nil
+}
+
+// Close closes the tenants' Trino clients
+func (r *tenantRouter) Close() {
+	for id, tenant := range r.tenants {
+		if err := tenant.client.Close(); err != nil {
+			log.Printf("Error closing Trino client for tenant %s: %v", id, err)
+		}
+	}
+}
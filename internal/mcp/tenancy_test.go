@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+func tenantToken(tenant string) string {
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"alice","tenant":"`+tenant+`"}`)) + ".sig"
+}
+
+func TestTenantRouter(t *testing.T) {
+	tenantsFile := filepath.Join(t.TempDir(), "tenants.yaml")
+	if err := os.WriteFile(tenantsFile, []byte("tenants:\n  - id: acme\n    trino: {catalog: acme_hive}\n    catalog_prefix: acme_\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.TrinoConfig{
+		Host: "localhost", Port: 1, Scheme: "http", User: "svc", Catalog: "hive", Schema: "default",
+		TenantsFile: tenantsFile, TenantClaim: "tenant",
+	}
+	router := newTenantRouter(cfg, serverComponents{})
+	defer router.Close()
+	if len(router.tenants) != 1 {
+		t.Fatalf("loaded %d tenants, want 1", len(router.tenants))
+	}
+
+	request := func(tool, query string) mcp.CallToolRequest {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = tool
+		req.Params.Arguments = map[string]interface{}{"query": query}
+		return req
+	}
+	withArgument := func(req mcp.CallToolRequest, name, value string) mcp.CallToolRequest {
+		req.Params.Arguments.(map[string]interface{})[name] = value
+		return req
+	}
+	otherQueryRequest := func(query, otherQuery string) mcp.CallToolRequest {
+		return withArgument(request("diff_queries", query), "other_query", otherQuery)
+	}
+	tests := []struct {
+		name    string
+		token   string
+		request mcp.CallToolRequest
+		wantErr string
+	}{
+		{"no token", "", request("list_catalogs", ""), "missing OAuth token"},
+		{"unknown tenant", tenantToken("globex"), request("list_catalogs", ""), `no workspace is configured for tenant "globex"`},
+		{"other tenant's catalog", tenantToken("acme"), request("execute_query", "SELECT * FROM globex_hive.sales.orders"), "not in allowlist"},
+		{"unknown tool", tenantToken("acme"), request("drop_everything", ""), "not available"},
+		{"profile", tenantToken("acme"), withArgument(request("execute_query", "SELECT 1"), "profile", "staging"), "not available in multi-tenant mode"},
+		{"other_profile", tenantToken("acme"), withArgument(request("diff_queries", "SELECT 1"), "other_profile", "prod"), "not available in multi-tenant mode"},
+		{"other tenant's catalog in other_query", tenantToken("acme"), otherQueryRequest("SELECT * FROM sales.orders", "SELECT * FROM globex_hive.sales.orders"), "not in allowlist"},
+		{"own catalog", tenantToken("acme"), request("execute_query", "SELECT * FROM sales.orders"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = oauth.WithOAuthToken(ctx, tt.token)
+			}
+			tool, err := router.route(ctx, tt.request)
+			if tt.wantErr == "" {
+				if err != nil || tool == nil {
+					t.Fatalf("route() = %v, %v; want the tenant's tool", tool, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("route() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTenantRouterRejectsAllWhenFileInvalid(t *testing.T) {
+	cfg := &config.TrinoConfig{TenantsFile: filepath.Join(t.TempDir(), "missing.yaml"), TenantClaim: "tenant"}
+	router := newTenantRouter(cfg, serverComponents{})
+
+	next := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		t.Fatal("shared handler must not be called in multi-tenant mode")
+		return nil, nil
+	}
+	ctx := oauth.WithOAuthToken(context.Background(), tenantToken("acme"))
+	result, err := router.middleware()(next)(ctx, mcp.CallToolRequest{})
+	if err != nil || !result.IsError {
+		t.Fatalf("result = %+v, err = %v; want a tool error", result, err)
+	}
+	assertContentContains(t, result, "no workspace is configured")
+}
@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+// warmupTarget is one cluster whose metadata is warmed
+type warmupTarget struct {
+	label  string
+	client *trino.Client
+}
+
+// metadataWarmer lists metadata on every configured cluster at startup, and
+// optionally on a schedule, so the first tool calls after a deploy do not
+// wait on cold metadata caches
+type metadataWarmer struct {
+	targets    []warmupTarget
+	tables     []string
+	maxSchemas int
+	interval   time.Duration // 0 = startup only
+}
+
+// newMetadataWarmer creates a warmer for the server's cluster and those of
+// its tenants and profiles, or returns nil when warm-up is disabled
+func newMetadataWarmer(cfg *config.TrinoConfig, client *trino.Client, components serverComponents) *metadataWarmer {
+	if !cfg.MetadataWarmup {
+		return nil
+	}
+	w := &metadataWarmer{
+		targets:    []warmupTarget{{label: "default cluster", client: client}},
+		tables:     cfg.WarmupTables,
+		maxSchemas: cfg.WarmupMaxSchemas,
+		interval:   cfg.WarmupInterval,
+	}
+	if components.tenants != nil {
+		w.targets = append(w.targets, boundTargets("tenant", components.tenants.tenants)...)
+	}
+	if components.profiles != nil {
+		w.targets = append(w.targets, boundTargets("profile", components.profiles.profiles)...)
+	}
+	return w
+}
+
+// boundTargets returns the clients of tenants or profiles, sorted by name
+func boundTargets(kind string, bound map[string]*boundTools) []warmupTarget {
+	names := make([]string, 0, len(bound))
+	for name := range bound {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	targets := make([]warmupTarget, 0, len(names))
+	for _, name := range names {
+		targets = append(targets, warmupTarget{label: kind + " " + name, client: bound[name].client})
+	}
+	return targets
+}
+
+// Run warms metadata once, then every interval until ctx ends
+func (w *metadataWarmer) Run(ctx context.Context) {
+	w.warm(ctx)
+	if w.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.warm(ctx)
+		}
+	}
+}
+
+// warm warms each cluster in turn, logging what was listed
+func (w *metadataWarmer) warm(ctx context.Context) {
+	for _, target := range w.targets {
+		if ctx.Err() != nil {
+			return
+		}
+		report := target.client.WarmMetadata(ctx, w.tables, w.maxSchemas)
+		log.Printf("INFO: Warmed metadata on %s in %s: %d catalogs, %d schemas, %d table lists, %d hot tables",
+			target.label, report.Duration.Round(time.Millisecond), report.Catalogs, report.Schemas, report.TableLists, report.TableSchemas)
+		if report.SkippedSchemas > 0 {
+			log.Printf("INFO: Skipped listing tables in %d schemas on %s past MCP_WARMUP_MAX_SCHEMAS", report.SkippedSchemas, target.label)
+		}
+		if report.Failed > 0 {
+			log.Printf("WARNING: %d metadata warm-up queries failed on %s: %s", report.Failed, target.label, strings.Join(report.Errors, "; "))
+		}
+	}
+}
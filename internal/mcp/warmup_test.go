@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+)
+
+func TestNewMetadataWarmer(t *testing.T) {
+	if w := newMetadataWarmer(&config.TrinoConfig{}, nil, serverComponents{}); w != nil {
+		t.Error("newMetadataWarmer() should be nil when warm-up is disabled")
+	}
+
+	cfg := &config.TrinoConfig{MetadataWarmup: true, WarmupInterval: time.Hour, WarmupMaxSchemas: 10, WarmupTables: []string{"hive.sales.orders"}}
+	components := serverComponents{profiles: &profileRouter{profiles: map[string]*boundTools{
+		"staging": {client: &trino.Client{}},
+		"prod":    {client: &trino.Client{}},
+	}}}
+	w := newMetadataWarmer(cfg, &trino.Client{}, components)
+	if w == nil {
+		t.Fatal("newMetadataWarmer() = nil, want a warmer")
+	}
+	var labels []string
+	for _, target := range w.targets {
+		labels = append(labels, target.label)
+	}
+	if len(labels) != 3 || labels[0] != "default cluster" || labels[1] != "profile prod" || labels[2] != "profile staging" {
+		t.Errorf("targets = %q, want the default cluster then profiles by name", labels)
+	}
+	if w.interval != time.Hour || w.maxSchemas != 10 || len(w.tables) != 1 {
+		t.Errorf("warmer = %+v", w)
+	}
+}
@@ -0,0 +1,140 @@
+// Package memguard enforces a process-wide memory budget for materialized
+// query results, so that a few concurrent large results fail fast with a
+// clear error instead of getting the process OOM-killed.
+package memguard
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrBudgetExceeded is returned when a reservation cannot grow within the budget.
+var ErrBudgetExceeded = errors.New("result memory budget exceeded")
+
+// reserveChunk is the granularity of budget reservations; rows are tiny
+// compared to the budget, so reserving in chunks keeps lock traffic low.
+const reserveChunk = 1 << 20
+
+// Budget tracks estimated bytes held by in-flight results.
+type Budget struct {
+	limit int64
+
+	mu      sync.Mutex
+	used    int64
+	waiters chan struct{} // closed and replaced whenever memory is released
+}
+
+// NewBudget creates a budget of limit bytes. A non-positive limit disables it.
+func NewBudget(limit int64) *Budget {
+	if limit <= 0 {
+		return nil
+	}
+	return &Budget{limit: limit, waiters: make(chan struct{})}
+}
+
+// Limit returns the budget size in bytes.
+func (b *Budget) Limit() int64 {
+	return b.limit
+}
+
+// Used returns the bytes currently reserved.
+func (b *Budget) Used() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// Begin waits until the budget has free capacity and returns an empty
+// reservation. New queries queue here while the budget is exhausted rather
+// than competing with results that are already materializing.
+func (b *Budget) Begin(ctx context.Context) (*Reservation, error) {
+	for {
+		b.mu.Lock()
+		if b.used < b.limit {
+			b.mu.Unlock()
+			return &Reservation{budget: b}, nil
+		}
+		wait := b.waiters
+		b.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ErrBudgetExceeded
+		}
+	}
+}
+
+func (b *Budget) reserve(n int64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.used+n > b.limit {
+		return false
+	}
+	b.used += n
+	return true
+}
+
+func (b *Budget) release(n int64) {
+	if n == 0 {
+		return
+	}
+	b.mu.Lock()
+	b.used -= n
+	close(b.waiters)
+	b.waiters = make(chan struct{})
+	b.mu.Unlock()
+}
+
+// Reservation is one result's share of the budget. A nil Reservation is
+// valid and never fails, which keeps call sites simple when the budget is
+// disabled.
+type Reservation struct {
+	budget   *Budget
+	used     int64
+	reserved int64
+}
+
+// Grow accounts for n more bytes, returning ErrBudgetExceeded if the budget
+// cannot cover them.
+func (r *Reservation) Grow(n int64) error {
+	if r == nil {
+		return nil
+	}
+	r.used += n
+	if r.used <= r.reserved {
+		return nil
+	}
+	need := r.used - r.reserved
+	chunk := ((need + reserveChunk - 1) / reserveChunk) * reserveChunk
+	if !r.budget.reserve(chunk) {
+		// Fall back to the exact amount when a whole chunk does not fit
+		if !r.budget.reserve(need) {
+			r.used -= n
+			return ErrBudgetExceeded
+		}
+		chunk = need
+	}
+	r.reserved += chunk
+	return nil
+}
+
+// Used returns the bytes accounted to this reservation.
+func (r *Reservation) Used() int64 {
+	if r == nil {
+		return 0
+	}
+	return r.used
+}
+
+// Release returns all reserved bytes to the budget. It is safe to call more
+// than once.
+func (r *Reservation) Release() {
+	if r == nil {
+		return
+	}
+	r.budget.release(r.reserved)
+	r.used = 0
+	r.reserved = 0
+}
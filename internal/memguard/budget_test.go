@@ -0,0 +1,93 @@
+package memguard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewBudgetDisabled(t *testing.T) {
+	if b := NewBudget(0); b != nil {
+		t.Errorf("NewBudget(0) = %v, want nil", b)
+	}
+	var r *Reservation
+	if err := r.Grow(1 << 40); err != nil {
+		t.Errorf("nil reservation Grow() error = %v", err)
+	}
+	r.Release()
+}
+
+func TestReservationGrowAndRelease(t *testing.T) {
+	b := NewBudget(3 * reserveChunk)
+	r, err := b.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+
+	if err := r.Grow(100); err != nil {
+		t.Fatalf("Grow(100) error = %v", err)
+	}
+	if b.Used() != reserveChunk {
+		t.Errorf("Used() = %d, want one chunk reserved", b.Used())
+	}
+	if err := r.Grow(2 * reserveChunk); err != nil {
+		t.Fatalf("Grow(2 chunks) error = %v", err)
+	}
+	if err := r.Grow(reserveChunk); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Grow past budget error = %v, want ErrBudgetExceeded", err)
+	}
+	if r.Used() != 2*reserveChunk+100 {
+		t.Errorf("Used() = %d after failed Grow, want unchanged", r.Used())
+	}
+
+	r.Release()
+	r.Release()
+	if b.Used() != 0 {
+		t.Errorf("budget Used() = %d after Release, want 0", b.Used())
+	}
+}
+
+func TestBeginQueuesUntilRelease(t *testing.T) {
+	b := NewBudget(reserveChunk)
+	first, _ := b.Begin(context.Background())
+	if err := first.Grow(reserveChunk); err != nil {
+		t.Fatalf("Grow() error = %v", err)
+	}
+
+	// Exhausted budget times out new queries
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := b.Begin(ctx); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Begin() on exhausted budget error = %v, want ErrBudgetExceeded", err)
+	}
+
+	// Queued queries start once memory is released
+	done := make(chan error, 1)
+	go func() {
+		_, err := b.Begin(context.Background())
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	first.Release()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("queued Begin() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued Begin() did not resume after Release")
+	}
+}
+
+func TestEstimateRow(t *testing.T) {
+	small := EstimateRow(map[string]interface{}{"id": int64(1)})
+	large := EstimateRow(map[string]interface{}{"id": int64(1), "payload": string(make([]byte, 4096))})
+	if small <= 0 || large-small < 4096 {
+		t.Errorf("EstimateRow small=%d large=%d; want large to account for payload", small, large)
+	}
+	nested := EstimateValue([]interface{}{"abc", map[string]interface{}{"k": "value"}})
+	if nested <= EstimateValue("abc") {
+		t.Errorf("EstimateValue(nested) = %d, want more than its first element", nested)
+	}
+}
@@ -0,0 +1,50 @@
+package memguard
+
+import "time"
+
+// Rough per-item overheads of the Go runtime representation, in bytes
+const (
+	mapEntryOverhead = 48
+	valueOverhead    = 16
+)
+
+// EstimateRow approximates the in-memory size of a result row, including its
+// map entries. The estimate is deliberately simple; it only needs to be in
+// the right order of magnitude for budget accounting.
+func EstimateRow(row map[string]interface{}) int64 {
+	size := int64(mapEntryOverhead)
+	for key, value := range row {
+		size += mapEntryOverhead + int64(len(key)) + EstimateValue(value)
+	}
+	return size
+}
+
+// EstimateValue approximates the in-memory size of a single value.
+func EstimateValue(value interface{}) int64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		return valueOverhead + int64(len(v))
+	case []byte:
+		return valueOverhead + int64(len(v))
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return valueOverhead
+	case time.Time:
+		return valueOverhead + 24
+	case []interface{}:
+		size := int64(valueOverhead)
+		for _, item := range v {
+			size += EstimateValue(item)
+		}
+		return size
+	case map[string]interface{}:
+		size := int64(valueOverhead)
+		for key, item := range v {
+			size += mapEntryOverhead + int64(len(key)) + EstimateValue(item)
+		}
+		return size
+	default:
+		return valueOverhead * 4
+	}
+}
@@ -0,0 +1,169 @@
+// Package pii flags likely personal data in query results before they reach
+// an LLM: email addresses, US social security numbers, and phone numbers in
+// values, and columns the operator has tagged as personal data. It can also
+// mask what it finds.
+package pii
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of personal data the scanner reports
+const (
+	KindEmail  = "email"
+	KindSSN    = "ssn"
+	KindPhone  = "phone"
+	KindTagged = "tagged column"
+)
+
+// valuePatterns are checked in order; SSNs come before phone numbers so a
+// value is reported as the more specific kind
+var valuePatterns = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{KindEmail, regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	{KindSSN, regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	{KindPhone, regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{3}\)\s?|\b\d{3}[\s.-])\d{3}[\s.-]\d{4}\b|\+\d{10,14}\b`)},
+}
+
+// Finding counts the values of one kind found under one column. Column is
+// empty for values outside any column, such as plain text responses.
+type Finding struct {
+	Column string `json:"column,omitempty"`
+	Kind   string `json:"kind"`
+	Count  int    `json:"count"`
+}
+
+// String describes the finding for a warning message.
+func (f Finding) String() string {
+	switch {
+	case f.Kind == KindTagged:
+		return fmt.Sprintf("column %q is tagged as personal data (%d values)", f.Column, f.Count)
+	case f.Column != "":
+		return fmt.Sprintf("%d values in column %q look like %s", f.Count, f.Column, describeKind(f.Kind))
+	default:
+		return fmt.Sprintf("%d values look like %s", f.Count, describeKind(f.Kind))
+	}
+}
+
+func describeKind(kind string) string {
+	switch kind {
+	case KindEmail:
+		return "email addresses"
+	case KindSSN:
+		return "social security numbers"
+	case KindPhone:
+		return "phone numbers"
+	}
+	return kind
+}
+
+// Scanner finds personal data in decoded JSON values.
+type Scanner struct {
+	columns []string // lowercase column name globs
+	mask    bool
+}
+
+// NewScanner creates a scanner. Columns are case-insensitive glob patterns
+// (such as email or *_ssn) naming columns tagged as personal data. When mask
+// is set, the values found are replaced with a [REDACTED:kind] marker.
+func NewScanner(columns []string, mask bool) *Scanner {
+	s := &Scanner{mask: mask}
+	for _, column := range columns {
+		s.columns = append(s.columns, strings.ToLower(column))
+	}
+	return s
+}
+
+// Masks reports whether the scanner replaces the values it finds.
+func (s *Scanner) Masks() bool {
+	return s.mask
+}
+
+// Scan walks a value decoded from JSON and returns it, masked when masking
+// is enabled, along with what was found. Object keys are treated as column
+// names.
+func (s *Scanner) Scan(value interface{}) (interface{}, []Finding) {
+	counts := make(map[Finding]int)
+	scanned := s.walk(value, "", counts)
+	return scanned, sortedFindings(counts)
+}
+
+// ScanText scans plain text that is not JSON.
+func (s *Scanner) ScanText(text string) (string, []Finding) {
+	counts := make(map[Finding]int)
+	scanned := s.scanString(text, "", counts)
+	return scanned, sortedFindings(counts)
+}
+
+func (s *Scanner) walk(value interface{}, column string, counts map[Finding]int) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if s.tagged(key) && item != nil {
+				counts[Finding{Column: key, Kind: KindTagged}]++
+				if s.mask {
+					v[key] = "[REDACTED:" + key + "]"
+				}
+				continue
+			}
+			v[key] = s.walk(item, key, counts)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = s.walk(item, column, counts)
+		}
+		return v
+	case string:
+		return s.scanString(v, column, counts)
+	default:
+		return value
+	}
+}
+
+func (s *Scanner) scanString(value, column string, counts map[Finding]int) string {
+	for _, p := range valuePatterns {
+		matches := p.pattern.FindAllStringIndex(value, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		counts[Finding{Column: column, Kind: p.kind}] += len(matches)
+		if s.mask {
+			value = p.pattern.ReplaceAllString(value, "[REDACTED:"+p.kind+"]")
+		}
+	}
+	return value
+}
+
+// tagged reports whether a column matches one of the tagged column patterns
+func (s *Scanner) tagged(column string) bool {
+	column = strings.ToLower(column)
+	for _, pattern := range s.columns {
+		if ok, _ := path.Match(pattern, column); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedFindings turns per-finding counts into a stable list
+func sortedFindings(counts map[Finding]int) []Finding {
+	findings := make([]Finding, 0, len(counts))
+	for finding, count := range counts {
+		finding.Count = count
+		findings = append(findings, finding)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Column != findings[j].Column {
+			return findings[i].Column < findings[j].Column
+		}
+		return findings[i].Kind < findings[j].Kind
+	})
+	return findings
+}
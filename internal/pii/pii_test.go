@@ -0,0 +1,59 @@
+package pii
+
+import (
+	"reflect"
+	"testing"
+)
+
+func rows() []interface{} {
+	return []interface{}{
+		map[string]interface{}{"id": "1", "contact": "alice@example.com", "note": "call +1 555-123-4567", "tax_ssn": "123-45-6789"},
+		map[string]interface{}{"id": "2", "contact": "bob@example.org", "note": "shipped 2026-10-17", "tax_ssn": nil},
+	}
+}
+
+func TestScanWarns(t *testing.T) {
+	scanned, findings := NewScanner([]string{"*_SSN"}, false).Scan(rows())
+
+	want := []Finding{
+		{Column: "contact", Kind: KindEmail, Count: 2},
+		{Column: "note", Kind: KindPhone, Count: 1},
+		{Column: "tax_ssn", Kind: KindTagged, Count: 1},
+	}
+	if !reflect.DeepEqual(findings, want) {
+		t.Errorf("Scan() findings = %+v, want %+v", findings, want)
+	}
+	if !reflect.DeepEqual(scanned, rows()) {
+		t.Error("Scan() changed values without masking")
+	}
+}
+
+func TestScanMasks(t *testing.T) {
+	scanned, _ := NewScanner([]string{"tax_ssn"}, true).Scan(rows())
+
+	first := scanned.([]interface{})[0].(map[string]interface{})
+	if first["contact"] != "[REDACTED:email]" || first["note"] != "call [REDACTED:phone]" || first["tax_ssn"] != "[REDACTED:tax_ssn]" {
+		t.Errorf("Scan() masked row = %v", first)
+	}
+	if first["id"] != "1" {
+		t.Errorf("Scan() masked id = %v, want it unchanged", first["id"])
+	}
+}
+
+func TestScanText(t *testing.T) {
+	text, findings := NewScanner(nil, true).ScanText("SSN 078-05-1120 on file")
+	if text != "SSN [REDACTED:ssn] on file" {
+		t.Errorf("ScanText() = %q", text)
+	}
+	if len(findings) != 1 || findings[0].Kind != KindSSN || findings[0].String() != "1 values look like social security numbers" {
+		t.Errorf("ScanText() findings = %+v", findings)
+	}
+}
+
+func TestScanIgnoresLookalikes(t *testing.T) {
+	for _, value := range []string{"2026-10-17", "10.0.0.1", "order 12345678", "v1.2.3", "12:30:45"} {
+		if _, findings := NewScanner(nil, false).ScanText(value); len(findings) > 0 {
+			t.Errorf("ScanText(%q) = %+v, want no findings", value, findings)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package profiles defines named environments, such as dev, staging, and
+// prod, that a tool call selects with its profile argument. Each profile has
+// its own Trino cluster, allowlists, and write mode.
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/tenancy"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is one environment.
+type Profile struct {
+	Name              string          `yaml:"name"`
+	Cluster           tenancy.Cluster `yaml:"trino"`
+	Catalogs          []string        `yaml:"allowed_catalogs,omitempty"`
+	Schemas           []string        `yaml:"allowed_schemas,omitempty"` // catalog.schema
+	Tables            []string        `yaml:"allowed_tables,omitempty"`  // catalog.schema.table
+	AllowWriteQueries bool            `yaml:"allow_write_queries,omitempty"`
+}
+
+// Load reads profiles from a YAML file with a top-level profiles list.
+func Load(path string) ([]Profile, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-configured profiles file
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates profiles.
+func Parse(data []byte) ([]Profile, error) {
+	var file struct {
+		Profiles []Profile `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles file: %w", err)
+	}
+	if len(file.Profiles) == 0 {
+		return nil, errors.New("profiles file defines no profiles")
+	}
+	seen := make(map[string]bool)
+	for i, profile := range file.Profiles {
+		if profile.Name == "" {
+			return nil, fmt.Errorf("profile %d: name is required", i+1)
+		}
+		if seen[profile.Name] {
+			return nil, fmt.Errorf("profile %s: duplicate name", profile.Name)
+		}
+		seen[profile.Name] = true
+		if err := profile.Cluster.Validate(); err != nil {
+			return nil, fmt.Errorf("profile %s: %w", profile.Name, err)
+		}
+		for _, schema := range profile.Schemas {
+			if strings.Count(schema, ".") != 1 {
+				return nil, fmt.Errorf("profile %s: allowed schema %q must be catalog.schema", profile.Name, schema)
+			}
+		}
+		for _, table := range profile.Tables {
+			if strings.Count(table, ".") != 2 {
+				return nil, fmt.Errorf("profile %s: allowed table %q must be catalog.schema.table", profile.Name, table)
+			}
+		}
+	}
+	return file.Profiles, nil
+}
+
+// Config derives the profile's Trino configuration from the server's. The
+// cluster fields the profile sets replace the server's, and the profile's
+// allowlists and write mode replace the server's entirely, so a profile
+// never inherits write access.
+func (p Profile) Config(base *config.TrinoConfig) (*config.TrinoConfig, error) {
+	cfg := *base
+	if err := p.Cluster.Apply(&cfg); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", p.Name, err)
+	}
+	// Workload routing targets the server's own environment
+	cfg.BatchHost = ""
+
+	cfg.AllowedCatalogs = append([]string(nil), p.Catalogs...)
+	cfg.AllowedSchemas = append([]string(nil), p.Schemas...)
+	cfg.AllowedTables = append([]string(nil), p.Tables...)
+	cfg.AllowWriteQueries = p.AllowWriteQueries
+	return &cfg, nil
+}
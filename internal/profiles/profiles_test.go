@@ -0,0 +1,84 @@
+package profiles
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/tenancy"
+)
+
+const sampleProfilesFile = `
+profiles:
+  - name: staging
+    trino:
+      host: trino.staging.example.com
+      catalog: iceberg_staging
+    allowed_catalogs: [iceberg_staging]
+    allow_write_queries: true
+  - name: prod
+    trino:
+      host: trino.prod.example.com
+    allowed_schemas: [iceberg_prod.sales]
+`
+
+func TestParse(t *testing.T) {
+	profiles, err := Parse([]byte(sampleProfilesFile))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != "staging" || !profiles[0].AllowWriteQueries || profiles[1].Cluster.Host != "trino.prod.example.com" {
+		t.Errorf("Parse() = %+v", profiles)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{"empty", "profiles: []", "no profiles"},
+		{"missing name", "profiles: [{allowed_catalogs: [hive]}]", "name is required"},
+		{"duplicate name", "profiles: [{name: prod}, {name: prod}]", "duplicate name"},
+		{"two passwords", "profiles: [{name: prod, trino: {password: x, password_file: /p}}]", "not both"},
+		{"bad schema", "profiles: [{name: prod, allowed_schemas: [sales]}]", "catalog.schema"},
+		{"bad table", "profiles: [{name: prod, allowed_tables: [sales.orders]}]", "catalog.schema.table"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.file)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProfileConfig(t *testing.T) {
+	base := &config.TrinoConfig{
+		Host: "trino.dev", Port: 8080, Scheme: "http", User: "svc", Catalog: "hive",
+		AllowedCatalogs: []string{"hive"}, AllowWriteQueries: true, BatchHost: "batch.trino", MaxRows: 1000,
+	}
+
+	prod := Profile{Name: "prod", Cluster: tenancy.Cluster{Host: "trino.prod"}, Schemas: []string{"iceberg_prod.sales"}}
+	cfg, err := prod.Config(base)
+	if err != nil {
+		t.Fatalf("Config() error = %v", err)
+	}
+	if cfg.Host != "trino.prod" || cfg.User != "svc" || cfg.MaxRows != 1000 {
+		t.Errorf("cluster settings = %+v", cfg)
+	}
+	if cfg.AllowedCatalogs != nil || !reflect.DeepEqual(cfg.AllowedSchemas, []string{"iceberg_prod.sales"}) {
+		t.Errorf("allowlists = %v %v, want the profile's", cfg.AllowedCatalogs, cfg.AllowedSchemas)
+	}
+	if cfg.AllowWriteQueries {
+		t.Error("AllowWriteQueries inherited from the server, want the profile's setting")
+	}
+	if cfg.BatchHost != "" {
+		t.Errorf("BatchHost = %q, want workload routing off for profiles", cfg.BatchHost)
+	}
+	if base.Host != "trino.dev" || !base.AllowWriteQueries {
+		t.Error("Config() modified the server configuration")
+	}
+}
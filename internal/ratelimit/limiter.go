@@ -0,0 +1,74 @@
+// Package ratelimit enforces per-identity tool call quotas. A local token
+// bucket limiter covers single-replica deployments; a Redis-backed limiter
+// enforces one quota across replicas and degrades to local limits when Redis
+// is unavailable.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported limiter backends
+const (
+	BackendLocal = "local"
+	BackendRedis = "redis"
+)
+
+// Decision is the outcome of a rate limit check.
+type Decision struct {
+	Allowed    bool
+	Limit      int           // requests allowed per window
+	Remaining  int           // requests left in the current window
+	RetryAfter time.Duration // time until the next request is allowed (when denied)
+}
+
+// Limiter decides whether a request identified by key may proceed.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Decision, error)
+	Close() error
+}
+
+// Options configures limiter construction.
+type Options struct {
+	Backend   string // local (default) or redis
+	PerMinute int    // sustained requests per minute per key
+	Burst     int    // local bucket capacity (default: PerMinute)
+	RedisURL  string // required for the redis backend
+	KeyPrefix string // redis key prefix (default: "mcp-trino:ratelimit:")
+}
+
+// NewLimiter creates a limiter for the configured backend. The redis backend
+// is wrapped so that backend errors fall back to a local limiter.
+func NewLimiter(opts Options) (Limiter, error) {
+	if opts.PerMinute <= 0 {
+		return nil, fmt.Errorf("rate limit must be positive, got %d", opts.PerMinute)
+	}
+	burst := opts.Burst
+	if burst <= 0 {
+		burst = opts.PerMinute
+	}
+	local := NewLocalLimiter(opts.PerMinute, burst)
+
+	switch strings.ToLower(strings.TrimSpace(opts.Backend)) {
+	case "", BackendLocal:
+		return local, nil
+	case BackendRedis:
+		if opts.RedisURL == "" {
+			return nil, fmt.Errorf("redis rate limiter requires MCP_REDIS_URL")
+		}
+		prefix := opts.KeyPrefix
+		if prefix == "" {
+			prefix = "mcp-trino:ratelimit:"
+		}
+		remote, err := NewRedisLimiter(opts.RedisURL, prefix, opts.PerMinute, time.Minute)
+		if err != nil {
+			return nil, err
+		}
+		return NewFallbackLimiter(remote, local), nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit backend %q (supported: local, redis)", opts.Backend)
+	}
+}
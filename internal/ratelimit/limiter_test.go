@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLocalLimiterBurstAndRefill(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewLocalLimiter(60, 2) // 1 token per second, burst of 2
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		d, err := limiter.Allow(ctx, "alice")
+		if err != nil || !d.Allowed {
+			t.Fatalf("request %d: Allow() = (%+v, %v), want allowed", i+1, d, err)
+		}
+	}
+
+	d, _ := limiter.Allow(ctx, "alice")
+	if d.Allowed {
+		t.Fatal("expected third request within burst window to be denied")
+	}
+	if d.RetryAfter <= 0 || d.RetryAfter > time.Second {
+		t.Errorf("RetryAfter = %v, want (0, 1s]", d.RetryAfter)
+	}
+
+	// Other identities have independent buckets
+	if d, _ := limiter.Allow(ctx, "bob"); !d.Allowed {
+		t.Error("expected bob to be allowed independently of alice")
+	}
+
+	now = now.Add(time.Second)
+	if d, _ := limiter.Allow(ctx, "alice"); !d.Allowed {
+		t.Error("expected alice to be allowed after refill")
+	}
+}
+
+type stubLimiter struct {
+	decision Decision
+	err      error
+	calls    int
+	closed   bool
+}
+
+func (s *stubLimiter) Allow(context.Context, string) (Decision, error) {
+	s.calls++
+	return s.decision, s.err
+}
+
+func (s *stubLimiter) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestFallbackLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("uses primary when healthy", func(t *testing.T) {
+		primary := &stubLimiter{decision: Decision{Allowed: false}}
+		fallback := &stubLimiter{decision: Decision{Allowed: true}}
+		limiter := NewFallbackLimiter(primary, fallback)
+
+		d, err := limiter.Allow(ctx, "alice")
+		if err != nil || d.Allowed {
+			t.Fatalf("Allow() = (%+v, %v), want primary denial", d, err)
+		}
+		if fallback.calls != 0 {
+			t.Errorf("fallback called %d times, want 0", fallback.calls)
+		}
+	})
+
+	t.Run("degrades to fallback on primary error", func(t *testing.T) {
+		primary := &stubLimiter{err: errors.New("connection refused")}
+		fallback := &stubLimiter{decision: Decision{Allowed: true}}
+		limiter := NewFallbackLimiter(primary, fallback)
+
+		d, err := limiter.Allow(ctx, "alice")
+		if err != nil || !d.Allowed {
+			t.Fatalf("Allow() = (%+v, %v), want fallback decision", d, err)
+		}
+		if fallback.calls != 1 {
+			t.Errorf("fallback called %d times, want 1", fallback.calls)
+		}
+
+		if err := limiter.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if !primary.closed || !fallback.closed {
+			t.Error("expected both limiters to be closed")
+		}
+	})
+}
+
+func TestNewLimiter(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{"local default", Options{PerMinute: 10}, false},
+		{"zero rate", Options{PerMinute: 0}, true},
+		{"redis without URL", Options{Backend: "redis", PerMinute: 10}, true},
+		{"redis with URL", Options{Backend: "redis", PerMinute: 10, RedisURL: "redis://localhost:6379"}, false},
+		{"unknown backend", Options{Backend: "memcached", PerMinute: 10}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := NewLimiter(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewLimiter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if limiter != nil {
+				_ = limiter.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// idleBucketTTL controls when untouched buckets are dropped
+const idleBucketTTL = 10 * time.Minute
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// LocalLimiter is an in-process token bucket limiter keyed by identity.
+type LocalLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	perMinute int
+	rate      float64 // tokens per second
+	burst     float64
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// NewLocalLimiter creates a token bucket limiter refilling perMinute tokens
+// per minute up to burst.
+func NewLocalLimiter(perMinute, burst int) *LocalLimiter {
+	return &LocalLimiter{
+		buckets:   make(map[string]*bucket),
+		perMinute: perMinute,
+		rate:      float64(perMinute) / 60,
+		burst:     float64(burst),
+		now:       time.Now,
+	}
+}
+
+func (l *LocalLimiter) Allow(_ context.Context, key string) (Decision, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweepLocked(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.lastSeen).Seconds()
+	b.tokens = math.Min(l.burst, b.tokens+elapsed*l.rate)
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return Decision{Allowed: true, Limit: l.perMinute, Remaining: int(b.tokens)}, nil
+	}
+
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return Decision{Allowed: false, Limit: l.perMinute, Remaining: 0, RetryAfter: wait}, nil
+}
+
+func (l *LocalLimiter) Close() error {
+	return nil
+}
+
+// sweepLocked drops buckets idle long enough to have fully refilled.
+// Callers must hold l.mu.
+func (l *LocalLimiter) sweepLocked(now time.Time) {
+	if now.Sub(l.lastSweep) < idleBucketTTL {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idleBucketTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
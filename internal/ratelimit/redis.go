@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fixedWindowScript increments the window counter and sets its expiry on first use.
+// Returns the current count and the remaining TTL in milliseconds.
+var fixedWindowScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+  redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+// redisCheckTimeout bounds each Redis round-trip so an unreachable backend
+// degrades to local limits quickly instead of stalling tool calls
+const redisCheckTimeout = 500 * time.Millisecond
+
+// RedisLimiter is a fixed-window limiter whose counters live in Redis, so
+// every replica enforces the same quota.
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+	limit  int
+	window time.Duration
+	now    func() time.Time
+}
+
+// NewRedisLimiter connects to Redis and enforces limit requests per window.
+func NewRedisLimiter(redisURL, prefix string, limit int, window time.Duration) (*RedisLimiter, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	return &RedisLimiter{
+		client: redis.NewClient(opts),
+		prefix: prefix,
+		limit:  limit,
+		window: window,
+		now:    time.Now,
+	}, nil
+}
+
+func (r *RedisLimiter) Allow(ctx context.Context, key string) (Decision, error) {
+	windowStart := r.now().UnixNano() / int64(r.window)
+	redisKey := r.prefix + key + ":" + strconv.FormatInt(windowStart, 10)
+
+	ctx, cancel := context.WithTimeout(ctx, redisCheckTimeout)
+	defer cancel()
+
+	res, err := fixedWindowScript.Run(ctx, r.client, []string{redisKey}, r.window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return Decision{}, fmt.Errorf("redis rate limit check failed: %w", err)
+	}
+	if len(res) != 2 {
+		return Decision{}, fmt.Errorf("unexpected redis rate limit response: %v", res)
+	}
+
+	count, ttlMillis := int(res[0]), res[1]
+	if count <= r.limit {
+		return Decision{Allowed: true, Limit: r.limit, Remaining: r.limit - count}, nil
+	}
+	retryAfter := time.Duration(ttlMillis) * time.Millisecond
+	if retryAfter <= 0 {
+		retryAfter = r.window
+	}
+	return Decision{Allowed: false, Limit: r.limit, RetryAfter: retryAfter}, nil
+}
+
+func (r *RedisLimiter) Close() error {
+	return r.client.Close()
+}
+
+// fallbackLogInterval throttles degradation warnings
+const fallbackLogInterval = time.Minute
+
+// FallbackLimiter consults the primary (shared) limiter and degrades to the
+// fallback (local) limiter whenever the primary returns an error, so an
+// outage of the shared backend never blocks or unthrottles all traffic.
+type FallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+
+	mu         sync.Mutex
+	lastWarned time.Time
+}
+
+// NewFallbackLimiter wraps primary with a fallback limiter.
+func NewFallbackLimiter(primary, fallback Limiter) *FallbackLimiter {
+	return &FallbackLimiter{primary: primary, fallback: fallback}
+}
+
+func (f *FallbackLimiter) Allow(ctx context.Context, key string) (Decision, error) {
+	decision, err := f.primary.Allow(ctx, key)
+	if err == nil {
+		return decision, nil
+	}
+
+	f.mu.Lock()
+	if time.Since(f.lastWarned) >= fallbackLogInterval {
+		f.lastWarned = time.Now()
+		log.Printf("WARNING: Distributed rate limiter unavailable, enforcing per-replica limits: %v", err)
+	}
+	f.mu.Unlock()
+
+	return f.fallback.Allow(ctx, key)
+}
+
+func (f *FallbackLimiter) Close() error {
+	fallbackErr := f.fallback.Close()
+	if err := f.primary.Close(); err != nil {
+		return err
+	}
+	return fallbackErr
+}
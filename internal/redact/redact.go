@@ -0,0 +1,212 @@
+// Package redact keeps the values of sensitive columns out of logs, audit
+// records, and error messages. It complements PII masking: the results
+// returned to clients are unchanged, but literals compared with a redacted
+// column in query text, and quoted values in errors from queries that touch
+// one, are replaced with a marker wherever the server records or reports
+// them.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Marker replaces redacted values
+const Marker = "[REDACTED]"
+
+// Building blocks for matching comparisons in SQL text
+const (
+	identifier = `(?:"(?:[^"]|"")+"|[A-Za-z_][A-Za-z0-9_]*)`
+	literal    = `(?:'(?:[^']|'')*'|-?\d+(?:\.\d+)?)`
+	operator   = `(?:=|<>|!=|<=|>=|<|>|(?i:not\s+)?(?i:like|in)\b)`
+	value      = `(?:` + literal + `|\(\s*` + literal + `(?:\s*,\s*` + literal + `)*\s*\))`
+)
+
+var (
+	// columnFirst matches "column op value", such as ssn = '123-45-6789'
+	columnFirst = regexp.MustCompile(`(` + identifier + `(?:\.` + identifier + `)*)(\s*` + operator + `\s*)(` + value + `)`)
+
+	// valueFirst matches "value op column", such as '123-45-6789' = ssn. The
+	// leading group keeps digits inside identifiers from matching as numbers.
+	valueFirst = regexp.MustCompile(`(^|[^A-Za-z0-9_."])(` + literal + `)(\s*(?:=|<>|!=|<=|>=|<|>)\s*)(` + identifier + `(?:\.` + identifier + `)*)`)
+
+	// identifierPattern finds the identifiers a query references
+	identifierPattern = regexp.MustCompile(identifier)
+
+	// stringLiteral matches SQL string literals, which are not identifiers
+	stringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+	// quotedValue matches values quoted in error messages: SQL string
+	// literals, as in Trino's "Cannot cast 'x' to INT", and Go-quoted values
+	// in parentheses, as in database/sql scan errors
+	quotedValue = regexp.MustCompile(`'(?:[^']|'')*'|\("(?:[^"\\]|\\.)*"\)`)
+)
+
+// Redactor recognizes redacted columns and scrubs their values from text.
+// A nil Redactor redacts nothing.
+type Redactor struct {
+	columns map[string]bool // lowercase column names
+	pattern *regexp.Regexp  // matched against lowercase column names (nil = none)
+}
+
+// New creates a redactor for the named columns and the columns whose names
+// match pattern, both case-insensitive. It returns nil when neither is set.
+func New(columns []string, pattern string) (*Redactor, error) {
+	if len(columns) == 0 && pattern == "" {
+		return nil, nil
+	}
+	r := &Redactor{columns: make(map[string]bool, len(columns))}
+	for _, column := range columns {
+		r.columns[strings.ToLower(column)] = true
+	}
+	if pattern != "" {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid column pattern %q: %w", pattern, err)
+		}
+		r.pattern = re
+	}
+	return r, nil
+}
+
+// Column reports whether a column's values are redacted. The name may be
+// quoted or qualified; only its last part is matched.
+func (r *Redactor) Column(name string) bool {
+	if r == nil {
+		return false
+	}
+	if parts := identifierPattern.FindAllString(name, -1); len(parts) > 0 {
+		name = parts[len(parts)-1]
+	}
+	if strings.HasPrefix(name, `"`) {
+		name = strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+	}
+	name = strings.ToLower(name)
+	return r.columns[name] || r.pattern != nil && r.pattern.MatchString(name)
+}
+
+// AnyColumn reports whether any of the columns is redacted.
+func (r *Redactor) AnyColumn(columns []string) bool {
+	for _, column := range columns {
+		if r.Column(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// References reports whether a query names a redacted column.
+func (r *Redactor) References(query string) bool {
+	if r == nil {
+		return false
+	}
+	for _, name := range identifierPattern.FindAllString(stringLiteral.ReplaceAllString(query, "''"), -1) {
+		if r.Column(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Query replaces the literals compared with redacted columns in SQL text,
+// including comparisons, LIKE patterns, and IN lists. Other literals are
+// kept so the text stays useful for debugging.
+func (r *Redactor) Query(query string) string {
+	if r == nil {
+		return query
+	}
+	query = replaceGroups(columnFirst, query, func(groups []string) string {
+		if !r.Column(groups[1]) {
+			return ""
+		}
+		return groups[1] + groups[2] + redactValue(groups[3])
+	})
+	return replaceGroups(valueFirst, query, func(groups []string) string {
+		if !r.Column(groups[4]) {
+			return ""
+		}
+		return groups[1] + redactValue(groups[2]) + groups[3] + groups[4]
+	})
+}
+
+// Message scrubs a log or error message. Comparisons with redacted columns
+// are always replaced, as errors often echo query text; when scrubValues is
+// set, because the message concerns a query or result that involves a
+// redacted column, every quoted value is replaced too.
+func (r *Redactor) Message(message string, scrubValues bool) string {
+	if r == nil {
+		return message
+	}
+	message = r.Query(message)
+	if scrubValues {
+		message = quotedValue.ReplaceAllStringFunc(message, func(quoted string) string {
+			if strings.HasPrefix(quoted, "(") {
+				return `("` + Marker + `")`
+			}
+			return "'" + Marker + "'"
+		})
+	}
+	return message
+}
+
+// Error scrubs an error's message like Message. The result unwraps to err,
+// so callers can still match it with errors.Is and errors.As.
+func (r *Redactor) Error(err error, scrubValues bool) error {
+	if r == nil || err == nil {
+		return err
+	}
+	message := r.Message(err.Error(), scrubValues)
+	if message == err.Error() {
+		return err
+	}
+	return &redactedError{message: message, err: err}
+}
+
+// redactedError is an error whose message has been scrubbed
+type redactedError struct {
+	message string
+	err     error
+}
+
+func (e *redactedError) Error() string { return e.message }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// redactValue replaces a literal or a parenthesized list of literals
+func redactValue(value string) string {
+	switch {
+	case strings.HasPrefix(value, "("):
+		return "('" + Marker + "')"
+	case strings.HasPrefix(value, "'"):
+		return "'" + Marker + "'"
+	default:
+		return Marker
+	}
+}
+
+// replaceGroups replaces each match with the result of fn, or leaves it
+// unchanged when fn returns ""
+func replaceGroups(re *regexp.Regexp, s string, fn func(groups []string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(s, -1) {
+		groups := make([]string, len(loc)/2)
+		for i := range groups {
+			if loc[2*i] >= 0 {
+				groups[i] = s[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		replacement := fn(groups)
+		if replacement == "" {
+			continue
+		}
+		b.WriteString(s[last:loc[0]])
+		b.WriteString(replacement)
+		last = loc[1]
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
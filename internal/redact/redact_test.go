@@ -0,0 +1,128 @@
+package redact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func newTestRedactor(t *testing.T) *Redactor {
+	t.Helper()
+	r, err := New([]string{"SSN", "email"}, `_token$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestNew(t *testing.T) {
+	if r, err := New(nil, ""); r != nil || err != nil {
+		t.Errorf("New() without columns = %v, %v, want nil", r, err)
+	}
+	if _, err := New(nil, "("); err == nil {
+		t.Error("New() accepted an invalid pattern")
+	}
+}
+
+func TestColumn(t *testing.T) {
+	r := newTestRedactor(t)
+	tests := map[string]bool{
+		"ssn":                  true,
+		"Email":                true,
+		`"SSN"`:                true,
+		"c.ssn":                true,
+		`hr."employees".email`: true,
+		"api_token":            true,
+		"API_TOKEN":            true,
+		"token_type":           false,
+		"ssn_last4":            false,
+		"name":                 false,
+	}
+	for name, want := range tests {
+		if got := r.Column(name); got != want {
+			t.Errorf("Column(%q) = %t, want %t", name, got, want)
+		}
+	}
+	var disabled *Redactor
+	if disabled.Column("ssn") || disabled.References("SELECT ssn FROM t") {
+		t.Error("nil Redactor redacts")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	r := newTestRedactor(t)
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{
+			"SELECT * FROM hr.employees WHERE ssn = '123-45-6789' AND dept = 'sales'",
+			"SELECT * FROM hr.employees WHERE ssn = '[REDACTED]' AND dept = 'sales'",
+		},
+		{
+			"SELECT * FROM users u WHERE u.email LIKE 'alice%' OR u.api_token <> 'abc''def'",
+			"SELECT * FROM users u WHERE u.email LIKE '[REDACTED]' OR u.api_token <> '[REDACTED]'",
+		},
+		{
+			`SELECT 1 FROM t WHERE "SSN" IN ('1', '2') AND email NOT IN ('a@example.com')`,
+			`SELECT 1 FROM t WHERE "SSN" IN ('[REDACTED]') AND email NOT IN ('[REDACTED]')`,
+		},
+		{
+			"SELECT * FROM t WHERE '123-45-6789' = ssn AND t1 = ssn AND id = 42",
+			"SELECT * FROM t WHERE '[REDACTED]' = ssn AND t1 = ssn AND id = 42",
+		},
+		{
+			"SELECT ssn FROM t WHERE ssn=123456789",
+			"SELECT ssn FROM t WHERE ssn=[REDACTED]",
+		},
+		{
+			"SELECT name FROM t WHERE name = 'bob'",
+			"SELECT name FROM t WHERE name = 'bob'",
+		},
+	}
+	for _, tt := range tests {
+		if got := r.Query(tt.query); got != tt.want {
+			t.Errorf("Query(%q) =\n%s\nwant\n%s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestReferences(t *testing.T) {
+	r := newTestRedactor(t)
+	if !r.References("SELECT CAST(ssn AS INTEGER) FROM hr.employees") {
+		t.Error("References() missed a redacted column")
+	}
+	if r.References("SELECT name FROM t WHERE note = 'ssn'") {
+		t.Error("References() matched a string literal")
+	}
+}
+
+func TestMessageAndError(t *testing.T) {
+	r := newTestRedactor(t)
+
+	message := "line 1:8: Cannot cast '123-45-6789' to INT"
+	if got := r.Message(message, false); got != message {
+		t.Errorf("Message() without values = %q", got)
+	}
+	if got := r.Message(message, true); got != "line 1:8: Cannot cast '[REDACTED]' to INT" {
+		t.Errorf("Message() = %q", got)
+	}
+	scan := `sql: Scan error on column index 0, name "ssn": converting driver.Value type string ("123-45-6789") to a int`
+	if got := r.Message(scan, true); got != `sql: Scan error on column index 0, name "ssn": converting driver.Value type string ("[REDACTED]") to a int` {
+		t.Errorf("Message() of scan error = %q", got)
+	}
+	// Query text echoed in errors is redacted even without scrubValues
+	if got := r.Message("mismatched input near ssn = '123-45-6789'", false); got != "mismatched input near ssn = '[REDACTED]'" {
+		t.Errorf("Message() of echoed query = %q", got)
+	}
+
+	err := r.Error(fmt.Errorf("query failed: %w: Cannot cast '1' to INT", context.DeadlineExceeded), true)
+	if err.Error() != "query failed: context deadline exceeded: Cannot cast '[REDACTED]' to INT" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Error() = %v", err)
+	}
+	plain := errors.New("no values here")
+	if got := r.Error(plain, true); got != plain {
+		t.Errorf("Error() wrapped an unchanged error: %v", got)
+	}
+}
@@ -0,0 +1,133 @@
+package resultstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ChunkMeta describes an oversized response stored as byte-range chunks.
+type ChunkMeta struct {
+	Handle     string    `json:"handle"`
+	Owner      string    `json:"owner"`
+	Tool       string    `json:"tool"`
+	TotalBytes int       `json:"total_bytes"`
+	ChunkSize  int       `json:"chunk_size"`
+	ChunkCount int       `json:"chunk_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Chunker splits oversized tool responses into chunks that fit within a
+// client's payload limit and persists them in a BlobStore.
+type Chunker struct {
+	store     BlobStore
+	chunkSize int
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+// NewChunker creates a chunker writing chunks of at most chunkSize bytes that
+// expire after ttl.
+func NewChunker(store BlobStore, chunkSize int, ttl time.Duration) *Chunker {
+	return &Chunker{
+		store:     store,
+		chunkSize: chunkSize,
+		ttl:       ttl,
+		now:       time.Now,
+	}
+}
+
+// ChunkSize returns the maximum chunk size in bytes.
+func (c *Chunker) ChunkSize() int {
+	return c.chunkSize
+}
+
+// Save stores text as chunks owned by owner. Chunks never split a UTF-8
+// sequence, so each chunk is valid text on its own.
+func (c *Chunker) Save(ctx context.Context, owner, tool, text string) (*ChunkMeta, error) {
+	now := c.now()
+	meta := &ChunkMeta{
+		Handle:     uuid.New().String(),
+		Owner:      owner,
+		Tool:       tool,
+		TotalBytes: len(text),
+		ChunkSize:  c.chunkSize,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(c.ttl),
+	}
+
+	for start := 0; start < len(text) || meta.ChunkCount == 0; {
+		end := start + c.chunkSize
+		if end >= len(text) {
+			end = len(text)
+		} else {
+			for end > start && !utf8.RuneStart(text[end]) {
+				end--
+			}
+			if end == start {
+				end = start + c.chunkSize
+			}
+		}
+		meta.ChunkCount++
+		if err := c.store.Put(ctx, chunkKey(meta.Handle, meta.ChunkCount), []byte(text[start:end]), c.ttl); err != nil {
+			return nil, fmt.Errorf("failed to store response chunk %d: %w", meta.ChunkCount, err)
+		}
+		start = end
+	}
+
+	// Metadata is written last so readers never observe a partially stored response
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chunk metadata: %w", err)
+	}
+	if err := c.store.Put(ctx, chunkMetaKey(meta.Handle), data, c.ttl); err != nil {
+		return nil, fmt.Errorf("failed to store chunk metadata: %w", err)
+	}
+	return meta, nil
+}
+
+// Chunk returns a 1-indexed chunk along with its metadata. Responses owned
+// by other identities are reported as not found.
+func (c *Chunker) Chunk(ctx context.Context, handle, owner string, chunk int) (*ChunkMeta, string, error) {
+	if _, err := uuid.Parse(handle); err != nil {
+		return nil, "", fmt.Errorf("invalid result handle: %s", handle)
+	}
+	data, err := c.store.Get(ctx, chunkMetaKey(handle))
+	if err != nil {
+		return nil, "", err
+	}
+	var meta ChunkMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, "", fmt.Errorf("corrupt chunk metadata: %w", err)
+	}
+	if meta.Owner != owner {
+		return nil, "", ErrNotFound
+	}
+	if chunk < 1 || chunk > meta.ChunkCount {
+		return nil, "", fmt.Errorf("chunk %d out of range (1-%d)", chunk, meta.ChunkCount)
+	}
+
+	data, err = c.store.Get(ctx, chunkKey(handle, chunk))
+	if errors.Is(err, ErrNotFound) {
+		return nil, "", fmt.Errorf("response chunk %d expired: %w", chunk, err)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return &meta, string(data), nil
+}
+
+func chunkMetaKey(handle string) string {
+	return handle + "/chunks"
+}
+
+func chunkKey(handle string, chunk int) string {
+	return handle + "/chunk/" + strconv.Itoa(chunk)
+}
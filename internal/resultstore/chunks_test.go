@@ -0,0 +1,90 @@
+package resultstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func newTestChunker(t *testing.T, chunkSize int) *Chunker {
+	t.Helper()
+	store, err := NewBlobStore(context.Background(), Options{Backend: BackendMemory})
+	if err != nil {
+		t.Fatalf("NewBlobStore() error = %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return NewChunker(store, chunkSize, time.Hour)
+}
+
+func TestChunkerRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	chunker := newTestChunker(t, 10)
+	text := strings.Repeat("abcdefghij", 4) + "xyz"
+
+	meta, err := chunker.Save(ctx, "alice", "execute_query", text)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if meta.ChunkCount != 5 || meta.TotalBytes != len(text) {
+		t.Fatalf("meta = %+v, want 5 chunks / %d bytes", meta, len(text))
+	}
+
+	var sb strings.Builder
+	for i := 1; i <= meta.ChunkCount; i++ {
+		_, chunk, err := chunker.Chunk(ctx, meta.Handle, "alice", i)
+		if err != nil {
+			t.Fatalf("Chunk(%d) error = %v", i, err)
+		}
+		sb.WriteString(chunk)
+	}
+	if sb.String() != text {
+		t.Errorf("reassembled text = %q, want %q", sb.String(), text)
+	}
+}
+
+func TestChunkerKeepsRunesWhole(t *testing.T) {
+	ctx := context.Background()
+	chunker := newTestChunker(t, 4)
+	text := "ééééé" // 2-byte runes
+
+	meta, err := chunker.Save(ctx, "alice", "execute_query", text)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	var sb strings.Builder
+	for i := 1; i <= meta.ChunkCount; i++ {
+		_, chunk, err := chunker.Chunk(ctx, meta.Handle, "alice", i)
+		if err != nil {
+			t.Fatalf("Chunk(%d) error = %v", i, err)
+		}
+		if !utf8.ValidString(chunk) {
+			t.Errorf("chunk %d is not valid UTF-8: %q", i, chunk)
+		}
+		sb.WriteString(chunk)
+	}
+	if sb.String() != text {
+		t.Errorf("reassembled text = %q, want %q", sb.String(), text)
+	}
+}
+
+func TestChunkerAccessControl(t *testing.T) {
+	ctx := context.Background()
+	chunker := newTestChunker(t, 10)
+	meta, err := chunker.Save(ctx, "alice", "execute_query", "hello")
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if _, _, err := chunker.Chunk(ctx, meta.Handle, "bob", 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Chunk() by other owner error = %v, want ErrNotFound", err)
+	}
+	if _, _, err := chunker.Chunk(ctx, meta.Handle, "alice", 2); err == nil {
+		t.Error("expected out of range error")
+	}
+	if _, _, err := chunker.Chunk(ctx, "not-a-uuid", "alice", 1); err == nil {
+		t.Error("expected invalid handle error")
+	}
+}
@@ -0,0 +1,168 @@
+package resultstore
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/spill"
+)
+
+// diskSweepInterval bounds how often Put scans for expired blobs
+const diskSweepInterval = time.Minute
+
+// diskBlobSuffix marks blob files so the sweeper never touches anything else
+const diskBlobSuffix = ".blob"
+
+// DiskBlobStore stores result blobs as files in a local spill directory,
+// encrypted when the spiller is configured to encrypt. Pages are local to
+// the replica that wrote them.
+type DiskBlobStore struct {
+	spiller *spill.Spiller
+	dir     string
+	now     func() time.Time
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// DiskResultDir returns the directory the disk store uses under a spill directory.
+func DiskResultDir(spillDir string) string {
+	return filepath.Join(spillDir, "results")
+}
+
+// IsDiskBlob reports whether a file name is a disk-stored result blob.
+func IsDiskBlob(name string) bool {
+	return strings.HasSuffix(name, diskBlobSuffix)
+}
+
+// NewDiskBlobStore creates a disk-backed blob store under the spiller's directory.
+func NewDiskBlobStore(spiller *spill.Spiller) (*DiskBlobStore, error) {
+	dir := DiskResultDir(spiller.Dir())
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create result directory: %w", err)
+	}
+	return &DiskBlobStore{spiller: spiller, dir: dir, now: time.Now}, nil
+}
+
+func (d *DiskBlobStore) Put(_ context.Context, key string, data []byte, ttl time.Duration) error {
+	sealed, err := d.spiller.Seal(data)
+	if err != nil {
+		return err
+	}
+
+	// Each blob starts with its expiry as Unix nanoseconds (0 = never)
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = d.now().Add(ttl).UnixNano()
+	}
+	content := make([]byte, 8+len(sealed))
+	binary.BigEndian.PutUint64(content, uint64(expiresAt))
+	copy(content[8:], sealed)
+
+	// Write then rename so readers never observe a partial blob
+	tmp, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("disk put failed: %w", err)
+	}
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("disk put failed: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("disk put failed: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("disk put failed: %w", err)
+	}
+
+	d.sweep()
+	return nil
+}
+
+func (d *DiskBlobStore) Get(_ context.Context, key string) ([]byte, error) {
+	content, err := os.ReadFile(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("disk get failed: %w", err)
+	}
+	if len(content) < 8 {
+		return nil, fmt.Errorf("corrupt result blob %s", key)
+	}
+	if d.expired(content) {
+		_ = os.Remove(d.path(key))
+		return nil, ErrNotFound
+	}
+	return d.spiller.Open(content[8:])
+}
+
+func (d *DiskBlobStore) Delete(_ context.Context, key string) error {
+	if err := os.Remove(d.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("disk delete failed: %w", err)
+	}
+	return nil
+}
+
+func (d *DiskBlobStore) Backend() string {
+	return BackendDisk
+}
+
+// Close leaves blobs on disk; they are removed as they expire.
+func (d *DiskBlobStore) Close() error {
+	return nil
+}
+
+// path maps a key to a flat, traversal-safe file name
+func (d *DiskBlobStore) path(key string) string {
+	return filepath.Join(d.dir, hex.EncodeToString([]byte(key))+diskBlobSuffix)
+}
+
+func (d *DiskBlobStore) expired(content []byte) bool {
+	expiresAt := int64(binary.BigEndian.Uint64(content[:8]))
+	return expiresAt != 0 && d.now().UnixNano() > expiresAt
+}
+
+// sweep removes expired blobs at most once per diskSweepInterval.
+func (d *DiskBlobStore) sweep() {
+	d.mu.Lock()
+	now := d.now()
+	if now.Sub(d.lastSweep) < diskSweepInterval {
+		d.mu.Unlock()
+		return
+	}
+	d.lastSweep = now
+	d.mu.Unlock()
+
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+	var header [8]byte
+	for _, entry := range entries {
+		if entry.IsDir() || !IsDiskBlob(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(d.dir, entry.Name())
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		n, _ := f.Read(header[:])
+		_ = f.Close()
+		if n == len(header) && d.expired(header[:]) {
+			_ = os.Remove(path)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package resultstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/spill"
+)
+
+func TestDiskBlobStore(t *testing.T) {
+	ctx := context.Background()
+	spiller, err := spill.New(spill.Options{Dir: t.TempDir(), Encrypt: true})
+	if err != nil {
+		t.Fatalf("spill.New() error = %v", err)
+	}
+	store, err := NewDiskBlobStore(spiller)
+	if err != nil {
+		t.Fatalf("NewDiskBlobStore() error = %v", err)
+	}
+
+	if err := store.Put(ctx, "id/page/1", []byte(`[{"a":1}]`), time.Hour); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	got, err := store.Get(ctx, "id/page/1")
+	if err != nil || string(got) != `[{"a":1}]` {
+		t.Fatalf("Get() = %q, %v", got, err)
+	}
+
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+
+	// Expired blobs are reported missing and removed
+	store.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err := store.Get(ctx, "id/page/1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(expired) error = %v, want ErrNotFound", err)
+	}
+	if _, err := os.Stat(store.path("id/page/1")); !os.IsNotExist(err) {
+		t.Error("expired blob was not removed")
+	}
+}
+
+func TestDiskBlobStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	spiller, _ := spill.New(spill.Options{Dir: t.TempDir()})
+	store, err := NewDiskBlobStore(spiller)
+	if err != nil {
+		t.Fatalf("NewDiskBlobStore() error = %v", err)
+	}
+
+	_ = store.Put(ctx, "old", []byte("x"), time.Minute)
+	store.now = func() time.Time { return time.Now().Add(time.Hour) }
+	_ = store.Put(ctx, "new", []byte("y"), time.Hour)
+
+	if _, err := os.Stat(store.path("old")); !os.IsNotExist(err) {
+		t.Error("sweep did not remove expired blob")
+	}
+	if _, err := os.Stat(store.path("new")); err != nil {
+		t.Error("sweep removed a live blob")
+	}
+}
+
+func TestPagerSaveFromDisk(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewBlobStore(ctx, Options{Backend: BackendDisk, SpillDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewBlobStore(disk) error = %v", err)
+	}
+	pager := NewPager(store, 3, time.Hour)
+
+	rows := makeRows(7)
+	meta, err := pager.SaveFrom(ctx, "alice", func(fn func(map[string]interface{}) error) error {
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, false)
+	if err != nil {
+		t.Fatalf("SaveFrom() error = %v", err)
+	}
+	if meta.PageCount != 3 || meta.TotalRows != 7 {
+		t.Fatalf("meta = %+v, want 3 pages / 7 rows", meta)
+	}
+	_, page, err := pager.Page(ctx, meta.QueryID, "alice", 3)
+	if err != nil || len(page) != 1 || page[0]["id"] != json.Number("7") {
+		t.Errorf("Page(3) = %v, %v", page, err)
+	}
+}
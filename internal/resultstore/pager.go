@@ -0,0 +1,177 @@
+package resultstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Meta describes a stored, paginated result.
+type Meta struct {
+	QueryID   string    `json:"query_id"`
+	Owner     string    `json:"owner"`
+	TotalRows int       `json:"total_rows"`
+	PageSize  int       `json:"page_size"`
+	PageCount int       `json:"page_count"`
+	Truncated bool      `json:"truncated"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Pager splits results into fixed-size pages and persists them in a BlobStore.
+type Pager struct {
+	store    BlobStore
+	pageSize int
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// NewPager creates a pager writing pages of pageSize rows that expire after ttl.
+func NewPager(store BlobStore, pageSize int, ttl time.Duration) *Pager {
+	return &Pager{
+		store:    store,
+		pageSize: pageSize,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// PageSize returns the number of rows per page.
+func (p *Pager) PageSize() int {
+	return p.pageSize
+}
+
+// Store returns the underlying blob store.
+func (p *Pager) Store() BlobStore {
+	return p.store
+}
+
+// Save persists rows as pages owned by owner and returns the result metadata.
+// Page 1 is stored too, so every page can be re-fetched by query ID.
+func (p *Pager) Save(ctx context.Context, owner string, rows []map[string]interface{}, truncated bool) (*Meta, error) {
+	return p.SaveFrom(ctx, owner, func(fn func(row map[string]interface{}) error) error {
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, truncated)
+}
+
+// SaveFrom persists rows produced by iterate as pages owned by owner. Only one
+// page is buffered at a time, so results streamed from a spill file are never
+// fully loaded into memory.
+func (p *Pager) SaveFrom(ctx context.Context, owner string, iterate func(fn func(row map[string]interface{}) error) error, truncated bool) (*Meta, error) {
+	now := p.now()
+	meta := &Meta{
+		QueryID:   uuid.New().String(),
+		Owner:     owner,
+		PageSize:  p.pageSize,
+		Truncated: truncated,
+		CreatedAt: now,
+		ExpiresAt: now.Add(p.ttl),
+	}
+
+	buffer := make([]map[string]interface{}, 0, p.pageSize)
+	flush := func() error {
+		meta.PageCount++
+		data, err := json.Marshal(buffer)
+		if err != nil {
+			return fmt.Errorf("failed to encode result page %d: %w", meta.PageCount, err)
+		}
+		if err := p.store.Put(ctx, pageKey(meta.QueryID, meta.PageCount), data, p.ttl); err != nil {
+			return fmt.Errorf("failed to store result page %d: %w", meta.PageCount, err)
+		}
+		buffer = buffer[:0]
+		return nil
+	}
+
+	err := iterate(func(row map[string]interface{}) error {
+		buffer = append(buffer, row)
+		meta.TotalRows++
+		if len(buffer) == p.pageSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(buffer) > 0 || meta.PageCount == 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Metadata is written last so readers never observe a partially stored result
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result metadata: %w", err)
+	}
+	if err := p.store.Put(ctx, metaKey(meta.QueryID), data, p.ttl); err != nil {
+		return nil, fmt.Errorf("failed to store result metadata: %w", err)
+	}
+	return meta, nil
+}
+
+// Meta loads result metadata, enforcing that the caller owns the result.
+// Results owned by other identities are reported as not found.
+func (p *Pager) Meta(ctx context.Context, queryID, owner string) (*Meta, error) {
+	if _, err := uuid.Parse(queryID); err != nil {
+		return nil, fmt.Errorf("invalid query_id: %s", queryID)
+	}
+	data, err := p.store.Get(ctx, metaKey(queryID))
+	if err != nil {
+		return nil, err
+	}
+	var meta Meta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("corrupt result metadata: %w", err)
+	}
+	if meta.Owner != owner {
+		return nil, ErrNotFound
+	}
+	return &meta, nil
+}
+
+// Page returns the rows of a 1-indexed page along with result metadata.
+func (p *Pager) Page(ctx context.Context, queryID, owner string, page int) (*Meta, []map[string]interface{}, error) {
+	meta, err := p.Meta(ctx, queryID, owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	if page < 1 || page > meta.PageCount {
+		return nil, nil, fmt.Errorf("page %d out of range (1-%d)", page, meta.PageCount)
+	}
+
+	data, err := p.store.Get(ctx, pageKey(queryID, page))
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil, fmt.Errorf("result page %d expired: %w", page, err)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	// Keep numbers exact, so BIGINT and DECIMAL values above 2^53 survive
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var rows []map[string]interface{}
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, nil, fmt.Errorf("corrupt result page %d: %w", page, err)
+	}
+	return meta, rows, nil
+}
+
+func metaKey(queryID string) string {
+	return queryID + "/meta"
+}
+
+func pageKey(queryID string, page int) string {
+	return queryID + "/page/" + strconv.Itoa(page)
+}
@@ -0,0 +1,117 @@
+package resultstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestPager(t *testing.T, pageSize int) *Pager {
+	t.Helper()
+	store, err := NewBlobStore(context.Background(), Options{Backend: BackendMemory})
+	if err != nil {
+		t.Fatalf("NewBlobStore() error = %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return NewPager(store, pageSize, time.Hour)
+}
+
+func makeRows(n int) []map[string]interface{} {
+	rows := make([]map[string]interface{}, n)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"id": float64(i + 1)}
+	}
+	return rows
+}
+
+func TestPagerSaveAndPage(t *testing.T) {
+	ctx := context.Background()
+	pager := newTestPager(t, 2)
+
+	meta, err := pager.Save(ctx, "alice", makeRows(5), false)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if meta.PageCount != 3 || meta.TotalRows != 5 {
+		t.Fatalf("meta = %+v, want 3 pages / 5 rows", meta)
+	}
+
+	tests := []struct {
+		page    int
+		wantIDs []json.Number
+	}{
+		{1, []json.Number{"1", "2"}},
+		{2, []json.Number{"3", "4"}},
+		{3, []json.Number{"5"}},
+	}
+	for _, tt := range tests {
+		_, rows, err := pager.Page(ctx, meta.QueryID, "alice", tt.page)
+		if err != nil {
+			t.Fatalf("Page(%d) error = %v", tt.page, err)
+		}
+		if len(rows) != len(tt.wantIDs) {
+			t.Fatalf("Page(%d) returned %d rows, want %d", tt.page, len(rows), len(tt.wantIDs))
+		}
+		for i, id := range tt.wantIDs {
+			if rows[i]["id"] != id {
+				t.Errorf("Page(%d)[%d].id = %v, want %v", tt.page, i, rows[i]["id"], id)
+			}
+		}
+	}
+
+	if _, _, err := pager.Page(ctx, meta.QueryID, "alice", 4); err == nil {
+		t.Error("expected error for out-of-range page")
+	}
+}
+
+func TestPagerSharedAcrossPagers(t *testing.T) {
+	ctx := context.Background()
+	store, _ := NewBlobStore(ctx, Options{})
+	replicaA := NewPager(store, 2, time.Hour)
+	replicaB := NewPager(store, 2, time.Hour)
+
+	meta, err := replicaA.Save(ctx, "alice", makeRows(3), false)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, rows, err := replicaB.Page(ctx, meta.QueryID, "alice", 2); err != nil || len(rows) != 1 {
+		t.Fatalf("Page(2) on other replica = (%v, %v), want 1 row", rows, err)
+	}
+}
+
+func TestPagerOwnership(t *testing.T) {
+	ctx := context.Background()
+	pager := newTestPager(t, 10)
+
+	meta, err := pager.Save(ctx, "alice", makeRows(1), false)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, _, err := pager.Page(ctx, meta.QueryID, "mallory", 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Page() by other user error = %v, want ErrNotFound", err)
+	}
+	if _, err := pager.Meta(ctx, "not-a-uuid", "alice"); err == nil {
+		t.Error("expected error for malformed query_id")
+	}
+}
+
+func TestPagerKeepsLargeNumbersExact(t *testing.T) {
+	ctx := context.Background()
+	pager := newTestPager(t, 10)
+
+	// Above 2^53, so a float64 round-trip would change it
+	rows := []map[string]interface{}{{"id": int64(1234567890123456789), "amount": json.Number("98765432109876543.21")}}
+	meta, err := pager.Save(ctx, "alice", rows, false)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	_, page, err := pager.Page(ctx, meta.QueryID, "alice", 1)
+	if err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+	if page[0]["id"] != json.Number("1234567890123456789") || page[0]["amount"] != json.Number("98765432109876543.21") {
+		t.Errorf("Page() = %v, want the stored numbers unchanged", page[0])
+	}
+}
@@ -0,0 +1,108 @@
+package resultstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// expiresAtMetadataKey records the logical expiry of an object. S3 lifecycle
+// rules should be configured on the prefix to physically delete old objects;
+// reads past the expiry are treated as missing.
+const expiresAtMetadataKey = "mcp-trino-expires-at"
+
+// S3BlobStore stores result blobs as S3 objects. Credentials and region are
+// resolved through the standard AWS SDK chain (env vars, shared config, IRSA).
+type S3BlobStore struct {
+	client *s3.Client
+	bucket string
+	prefix string
+	now    func() time.Time
+}
+
+// NewS3BlobStore creates an S3-backed blob store.
+func NewS3BlobStore(ctx context.Context, bucket, prefix string) (*S3BlobStore, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	return &S3BlobStore{
+		client: s3.NewFromConfig(cfg),
+		bucket: bucket,
+		prefix: prefix,
+		now:    time.Now,
+	}, nil
+}
+
+func (s *S3BlobStore) Put(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.prefix + key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	}
+	if ttl > 0 {
+		input.Metadata = map[string]string{
+			expiresAtMetadataKey: strconv.FormatInt(s.now().Add(ttl).Unix(), 10),
+		}
+	}
+	if _, err := s.client.PutObject(ctx, input); err != nil {
+		return fmt.Errorf("s3 put failed: %w", err)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("s3 get failed: %w", err)
+	}
+	defer func() { _ = out.Body.Close() }()
+
+	if raw, ok := out.Metadata[expiresAtMetadataKey]; ok {
+		if expiresAt, err := strconv.ParseInt(raw, 10, 64); err == nil && s.now().Unix() > expiresAt {
+			return nil, ErrNotFound
+		}
+	}
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("s3 read failed: %w", err)
+	}
+	return data, nil
+}
+
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	if err != nil {
+		return fmt.Errorf("s3 delete failed: %w", err)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) Backend() string {
+	return BackendS3
+}
+
+func (s *S3BlobStore) Close() error {
+	return nil
+}
@@ -0,0 +1,113 @@
+// Package resultstore keeps query results server-side so that pagination
+// cursors and large results can be served by any replica. Results are written
+// as independent page blobs, so fetching page N never loads the full result.
+package resultstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/spill"
+	"github.com/tuannvm/mcp-trino/internal/state"
+)
+
+// Supported result store backends
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+	BackendS3     = "s3"
+	BackendDisk   = "disk"
+)
+
+// ErrNotFound is returned when a result or page does not exist or has expired.
+var ErrNotFound = errors.New("result not found or expired")
+
+// BlobStore persists opaque blobs with a TTL.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, ttl time.Duration) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	Backend() string
+	Close() error
+}
+
+// Options configures blob store construction.
+type Options struct {
+	Backend      string // memory (default), redis, or s3
+	RedisURL     string // required for redis
+	S3Bucket     string // required for s3
+	S3Prefix     string // object key prefix for s3 (default: "mcp-trino/results/")
+	KeyPrefix    string // key prefix for redis (default: "mcp-trino:results:")
+	SpillDir     string // local directory for disk (default: system temp dir)
+	SpillEncrypt bool   // encrypt disk blobs with an ephemeral key
+}
+
+// NewBlobStore creates a blob store for the configured backend.
+func NewBlobStore(ctx context.Context, opts Options) (BlobStore, error) {
+	switch strings.ToLower(strings.TrimSpace(opts.Backend)) {
+	case "", BackendMemory:
+		return &stateBlobStore{store: state.NewMemoryStore()}, nil
+	case BackendRedis:
+		if opts.RedisURL == "" {
+			return nil, fmt.Errorf("redis result store requires MCP_REDIS_URL")
+		}
+		prefix := opts.KeyPrefix
+		if prefix == "" {
+			prefix = "mcp-trino:results:"
+		}
+		store, err := state.NewRedisStore(opts.RedisURL, prefix)
+		if err != nil {
+			return nil, err
+		}
+		return &stateBlobStore{store: store}, nil
+	case BackendS3:
+		if opts.S3Bucket == "" {
+			return nil, fmt.Errorf("s3 result store requires MCP_RESULT_S3_BUCKET")
+		}
+		prefix := opts.S3Prefix
+		if prefix == "" {
+			prefix = "mcp-trino/results/"
+		}
+		return NewS3BlobStore(ctx, opts.S3Bucket, prefix)
+	case BackendDisk:
+		spiller, err := spill.New(spill.Options{Dir: opts.SpillDir, Encrypt: opts.SpillEncrypt})
+		if err != nil {
+			return nil, err
+		}
+		return NewDiskBlobStore(spiller)
+	default:
+		return nil, fmt.Errorf("unsupported result store %q (supported: memory, redis, s3, disk)", opts.Backend)
+	}
+}
+
+// stateBlobStore adapts a state.Store (memory or redis) to BlobStore.
+type stateBlobStore struct {
+	store state.Store
+}
+
+func (s *stateBlobStore) Put(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	return s.store.Set(ctx, key, data, ttl)
+}
+
+func (s *stateBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.store.Get(ctx, key)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (s *stateBlobStore) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, key)
+}
+
+func (s *stateBlobStore) Backend() string {
+	return s.store.Backend()
+}
+
+func (s *stateBlobStore) Close() error {
+	return s.store.Close()
+}
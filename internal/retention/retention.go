@@ -0,0 +1,212 @@
+// Package retention enforces age and size limits on data the server keeps on
+// local disk, such as rotated audit logs, disk-stored results, and spill
+// files, so long-running deployments do not grow without bound.
+package retention
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy bounds how much of a target is kept. Zero values are unlimited.
+type Policy struct {
+	MaxAge   time.Duration // files last modified longer ago are removed
+	MaxBytes int64         // oldest files are removed until the total fits
+}
+
+// Enabled reports whether the policy limits anything.
+func (p Policy) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxBytes > 0
+}
+
+// Report describes one enforcement pass over a target.
+type Report struct {
+	Removed    int
+	FreedBytes int64
+	Unit       string // what Removed counts (default: "files")
+}
+
+// Target is one kind of retained data.
+type Target interface {
+	Name() string
+	Enforce(now time.Time) (Report, error)
+}
+
+// Files is a target made of the files in Dir whose names Match accepts.
+type Files struct {
+	Label  string
+	Dir    string
+	Match  func(name string) bool
+	Policy Policy
+}
+
+// Name returns the target's label.
+func (f *Files) Name() string {
+	return f.Label
+}
+
+// Enforce removes matching files older than MaxAge, then the oldest
+// remaining files until their total size is within MaxBytes.
+func (f *Files) Enforce(now time.Time) (Report, error) {
+	var report Report
+	entries, err := os.ReadDir(f.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return report, nil
+	}
+	if err != nil {
+		return report, err
+	}
+
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || !f.Match(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{path: filepath.Join(f.Dir, entry.Name()), size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	for _, candidate := range files {
+		expired := f.Policy.MaxAge > 0 && now.Sub(candidate.modTime) > f.Policy.MaxAge
+		oversize := f.Policy.MaxBytes > 0 && total > f.Policy.MaxBytes
+		if !expired && !oversize {
+			break
+		}
+		if err := os.Remove(candidate.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		report.Removed++
+		report.FreedBytes += candidate.size
+		total -= candidate.size
+	}
+	return report, nil
+}
+
+// Janitor enforces every registered target on an interval.
+type Janitor struct {
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	targets []Target
+}
+
+// New creates a janitor that sweeps every interval.
+func New(interval time.Duration) *Janitor {
+	return &Janitor{interval: interval, now: time.Now}
+}
+
+// Add registers a target.
+func (j *Janitor) Add(target Target) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.targets = append(j.targets, target)
+}
+
+// Targets returns the names of the registered targets.
+func (j *Janitor) Targets() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	names := make([]string, len(j.targets))
+	for i, target := range j.targets {
+		names[i] = target.Name()
+	}
+	return names
+}
+
+// Run sweeps immediately and then every interval until ctx is cancelled.
+func (j *Janitor) Run(ctx context.Context) {
+	j.Sweep()
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.Sweep()
+		}
+	}
+}
+
+// Sweep enforces every target once. Failures are logged and retried on the
+// next sweep.
+func (j *Janitor) Sweep() {
+	j.mu.Lock()
+	targets := append([]Target(nil), j.targets...)
+	j.mu.Unlock()
+
+	now := j.now()
+	for _, target := range targets {
+		report, err := target.Enforce(now)
+		if err != nil {
+			log.Printf("WARNING: Retention sweep of %s failed: %v", target.Name(), err)
+		}
+		if report.Removed > 0 {
+			unit := report.Unit
+			if unit == "" {
+				unit = "files"
+			}
+			log.Printf("INFO: Retention removed %d %s %s (%d bytes)", report.Removed, target.Name(), unit, report.FreedBytes)
+		}
+	}
+}
+
+// Rotator is an append-only log that can be rotated, such as the audit log.
+type Rotator interface {
+	Path() string
+	Size() int64
+	Rotate(now time.Time) (string, error)
+}
+
+// RotatedLog rotates a log once it reaches RotateBytes and applies Policy to
+// the rotated files, which are named after the log with a suffix.
+type RotatedLog struct {
+	Label       string
+	Log         Rotator
+	RotateBytes int64 // 0 = never rotate
+	Policy      Policy
+}
+
+// Name returns the target's label.
+func (r *RotatedLog) Name() string {
+	return r.Label
+}
+
+// Enforce rotates the log if it is too large, then prunes rotated files. The
+// live log itself is never removed.
+func (r *RotatedLog) Enforce(now time.Time) (Report, error) {
+	if r.RotateBytes > 0 && r.Log.Size() >= r.RotateBytes {
+		rotated, err := r.Log.Rotate(now)
+		if err != nil {
+			return Report{}, err
+		}
+		log.Printf("INFO: Rotated %s to %s", r.Label, rotated)
+	}
+	prefix := filepath.Base(r.Log.Path()) + "."
+	files := &Files{
+		Label:  r.Label,
+		Dir:    filepath.Dir(r.Log.Path()),
+		Match:  func(name string) bool { return strings.HasPrefix(name, prefix) },
+		Policy: r.Policy,
+	}
+	return files.Enforce(now)
+}
@@ -0,0 +1,126 @@
+package retention
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name string, size int, modTime time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, make([]byte, size), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func remaining(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFilesEnforce(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	writeFile(t, dir, "spill-old", 100, now.Add(-48*time.Hour))
+	writeFile(t, dir, "spill-a", 100, now.Add(-3*time.Hour))
+	writeFile(t, dir, "spill-b", 100, now.Add(-2*time.Hour))
+	writeFile(t, dir, "spill-c", 100, now.Add(-time.Hour))
+	writeFile(t, dir, "unrelated", 1000, now.Add(-72*time.Hour))
+
+	files := &Files{
+		Label:  "spill",
+		Dir:    dir,
+		Match:  func(name string) bool { return strings.HasPrefix(name, "spill-") },
+		Policy: Policy{MaxAge: 24 * time.Hour, MaxBytes: 250},
+	}
+	report, err := files.Enforce(now)
+	if err != nil {
+		t.Fatalf("Enforce() error = %v", err)
+	}
+	// The expired file goes first, then the oldest until 200 bytes remain
+	if report.Removed != 2 || report.FreedBytes != 200 {
+		t.Errorf("Enforce() = %+v, want 2 files and 200 bytes removed", report)
+	}
+	if got := remaining(t, dir); strings.Join(got, ",") != "spill-b,spill-c,unrelated" {
+		t.Errorf("remaining files = %v", got)
+	}
+
+	// A missing directory is not an error
+	missing := &Files{Label: "x", Dir: filepath.Join(dir, "missing"), Match: func(string) bool { return true }, Policy: Policy{MaxAge: time.Hour}}
+	if _, err := missing.Enforce(now); err != nil {
+		t.Errorf("Enforce() on missing directory = %v", err)
+	}
+}
+
+type fakeLog struct {
+	path    string
+	size    int64
+	rotated int
+}
+
+func (f *fakeLog) Path() string { return f.path }
+func (f *fakeLog) Size() int64  { return f.size }
+func (f *fakeLog) Rotate(now time.Time) (string, error) {
+	f.rotated++
+	f.size = 0
+	rotated := f.path + "." + now.Format("20060102T150405Z")
+	return rotated, os.WriteFile(rotated, nil, 0o600)
+}
+
+func TestRotatedLogEnforce(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	log := &fakeLog{path: filepath.Join(dir, "audit.jsonl"), size: 2048}
+	writeFile(t, dir, "audit.jsonl", 0, now)
+	writeFile(t, dir, "audit.jsonl.20260901T000000Z", 10, now.Add(-40*24*time.Hour))
+	writeFile(t, dir, "audit.jsonl.20261010T000000Z", 10, now.Add(-7*24*time.Hour))
+
+	target := &RotatedLog{Label: "audit log", Log: log, RotateBytes: 1024, Policy: Policy{MaxAge: 30 * 24 * time.Hour}}
+	report, err := target.Enforce(now)
+	if err != nil {
+		t.Fatalf("Enforce() error = %v", err)
+	}
+	if log.rotated != 1 || report.Removed != 1 {
+		t.Errorf("rotated %d times, removed %d files", log.rotated, report.Removed)
+	}
+	want := "audit.jsonl,audit.jsonl.20261010T000000Z,audit.jsonl.20261017T120000Z"
+	if got := remaining(t, dir); strings.Join(got, ",") != want {
+		t.Errorf("remaining files = %v, want %s", got, want)
+	}
+
+	// Below the size threshold the log is left alone
+	if _, err := target.Enforce(now); err != nil || log.rotated != 1 {
+		t.Errorf("second Enforce() rotated again (%d) or failed: %v", log.rotated, err)
+	}
+}
+
+func TestJanitorSweep(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "old", 10, time.Now().Add(-2*time.Hour))
+
+	janitor := New(time.Minute)
+	janitor.Add(&Files{Label: "test", Dir: dir, Match: func(string) bool { return true }, Policy: Policy{MaxAge: time.Hour}})
+	if got := janitor.Targets(); len(got) != 1 || got[0] != "test" {
+		t.Errorf("Targets() = %v", got)
+	}
+	janitor.Sweep()
+	if got := remaining(t, dir); len(got) != 0 {
+		t.Errorf("Sweep() left %v", got)
+	}
+}
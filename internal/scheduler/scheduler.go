@@ -0,0 +1,146 @@
+// Package scheduler caps concurrent Trino queries and queues excess queries
+// fairly between identities, so one caller flooding the server cannot starve
+// everyone else sharing it.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrQueueTimeout is returned when a query gives up waiting for a slot.
+var ErrQueueTimeout = errors.New("timed out waiting for a query slot")
+
+// Scheduler admits up to a fixed number of concurrent queries. Waiting queries
+// are grouped by identity and admitted round-robin between identities, FIFO
+// within each identity.
+type Scheduler struct {
+	maxConcurrent int
+
+	mu      sync.Mutex
+	running int
+	queues  map[string][]*waiter
+	order   []string // identities with waiters, in round-robin order
+}
+
+type waiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// New creates a scheduler admitting maxConcurrent queries at a time. A
+// non-positive limit disables scheduling.
+func New(maxConcurrent int) *Scheduler {
+	if maxConcurrent <= 0 {
+		return nil
+	}
+	return &Scheduler{
+		maxConcurrent: maxConcurrent,
+		queues:        make(map[string][]*waiter),
+	}
+}
+
+// Stats reports the number of running and queued queries.
+func (s *Scheduler) Stats() (running, queued int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, q := range s.queues {
+		queued += len(q)
+	}
+	return s.running, queued
+}
+
+// Acquire blocks until identity may run a query, returning a release function
+// that must be called when the query finishes. It returns ErrQueueTimeout if
+// ctx ends first. A nil Scheduler admits every query immediately.
+func (s *Scheduler) Acquire(ctx context.Context, identity string) (func(), error) {
+	if s == nil {
+		return func() {}, nil
+	}
+
+	s.mu.Lock()
+	// Only bypass the queue when nobody is waiting, otherwise fairness breaks
+	if s.running < s.maxConcurrent && len(s.order) == 0 {
+		s.running++
+		s.mu.Unlock()
+		return s.releaseFunc(), nil
+	}
+	w := &waiter{ready: make(chan struct{})}
+	if len(s.queues[identity]) == 0 {
+		s.order = append(s.order, identity)
+	}
+	s.queues[identity] = append(s.queues[identity], w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return s.releaseFunc(), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		if w.granted {
+			// Admitted concurrently with cancellation; hand the slot on
+			s.mu.Unlock()
+			s.releaseFunc()()
+			return nil, ErrQueueTimeout
+		}
+		s.removeLocked(identity, w)
+		s.mu.Unlock()
+		return nil, ErrQueueTimeout
+	}
+}
+
+func (s *Scheduler) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.running--
+			s.dispatchLocked()
+		})
+	}
+}
+
+// dispatchLocked admits waiters while slots are free, taking one waiter from
+// the identity at the head of the round-robin order and rotating it to the back.
+func (s *Scheduler) dispatchLocked() {
+	for s.running < s.maxConcurrent && len(s.order) > 0 {
+		identity := s.order[0]
+		s.order = s.order[1:]
+
+		queue := s.queues[identity]
+		w := queue[0]
+		if len(queue) == 1 {
+			delete(s.queues, identity)
+		} else {
+			s.queues[identity] = queue[1:]
+			s.order = append(s.order, identity)
+		}
+
+		s.running++
+		w.granted = true
+		close(w.ready)
+	}
+}
+
+func (s *Scheduler) removeLocked(identity string, w *waiter) {
+	queue := s.queues[identity]
+	for i, candidate := range queue {
+		if candidate == w {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	if len(queue) > 0 {
+		s.queues[identity] = queue
+		return
+	}
+	delete(s.queues, identity)
+	for i, candidate := range s.order {
+		if candidate == identity {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNilSchedulerAdmitsImmediately(t *testing.T) {
+	var s *Scheduler
+	release, err := s.Acquire(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	release()
+	if New(0) != nil {
+		t.Error("New(0) should disable scheduling")
+	}
+}
+
+func TestAcquireQueuesPastLimit(t *testing.T) {
+	s := New(1)
+	release, err := s.Acquire(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.Acquire(ctx, "bob"); !errors.Is(err, ErrQueueTimeout) {
+		t.Errorf("Acquire() past limit error = %v, want ErrQueueTimeout", err)
+	}
+	if running, queued := s.Stats(); running != 1 || queued != 0 {
+		t.Errorf("Stats() = %d running, %d queued; want 1, 0 after timeout", running, queued)
+	}
+
+	release()
+	release() // idempotent
+	if running, _ := s.Stats(); running != 0 {
+		t.Errorf("running = %d after release, want 0", running)
+	}
+}
+
+// TestRoundRobinBetweenIdentities verifies that a caller with many queued
+// queries cannot starve a caller with one.
+func TestRoundRobinBetweenIdentities(t *testing.T) {
+	s := New(1)
+	hold, _ := s.Acquire(context.Background(), "seed")
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	enqueue := func(identity string) {
+		before := countQueued(s, identity)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := s.Acquire(context.Background(), identity)
+			if err != nil {
+				t.Errorf("Acquire(%s) error = %v", identity, err)
+				return
+			}
+			mu.Lock()
+			order = append(order, identity)
+			mu.Unlock()
+			release()
+		}()
+		// Wait until the waiter is queued so arrival order is deterministic
+		for countQueued(s, identity) == before {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	enqueue("flood")
+	enqueue("flood")
+	enqueue("flood")
+	enqueue("quiet")
+
+	hold()
+	wg.Wait()
+
+	if len(order) != 4 {
+		t.Fatalf("order = %v, want 4 admissions", order)
+	}
+	if order[0] != "flood" || order[1] != "quiet" {
+		t.Errorf("order = %v, want quiet admitted second", order)
+	}
+}
+
+func countQueued(s *Scheduler, identity string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queues[identity])
+}
+
+func TestCancelledWaiterIsRemoved(t *testing.T) {
+	s := New(1)
+	hold, _ := s.Acquire(context.Background(), "alice")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Acquire(ctx, "bob")
+		done <- err
+	}()
+	for countQueued(s, "bob") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, ErrQueueTimeout) {
+		t.Errorf("cancelled Acquire() error = %v, want ErrQueueTimeout", err)
+	}
+
+	hold()
+	if running, queued := s.Stats(); running != 0 || queued != 0 {
+		t.Errorf("Stats() = %d running, %d queued; want 0, 0", running, queued)
+	}
+}
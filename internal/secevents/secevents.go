@@ -0,0 +1,35 @@
+// Package secevents forwards security-relevant events, such as
+// authentication failures, blocked writes, allowlist violations, and
+// behavioral anomalies, to a syslog collector (RFC 5424 over TCP or TLS) or
+// a webhook, separate from the server's general logs, so SOC tooling
+// receives them without parsing application output.
+package secevents
+
+import (
+	"time"
+)
+
+// Event types
+const (
+	TypeAuthFailure        = "auth_failure"
+	TypeBlockedWrite       = "blocked_write"
+	TypeAllowlistViolation = "allowlist_violation"
+	TypeAnomaly            = "anomaly"
+)
+
+// Event is one security-relevant occurrence.
+type Event struct {
+	Time       time.Time
+	Type       string
+	Kind       string // what was detected, for anomalies
+	User       string // empty when the caller is not authenticated
+	Tool       string // empty for HTTP-level events
+	RemoteAddr string // empty for tool-level events
+	Detail     string
+}
+
+// Sink receives security events. Emit must not block the caller.
+type Sink interface {
+	Emit(event Event)
+	Close() error
+}
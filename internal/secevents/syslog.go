@@ -0,0 +1,294 @@
+package secevents
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Syslog facilities accepted by name
+var facilities = map[string]int{
+	"auth":     4,
+	"authpriv": 10,
+	"audit":    13,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// Syslog severities used for events
+const (
+	severityWarning = 4
+	severityNotice  = 5
+)
+
+// appName is the RFC 5424 APP-NAME of every message
+const appName = "mcp-trino"
+
+// sdID names the structured data element carrying event fields. 32473 is
+// the private enterprise number reserved for documentation (RFC 5612).
+const sdID = "mcptrino@32473"
+
+// syslogQueueSize bounds the events buffered while the collector is slow
+// or unreachable; further events are dropped rather than blocking calls
+const syslogQueueSize = 1024
+
+// dialTimeout bounds connecting to the collector
+const dialTimeout = 5 * time.Second
+
+// maxReconnectDelay caps the backoff between connection attempts
+const maxReconnectDelay = time.Minute
+
+// SyslogOptions configures a syslog sink.
+type SyslogOptions struct {
+	Address  string // collector host:port
+	Protocol string // "tls" (default) or "tcp"
+	CAFile   string // PEM bundle to verify the collector's certificate (default: system roots)
+	Facility string // facility name (default: authpriv)
+}
+
+// SyslogSink sends events to a syslog collector as RFC 5424 messages with
+// octet-counting framing (RFC 6587, RFC 5425). Events are queued and sent by
+// a background goroutine that reconnects with backoff.
+type SyslogSink struct {
+	address   string
+	tlsConfig *tls.Config // nil for plain TCP
+	facility  int
+	hostname  string
+	procID    string
+
+	queue   chan Event
+	done    chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	dropped int
+}
+
+// NewSyslog validates the options and starts the sender. It does not wait
+// for the collector to be reachable.
+func NewSyslog(opts SyslogOptions) (*SyslogSink, error) {
+	if opts.Address == "" {
+		return nil, fmt.Errorf("syslog address is required")
+	}
+	if _, _, err := net.SplitHostPort(opts.Address); err != nil {
+		return nil, fmt.Errorf("invalid syslog address %q: %w", opts.Address, err)
+	}
+	facilityName := strings.ToLower(strings.TrimSpace(opts.Facility))
+	if facilityName == "" {
+		facilityName = "authpriv"
+	}
+	facility, ok := facilities[facilityName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported syslog facility %q", opts.Facility)
+	}
+
+	s := &SyslogSink{
+		address:  opts.Address,
+		facility: facility,
+		hostname: "-",
+		procID:   strconv.Itoa(os.Getpid()),
+		queue:    make(chan Event, syslogQueueSize),
+		done:     make(chan struct{}),
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		s.hostname = hostname
+	}
+
+	switch strings.ToLower(strings.TrimSpace(opts.Protocol)) {
+	case "", "tls":
+		host, _, _ := net.SplitHostPort(opts.Address)
+		s.tlsConfig = &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+		if opts.CAFile != "" {
+			pem, err := os.ReadFile(opts.CAFile) // #nosec G304 -- operator-configured CA bundle
+			if err != nil {
+				return nil, fmt.Errorf("failed to read syslog CA file: %w", err)
+			}
+			roots := x509.NewCertPool()
+			if !roots.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("syslog CA file %s contains no certificates", opts.CAFile)
+			}
+			s.tlsConfig.RootCAs = roots
+		}
+	case "tcp":
+	default:
+		return nil, fmt.Errorf("unsupported syslog protocol %q (supported: tls, tcp)", opts.Protocol)
+	}
+
+	s.wg.Add(1)
+	go s.run()
+	return s, nil
+}
+
+// Emit queues an event, dropping it if the queue is full.
+func (s *SyslogSink) Emit(event Event) {
+	select {
+	case s.queue <- event:
+	default:
+		s.mu.Lock()
+		s.dropped++
+		s.mu.Unlock()
+	}
+}
+
+// Close sends the events already queued if the collector is connected, then
+// stops the sender.
+func (s *SyslogSink) Close() error {
+	close(s.done)
+	s.wg.Wait()
+	return nil
+}
+
+// run sends queued events until Close, reconnecting after failures
+func (s *SyslogSink) run() {
+	defer s.wg.Done()
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			_ = conn.Close()
+		}
+	}()
+	delay := time.Second
+
+	// send writes one event, reconnecting first if needed. A write on a
+	// connection the collector has closed is retried once on a new one.
+	send := func(event Event) bool {
+		for attempt := 0; attempt < 2; attempt++ {
+			for conn == nil {
+				var err error
+				conn, err = s.dial()
+				if err == nil {
+					delay = time.Second
+					break
+				}
+				log.Printf("WARNING: Failed to connect to syslog collector %s, retrying in %s: %v", s.address, delay, err)
+				select {
+				case <-s.done:
+					return false
+				case <-time.After(delay):
+				}
+				delay = min(delay*2, maxReconnectDelay)
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(dialTimeout))
+			_, err := conn.Write(s.frame(event))
+			if err == nil {
+				return true
+			}
+			log.Printf("WARNING: Failed to send security event to syslog collector %s: %v", s.address, err)
+			_ = conn.Close()
+			conn = nil
+		}
+		return true
+	}
+
+	for {
+		select {
+		case event := <-s.queue:
+			s.reportDropped()
+			if !send(event) {
+				return
+			}
+		case <-s.done:
+			// Flush what is already queued, without waiting to reconnect
+			for {
+				select {
+				case event := <-s.queue:
+					if conn == nil {
+						return
+					}
+					send(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *SyslogSink) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if s.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, "tcp", s.address, s.tlsConfig)
+	}
+	return dialer.Dial("tcp", s.address)
+}
+
+// reportDropped logs events dropped while the queue was full
+func (s *SyslogSink) reportDropped() {
+	s.mu.Lock()
+	dropped := s.dropped
+	s.dropped = 0
+	s.mu.Unlock()
+	if dropped > 0 {
+		log.Printf("WARNING: Dropped %d security events while the syslog queue was full", dropped)
+	}
+}
+
+// frame encodes an event as an octet-counted RFC 5424 message
+func (s *SyslogSink) frame(event Event) []byte {
+	message := s.format(event)
+	return []byte(strconv.Itoa(len(message)) + " " + message)
+}
+
+// format encodes an event as an RFC 5424 message
+func (s *SyslogSink) format(event Event) string {
+	severity := severityWarning
+	if event.Type == TypeBlockedWrite {
+		severity = severityNotice
+	}
+	var sd strings.Builder
+	sd.WriteString("[" + sdID)
+	for _, param := range [][2]string{
+		{"type", event.Type},
+		{"kind", event.Kind},
+		{"user", event.User},
+		{"tool", event.Tool},
+		{"remote", event.RemoteAddr},
+	} {
+		if param[1] != "" {
+			fmt.Fprintf(&sd, ` %s="%s"`, param[0], escapeParam(param[1]))
+		}
+	}
+	sd.WriteString("]")
+
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s %s %s",
+		s.facility*8+severity,
+		event.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		headerField(s.hostname, 255),
+		appName,
+		headerField(s.procID, 128),
+		headerField(event.Type, 32),
+		sd.String(),
+		event.Detail)
+}
+
+// escapeParam escapes an SD-PARAM value as RFC 5424 section 6.3.3 requires
+func escapeParam(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
+
+// headerField restricts a header field to printable ASCII without spaces
+// and to its maximum length, or returns the NILVALUE when nothing remains
+func headerField(value string, maxLen int) string {
+	var b strings.Builder
+	for i := 0; i < len(value) && b.Len() < maxLen; i++ {
+		if value[i] > ' ' && value[i] < 127 {
+			b.WriteByte(value[i])
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
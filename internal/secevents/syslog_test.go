@@ -0,0 +1,115 @@
+package secevents
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	s := &SyslogSink{facility: facilities["authpriv"], hostname: "trino-mcp-1", procID: "42"}
+	got := s.format(Event{
+		Time:   time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC),
+		Type:   TypeAllowlistViolation,
+		User:   `eve"]\`,
+		Tool:   "execute_query",
+		Detail: "table access denied: hive.hr.salaries not in allowlist",
+	})
+	want := `<84>1 2026-10-17T09:30:00.000000Z trino-mcp-1 mcp-trino 42 allowlist_violation [mcptrino@32473 type="allowlist_violation" user="eve\"\]\\" tool="execute_query"] table access denied: hive.hr.salaries not in allowlist`
+	if got != want {
+		t.Errorf("format() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Anomalies name what was detected
+	if got := s.format(Event{Type: TypeAnomaly, Kind: "scan_spike", User: "alice"}); !strings.Contains(got, `[mcptrino@32473 type="anomaly" kind="scan_spike" user="alice"]`) {
+		t.Errorf("format() of anomaly = %q", got)
+	}
+
+	// Blocked writes are logged at notice severity
+	if got := s.format(Event{Type: TypeBlockedWrite}); !strings.HasPrefix(got, "<85>1 ") {
+		t.Errorf("format() of blocked write = %q", got)
+	}
+}
+
+func TestHeaderField(t *testing.T) {
+	if got := headerField("", 10); got != "-" {
+		t.Errorf("headerField(\"\") = %q", got)
+	}
+	if got := headerField("my host\n", 4); got != "myho" {
+		t.Errorf("headerField() = %q", got)
+	}
+}
+
+func TestNewSyslogValidation(t *testing.T) {
+	tests := []SyslogOptions{
+		{},
+		{Address: "siem.example.com"},
+		{Address: "siem.example.com:6514", Protocol: "udp"},
+		{Address: "siem.example.com:6514", Facility: "kern"},
+		{Address: "siem.example.com:6514", CAFile: "/nonexistent/ca.pem"},
+	}
+	for _, opts := range tests {
+		if sink, err := NewSyslog(opts); err == nil {
+			_ = sink.Close()
+			t.Errorf("NewSyslog(%+v) succeeded, want error", opts)
+		}
+	}
+}
+
+func TestSyslogDelivery(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		reader := bufio.NewReader(conn)
+		for {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(length))
+			if err != nil {
+				return
+			}
+			message := make([]byte, n)
+			if _, err := io.ReadFull(reader, message); err != nil {
+				return
+			}
+			received <- string(message)
+		}
+	}()
+
+	sink, err := NewSyslog(SyslogOptions{Address: listener.Addr().String(), Protocol: "tcp", Facility: "local4"})
+	if err != nil {
+		t.Fatalf("NewSyslog() error = %v", err)
+	}
+	sink.Emit(Event{Time: time.Now(), Type: TypeAuthFailure, RemoteAddr: "10.0.0.8:51000", Detail: "missing bearer token"})
+	sink.Emit(Event{Time: time.Now(), Type: TypeBlockedWrite, User: "alice", Tool: "execute_query", Detail: "security restriction"})
+
+	for _, want := range []string{`remote="10.0.0.8:51000"] missing bearer token`, `user="alice" tool="execute_query"] security restriction`} {
+		select {
+		case message := <-received:
+			if !strings.Contains(message, want) {
+				t.Errorf("received %q, want it to contain %q", message, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for syslog message")
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
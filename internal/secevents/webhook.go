@@ -0,0 +1,195 @@
+package secevents
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook body, as
+// "sha256=<hex>", when a secret is configured
+const SignatureHeader = "X-MCP-Trino-Signature"
+
+// Webhook delivery limits
+const (
+	webhookQueueSize = 256
+	webhookTimeout   = 10 * time.Second
+	webhookAttempts  = 3
+)
+
+// webhookPayload is the JSON body posted for an event. Text summarizes the
+// event for chat webhooks that display a text field.
+type webhookPayload struct {
+	Time       time.Time `json:"time"`
+	Type       string    `json:"type"`
+	Kind       string    `json:"kind,omitempty"`
+	User       string    `json:"user,omitempty"`
+	Tool       string    `json:"tool,omitempty"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	Detail     string    `json:"detail"`
+	Text       string    `json:"text"`
+}
+
+// WebhookSink posts events as JSON to an HTTP endpoint. Events are queued
+// and posted by a background goroutine, with retries for failed posts.
+type WebhookSink struct {
+	url    string
+	secret []byte
+	client *http.Client
+	delay  time.Duration // base delay between attempts
+
+	queue   chan Event
+	done    chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	dropped int
+}
+
+// NewWebhook validates the URL and starts the sender. A non-empty secret
+// signs every body in SignatureHeader.
+func NewWebhook(endpoint, secret string) (*WebhookSink, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q: want an http or https URL", endpoint)
+	}
+	w := &WebhookSink{
+		url:    endpoint,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: webhookTimeout},
+		delay:  time.Second,
+		queue:  make(chan Event, webhookQueueSize),
+		done:   make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+// Emit queues an event, dropping it if the queue is full.
+func (w *WebhookSink) Emit(event Event) {
+	select {
+	case w.queue <- event:
+	default:
+		w.mu.Lock()
+		w.dropped++
+		w.mu.Unlock()
+	}
+}
+
+// Close posts the events already queued, without retrying, then stops the
+// sender.
+func (w *WebhookSink) Close() error {
+	close(w.done)
+	w.wg.Wait()
+	return nil
+}
+
+// run posts queued events until Close
+func (w *WebhookSink) run() {
+	defer w.wg.Done()
+	for {
+		select {
+		case event := <-w.queue:
+			w.reportDropped()
+			w.deliver(event, webhookAttempts)
+		case <-w.done:
+			for {
+				select {
+				case event := <-w.queue:
+					w.deliver(event, 1)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver posts an event, retrying with backoff on failure
+func (w *WebhookSink) deliver(event Event, attempts int) {
+	body, err := json.Marshal(newWebhookPayload(event))
+	if err != nil {
+		log.Printf("WARNING: Failed to encode security event for webhook: %v", err)
+		return
+	}
+retry:
+	for attempt := 1; ; attempt++ {
+		if err = w.post(body); err == nil {
+			return
+		}
+		if attempt >= attempts {
+			break
+		}
+		select {
+		case <-w.done:
+			break retry // stop retrying once closing
+		case <-time.After(time.Duration(attempt) * w.delay):
+		}
+	}
+	log.Printf("WARNING: Failed to send %s event to webhook: %v", event.Type, err)
+}
+
+// post sends one body; any 2xx response is success
+func (w *WebhookSink) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", appName)
+	if len(w.secret) > 0 {
+		mac := hmac.New(sha256.New, w.secret)
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// reportDropped logs events dropped while the queue was full
+func (w *WebhookSink) reportDropped() {
+	w.mu.Lock()
+	dropped := w.dropped
+	w.dropped = 0
+	w.mu.Unlock()
+	if dropped > 0 {
+		log.Printf("WARNING: Dropped %d security events while the webhook queue was full", dropped)
+	}
+}
+
+// newWebhookPayload builds the JSON body for an event
+func newWebhookPayload(event Event) webhookPayload {
+	kind := event.Type
+	if event.Kind != "" {
+		kind += " (" + event.Kind + ")"
+	}
+	text := fmt.Sprintf("%s: %s: %s", appName, kind, event.Detail)
+	if event.User != "" {
+		text = fmt.Sprintf("%s: %s for %s: %s", appName, kind, event.User, event.Detail)
+	}
+	return webhookPayload{
+		Time:       event.Time.UTC(),
+		Type:       event.Type,
+		Kind:       event.Kind,
+		User:       event.User,
+		Tool:       event.Tool,
+		RemoteAddr: event.RemoteAddr,
+		Detail:     event.Detail,
+		Text:       text,
+	}
+}
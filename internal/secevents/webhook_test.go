@@ -0,0 +1,81 @@
+package secevents
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	var mu sync.Mutex
+	var bodies [][]byte
+	var signatures []string
+	failures := 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		bodies = append(bodies, body)
+		signatures = append(signatures, r.Header.Get(SignatureHeader))
+	}))
+	defer srv.Close()
+
+	sink, err := NewWebhook(srv.URL, "hook-secret")
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	sink.delay = time.Millisecond
+	sink.Emit(Event{Time: time.Date(2026, 10, 17, 2, 14, 0, 0, time.UTC), Type: TypeAnomaly, Kind: "off_hours", User: "alice", Tool: "execute_query", Detail: "called at night"})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		delivered := len(bodies)
+		mu.Unlock()
+		if delivered > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 1 {
+		t.Fatalf("delivered %d events, want 1 after a retry", len(bodies))
+	}
+	var payload webhookPayload
+	if err := json.Unmarshal(bodies[0], &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.Type != TypeAnomaly || payload.Kind != "off_hours" || payload.User != "alice" || payload.Text != "mcp-trino: anomaly (off_hours) for alice: called at night" {
+		t.Errorf("payload = %+v", payload)
+	}
+	mac := hmac.New(sha256.New, []byte("hook-secret"))
+	mac.Write(bodies[0])
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signatures[0] != want {
+		t.Errorf("signature = %q, want %q", signatures[0], want)
+	}
+}
+
+func TestNewWebhookValidation(t *testing.T) {
+	for _, endpoint := range []string{"", "ftp://example.com/hook", "https://", "not a url"} {
+		if _, err := NewWebhook(endpoint, ""); err == nil {
+			t.Errorf("NewWebhook(%q) should fail", endpoint)
+		}
+	}
+}
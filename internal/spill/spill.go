@@ -0,0 +1,279 @@
+// Package spill writes oversized query results to temporary local files so
+// they can be paged through without holding every row in memory. Files can
+// optionally be encrypted with a per-process key that is never written to
+// disk, so spill files left behind by a crash are unreadable.
+package spill
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// filePrefix names every spill file so stale files can be identified safely
+const filePrefix = "mcp-trino-spill-"
+
+// maxRecordSize guards against corrupt length prefixes
+const maxRecordSize = 256 << 20
+
+// Options configures a Spiller.
+type Options struct {
+	Dir     string // directory for spill files (created with 0700 if missing)
+	Encrypt bool   // encrypt records with an ephemeral AES-256-GCM key
+}
+
+// Spiller creates spill files in a single directory.
+type Spiller struct {
+	dir  string
+	aead cipher.AEAD // nil when encryption is disabled
+}
+
+// New creates a Spiller, creating the spill directory if needed.
+func New(opts Options) (*Spiller, error) {
+	dir := opts.Dir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "mcp-trino-spill")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create spill directory: %w", err)
+	}
+
+	s := &Spiller{dir: dir}
+	if opts.Encrypt {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate spill encryption key: %w", err)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize spill encryption: %w", err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize spill encryption: %w", err)
+		}
+		s.aead = aead
+	}
+	return s, nil
+}
+
+// Dir returns the spill directory.
+func (s *Spiller) Dir() string {
+	return s.dir
+}
+
+// Encrypted reports whether spill data is encrypted.
+func (s *Spiller) Encrypted() bool {
+	return s.aead != nil
+}
+
+// Seal encrypts data when encryption is enabled; otherwise it returns data unchanged.
+func (s *Spiller) Seal(data []byte) ([]byte, error) {
+	if s.aead == nil {
+		return data, nil
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return s.aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// Open reverses Seal.
+func (s *Spiller) Open(data []byte) ([]byte, error) {
+	if s.aead == nil {
+		return data, nil
+	}
+	nonceSize := s.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("spill record too short")
+	}
+	plain, err := s.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt spill record: %w", err)
+	}
+	return plain, nil
+}
+
+// Create starts a new spill file.
+func (s *Spiller) Create() (*Writer, error) {
+	f, err := os.CreateTemp(s.dir, filePrefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spill file: %w", err)
+	}
+	return &Writer{spiller: s, file: f, buf: bufio.NewWriter(f)}, nil
+}
+
+// Writer appends rows to a spill file.
+type Writer struct {
+	spiller *Spiller
+	file    *os.File
+	buf     *bufio.Writer
+	rows    int
+	bytes   int64
+}
+
+// Write appends a single row.
+func (w *Writer) Write(row map[string]interface{}) error {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return fmt.Errorf("failed to encode spill row: %w", err)
+	}
+	data, err = w.spiller.Seal(data)
+	if err != nil {
+		return err
+	}
+
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+	if _, err := w.buf.Write(header[:]); err != nil {
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	if _, err := w.buf.Write(data); err != nil {
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	w.rows++
+	w.bytes += int64(len(header) + len(data))
+	return nil
+}
+
+// Rows returns the number of rows written so far.
+func (w *Writer) Rows() int {
+	return w.rows
+}
+
+// Bytes returns the number of bytes written so far.
+func (w *Writer) Bytes() int64 {
+	return w.bytes
+}
+
+// Finish flushes and closes the file, returning a readable handle.
+func (w *Writer) Finish() (*File, error) {
+	if err := w.buf.Flush(); err != nil {
+		w.Abort()
+		return nil, fmt.Errorf("failed to flush spill file: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		_ = os.Remove(w.file.Name())
+		return nil, fmt.Errorf("failed to close spill file: %w", err)
+	}
+	return &File{spiller: w.spiller, path: w.file.Name(), rows: w.rows, bytes: w.bytes}, nil
+}
+
+// Abort closes and deletes the file without producing a handle.
+func (w *Writer) Abort() {
+	_ = w.file.Close()
+	_ = os.Remove(w.file.Name())
+}
+
+// File is a completed spill file.
+type File struct {
+	spiller *Spiller
+	path    string
+	rows    int
+	bytes   int64
+}
+
+// Rows returns the number of rows in the file.
+func (f *File) Rows() int {
+	return f.rows
+}
+
+// Bytes returns the on-disk size of the file.
+func (f *File) Bytes() int64 {
+	return f.bytes
+}
+
+// Iterate calls fn for each row in order, stopping at the first error.
+func (f *File) Iterate(fn func(row map[string]interface{}) error) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to open spill file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	r := bufio.NewReader(file)
+	var header [4]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read spill file: %w", err)
+		}
+		size := binary.BigEndian.Uint32(header[:])
+		if size > maxRecordSize {
+			return fmt.Errorf("corrupt spill file: record of %d bytes", size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return fmt.Errorf("failed to read spill file: %w", err)
+		}
+		data, err = f.spiller.Open(data)
+		if err != nil {
+			return err
+		}
+		// Keep numbers exact, so BIGINT and DECIMAL values above 2^53 survive
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return fmt.Errorf("corrupt spill row: %w", err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+}
+
+// Remove deletes the spill file.
+func (f *File) Remove() error {
+	if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// IsSpillFile reports whether a file name is a spill file.
+func IsSpillFile(name string) bool {
+	return strings.HasPrefix(name, filePrefix)
+}
+
+// CleanupStale removes spill files in dir last modified more than olderThan
+// ago, e.g. files left behind by a crashed process. It returns the number of
+// files removed.
+func CleanupStale(dir string, olderThan time.Duration) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !IsSpillFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,155 @@
+package spill
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeRows(t *testing.T, s *Spiller, n int) *File {
+	t.Helper()
+	w, err := s.Create()
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if err := w.Write(map[string]interface{}{"id": float64(i), "name": "secret-value"}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	f, err := w.Finish()
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	return f
+}
+
+func TestSpillRoundTrip(t *testing.T) {
+	for _, encrypt := range []bool{false, true} {
+		s, err := New(Options{Dir: t.TempDir(), Encrypt: encrypt})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		f := writeRows(t, s, 100)
+		if f.Rows() != 100 {
+			t.Errorf("Rows() = %d, want 100", f.Rows())
+		}
+
+		var got []json.Number
+		err = f.Iterate(func(row map[string]interface{}) error {
+			got = append(got, row["id"].(json.Number))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Iterate() error = %v", err)
+		}
+		if len(got) != 100 || got[0] != "0" || got[99] != "99" {
+			t.Errorf("encrypt=%t: unexpected rows %v", encrypt, got)
+		}
+
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		if contains := bytes.Contains(data, []byte("secret-value")); contains == encrypt {
+			t.Errorf("encrypt=%t: plaintext present = %t", encrypt, contains)
+		}
+
+		if err := f.Remove(); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+		if _, err := os.Stat(f.path); !os.IsNotExist(err) {
+			t.Errorf("spill file still exists after Remove()")
+		}
+	}
+}
+
+func TestSpillKeepsLargeNumbersExact(t *testing.T) {
+	s, err := New(Options{Dir: t.TempDir(), Encrypt: true})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	w, err := s.Create()
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	// Above 2^53, so a float64 round-trip would change it
+	if err := w.Write(map[string]interface{}{"id": int64(1234567890123456789)}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	f, err := w.Finish()
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	defer f.Remove()
+
+	var got interface{}
+	if err := f.Iterate(func(row map[string]interface{}) error {
+		got = row["id"]
+		return nil
+	}); err != nil {
+		t.Fatalf("Iterate() error = %v", err)
+	}
+	if got != json.Number("1234567890123456789") {
+		t.Errorf("id = %v, want 1234567890123456789", got)
+	}
+}
+
+func TestEncryptedSpillUnreadableByOtherProcess(t *testing.T) {
+	dir := t.TempDir()
+	writer, _ := New(Options{Dir: dir, Encrypt: true})
+	reader, _ := New(Options{Dir: dir, Encrypt: true})
+
+	f := writeRows(t, writer, 1)
+	f.spiller = reader
+	if err := f.Iterate(func(map[string]interface{}) error { return nil }); err == nil {
+		t.Error("expected decryption to fail with a different key")
+	}
+}
+
+func TestWriterAbortRemovesFile(t *testing.T) {
+	s, _ := New(Options{Dir: t.TempDir()})
+	w, err := s.Create()
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	name := w.file.Name()
+	w.Abort()
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Error("aborted spill file still exists")
+	}
+}
+
+func TestCleanupStale(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, filePrefix+"old")
+	fresh := filepath.Join(dir, filePrefix+"fresh")
+	other := filepath.Join(dir, "unrelated")
+	for _, p := range []string{old, fresh, other} {
+		if err := os.WriteFile(p, []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	past := time.Now().Add(-48 * time.Hour)
+	_ = os.Chtimes(old, past, past)
+	_ = os.Chtimes(other, past, past)
+
+	removed, err := CleanupStale(dir, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("CleanupStale() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("removed = %d, want 1", removed)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("stale spill file was not removed")
+	}
+	for _, p := range []string{fresh, other} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("%s should not have been removed", filepath.Base(p))
+		}
+	}
+}
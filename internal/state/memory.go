@@ -0,0 +1,123 @@
+package state
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time // zero means no expiry
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// MemoryStore is an in-process Store. State is lost on restart and is not
+// shared between replicas.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// memorySweepInterval bounds how often Set scans for expired entries
+const memorySweepInterval = time.Minute
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if entry.expired(m.now()) {
+		delete(m.entries, key)
+		return nil, ErrNotFound
+	}
+	return cloneBytes(entry.value), nil
+}
+
+func (m *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = m.newEntry(value, ttl)
+	m.sweepLocked()
+	return nil
+}
+
+func (m *MemoryStore) SetNX(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, ok := m.entries[key]; ok && !entry.expired(m.now()) {
+		return false, nil
+	}
+	m.entries[key] = m.newEntry(value, ttl)
+	return true, nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *MemoryStore) Backend() string {
+	return BackendMemory
+}
+
+func (m *MemoryStore) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = make(map[string]memoryEntry)
+	return nil
+}
+
+func (m *MemoryStore) newEntry(value []byte, ttl time.Duration) memoryEntry {
+	entry := memoryEntry{value: cloneBytes(value)}
+	if ttl > 0 {
+		entry.expiresAt = m.now().Add(ttl)
+	}
+	return entry
+}
+
+// sweepLocked drops expired entries so abandoned keys do not accumulate.
+// Callers must hold m.mu.
+func (m *MemoryStore) sweepLocked() {
+	now := m.now()
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, entry := range m.entries {
+		if entry.expired(now) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	out := make([]byte, len(b))
+	copy(out, b)
+	return out
+}
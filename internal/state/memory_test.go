@@ -0,0 +1,100 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreSetGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+
+	if err := store.Set(ctx, "k", []byte("v"), 0); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	got, err := store.Get(ctx, "k")
+	if err != nil || string(got) != "v" {
+		t.Fatalf("Get(k) = (%q, %v), want (v, nil)", got, err)
+	}
+
+	if err := store.Delete(ctx, "k"); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if _, err := store.Get(ctx, "k"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	if err := store.Set(ctx, "k", []byte("v"), time.Minute); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := store.Get(ctx, "k"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after expiry error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStoreSetNX(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	ok, err := store.SetNX(ctx, "k", []byte("first"), 0)
+	if err != nil || !ok {
+		t.Fatalf("first SetNX = (%v, %v), want (true, nil)", ok, err)
+	}
+	ok, err = store.SetNX(ctx, "k", []byte("second"), 0)
+	if err != nil || ok {
+		t.Fatalf("second SetNX = (%v, %v), want (false, nil)", ok, err)
+	}
+	got, _ := store.Get(ctx, "k")
+	if string(got) != "first" {
+		t.Fatalf("Get(k) = %q, want first", got)
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		backend string
+		wantErr bool
+	}{
+		{"default is memory", Options{}, BackendMemory, false},
+		{"explicit memory", Options{Backend: "memory"}, BackendMemory, false},
+		{"redis without URL", Options{Backend: "redis"}, "", true},
+		{"redis with invalid URL", Options{Backend: "redis", RedisURL: "http://bad"}, "", true},
+		{"redis with URL", Options{Backend: "redis", RedisURL: "redis://localhost:6379/0"}, BackendRedis, false},
+		{"unknown backend", Options{Backend: "etcd"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewStore(tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewStore() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewStore() error = %v", err)
+			}
+			defer func() { _ = store.Close() }()
+			if store.Backend() != tt.backend {
+				t.Errorf("Backend() = %q, want %q", store.Backend(), tt.backend)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore is a Store backed by Redis, shared by every replica pointing at
+// the same instance.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore connects to Redis using a redis:// or rediss:// URL.
+func NewRedisStore(redisURL, prefix string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	return NewRedisStoreFromClient(redis.NewClient(opts), prefix), nil
+}
+
+// NewRedisStoreFromClient wraps an existing Redis client.
+func NewRedisStoreFromClient(client *redis.Client, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (r *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get failed: %w", err)
+	}
+	return value, nil
+}
+
+func (r *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := r.client.Set(ctx, r.prefix+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("redis set failed: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisStore) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	ok, err := r.client.SetNX(ctx, r.prefix+key, value, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis setnx failed: %w", err)
+	}
+	return ok, nil
+}
+
+func (r *RedisStore) Delete(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, r.prefix+key).Err(); err != nil {
+		return fmt.Errorf("redis del failed: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisStore) Backend() string {
+	return BackendRedis
+}
+
+func (r *RedisStore) Close() error {
+	return r.client.Close()
+}
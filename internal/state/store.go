@@ -0,0 +1,65 @@
+// Package state provides a shared key/value store for server-side state that
+// must survive across replicas: OAuth signing material, MCP session IDs, and
+// other short-lived records. The in-memory backend keeps single-pod
+// deployments dependency-free; the Redis backend enables horizontal scaling.
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported store backends
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// ErrNotFound is returned by Get when a key does not exist or has expired.
+var ErrNotFound = errors.New("state: key not found")
+
+// Store is a TTL-aware key/value store shared by server components.
+type Store interface {
+	// Get returns the value for key, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key. A zero ttl means no expiry.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SetNX stores value only if key does not exist and reports whether it was stored.
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// Backend returns the backend name (memory, redis).
+	Backend() string
+	// Close releases backend resources.
+	Close() error
+}
+
+// Options configures store construction.
+type Options struct {
+	Backend   string // memory (default) or redis
+	RedisURL  string // redis://[:password@]host:port/db or rediss:// for TLS
+	KeyPrefix string // prefix applied to every key (default: "mcp-trino:")
+}
+
+// NewStore creates a store for the configured backend.
+func NewStore(opts Options) (Store, error) {
+	prefix := opts.KeyPrefix
+	if prefix == "" {
+		prefix = "mcp-trino:"
+	}
+
+	switch strings.ToLower(strings.TrimSpace(opts.Backend)) {
+	case "", BackendMemory:
+		return NewMemoryStore(), nil
+	case BackendRedis:
+		if opts.RedisURL == "" {
+			return nil, fmt.Errorf("redis state store requires MCP_REDIS_URL")
+		}
+		return NewRedisStore(opts.RedisURL, prefix)
+	default:
+		return nil, fmt.Errorf("unsupported state store %q (supported: memory, redis)", opts.Backend)
+	}
+}
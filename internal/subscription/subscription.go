@@ -0,0 +1,552 @@
+// Package subscription re-runs queries on an interval and reports when their
+// results change, so callers can be told when data lands rather than polling
+// for it. Subscriptions are kept in memory by the server instance that
+// created them and end when it restarts.
+package subscription
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Watch kinds
+const (
+	WatchResult   = "result"    // any change to the result's rows
+	WatchRowCount = "row_count" // the number of rows
+	WatchValue    = "value"     // a numeric value in the first row
+)
+
+// Change reasons
+const (
+	ReasonChanged = "changed"
+	ReasonFailed  = "failed"
+)
+
+const (
+	// DefaultLifetime is how long a subscription stays active when the
+	// caller does not say
+	DefaultLifetime = 24 * time.Hour
+
+	// maxFailures is how many consecutive failed runs end a subscription
+	maxFailures = 5
+)
+
+// ErrUndeliverable is returned by a Notifier when the subscriber can no
+// longer be reached, such as after its session ended. The subscription is
+// then removed.
+var ErrUndeliverable = errors.New("subscriber can no longer be notified")
+
+// Limits bounds the subscriptions a manager accepts. Zero values fall back
+// to the defaults.
+type Limits struct {
+	MinInterval time.Duration // shortest interval between runs (default: one minute)
+	MaxLifetime time.Duration // longest a subscription may stay active (default: DefaultLifetime)
+	MaxPerOwner int           // active subscriptions per owner (default: 5)
+	MaxTotal    int           // active subscriptions across owners (default: 100)
+}
+
+// Spec describes a subscription.
+type Spec struct {
+	Owner            string
+	Query            string
+	Interval         time.Duration // time between runs (default: Limits.MinInterval)
+	Lifetime         time.Duration // how long to stay active (default: DefaultLifetime, capped at Limits.MaxLifetime)
+	Watch            string        // WatchResult, WatchRowCount, or WatchValue (default: WatchResult)
+	ValueColumn      string        // column holding the watched value (default: the only column)
+	Threshold        float64       // smallest absolute change in the row count or value that notifies
+	ThresholdPercent float64       // smallest change relative to the previous row count or value that notifies
+	Once             bool          // end the subscription after its first notification
+	Webhook          string        // URL also notified of changes (empty = none)
+}
+
+// Observation is what one run of a subscribed query saw.
+type Observation struct {
+	Time        time.Time `json:"time"`
+	RowCount    int       `json:"row_count"`
+	Value       *float64  `json:"value,omitempty"` // only for value watches; nil when the first row's value is NULL
+	Fingerprint string    `json:"fingerprint"`     // hash of the rows, independent of their order
+}
+
+// Info describes an active subscription.
+type Info struct {
+	ID               string       `json:"id"`
+	Owner            string       `json:"owner"`
+	Query            string       `json:"query"`
+	Watch            string       `json:"watch"`
+	ValueColumn      string       `json:"value_column,omitempty"`
+	Threshold        float64      `json:"threshold,omitempty"`
+	ThresholdPercent float64      `json:"threshold_percent,omitempty"`
+	Once             bool         `json:"once,omitempty"`
+	Webhook          string       `json:"webhook,omitempty"`
+	IntervalSeconds  int          `json:"interval_seconds"`
+	CreatedAt        time.Time    `json:"created_at"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	Baseline         Observation  `json:"baseline"` // the result changes are measured against
+	LastRun          *Observation `json:"last_run,omitempty"`
+	LastError        string       `json:"last_error,omitempty"`
+	Runs             int          `json:"runs"`
+	Notifications    int          `json:"notifications"`
+}
+
+// Change is a notification that a subscription's result changed, or that
+// the subscription ended because its query kept failing.
+type Change struct {
+	Subscription Info        `json:"subscription"`
+	Reason       string      `json:"reason"`
+	Previous     Observation `json:"previous"`
+	Current      Observation `json:"current"`
+	Detail       string      `json:"detail"`
+}
+
+// Runner runs a subscription's query once.
+type Runner func(ctx context.Context) (Observation, error)
+
+// Notifier delivers a change to the subscriber.
+type Notifier func(ctx context.Context, change Change) error
+
+// Manager runs subscriptions, each on its own timer. It is safe for
+// concurrent use.
+type Manager struct {
+	limits Limits
+
+	mu     sync.Mutex
+	subs   map[string]*entry
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// entry is one active subscription
+type entry struct {
+	info   Info
+	spec   Spec
+	run    Runner
+	notify Notifier
+	cancel context.CancelFunc
+}
+
+// NewManager creates a manager enforcing limits.
+func NewManager(limits Limits) *Manager {
+	if limits.MinInterval <= 0 {
+		limits.MinInterval = time.Minute
+	}
+	if limits.MaxLifetime <= 0 {
+		limits.MaxLifetime = DefaultLifetime
+	}
+	if limits.MaxPerOwner <= 0 {
+		limits.MaxPerOwner = 5
+	}
+	if limits.MaxTotal <= 0 {
+		limits.MaxTotal = 100
+	}
+	return &Manager{limits: limits, subs: make(map[string]*entry)}
+}
+
+// Limits returns the limits the manager enforces.
+func (m *Manager) Limits() Limits {
+	return m.limits
+}
+
+// Add validates spec, runs its query once to record the baseline, and starts
+// re-running it in the background. Only the first run gets ctx; later runs
+// get a fresh context, since the subscription outlives the call that created
+// it, so run must carry over what it needs from the caller, such as their
+// identity.
+func (m *Manager) Add(ctx context.Context, spec Spec, run Runner, notify Notifier) (Info, error) {
+	spec, err := m.normalize(spec)
+	if err != nil {
+		return Info{}, err
+	}
+	if err := m.checkCapacity(spec.Owner); err != nil {
+		return Info{}, err
+	}
+
+	baseline, err := run(ctx)
+	if err != nil {
+		return Info{}, fmt.Errorf("initial run failed: %w", err)
+	}
+	if spec.Watch == WatchValue && baseline.RowCount == 0 {
+		return Info{}, fmt.Errorf("the query returned no rows, so there is no value to watch")
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Info{}, err
+	}
+	now := time.Now()
+	e := &entry{
+		info: Info{
+			ID:               id,
+			Owner:            spec.Owner,
+			Query:            spec.Query,
+			Watch:            spec.Watch,
+			ValueColumn:      spec.ValueColumn,
+			Threshold:        spec.Threshold,
+			ThresholdPercent: spec.ThresholdPercent,
+			Once:             spec.Once,
+			Webhook:          spec.Webhook,
+			IntervalSeconds:  int(spec.Interval / time.Second),
+			CreatedAt:        now,
+			ExpiresAt:        now.Add(spec.Lifetime),
+			Baseline:         baseline,
+			Runs:             1,
+		},
+		spec:   spec,
+		run:    run,
+		notify: notify,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Re-check, since the initial run happened without the lock
+	if err := m.checkCapacityLocked(spec.Owner); err != nil {
+		return Info{}, err
+	}
+	loopCtx, cancel := context.WithDeadline(context.Background(), e.info.ExpiresAt)
+	e.cancel = cancel
+	m.subs[id] = e
+	m.wg.Add(1)
+	go m.loop(loopCtx, e)
+	return e.info, nil
+}
+
+// List returns owner's active subscriptions, oldest first.
+func (m *Manager) List(owner string) []Info {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var infos []Info
+	for _, e := range m.subs {
+		if e.info.Owner == owner {
+			infos = append(infos, e.info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].CreatedAt.Before(infos[j].CreatedAt) })
+	return infos
+}
+
+// Remove ends owner's subscription id, reporting whether it existed.
+// Subscriptions of other owners cannot be removed.
+func (m *Manager) Remove(owner, id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.subs[id]
+	if !ok || e.info.Owner != owner {
+		return false
+	}
+	m.removeLocked(e)
+	return true
+}
+
+// Close ends every subscription and waits for in-flight runs to finish.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	m.closed = true
+	for _, e := range m.subs {
+		m.removeLocked(e)
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+	return nil
+}
+
+// normalize validates spec and applies defaults and limits
+func (m *Manager) normalize(spec Spec) (Spec, error) {
+	if spec.Query == "" {
+		return spec, fmt.Errorf("query is required")
+	}
+	switch spec.Watch {
+	case "":
+		spec.Watch = WatchResult
+	case WatchResult, WatchRowCount, WatchValue:
+	default:
+		return spec, fmt.Errorf("invalid watch %q: use %s, %s, or %s", spec.Watch, WatchResult, WatchRowCount, WatchValue)
+	}
+	if spec.Threshold < 0 || spec.ThresholdPercent < 0 {
+		return spec, fmt.Errorf("thresholds must not be negative")
+	}
+	if spec.Watch == WatchResult && (spec.Threshold > 0 || spec.ThresholdPercent > 0) {
+		return spec, fmt.Errorf("thresholds apply only to %s and %s watches", WatchRowCount, WatchValue)
+	}
+	if spec.Interval == 0 {
+		spec.Interval = m.limits.MinInterval
+	}
+	if spec.Interval < m.limits.MinInterval {
+		return spec, fmt.Errorf("interval must be at least %s", m.limits.MinInterval)
+	}
+	if spec.Lifetime <= 0 {
+		spec.Lifetime = DefaultLifetime
+	}
+	spec.Lifetime = min(spec.Lifetime, m.limits.MaxLifetime)
+	return spec, nil
+}
+
+func (m *Manager) checkCapacity(owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.checkCapacityLocked(owner)
+}
+
+func (m *Manager) checkCapacityLocked(owner string) error {
+	if m.closed {
+		return fmt.Errorf("subscriptions are shutting down")
+	}
+	if len(m.subs) >= m.limits.MaxTotal {
+		return fmt.Errorf("the server has reached its limit of %d active subscriptions", m.limits.MaxTotal)
+	}
+	owned := 0
+	for _, e := range m.subs {
+		if e.info.Owner == owner {
+			owned++
+		}
+	}
+	if owned >= m.limits.MaxPerOwner {
+		return fmt.Errorf("you have %d active subscriptions, the most allowed; unsubscribe from one first", owned)
+	}
+	return nil
+}
+
+func (m *Manager) removeLocked(e *entry) {
+	e.cancel()
+	delete(m.subs, e.info.ID)
+}
+
+// remove ends a subscription from its own loop
+func (m *Manager) remove(e *entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.subs[e.info.ID] == e {
+		m.removeLocked(e)
+	}
+}
+
+// loop re-runs a subscription until it is removed or expires
+func (m *Manager) loop(ctx context.Context, e *entry) {
+	defer m.wg.Done()
+	defer m.remove(e)
+
+	timer := time.NewTimer(e.spec.Interval)
+	defer timer.Stop()
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		current, err := e.run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		m.mu.Lock()
+		e.info.Runs++
+		previous := e.info.Baseline
+		if err != nil {
+			e.info.LastError = err.Error()
+		} else {
+			e.info.LastError = ""
+			e.info.LastRun = &current
+		}
+		m.mu.Unlock()
+
+		if err != nil {
+			failures++
+			log.Printf("WARNING: Subscription %s run failed (%d in a row): %v", e.info.ID, failures, err)
+			if failures >= maxFailures {
+				m.send(ctx, e, Change{
+					Reason:   ReasonFailed,
+					Previous: previous,
+					Current:  previous,
+					Detail:   fmt.Sprintf("subscription ended after %d consecutive failed runs: %v", failures, err),
+				})
+				return
+			}
+			timer.Reset(e.spec.Interval)
+			continue
+		}
+		failures = 0
+
+		if detail, ok := Changed(e.spec, previous, current); ok {
+			if !m.send(ctx, e, Change{Reason: ReasonChanged, Previous: previous, Current: current, Detail: detail}) {
+				return
+			}
+			m.mu.Lock()
+			e.info.Baseline = current
+			e.info.Notifications++
+			m.mu.Unlock()
+			if e.spec.Once {
+				return
+			}
+		}
+		timer.Reset(e.spec.Interval)
+	}
+}
+
+// send notifies the subscriber of a change, reporting whether the
+// subscription should continue
+func (m *Manager) send(ctx context.Context, e *entry, change Change) bool {
+	m.mu.Lock()
+	change.Subscription = e.info
+	m.mu.Unlock()
+	err := e.notify(ctx, change)
+	switch {
+	case errors.Is(err, ErrUndeliverable):
+		log.Printf("INFO: Subscription %s ended: %v", e.info.ID, err)
+		return false
+	case err != nil:
+		// Keep the old baseline so the change is reported again next run
+		log.Printf("WARNING: Subscription %s notification failed: %v", e.info.ID, err)
+	}
+	return true
+}
+
+// Changed reports whether current differs from previous enough to notify,
+// with a description of the change. A change must meet every threshold
+// spec sets; without thresholds any change notifies.
+func Changed(spec Spec, previous, current Observation) (string, bool) {
+	switch spec.Watch {
+	case WatchRowCount:
+		if !exceeds(spec, float64(previous.RowCount), float64(current.RowCount)) {
+			return "", false
+		}
+		return fmt.Sprintf("row count changed from %d to %d", previous.RowCount, current.RowCount), true
+	case WatchValue:
+		switch {
+		case previous.Value == nil && current.Value == nil:
+			return "", false
+		case previous.Value == nil || current.Value == nil:
+		case !exceeds(spec, *previous.Value, *current.Value):
+			return "", false
+		}
+		return fmt.Sprintf("%s changed from %s to %s", cmp.Or(spec.ValueColumn, "value"), formatValue(previous.Value), formatValue(current.Value)), true
+	default:
+		if previous.Fingerprint == current.Fingerprint {
+			return "", false
+		}
+		return fmt.Sprintf("result changed (%d rows before, %d now)", previous.RowCount, current.RowCount), true
+	}
+}
+
+// exceeds reports whether the change from previous to current meets spec's
+// thresholds
+func exceeds(spec Spec, previous, current float64) bool {
+	delta := math.Abs(current - previous)
+	if delta == 0 {
+		return false
+	}
+	if spec.Threshold > 0 && delta < spec.Threshold {
+		return false
+	}
+	if spec.ThresholdPercent > 0 && previous != 0 && delta/math.Abs(previous)*100 < spec.ThresholdPercent {
+		return false
+	}
+	return true
+}
+
+// Observe summarizes a query result. For value watches, valueColumn names
+// the column read from the first row; it may be empty when the result has a
+// single column.
+func Observe(rows []map[string]interface{}, watch, valueColumn string) (Observation, error) {
+	obs := Observation{Time: time.Now(), RowCount: len(rows)}
+
+	// Hash sorted rows so a query without ORDER BY does not look changed
+	// whenever Trino returns its rows in a different order
+	encoded := make([]string, len(rows))
+	for i, row := range rows {
+		b, err := json.Marshal(row)
+		if err != nil {
+			return obs, fmt.Errorf("failed to encode row %d: %w", i+1, err)
+		}
+		encoded[i] = string(b)
+	}
+	sort.Strings(encoded)
+	h := sha256.New()
+	for _, row := range encoded {
+		h.Write([]byte(row))
+		h.Write([]byte{'\n'})
+	}
+	obs.Fingerprint = hex.EncodeToString(h.Sum(nil))[:16]
+
+	if watch != WatchValue || len(rows) == 0 {
+		return obs, nil
+	}
+	first := rows[0]
+	if valueColumn == "" {
+		if len(first) != 1 {
+			return obs, fmt.Errorf("the query returns %d columns; set value_column to the one to watch", len(first))
+		}
+		for column := range first {
+			valueColumn = column
+		}
+	}
+	raw, ok := first[valueColumn]
+	if !ok {
+		return obs, fmt.Errorf("the result has no column %q", valueColumn)
+	}
+	if raw == nil {
+		return obs, nil
+	}
+	value, err := toFloat(raw)
+	if err != nil {
+		return obs, fmt.Errorf("column %q: %w", valueColumn, err)
+	}
+	obs.Value = &value
+	return obs, nil
+}
+
+// toFloat converts a numeric result value
+func toFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int8:
+		return float64(n), nil
+	case int16:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case json.Number:
+		return n.Float64()
+	case string:
+		// DECIMAL values arrive as strings
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value %q is not numeric", n)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("value of type %T is not numeric", v)
+	}
+}
+
+func formatValue(v *float64) string {
+	if v == nil {
+		return "NULL"
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate subscription ID: %w", err)
+	}
+	return "sub_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,178 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestObserve(t *testing.T) {
+	a, err := Observe([]map[string]interface{}{{"day": "2026-10-16", "n": int64(1)}, {"day": "2026-10-17", "n": int64(2)}}, WatchResult, "")
+	if err != nil {
+		t.Fatalf("Observe() error = %v", err)
+	}
+	b, _ := Observe([]map[string]interface{}{{"day": "2026-10-17", "n": int32(2)}, {"day": "2026-10-16", "n": int64(1)}}, WatchResult, "")
+	if a.RowCount != 2 || a.Fingerprint == "" || a.Fingerprint != b.Fingerprint {
+		t.Errorf("Observe() = %+v and %+v, want equal fingerprints regardless of row order", a, b)
+	}
+
+	value, err := Observe([]map[string]interface{}{{"total": "1234.50"}}, WatchValue, "")
+	if err != nil || value.Value == nil || *value.Value != 1234.5 {
+		t.Errorf("Observe(decimal) = %+v, %v", value, err)
+	}
+	if null, err := Observe([]map[string]interface{}{{"total": nil}}, WatchValue, "total"); err != nil || null.Value != nil {
+		t.Errorf("Observe(NULL) = %+v, %v", null, err)
+	}
+	for name, rows := range map[string][]map[string]interface{}{
+		"ambiguous column": {{"a": 1, "b": 2}},
+		"not numeric":      {{"a": true}},
+	} {
+		if _, err := Observe(rows, WatchValue, ""); err == nil {
+			t.Errorf("Observe(%s) error = nil", name)
+		}
+	}
+}
+
+func TestChanged(t *testing.T) {
+	v := func(f float64) *float64 { return &f }
+	tests := []struct {
+		name     string
+		spec     Spec
+		previous Observation
+		current  Observation
+		want     bool
+	}{
+		{"result same", Spec{Watch: WatchResult}, Observation{Fingerprint: "a"}, Observation{Fingerprint: "a"}, false},
+		{"result changed", Spec{Watch: WatchResult}, Observation{Fingerprint: "a"}, Observation{Fingerprint: "b"}, true},
+		{"rows any change", Spec{Watch: WatchRowCount}, Observation{RowCount: 0}, Observation{RowCount: 1}, true},
+		{"rows below threshold", Spec{Watch: WatchRowCount, Threshold: 10}, Observation{RowCount: 100}, Observation{RowCount: 105}, false},
+		{"rows below percent", Spec{Watch: WatchRowCount, ThresholdPercent: 10}, Observation{RowCount: 100}, Observation{RowCount: 105}, false},
+		{"rows above percent", Spec{Watch: WatchRowCount, ThresholdPercent: 10}, Observation{RowCount: 100}, Observation{RowCount: 80}, true},
+		{"percent from zero", Spec{Watch: WatchRowCount, ThresholdPercent: 10}, Observation{RowCount: 0}, Observation{RowCount: 3}, true},
+		{"value same", Spec{Watch: WatchValue}, Observation{Value: v(5)}, Observation{Value: v(5)}, false},
+		{"value above threshold", Spec{Watch: WatchValue, Threshold: 1}, Observation{Value: v(5)}, Observation{Value: v(6.5)}, true},
+		{"value lands", Spec{Watch: WatchValue, Threshold: 100}, Observation{}, Observation{Value: v(1)}, true},
+		{"value stays NULL", Spec{Watch: WatchValue}, Observation{}, Observation{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, got := Changed(tt.spec, tt.previous, tt.current)
+			if got != tt.want {
+				t.Errorf("Changed() = %v (%q), want %v", got, detail, tt.want)
+			}
+		})
+	}
+}
+
+func TestManagerAdd(t *testing.T) {
+	m := NewManager(Limits{MinInterval: time.Minute, MaxLifetime: time.Hour, MaxPerOwner: 1})
+	defer func() { _ = m.Close() }()
+	ok := func(context.Context) (Observation, error) { return Observation{RowCount: 1}, nil }
+	notify := func(context.Context, Change) error { return nil }
+
+	for name, spec := range map[string]Spec{
+		"short interval":     {Owner: "alice", Query: "SELECT 1", Interval: time.Second},
+		"unknown watch":      {Owner: "alice", Query: "SELECT 1", Watch: "columns"},
+		"result threshold":   {Owner: "alice", Query: "SELECT 1", Threshold: 5},
+		"negative threshold": {Owner: "alice", Query: "SELECT 1", Watch: WatchRowCount, ThresholdPercent: -1},
+	} {
+		if _, err := m.Add(context.Background(), spec, ok, notify); err == nil {
+			t.Errorf("Add(%s) error = nil", name)
+		}
+	}
+	if _, err := m.Add(context.Background(), Spec{Owner: "alice", Query: "SELECT 1"}, func(context.Context) (Observation, error) {
+		return Observation{}, errors.New("table not found")
+	}, notify); err == nil || !strings.Contains(err.Error(), "initial run failed") {
+		t.Errorf("Add() error = %v, want the initial run's failure", err)
+	}
+
+	info, err := m.Add(context.Background(), Spec{Owner: "alice", Query: "SELECT 1", Lifetime: 48 * time.Hour}, ok, notify)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if info.Watch != WatchResult || info.IntervalSeconds != 60 || info.ExpiresAt.Sub(info.CreatedAt) != time.Hour || info.Baseline.RowCount != 1 {
+		t.Errorf("Add() = %+v, want defaults applied and the lifetime capped", info)
+	}
+	if _, err := m.Add(context.Background(), Spec{Owner: "alice", Query: "SELECT 2"}, ok, notify); err == nil {
+		t.Error("Add() beyond the per-owner limit error = nil")
+	}
+
+	if got := m.List("bob"); len(got) != 0 {
+		t.Errorf("List(bob) = %+v, want only bob's subscriptions", got)
+	}
+	if m.Remove("bob", info.ID) {
+		t.Error("Remove() removed another owner's subscription")
+	}
+	if !m.Remove("alice", info.ID) || len(m.List("alice")) != 0 {
+		t.Error("Remove() did not remove the subscription")
+	}
+}
+
+func TestManagerNotifiesChanges(t *testing.T) {
+	m := NewManager(Limits{MinInterval: 5 * time.Millisecond})
+	defer func() { _ = m.Close() }()
+
+	var runs atomic.Int32
+	run := func(context.Context) (Observation, error) {
+		// Rows land on the third run
+		if runs.Add(1) < 3 {
+			return Observation{RowCount: 0}, nil
+		}
+		return Observation{RowCount: 24}, nil
+	}
+	changes := make(chan Change, 10)
+	notify := func(_ context.Context, change Change) error {
+		changes <- change
+		return nil
+	}
+
+	info, err := m.Add(context.Background(), Spec{Owner: "alice", Query: "SELECT hour FROM events", Watch: WatchRowCount, Once: true}, run, notify)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	select {
+	case change := <-changes:
+		if change.Reason != ReasonChanged || change.Subscription.ID != info.ID || change.Previous.RowCount != 0 || change.Current.RowCount != 24 {
+			t.Errorf("change = %+v", change)
+		}
+		if change.Detail != "row count changed from 0 to 24" {
+			t.Errorf("Detail = %q", change.Detail)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change notification")
+	}
+
+	// A one-shot subscription ends after notifying
+	deadline := time.Now().Add(5 * time.Second)
+	for len(m.List("alice")) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := m.List("alice"); len(got) != 0 {
+		t.Errorf("List() = %+v, want the one-shot subscription removed", got)
+	}
+}
+
+func TestManagerUndeliverable(t *testing.T) {
+	m := NewManager(Limits{MinInterval: 5 * time.Millisecond})
+	defer func() { _ = m.Close() }()
+
+	var runs atomic.Int32
+	run := func(context.Context) (Observation, error) {
+		return Observation{Fingerprint: string(rune('a' + runs.Add(1)))}, nil
+	}
+	notify := func(context.Context, Change) error { return ErrUndeliverable }
+	if _, err := m.Add(context.Background(), Spec{Owner: "alice", Query: "SELECT 1"}, run, notify); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(m.List("alice")) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := m.List("alice"); len(got) != 0 {
+		t.Errorf("List() = %+v, want the subscription removed once its subscriber is gone", got)
+	}
+}
@@ -0,0 +1,170 @@
+// Package tenancy maps authenticated tenants to their own Trino cluster,
+// catalogs, and allowlists, so one server can serve several workspaces.
+package tenancy
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Tenant is one workspace, selected by the value of the tenant claim in the
+// caller's token.
+type Tenant struct {
+	ID            string   `yaml:"id"`
+	Cluster       Cluster  `yaml:"trino"`
+	CatalogPrefix string   `yaml:"catalog_prefix,omitempty"` // tenant sees only catalogs with this prefix
+	Catalogs      []string `yaml:"allowed_catalogs,omitempty"`
+	Schemas       []string `yaml:"allowed_schemas,omitempty"` // catalog.schema
+	Tables        []string `yaml:"allowed_tables,omitempty"`  // catalog.schema.table
+}
+
+// Cluster is the Trino connection for a tenant. Fields left empty inherit
+// the server's TRINO_* settings.
+type Cluster struct {
+	Host         string `yaml:"host,omitempty"`
+	Port         int    `yaml:"port,omitempty"`
+	Scheme       string `yaml:"scheme,omitempty"`
+	User         string `yaml:"user,omitempty"`
+	Password     string `yaml:"password,omitempty"`
+	PasswordFile string `yaml:"password_file,omitempty"`
+	Catalog      string `yaml:"catalog,omitempty"`
+	Schema       string `yaml:"schema,omitempty"`
+}
+
+// Load reads tenant definitions from a YAML file with a top-level tenants list.
+func Load(path string) ([]Tenant, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-configured tenants file
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates tenant definitions.
+func Parse(data []byte) ([]Tenant, error) {
+	var file struct {
+		Tenants []Tenant `yaml:"tenants"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse tenants file: %w", err)
+	}
+	if len(file.Tenants) == 0 {
+		return nil, errors.New("tenants file defines no tenants")
+	}
+	seen := make(map[string]bool)
+	for i, tenant := range file.Tenants {
+		if tenant.ID == "" {
+			return nil, fmt.Errorf("tenant %d: id is required", i+1)
+		}
+		if seen[tenant.ID] {
+			return nil, fmt.Errorf("tenant %s: duplicate id", tenant.ID)
+		}
+		seen[tenant.ID] = true
+		if err := tenant.Cluster.Validate(); err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenant.ID, err)
+		}
+		for _, schema := range tenant.Schemas {
+			if strings.Count(schema, ".") != 1 {
+				return nil, fmt.Errorf("tenant %s: allowed schema %q must be catalog.schema", tenant.ID, schema)
+			}
+		}
+		for _, table := range tenant.Tables {
+			if strings.Count(table, ".") != 2 {
+				return nil, fmt.Errorf("tenant %s: allowed table %q must be catalog.schema.table", tenant.ID, table)
+			}
+		}
+	}
+	return file.Tenants, nil
+}
+
+// Config derives the tenant's Trino configuration from the server's. The
+// cluster fields the tenant sets replace the server's, and the tenant's
+// allowlists replace the server's entirely.
+func (t Tenant) Config(base *config.TrinoConfig) (*config.TrinoConfig, error) {
+	cfg := *base
+	if err := t.Cluster.Apply(&cfg); err != nil {
+		return nil, fmt.Errorf("tenant %s: %w", t.ID, err)
+	}
+	// The server's batch cluster belongs to the shared workspace
+	cfg.BatchHost = ""
+
+	cfg.AllowedCatalogs = append([]string(nil), t.Catalogs...)
+	if t.CatalogPrefix != "" {
+		cfg.AllowedCatalogs = append(cfg.AllowedCatalogs, t.CatalogPrefix+"*")
+	}
+	cfg.AllowedSchemas = append([]string(nil), t.Schemas...)
+	cfg.AllowedTables = append([]string(nil), t.Tables...)
+	return &cfg, nil
+}
+
+// Validate checks that the cluster settings are consistent.
+func (c Cluster) Validate() error {
+	if c.Password != "" && c.PasswordFile != "" {
+		return errors.New("set password or password_file, not both")
+	}
+	return nil
+}
+
+// Apply replaces the connection settings in cfg with the fields the cluster
+// sets, reading the password file if one is given.
+func (c Cluster) Apply(cfg *config.TrinoConfig) error {
+	if c.Host != "" {
+		cfg.Host = c.Host
+	}
+	if c.Port != 0 {
+		cfg.Port = c.Port
+	}
+	if c.Scheme != "" {
+		cfg.Scheme = c.Scheme
+		cfg.SSL = strings.EqualFold(c.Scheme, "https")
+	}
+	if c.User != "" {
+		cfg.User = c.User
+	}
+	switch {
+	case c.PasswordFile != "":
+		password, err := config.ReadSecretFile(c.PasswordFile)
+		if err != nil {
+			return fmt.Errorf("failed to read password_file: %w", err)
+		}
+		cfg.Password, cfg.PasswordFile = password, c.PasswordFile
+	case c.Password != "":
+		cfg.Password, cfg.PasswordFile = c.Password, ""
+	}
+	if c.Catalog != "" {
+		cfg.Catalog = c.Catalog
+	}
+	if c.Schema != "" {
+		cfg.Schema = c.Schema
+	}
+	return nil
+}
+
+// ClaimFromToken reads a string claim from a JWT. The token must already have
+// been validated by the OAuth middleware; the signature is not checked here.
+func ClaimFromToken(token, claim string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("access token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode access token: %w", err)
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("failed to decode access token: %w", err)
+	}
+	value, ok := claims[claim].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("access token has no %s claim", claim)
+	}
+	return value, nil
+}
@@ -0,0 +1,109 @@
+package tenancy
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+const sampleTenantsFile = `
+tenants:
+  - id: acme
+    trino:
+      host: acme.trino.example.com
+      port: 443
+      scheme: https
+      user: svc-acme
+      catalog: acme_hive
+    catalog_prefix: acme_
+  - id: globex
+    allowed_schemas: [shared.globex]
+`
+
+func TestParse(t *testing.T) {
+	tenants, err := Parse([]byte(sampleTenantsFile))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(tenants) != 2 || tenants[0].Cluster.Host != "acme.trino.example.com" || tenants[0].CatalogPrefix != "acme_" {
+		t.Errorf("Parse() = %+v", tenants)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{"empty", "tenants: []", "no tenants"},
+		{"missing id", "tenants: [{catalog_prefix: a_}]", "id is required"},
+		{"duplicate id", "tenants: [{id: a}, {id: a}]", "duplicate id"},
+		{"two passwords", "tenants: [{id: a, trino: {password: x, password_file: /p}}]", "not both"},
+		{"bad schema", "tenants: [{id: a, allowed_schemas: [sales]}]", "catalog.schema"},
+		{"bad table", "tenants: [{id: a, allowed_tables: [sales.orders]}]", "catalog.schema.table"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.file)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTenantConfig(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("acme-pass\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	base := &config.TrinoConfig{
+		Host: "shared.trino", Port: 8080, Scheme: "http", User: "svc", Password: "base-pass",
+		Catalog: "hive", Schema: "default", AllowedCatalogs: []string{"hive"}, MaxRows: 1000,
+		BatchHost: "batch.trino",
+	}
+	tenant := Tenant{
+		ID:            "acme",
+		Cluster:       Cluster{Host: "acme.trino", Scheme: "https", PasswordFile: passwordFile, Catalog: "acme_hive"},
+		CatalogPrefix: "acme_",
+		Tables:        []string{"acme_hive.sales.orders"},
+	}
+
+	cfg, err := tenant.Config(base)
+	if err != nil {
+		t.Fatalf("Config() error = %v", err)
+	}
+	if cfg.Host != "acme.trino" || cfg.Port != 8080 || !cfg.SSL || cfg.User != "svc" || cfg.Password != "acme-pass" || cfg.Catalog != "acme_hive" {
+		t.Errorf("cluster settings = %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.AllowedCatalogs, []string{"acme_*"}) || !reflect.DeepEqual(cfg.AllowedTables, []string{"acme_hive.sales.orders"}) {
+		t.Errorf("allowlists = %v %v, want the tenant's", cfg.AllowedCatalogs, cfg.AllowedTables)
+	}
+	if cfg.MaxRows != 1000 {
+		t.Errorf("MaxRows = %d, want the server setting inherited", cfg.MaxRows)
+	}
+	if cfg.BatchHost != "" {
+		t.Errorf("BatchHost = %q, want tenant queries kept off the shared batch cluster", cfg.BatchHost)
+	}
+	if base.Host != "shared.trino" || !reflect.DeepEqual(base.AllowedCatalogs, []string{"hive"}) {
+		t.Error("Config() modified the server configuration")
+	}
+}
+
+func TestClaimFromToken(t *testing.T) {
+	token := "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"alice","org":"acme"}`)) + ".sig"
+	if got, err := ClaimFromToken(token, "org"); err != nil || got != "acme" {
+		t.Errorf("ClaimFromToken() = %q, %v, want acme", got, err)
+	}
+	if _, err := ClaimFromToken(token, "tenant"); err == nil {
+		t.Error("expected an error for a missing claim")
+	}
+	if _, err := ClaimFromToken("opaque-token", "org"); err == nil {
+		t.Error("expected an error for a non-JWT token")
+	}
+}
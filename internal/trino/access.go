@@ -0,0 +1,65 @@
+package trino
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CheckQueryAccess rejects a query that reads a table outside the configured
+// allowlists. Unqualified table names resolve against the default catalog and
+// schema. The listing tools filter by allowlist on their own; this extends
+// the same restriction to free-form SQL.
+func (c *Client) CheckQueryAccess(query string) error {
+	if len(c.config.AllowedCatalogs) == 0 && len(c.config.AllowedSchemas) == 0 && len(c.config.AllowedTables) == 0 {
+		return nil
+	}
+	for _, reference := range queryTables(query) {
+		parts := strings.Split(reference, ".")
+		if len(parts) > 3 {
+			return fmt.Errorf("table access denied: %s is not a valid table name", reference)
+		}
+		catalog, schema, table := c.resolveTable("", "", reference)
+		if !c.tableAccessAllowed(catalog, schema, table) {
+			return fmt.Errorf("table access denied: %s.%s.%s not in allowlist", catalog, schema, table)
+		}
+	}
+	return nil
+}
+
+// writeTarget matches the table a write statement modifies. UPDATE only
+// counts at the start, since MERGE uses UPDATE SET inside the statement.
+var writeTarget = regexp.MustCompile(`(?i)(?:^\s*update|\b(?:insert\s+into|merge\s+into|delete\s+from|truncate\s+table|` +
+	`(?:create|drop|alter)(?:\s+or\s+replace)?(?:\s+materialized)?\s+(?:table|view)(?:\s+if(?:\s+not)?\s+exists)?))\s+` + tableNamePattern)
+
+// mergeSource matches the table a MERGE reads from. JOIN ... USING (columns)
+// never matches, since a column list does not start with a name.
+var mergeSource = regexp.MustCompile(`(?i)\busing\s+` + tableNamePattern)
+
+// QueryAccess returns the tables a query touches, tables it modifies first,
+// and whether it is a write. Names are lowercased and unquoted as written in
+// the query, so unqualified names are not resolved against the defaults.
+func QueryAccess(query string) (tables []string, write bool) {
+	masked := singleQuoteLiteral.ReplaceAllString(query, "''")
+	masked = multiLineComment.ReplaceAllString(singleLineComment.ReplaceAllString(masked, ""), "")
+
+	seen := make(map[string]bool)
+	for _, match := range writeTarget.FindAllStringSubmatch(masked, -1) {
+		name := normalizeTableReference(match[1])
+		if !seen[name] {
+			seen[name] = true
+			tables = append(tables, name)
+		}
+	}
+	reads := queryTables(query)
+	for _, match := range mergeSource.FindAllStringSubmatch(masked, -1) {
+		reads = append(reads, normalizeTableReference(match[1]))
+	}
+	for _, name := range reads {
+		if !seen[name] {
+			seen[name] = true
+			tables = append(tables, name)
+		}
+	}
+	return tables, !isReadOnlyQuery(query)
+}
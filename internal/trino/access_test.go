@@ -0,0 +1,64 @@
+package trino
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestCheckQueryAccess(t *testing.T) {
+	client := &Client{config: &config.TrinoConfig{
+		Catalog:         "acme_hive",
+		Schema:          "sales",
+		AllowedCatalogs: []string{"acme_*"},
+	}}
+
+	tests := []struct {
+		query   string
+		wantErr string
+	}{
+		{"SELECT * FROM orders", ""},
+		{"SELECT * FROM acme_iceberg.web.events e JOIN sales.orders o ON e.id = o.id", ""},
+		{"WITH recent AS (SELECT * FROM orders) SELECT * FROM recent", ""},
+		{"SELECT * FROM globex_hive.sales.orders", "globex_hive.sales.orders not in allowlist"},
+		{"SELECT * FROM orders WHERE id IN (SELECT id FROM \"GLOBEX_HIVE\".sales.refunds)", "globex_hive.sales.refunds"},
+		{"SHOW CATALOGS", ""},
+	}
+	for _, tt := range tests {
+		err := client.CheckQueryAccess(tt.query)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("CheckQueryAccess(%q) error = %v, want nil", tt.query, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("CheckQueryAccess(%q) error = %v, want %q", tt.query, err, tt.wantErr)
+		}
+	}
+
+	if err := (&Client{config: &config.TrinoConfig{}}).CheckQueryAccess("SELECT * FROM any.schema.table"); err != nil {
+		t.Errorf("CheckQueryAccess() without allowlists error = %v, want nil", err)
+	}
+}
+
+func TestQueryAccess(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantTables string
+		wantWrite  bool
+	}{
+		{"SELECT * FROM sales.orders o JOIN customers c ON o.cid = c.id", "sales.orders,customers", false},
+		{"INSERT INTO hive.sales.daily SELECT * FROM sales.orders", "hive.sales.daily,sales.orders", true},
+		{"update \"Sales\".orders SET status = 'from x'", "sales.orders", true},
+		{"MERGE INTO orders t USING staging s ON t.id = s.id WHEN MATCHED THEN UPDATE SET status = s.status", "orders,staging", true},
+		{"CREATE TABLE IF NOT EXISTS scratch.tmp AS SELECT 1", "scratch.tmp", true},
+		{"DROP TABLE orders", "orders", true},
+		{"DELETE FROM orders WHERE id = 1", "orders", true},
+		{"SHOW TABLES", "", false},
+	}
+	for _, tt := range tests {
+		tables, write := QueryAccess(tt.query)
+		if got := strings.Join(tables, ","); got != tt.wantTables || write != tt.wantWrite {
+			t.Errorf("QueryAccess(%q) = %s, %t, want %s, %t", tt.query, got, write, tt.wantTables, tt.wantWrite)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package trino
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Advisor rule names, reported with each suggestion
+const (
+	RuleMissingPartitionFilter = "missing_partition_filter"
+	RuleNonSargablePredicate   = "non_sargable_predicate"
+	RuleApproxDistinct         = "approx_distinct"
+	RuleFilterBeforeJoin       = "filter_before_join"
+	RuleMaterializedView       = "materialized_view"
+)
+
+// Suggestion severities
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// approxDistinctRowThreshold is the scanned row estimate above which exact
+// COUNT(DISTINCT) is flagged as costly. Unknown estimates are flagged too.
+const approxDistinctRowThreshold = 1_000_000
+
+// QueryAdvice lists optimization suggestions for a query along with the cost
+// estimate they were derived from.
+type QueryAdvice struct {
+	Suggestions []Suggestion  `json:"suggestions"`
+	Cost        *CostEstimate `json:"cost,omitempty"`
+	Notes       []string      `json:"notes,omitempty"`
+}
+
+// Suggestion is one optimization opportunity found in a query.
+type Suggestion struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Table    string `json:"table,omitempty"`
+	Message  string `json:"message"`
+}
+
+// materializedView is a materialized view that may answer a query
+type materializedView struct {
+	Name       string // catalog.schema.view
+	Definition string
+}
+
+// AdviseQuery inspects a query's IO plan and the metadata of the tables it
+// reads, and suggests rewrites: partition filters, approximate aggregates,
+// filtering before joins, and existing materialized views.
+func (c *Client) AdviseQuery(ctx context.Context, query string) (*QueryAdvice, error) {
+	cost, err := c.EstimateQueryCost(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	advice := &QueryAdvice{Cost: cost}
+
+	partitions := make(map[string][]string)
+	catalogs := make(map[string]bool)
+	for _, table := range cost.Tables {
+		parts := strings.SplitN(table.Table, ".", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		catalogs[parts[0]] = true
+		columns, err := c.partitionColumns(ctx, parts[0], parts[1], parts[2])
+		if err != nil {
+			advice.Notes = append(advice.Notes, fmt.Sprintf("partitioning of %s unavailable: %v", table.Table, err))
+			continue
+		}
+		partitions[table.Table] = columns
+	}
+
+	var views []materializedView
+	for catalog := range catalogs {
+		found, err := c.materializedViews(ctx, catalog)
+		if err != nil {
+			continue // not every connector supports materialized views
+		}
+		views = append(views, found...)
+	}
+
+	advice.Suggestions = adviseQuery(query, cost, partitions, views)
+	return advice, nil
+}
+
+var (
+	// partitioningProperty matches Hive partitioned_by and Iceberg partitioning table properties
+	partitioningProperty = regexp.MustCompile(`(?i)\b(?:partitioned_by|partitioning)\s*=\s*ARRAY\s*\[([^\]]*)\]`)
+	quotedValue          = regexp.MustCompile(`'((?:[^']|'')*)'`)
+	// partitionTransform matches Iceberg transforms such as day(ts) or bucket(id, 16)
+	partitionTransform = regexp.MustCompile(`^\w+\(\s*"?(\w+)"?\s*(?:,[^)]*)?\)$`)
+)
+
+// partitionColumns returns a table's partition columns from SHOW CREATE TABLE,
+// or none for unpartitioned tables and views.
+func (c *Client) partitionColumns(ctx context.Context, catalog, schema, table string) ([]string, error) {
+	result, err := c.ExecuteQueryWithContext(ctx, "SHOW CREATE TABLE "+qualifiedTableName(catalog, schema, table))
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Rows) == 0 {
+		return nil, nil
+	}
+	ddl, _ := result.Rows[0]["Create Table"].(string)
+	return parsePartitionColumns(ddl), nil
+}
+
+// parsePartitionColumns extracts partition column names from table DDL
+func parsePartitionColumns(ddl string) []string {
+	match := partitioningProperty.FindStringSubmatch(ddl)
+	if match == nil {
+		return nil
+	}
+	var columns []string
+	for _, value := range quotedValue.FindAllStringSubmatch(match[1], -1) {
+		column := strings.TrimSpace(strings.ReplaceAll(value[1], "''", "'"))
+		if transform := partitionTransform.FindStringSubmatch(column); transform != nil {
+			column = transform[1]
+		}
+		columns = append(columns, strings.Trim(column, `"`))
+	}
+	return columns
+}
+
+// materializedViews lists the materialized views in a catalog
+func (c *Client) materializedViews(ctx context.Context, catalog string) ([]materializedView, error) {
+	result, err := c.ExecuteQueryWithContext(ctx,
+		"SELECT schema_name, name, definition FROM system.metadata.materialized_views WHERE catalog_name = "+quoteLiteral(catalog))
+	if err != nil {
+		return nil, err
+	}
+	views := make([]materializedView, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		schema, _ := row["schema_name"].(string)
+		name, _ := row["name"].(string)
+		definition, _ := row["definition"].(string)
+		if !c.tableAccessAllowed(catalog, schema, name) {
+			continue
+		}
+		views = append(views, materializedView{Name: catalog + "." + schema + "." + name, Definition: definition})
+	}
+	return views, nil
+}
+
+var (
+	countDistinctPattern = regexp.MustCompile(`\bcount\s*\(\s*distinct\b`)
+	joinPattern          = regexp.MustCompile(`\bjoin\b`)
+	// wrappedColumnPredicate matches WHERE-style predicates that apply a
+	// function or cast to a column, which blocks pruning and pushdown
+	wrappedColumnPredicate = regexp.MustCompile(`\b(?:where|and|or|on)\s+(?:not\s+)?(cast|date|date_trunc|date_format|format_datetime|substr|substring|lower|upper|trim|year|month|day|to_unixtime|coalesce)\s*\(`)
+)
+
+// adviseQuery applies the advisor rules to a query, its cost estimate, the
+// partition columns of its tables, and candidate materialized views.
+func adviseQuery(query string, cost *CostEstimate, partitions map[string][]string, views []materializedView) []Suggestion {
+	text := sanitizeQueryForKeywordDetection(strings.ToLower(query))
+	suggestions := []Suggestion{}
+
+	for _, table := range cost.Tables {
+		columns := partitions[table.Table]
+		if len(columns) == 0 {
+			continue
+		}
+		constrained := make(map[string]bool)
+		for _, constraint := range table.Constraints {
+			constrained[strings.ToLower(constraint.Column)] = true
+		}
+		filtered := false
+		for _, column := range columns {
+			if constrained[strings.ToLower(column)] {
+				filtered = true
+				break
+			}
+		}
+		if !filtered {
+			suggestions = append(suggestions, Suggestion{
+				Rule:     RuleMissingPartitionFilter,
+				Severity: SeverityHigh,
+				Table:    table.Table,
+				Message: fmt.Sprintf("%s is partitioned by %s but no filter on those columns reaches the scan, so every partition is read. Add a predicate on %s.",
+					table.Table, strings.Join(columns, ", "), columns[0]),
+			})
+		}
+	}
+
+	if match := wrappedColumnPredicate.FindStringSubmatch(text); match != nil {
+		suggestions = append(suggestions, Suggestion{
+			Rule:     RuleNonSargablePredicate,
+			Severity: SeverityMedium,
+			Message: fmt.Sprintf("A predicate applies %s() to a column, which can prevent partition pruning and predicate pushdown. Compare the raw column against a range instead, e.g. ts >= TIMESTAMP '2024-05-01' AND ts < TIMESTAMP '2024-05-02' rather than date(ts) = DATE '2024-05-01'.",
+				match[1]),
+		})
+	}
+
+	if countDistinctPattern.MatchString(text) && (cost.EstimatedRows == nil || *cost.EstimatedRows >= approxDistinctRowThreshold) {
+		suggestions = append(suggestions, Suggestion{
+			Rule:     RuleApproxDistinct,
+			Severity: SeverityMedium,
+			Message:  "COUNT(DISTINCT ...) over a large input holds every distinct value in memory. If an estimate within about 2% is acceptable, use approx_distinct(...) instead.",
+		})
+	}
+
+	if joinPattern.MatchString(text) && len(cost.Tables) > 1 {
+		anyFiltered := false
+		for _, table := range cost.Tables {
+			if !table.Unfiltered {
+				anyFiltered = true
+			}
+		}
+		for _, table := range cost.Tables {
+			if table.Unfiltered && anyFiltered {
+				suggestions = append(suggestions, Suggestion{
+					Rule:     RuleFilterBeforeJoin,
+					Severity: SeverityLow,
+					Table:    table.Table,
+					Message: fmt.Sprintf("No filter reaches the scan of %s, so all of it is read and shuffled into the join. If only a subset is needed, filter it in a subquery or the ON clause so the filter applies before the join.",
+						table.Table),
+				})
+			}
+		}
+	}
+
+	for _, view := range matchingViews(cost.Tables, views) {
+		suggestions = append(suggestions, Suggestion{
+			Rule:     RuleMaterializedView,
+			Severity: SeverityLow,
+			Table:    view,
+			Message:  fmt.Sprintf("Materialized view %s reads the same tables as this query. If its columns and aggregation fit, querying it avoids recomputing the result.", view),
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return severityRank(suggestions[i].Severity) < severityRank(suggestions[j].Severity)
+	})
+	return suggestions
+}
+
+// matchingViews returns the materialized views whose definitions reference
+// every table the query reads
+func matchingViews(tables []TableCost, views []materializedView) []string {
+	if len(tables) == 0 {
+		return nil
+	}
+	queried := make(map[string]bool)
+	for _, table := range tables {
+		queried[strings.ToLower(table.Table)] = true
+	}
+	var matches []string
+	for _, view := range views {
+		if queried[strings.ToLower(view.Name)] {
+			continue // the query already reads this view
+		}
+		definition := strings.ToLower(view.Definition)
+		covers := true
+		for _, table := range tables {
+			parts := strings.Split(strings.ToLower(table.Table), ".")
+			if !regexp.MustCompile(`\b` + regexp.QuoteMeta(parts[len(parts)-1]) + `\b`).MatchString(definition) {
+				covers = false
+				break
+			}
+		}
+		if covers {
+			matches = append(matches, view.Name)
+		}
+	}
+	return matches
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityHigh:
+		return 0
+	case SeverityMedium:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,94 @@
+package trino
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePartitionColumns(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+		want []string
+	}{
+		{
+			name: "hive",
+			ddl:  "CREATE TABLE hive.web.page_views (\n   url varchar,\n   ds varchar\n)\nWITH (\n   format = 'ORC',\n   partitioned_by = ARRAY['ds']\n)",
+			want: []string{"ds"},
+		},
+		{
+			name: "iceberg transforms",
+			ddl:  "CREATE TABLE iceberg.web.events (\n   id bigint,\n   ts timestamp(6)\n)\nWITH (\n   partitioning = ARRAY['day(ts)','bucket(id, 16)']\n)",
+			want: []string{"ts", "id"},
+		},
+		{
+			name: "unpartitioned",
+			ddl:  "CREATE TABLE hive.web.users (\n   id bigint\n)\nWITH (\n   format = 'ORC'\n)",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePartitionColumns(tt.ddl); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePartitionColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func adviceRules(suggestions []Suggestion) []string {
+	rules := []string{}
+	for _, s := range suggestions {
+		rules = append(rules, s.Rule)
+	}
+	return rules
+}
+
+func TestAdviseQuery(t *testing.T) {
+	rows := 5e6
+	cost := &CostEstimate{
+		EstimatedRows: &rows,
+		Tables: []TableCost{
+			{Table: "hive.web.page_views", Constraints: []ColumnConstraint{{Column: "url"}}},
+			{Table: "hive.web.users", Unfiltered: true},
+		},
+	}
+	partitions := map[string][]string{"hive.web.page_views": {"ds"}}
+	views := []materializedView{
+		{Name: "hive.web.daily_views", Definition: "SELECT ds, u.country, count(*) FROM page_views v JOIN users u ON v.user_id = u.id GROUP BY 1, 2"},
+		{Name: "hive.web.url_counts", Definition: "SELECT url, count(*) FROM page_views GROUP BY 1"},
+	}
+	query := `SELECT u.country, count(DISTINCT v.session_id)
+		FROM hive.web.page_views v JOIN hive.web.users u ON v.user_id = u.id
+		WHERE lower(v.url) LIKE '%checkout%'`
+
+	suggestions := adviseQuery(query, cost, partitions, views)
+
+	want := []string{RuleMissingPartitionFilter, RuleNonSargablePredicate, RuleApproxDistinct, RuleFilterBeforeJoin, RuleMaterializedView}
+	if got := adviceRules(suggestions); !reflect.DeepEqual(got, want) {
+		t.Fatalf("rules = %v, want %v", got, want)
+	}
+	if suggestions[0].Table != "hive.web.page_views" {
+		t.Errorf("partition suggestion table = %q", suggestions[0].Table)
+	}
+	if suggestions[3].Table != "hive.web.users" {
+		t.Errorf("join suggestion table = %q", suggestions[3].Table)
+	}
+	if suggestions[4].Table != "hive.web.daily_views" {
+		t.Errorf("materialized view = %q, want the view covering both tables", suggestions[4].Table)
+	}
+}
+
+func TestAdviseQueryCleanQuery(t *testing.T) {
+	rows := 1000.0
+	cost := &CostEstimate{
+		EstimatedRows: &rows,
+		Tables:        []TableCost{{Table: "hive.web.page_views", Constraints: []ColumnConstraint{{Column: "ds", Values: 1}}}},
+	}
+	partitions := map[string][]string{"hive.web.page_views": {"ds"}}
+	query := "SELECT count(DISTINCT url) FROM hive.web.page_views WHERE ds = '2024-05-01' -- lower(x)"
+
+	if suggestions := adviseQuery(query, cost, partitions, nil); len(suggestions) != 0 {
+		t.Errorf("expected no suggestions, got %v", adviceRules(suggestions))
+	}
+}
@@ -0,0 +1,199 @@
+package trino
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// bareIdentifier matches a name that needs no quoting in SQL
+var bareIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// catalogAliases maps friendly catalog names to the catalogs they stand for,
+// keyed by lowercase alias. A nil map disables aliasing.
+type catalogAliases map[string]string
+
+// newCatalogAliases builds the alias map from the configured aliases
+func newCatalogAliases(aliases map[string]string) catalogAliases {
+	if len(aliases) == 0 {
+		return nil
+	}
+	a := make(catalogAliases, len(aliases))
+	for alias, catalog := range aliases {
+		a[strings.ToLower(alias)] = catalog
+	}
+	return a
+}
+
+// resolve returns the catalog an alias stands for, or the name unchanged
+// when it is not an alias
+func (a catalogAliases) resolve(catalog string) string {
+	if target, ok := a[strings.ToLower(catalog)]; ok {
+		return target
+	}
+	return catalog
+}
+
+// present replaces each aliased catalog in a listing with its aliases, so
+// clients see the same names in every environment
+func (a catalogAliases) present(catalogs []string) []string {
+	if len(a) == 0 {
+		return catalogs
+	}
+	byCatalog := make(map[string][]string)
+	for alias, catalog := range a {
+		byCatalog[strings.ToLower(catalog)] = append(byCatalog[strings.ToLower(catalog)], alias)
+	}
+	presented := make([]string, 0, len(catalogs))
+	for _, catalog := range catalogs {
+		aliases, ok := byCatalog[strings.ToLower(catalog)]
+		if !ok {
+			presented = append(presented, catalog)
+			continue
+		}
+		sort.Strings(aliases)
+		presented = append(presented, aliases...)
+	}
+	return presented
+}
+
+// rewrite replaces catalog aliases in a query with the catalogs they stand
+// for. A name counts as a catalog when it starts a catalog.schema.table
+// reference, or follows FROM or IN in a SHOW statement (SHOW SCHEMAS FROM
+// warehouse, SHOW TABLES FROM warehouse.sales). Names inside string literals
+// and comments are left alone.
+func (a catalogAliases) rewrite(query string) string {
+	if len(a) == 0 {
+		return query
+	}
+	tokens := scanSQL(query)
+	isShow := len(tokens) > 0 && tokens[0].value == "show"
+
+	var b strings.Builder
+	last := 0
+	for i, tok := range tokens {
+		if tok.kind != identToken || (i > 0 && tokens[i-1].kind == dotToken) {
+			continue
+		}
+		target, ok := a[tok.value]
+		if !ok {
+			continue
+		}
+		parts := 1
+		for j := i + 1; j+1 < len(tokens) && tokens[j].kind == dotToken && tokens[j+1].kind == identToken; j += 2 {
+			parts++
+		}
+		showSource := isShow && i > 0 && (tokens[i-1].value == "from" || tokens[i-1].value == "in")
+		if parts < 3 && !showSource {
+			continue
+		}
+
+		replacement := target
+		if tok.quoted || !bareIdentifier.MatchString(target) {
+			replacement = quoteIdentifier(target)
+		}
+		b.WriteString(query[last:tok.start])
+		b.WriteString(replacement)
+		last = tok.end
+	}
+	if last == 0 {
+		return query
+	}
+	b.WriteString(query[last:])
+	return b.String()
+}
+
+type sqlTokenKind int
+
+const (
+	identToken sqlTokenKind = iota
+	dotToken
+	otherToken
+)
+
+// sqlToken is an identifier, dot, or other symbol in a query. Identifier
+// values are lowercased and unquoted.
+type sqlToken struct {
+	kind       sqlTokenKind
+	value      string
+	quoted     bool
+	start, end int
+}
+
+// scanSQL splits a query into tokens, skipping whitespace, string literals,
+// and comments
+func scanSQL(query string) []sqlToken {
+	var tokens []sqlToken
+	for i := 0; i < len(query); {
+		ch := query[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexAny(query[i:], "\r\n")
+			if end < 0 {
+				return tokens
+			}
+			i += end
+		case ch == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case ch == '\'':
+			i = skipQuoted(query, i, '\'')
+		case ch == '"':
+			end := skipQuoted(query, i, '"')
+			value := strings.ReplaceAll(strings.TrimSuffix(query[i+1:end], `"`), `""`, `"`)
+			tokens = append(tokens, sqlToken{kind: identToken, value: strings.ToLower(value), quoted: true, start: i, end: end})
+			i = end
+		case ch == '.':
+			tokens = append(tokens, sqlToken{kind: dotToken, value: ".", start: i, end: i + 1})
+			i++
+		case isIdentStart(ch):
+			end := i + 1
+			for end < len(query) && (isIdentStart(query[end]) || isDigit(query[end])) {
+				end++
+			}
+			tokens = append(tokens, sqlToken{kind: identToken, value: strings.ToLower(query[i:end]), start: i, end: end})
+			i = end
+		case isDigit(ch):
+			// Numbers such as 1.5 must not read as dotted names
+			end := i + 1
+			for end < len(query) && (isDigit(query[end]) || query[end] == '.' || isIdentStart(query[end])) {
+				end++
+			}
+			tokens = append(tokens, sqlToken{kind: otherToken, value: query[i:end], start: i, end: end})
+			i = end
+		default:
+			tokens = append(tokens, sqlToken{kind: otherToken, value: query[i : i+1], start: i, end: i + 1})
+			i++
+		}
+	}
+	return tokens
+}
+
+// skipQuoted returns the index just past the quoted text starting at start,
+// where a doubled quote character is an escaped quote
+func skipQuoted(query string, start int, quote byte) int {
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(query)
+}
+
+func isIdentStart(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
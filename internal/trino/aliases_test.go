@@ -0,0 +1,71 @@
+package trino
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestCatalogAliasesRewrite(t *testing.T) {
+	aliases := newCatalogAliases(map[string]string{"warehouse": "iceberg_prod_eu", "Lake": "Hive Prod"})
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT * FROM warehouse.sales.orders", "SELECT * FROM iceberg_prod_eu.sales.orders"},
+		{`SELECT * FROM "warehouse"."sales"."orders"`, `SELECT * FROM "iceberg_prod_eu"."sales"."orders"`},
+		{"SELECT * FROM WAREHOUSE . sales . orders o JOIN lake.web.events e ON o.id = e.id",
+			`SELECT * FROM iceberg_prod_eu . sales . orders o JOIN "Hive Prod".web.events e ON o.id = e.id`},
+		{"SHOW SCHEMAS FROM warehouse", "SHOW SCHEMAS FROM iceberg_prod_eu"},
+		{"SHOW TABLES IN warehouse.sales", "SHOW TABLES IN iceberg_prod_eu.sales"},
+		{"DESCRIBE warehouse.sales.orders", "DESCRIBE iceberg_prod_eu.sales.orders"},
+		// Table aliases, column references, literals, and comments are not catalogs
+		{"SELECT warehouse.id FROM orders warehouse", "SELECT warehouse.id FROM orders warehouse"},
+		{"SELECT 'warehouse.sales.orders' -- warehouse.sales.orders\nFROM t", "SELECT 'warehouse.sales.orders' -- warehouse.sales.orders\nFROM t"},
+		{"SELECT * FROM other.warehouse.orders", "SELECT * FROM other.warehouse.orders"},
+		{"SELECT 1.5 FROM warehouse.sales.orders", "SELECT 1.5 FROM iceberg_prod_eu.sales.orders"},
+	}
+	for _, tt := range tests {
+		if got := aliases.rewrite(tt.query); got != tt.want {
+			t.Errorf("rewrite(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	var none catalogAliases
+	if got := none.rewrite("SELECT * FROM warehouse.sales.orders"); got != "SELECT * FROM warehouse.sales.orders" {
+		t.Errorf("rewrite() without aliases = %q", got)
+	}
+}
+
+func TestCatalogAliasesPresent(t *testing.T) {
+	aliases := newCatalogAliases(map[string]string{"warehouse": "iceberg_prod_eu", "dwh": "iceberg_prod_eu"})
+	got := aliases.present([]string{"hive", "iceberg_prod_eu", "system"})
+	want := []string{"hive", "dwh", "warehouse", "system"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("present() = %v, want %v", got, want)
+	}
+}
+
+func TestCatalogAliasesInAllowlists(t *testing.T) {
+	client := &Client{
+		config: &config.TrinoConfig{
+			Catalog:         "warehouse",
+			Schema:          "sales",
+			AllowedCatalogs: []string{"iceberg_prod_eu"},
+			AllowedTables:   []string{"iceberg_prod_eu.sales.orders"},
+		},
+		aliases: newCatalogAliases(map[string]string{"warehouse": "iceberg_prod_eu"}),
+	}
+
+	if catalog, _, _ := client.resolveTable("", "", "orders"); catalog != "iceberg_prod_eu" {
+		t.Errorf("resolveTable() catalog = %q, want the aliased catalog", catalog)
+	}
+	if !client.tableAccessAllowed("warehouse", "sales", "orders") {
+		t.Error("expected the alias to match the allowlisted catalog")
+	}
+	if err := client.CheckQueryAccess("SELECT * FROM warehouse.sales.orders"); err != nil {
+		t.Errorf("CheckQueryAccess() error = %v", err)
+	}
+}
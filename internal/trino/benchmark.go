@@ -0,0 +1,202 @@
+package trino
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Benchmark defaults and caps
+const (
+	DefaultBenchmarkRuns = 5
+
+	maxBenchmarkRuns   = 20
+	maxBenchmarkWarmup = 5
+)
+
+// BenchmarkOptions configures a query benchmark.
+type BenchmarkOptions struct {
+	Query  string
+	Runs   int // measured runs (defaults to DefaultBenchmarkRuns)
+	Warmup int // unmeasured runs before the measured ones
+}
+
+// QueryBenchmark is the timing and resource usage of repeated runs of one
+// query.
+type QueryBenchmark struct {
+	Query        string         `json:"query"`
+	RunCount     int            `json:"run_count"`
+	Warmup       int            `json:"warmup"`
+	Latency      Distribution   `json:"latency_ms"`
+	ScannedBytes *Distribution  `json:"scanned_bytes,omitempty"` // nil when no run's stats could be fetched
+	CPUMs        *Distribution  `json:"cpu_ms,omitempty"`
+	Runs         []BenchmarkRun `json:"runs"`
+	Notes        []string       `json:"notes,omitempty"`
+}
+
+// BenchmarkRun is one measured run of a benchmarked query.
+type BenchmarkRun struct {
+	Run              int     `json:"run"`
+	QueryID          string  `json:"query_id,omitempty"`
+	LatencyMs        float64 `json:"latency_ms"` // wall time to run the query and read its rows
+	Rows             int     `json:"rows"`
+	ScannedBytes     int64   `json:"scanned_bytes"`
+	CPUMs            int64   `json:"cpu_ms"`
+	StatsUnavailable bool    `json:"stats_unavailable,omitempty"`
+}
+
+// Distribution summarizes a measurement over benchmark runs.
+type Distribution struct {
+	Min    float64 `json:"min"`
+	P50    float64 `json:"p50"`
+	P95    float64 `json:"p95"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	CV     float64 `json:"coefficient_of_variation"` // stddev relative to the mean
+}
+
+// BenchmarkQuery runs a read-only query repeatedly and reports the spread of
+// its latency, bytes scanned, and CPU time. Each run carries a unique comment
+// so caches keyed on the query text (in gateways, proxies, or connectors)
+// cannot answer it. Runs are sequential and go through the same scheduling,
+// routing, and memory budget as execute_query.
+func (c *Client) BenchmarkQuery(ctx context.Context, opts BenchmarkOptions) (*QueryBenchmark, error) {
+	query := strings.TrimSuffix(strings.TrimSpace(opts.Query), ";")
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	// Repeating a write would apply it once per run
+	if !isReadOnlyQuery(query) {
+		return nil, fmt.Errorf("only read-only queries (SELECT, SHOW, DESCRIBE, EXPLAIN) can be benchmarked")
+	}
+	runs := clampOption(opts.Runs, DefaultBenchmarkRuns, maxBenchmarkRuns)
+	warmup := min(max(opts.Warmup, 0), maxBenchmarkWarmup)
+
+	nonce := make([]byte, 4)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate benchmark ID: %w", err)
+	}
+	benchmarkID := hex.EncodeToString(nonce)
+
+	benchmark := &QueryBenchmark{Query: query, RunCount: runs, Warmup: warmup}
+	truncated := false
+	for i := 1; i <= warmup+runs; i++ {
+		tagged := fmt.Sprintf("/* mcp-trino benchmark %s run %d */ %s", benchmarkID, i, query)
+		run, wasTruncated, err := c.benchmarkRun(ctx, tagged)
+		if err != nil {
+			if i <= warmup {
+				return nil, fmt.Errorf("warmup run %d failed: %w", i, err)
+			}
+			return nil, fmt.Errorf("run %d of %d failed: %w", i-warmup, runs, err)
+		}
+		if i <= warmup {
+			continue
+		}
+		run.Run = i - warmup
+		truncated = truncated || wasTruncated
+		benchmark.Runs = append(benchmark.Runs, run)
+	}
+
+	summarizeBenchmark(benchmark)
+	if truncated {
+		benchmark.Notes = append(benchmark.Notes, "Results were truncated by TRINO_MAX_ROWS, so latency excludes reading the remaining rows.")
+	}
+	return benchmark, nil
+}
+
+// benchmarkRun runs a query once, timing it and fetching its stats
+func (c *Client) benchmarkRun(ctx context.Context, query string) (BenchmarkRun, bool, error) {
+	runCtx, queries := WithQueryLog(ctx)
+	start := time.Now()
+	qr, err := c.ExecuteQueryWithSpill(runCtx, query)
+	latency := time.Since(start)
+	if err != nil {
+		return BenchmarkRun{}, false, err
+	}
+	run := BenchmarkRun{LatencyMs: float64(latency.Microseconds()) / 1000, Rows: len(qr.Rows)}
+	if qr.Spill != nil {
+		run.Rows = qr.Spill.Rows()
+	}
+	truncated := qr.Truncated
+	_ = qr.Close()
+
+	stats, err := queries.Stats(ctx)
+	if err != nil || len(stats) == 0 {
+		run.StatsUnavailable = true
+		return run, truncated, nil
+	}
+	for _, s := range stats {
+		run.QueryID = s.QueryID
+		run.ScannedBytes += s.ScannedBytes
+		run.CPUMs += s.CPUTime.Milliseconds()
+	}
+	return run, truncated, nil
+}
+
+// summarizeBenchmark computes the distributions of a benchmark's runs.
+// Bytes scanned and CPU time cover only runs whose stats were fetched.
+func summarizeBenchmark(b *QueryBenchmark) {
+	var latencies, scanned, cpu []float64
+	missing := 0
+	for _, run := range b.Runs {
+		latencies = append(latencies, run.LatencyMs)
+		if run.StatsUnavailable {
+			missing++
+			continue
+		}
+		scanned = append(scanned, float64(run.ScannedBytes))
+		cpu = append(cpu, float64(run.CPUMs))
+	}
+	b.Latency = distribution(latencies)
+	if len(scanned) > 0 {
+		scannedDist, cpuDist := distribution(scanned), distribution(cpu)
+		b.ScannedBytes, b.CPUMs = &scannedDist, &cpuDist
+	}
+	if missing > 0 {
+		b.Notes = append(b.Notes, fmt.Sprintf("Coordinator stats were unavailable for %d of %d runs; bytes scanned and CPU time cover the rest.", missing, len(b.Runs)))
+	}
+}
+
+// distribution summarizes values, using nearest-rank percentiles and the
+// population standard deviation
+func distribution(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	mean := sum / float64(len(sorted))
+	var squares float64
+	for _, v := range sorted {
+		squares += (v - mean) * (v - mean)
+	}
+	d := Distribution{
+		Min:    sorted[0],
+		P50:    percentile(sorted, 50),
+		P95:    percentile(sorted, 95),
+		Max:    sorted[len(sorted)-1],
+		Mean:   mean,
+		StdDev: math.Sqrt(squares / float64(len(sorted))),
+	}
+	if mean != 0 {
+		d.CV = d.StdDev / mean
+	}
+	return d
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
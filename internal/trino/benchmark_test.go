@@ -0,0 +1,135 @@
+package trino
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestBenchmarkQuery(t *testing.T) {
+	var mu sync.Mutex
+	var statements []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/statement":
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			statements = append(statements, string(body))
+			queryID := fmt.Sprintf("20261017_000000_%05d_abcde", len(statements))
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      queryID,
+				"nextUri": "http://" + r.Host + "/v1/statement/executing/" + queryID,
+				"stats":   map[string]any{"state": "QUEUED"},
+			})
+		case strings.HasPrefix(r.URL.Path, "/v1/statement/executing/"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      strings.TrimPrefix(r.URL.Path, "/v1/statement/executing/"),
+				"columns": []map[string]any{{"name": "n", "type": "integer", "typeSignature": map[string]any{"rawType": "integer", "arguments": []any{}}}},
+				"data":    [][]any{{1}, {2}},
+				"stats":   map[string]any{"state": "FINISHED"},
+			})
+		case strings.HasPrefix(r.URL.Path, "/v1/query/"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"state":      "FINISHED",
+				"queryStats": map[string]string{"totalCpuTime": "250.00ms", "physicalInputDataSize": "1.00MB"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.Atoi(u.Port())
+	c, err := NewClient(&config.TrinoConfig{
+		Host: u.Hostname(), Port: port, Scheme: "http", User: "svc",
+		Catalog: "memory", Schema: "default", QueryTimeout: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, outer := WithQueryLog(context.Background())
+	benchmark, err := c.BenchmarkQuery(ctx, BenchmarkOptions{Query: "SELECT n FROM t;", Runs: 3, Warmup: 1})
+	if err != nil {
+		t.Fatalf("BenchmarkQuery() error = %v", err)
+	}
+
+	if len(statements) != 4 {
+		t.Fatalf("statements sent = %d, want 1 warmup and 3 measured runs", len(statements))
+	}
+	seen := map[string]bool{}
+	for _, statement := range statements {
+		if !strings.Contains(statement, "/* mcp-trino benchmark ") || !strings.HasSuffix(statement, " */ SELECT n FROM t") {
+			t.Errorf("statement = %q, want a cache-busting comment before the query", statement)
+		}
+		seen[statement] = true
+	}
+	if len(seen) != len(statements) {
+		t.Errorf("statements = %q, want each run's text to differ", statements)
+	}
+	if outer.Len() != 4 {
+		t.Errorf("enclosing query log has %d queries, want every run accounted", outer.Len())
+	}
+
+	if benchmark.RunCount != 3 || benchmark.Warmup != 1 || len(benchmark.Runs) != 3 {
+		t.Fatalf("benchmark = %+v", benchmark)
+	}
+	first := benchmark.Runs[0]
+	if first.Run != 1 || first.QueryID != "20261017_000000_00002_abcde" || first.Rows != 2 || first.ScannedBytes != 1<<20 || first.CPUMs != 250 {
+		t.Errorf("first run = %+v", first)
+	}
+	if benchmark.ScannedBytes == nil || benchmark.ScannedBytes.Mean != 1<<20 || benchmark.ScannedBytes.StdDev != 0 {
+		t.Errorf("ScannedBytes = %+v", benchmark.ScannedBytes)
+	}
+	if benchmark.Latency.P50 <= 0 || benchmark.Latency.Max < benchmark.Latency.P50 {
+		t.Errorf("Latency = %+v", benchmark.Latency)
+	}
+}
+
+func TestBenchmarkQueryRejectsWrites(t *testing.T) {
+	c := &Client{config: &config.TrinoConfig{AllowWriteQueries: true}}
+	if _, err := c.BenchmarkQuery(context.Background(), BenchmarkOptions{Query: "INSERT INTO t VALUES (1)"}); err == nil {
+		t.Error("BenchmarkQuery() accepted a write")
+	}
+	if _, err := c.BenchmarkQuery(context.Background(), BenchmarkOptions{Query: " ; "}); err == nil {
+		t.Error("BenchmarkQuery() accepted an empty query")
+	}
+}
+
+func TestSummarizeBenchmark(t *testing.T) {
+	b := &QueryBenchmark{}
+	for i, latency := range []float64{40, 10, 30, 20, 100} {
+		b.Runs = append(b.Runs, BenchmarkRun{Run: i + 1, LatencyMs: latency, ScannedBytes: 1000, CPUMs: int64(latency), StatsUnavailable: i == 4})
+	}
+	summarizeBenchmark(b)
+
+	want := Distribution{Min: 10, P50: 30, P95: 100, Max: 100, Mean: 40}
+	got := b.Latency
+	if got.Min != want.Min || got.P50 != want.P50 || got.P95 != want.P95 || got.Max != want.Max || got.Mean != want.Mean {
+		t.Errorf("Latency = %+v, want %+v", got, want)
+	}
+	if stddev := got.StdDev; stddev < 31.6 || stddev > 31.7 { // sqrt(1000)
+		t.Errorf("StdDev = %v", stddev)
+	}
+	if b.CPUMs == nil || b.CPUMs.Max != 40 || b.ScannedBytes.CV != 0 {
+		t.Errorf("CPUMs = %+v, ScannedBytes = %+v; want only runs with stats", b.CPUMs, b.ScannedBytes)
+	}
+	if len(b.Notes) != 1 || !strings.Contains(b.Notes[0], "1 of 5 runs") {
+		t.Errorf("Notes = %q", b.Notes)
+	}
+}
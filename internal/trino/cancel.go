@@ -0,0 +1,139 @@
+package trino
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const queryTrackerKey contextKey = "query_tracker"
+
+// killQueryTimeout bounds the DELETE sent to Trino for a cancelled query
+const killQueryTimeout = 5 * time.Second
+
+// queryTracker records the Trino query ID of an in-flight statement so it can
+// be killed if the caller cancels before the driver gets a chance to.
+type queryTracker struct {
+	mu sync.Mutex
+	id string
+}
+
+func withQueryTracker(ctx context.Context) (context.Context, *queryTracker) {
+	tracker := &queryTracker{}
+	return context.WithValue(ctx, queryTrackerKey, tracker), tracker
+}
+
+// ID returns the observed query ID, or "" if none was seen yet.
+func (t *queryTracker) ID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.id
+}
+
+func (t *queryTracker) set(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.id == "" {
+		t.id = id
+	}
+}
+
+// observeQueryID captures the query ID from the statement protocol. The ID
+// appears in every nextUri path; the initial POST response body carries it
+// before any nextUri has been requested.
+func observeQueryID(req *http.Request, resp *http.Response) {
+	tracker, ok := req.Context().Value(queryTrackerKey).(*queryTracker)
+	if !ok || tracker.ID() != "" {
+		return
+	}
+
+	if id := queryIDFromPath(req.URL.Path); id != "" {
+		tracker.set(id)
+		return
+	}
+
+	if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/v1/statement") ||
+		resp == nil || resp.StatusCode != http.StatusOK || resp.Body == nil {
+		return
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	var statement struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(body, &statement) == nil && statement.ID != "" {
+		tracker.set(statement.ID)
+	}
+}
+
+// queryIDFromPath extracts the ID from /v1/statement/{queued|executing}/{id}/...
+func queryIDFromPath(path string) string {
+	idx := strings.Index(path, "/v1/statement/")
+	if idx < 0 {
+		return ""
+	}
+	parts := strings.Split(strings.TrimPrefix(path[idx:], "/v1/statement/"), "/")
+	if len(parts) < 2 || (parts[0] != "queued" && parts[0] != "executing") {
+		return ""
+	}
+	return parts[1]
+}
+
+// killQuery asks the coordinator to cancel a query. It runs detached from the
+// caller's context, which is already cancelled by the time it is needed.
+func (c *Client) killQuery(ctx context.Context, queryID string) {
+	killCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), killQueryTimeout)
+	defer cancel()
+
+	req, err := c.newQueryRequest(killCtx, http.MethodDelete, queryID)
+	if err != nil {
+		log.Printf("WARNING: Failed to build kill request for Trino query %s: %v", queryID, err)
+		return
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		log.Printf("WARNING: Failed to kill Trino query %s: %v", queryID, err)
+		return
+	}
+	_ = resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		log.Printf("INFO: Killed Trino query %s after cancellation", queryID)
+	case http.StatusNotFound, http.StatusGone:
+		// Already finished or cleaned up by the driver
+	default:
+		log.Printf("WARNING: Failed to kill Trino query %s: %s", queryID, resp.Status)
+	}
+}
+
+// newQueryRequest builds a request for a query's resource in the
+// coordinator's REST API, authenticated as the user the query ran as
+func (c *Client) newQueryRequest(ctx context.Context, method, queryID string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+"/v1/query/"+url.PathEscape(queryID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if password := c.currentPassword(); password != "" {
+		req.SetBasicAuth(c.config.User, password)
+	}
+	req.Header.Set("X-Trino-User", c.config.User)
+	if c.config.EnableImpersonation {
+		if user, ok := GetImpersonatedUser(ctx); ok && user != "" {
+			req.Header.Set("X-Trino-User", user)
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,70 @@
+package trino
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestQueryIDFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/v1/statement/queued/20240101_000000_00001_abcde/y1/1", "20240101_000000_00001_abcde"},
+		{"/v1/statement/executing/20240101_000000_00002_abcde/y2/2", "20240101_000000_00002_abcde"},
+		{"/v1/statement", ""},
+		{"/v1/query/20240101_000000_00001_abcde", ""},
+	}
+	for _, tt := range tests {
+		if got := queryIDFromPath(tt.path); got != tt.want {
+			t.Errorf("queryIDFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestObserveQueryIDFromInitialResponse(t *testing.T) {
+	ctx, tracker := withQueryTracker(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "http://trino:8080/v1/statement", nil)
+	body := `{"id":"20240101_000000_00003_abcde","nextUri":"http://trino:8080/v1/statement/queued/20240101_000000_00003_abcde/y/1"}`
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}
+
+	observeQueryID(req, resp)
+
+	if tracker.ID() != "20240101_000000_00003_abcde" {
+		t.Errorf("tracker.ID() = %q", tracker.ID())
+	}
+	// The driver must still be able to read the body
+	rest, _ := io.ReadAll(resp.Body)
+	if string(rest) != body {
+		t.Errorf("response body was not restored: %q", rest)
+	}
+}
+
+func TestKillQuery(t *testing.T) {
+	var gotMethod, gotPath, gotUser string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotUser = r.Method, r.URL.Path, r.Header.Get("X-Trino-User")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	cfg := &config.TrinoConfig{User: "svc", EnableImpersonation: true}
+	c := &Client{config: cfg, httpClient: srv.Client(), baseURL: srv.URL}
+
+	ctx, cancel := context.WithCancel(WithImpersonatedUser(context.Background(), "alice"))
+	cancel() // killQuery must work even though the caller's context is done
+	c.killQuery(ctx, "20240101_000000_00004_abcde")
+
+	if gotMethod != http.MethodDelete || gotPath != "/v1/query/20240101_000000_00004_abcde" {
+		t.Errorf("kill request = %s %s", gotMethod, gotPath)
+	}
+	if gotUser != "alice" {
+		t.Errorf("X-Trino-User = %q, want impersonated user", gotUser)
+	}
+}
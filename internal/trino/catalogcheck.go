@@ -0,0 +1,121 @@
+package trino
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Catalog probe defaults
+const (
+	DefaultCatalogProbeTimeout  = 5 * time.Second
+	DefaultCatalogSlowThreshold = 2 * time.Second
+
+	// catalogProbeConcurrency bounds how many catalogs are probed at once
+	catalogProbeConcurrency = 4
+)
+
+// Catalog health statuses
+const (
+	CatalogReachable = "reachable"
+	CatalogSlow      = "slow"
+	CatalogBroken    = "broken"
+)
+
+// CatalogCheck is the result of probing every allowed catalog.
+type CatalogCheck struct {
+	Catalogs  []CatalogHealth `json:"catalogs"`
+	Reachable int             `json:"reachable"`
+	Slow      int             `json:"slow"`
+	Broken    int             `json:"broken"`
+}
+
+// CatalogHealth is the probe result for one catalog.
+type CatalogHealth struct {
+	Catalog   string `json:"catalog"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// CheckCatalogs runs SHOW SCHEMAS against each allowed catalog, each bounded
+// by timeout, and classifies it as reachable, slow (slower than
+// slowThreshold), or broken (failed or timed out). Broken and slow catalogs
+// are listed first.
+func (c *Client) CheckCatalogs(ctx context.Context, timeout, slowThreshold time.Duration) (*CatalogCheck, error) {
+	catalogs, err := c.ListCatalogsWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalogs: %w", err)
+	}
+
+	results := make([]CatalogHealth, len(catalogs))
+	sem := make(chan struct{}, catalogProbeConcurrency)
+	var wg sync.WaitGroup
+	for i, catalog := range catalogs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			probeCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			start := time.Now()
+			_, err := c.ExecuteQueryWithContext(probeCtx, "SHOW SCHEMAS FROM "+quoteIdentifier(catalog))
+			results[i] = classifyProbe(catalog, time.Since(start), err, probeCtx.Err(), timeout, slowThreshold)
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return summarizeCatalogHealth(results), nil
+}
+
+// classifyProbe turns one probe's latency and outcome into a health result.
+// probeErr is the probe context's error, which distinguishes a timeout from a
+// connector failure.
+func classifyProbe(catalog string, latency time.Duration, err, probeErr error, timeout, slowThreshold time.Duration) CatalogHealth {
+	health := CatalogHealth{Catalog: catalog, LatencyMs: latency.Milliseconds()}
+	switch {
+	case errors.Is(probeErr, context.DeadlineExceeded):
+		health.Status = CatalogBroken
+		health.Error = fmt.Sprintf("timed out after %s", timeout)
+	case err != nil:
+		health.Status = CatalogBroken
+		health.Error = err.Error()
+	case latency >= slowThreshold:
+		health.Status = CatalogSlow
+	default:
+		health.Status = CatalogReachable
+	}
+	return health
+}
+
+// summarizeCatalogHealth counts statuses and orders results by severity,
+// then by catalog name
+func summarizeCatalogHealth(results []CatalogHealth) *CatalogCheck {
+	check := &CatalogCheck{Catalogs: results}
+	for _, result := range results {
+		switch result.Status {
+		case CatalogReachable:
+			check.Reachable++
+		case CatalogSlow:
+			check.Slow++
+		case CatalogBroken:
+			check.Broken++
+		}
+	}
+	rank := map[string]int{CatalogBroken: 0, CatalogSlow: 1, CatalogReachable: 2}
+	sort.Slice(results, func(i, j int) bool {
+		if rank[results[i].Status] != rank[results[j].Status] {
+			return rank[results[i].Status] < rank[results[j].Status]
+		}
+		return results[i].Catalog < results[j].Catalog
+	})
+	return check
+}
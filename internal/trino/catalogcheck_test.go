@@ -0,0 +1,59 @@
+package trino
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClassifyProbe(t *testing.T) {
+	timeout, slow := 5*time.Second, 2*time.Second
+	tests := []struct {
+		name       string
+		latency    time.Duration
+		err        error
+		probeErr   error
+		wantStatus string
+		wantError  string
+	}{
+		{"fast", 100 * time.Millisecond, nil, nil, CatalogReachable, ""},
+		{"slow", 3 * time.Second, nil, nil, CatalogSlow, ""},
+		{"failed", 50 * time.Millisecond, errors.New("Hive metastore unavailable"), nil, CatalogBroken, "Hive metastore unavailable"},
+		{"timed out", timeout, fmt.Errorf("query failed: %w", context.DeadlineExceeded), context.DeadlineExceeded, CatalogBroken, "timed out after 5s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyProbe("hive", tt.latency, tt.err, tt.probeErr, timeout, slow)
+			if got.Status != tt.wantStatus || got.Error != tt.wantError {
+				t.Errorf("classifyProbe() = %+v, want status %q error %q", got, tt.wantStatus, tt.wantError)
+			}
+			if got.LatencyMs != tt.latency.Milliseconds() {
+				t.Errorf("LatencyMs = %d, want %d", got.LatencyMs, tt.latency.Milliseconds())
+			}
+		})
+	}
+}
+
+func TestSummarizeCatalogHealth(t *testing.T) {
+	check := summarizeCatalogHealth([]CatalogHealth{
+		{Catalog: "system", Status: CatalogReachable},
+		{Catalog: "postgres", Status: CatalogBroken},
+		{Catalog: "hive", Status: CatalogSlow},
+		{Catalog: "iceberg", Status: CatalogReachable},
+	})
+	if check.Reachable != 2 || check.Slow != 1 || check.Broken != 1 {
+		t.Errorf("counts = %d/%d/%d, want 2/1/1", check.Reachable, check.Slow, check.Broken)
+	}
+	var order []string
+	for _, health := range check.Catalogs {
+		order = append(order, health.Catalog)
+	}
+	want := []string{"postgres", "hive", "iceberg", "system"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package trino
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Data quality check types
+const (
+	CheckNotNull     = "not_null"
+	CheckUnique      = "unique"
+	CheckFreshness   = "freshness"
+	CheckReferential = "referential"
+)
+
+// Check outcomes
+const (
+	CheckPass  = "pass"
+	CheckFail  = "fail"
+	CheckError = "error"
+)
+
+// Check is an operator-defined data quality check, loaded from the checks file.
+type Check struct {
+	Name    string   `yaml:"name"`
+	Type    string   `yaml:"type"`
+	Table   string   `yaml:"table"`             // catalog.schema.table
+	Column  string   `yaml:"column,omitempty"`  // not_null, freshness, referential
+	Columns []string `yaml:"columns,omitempty"` // unique key columns
+	Where   string   `yaml:"where,omitempty"`   // optional SQL filter on Table
+
+	MaxNullRate   float64       `yaml:"max_null_rate,omitempty"`  // not_null: highest passing share of NULLs (default 0)
+	MaxDuplicates int64         `yaml:"max_duplicates,omitempty"` // unique: highest passing count of duplicate rows (default 0)
+	MaxAge        time.Duration `yaml:"max_age,omitempty"`        // freshness: oldest passing age of the newest row
+	RefTable      string        `yaml:"ref_table,omitempty"`      // referential: table the column points to
+	RefColumn     string        `yaml:"ref_column,omitempty"`     // referential: key column in RefTable
+	MaxOrphans    int64         `yaml:"max_orphans,omitempty"`    // referential: highest passing count of unmatched rows (default 0)
+}
+
+// CheckResult is the outcome of one check with the metrics it measured.
+type CheckResult struct {
+	Name    string             `json:"name"`
+	Type    string             `json:"type"`
+	Table   string             `json:"table"`
+	Status  string             `json:"status"`
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+	Message string             `json:"message"`
+}
+
+// CheckRun is the outcome of running a set of checks.
+type CheckRun struct {
+	Results []CheckResult `json:"results"`
+	Passed  int           `json:"passed"`
+	Failed  int           `json:"failed"`
+	Errors  int           `json:"errors"`
+}
+
+// LoadChecks reads check definitions from a YAML (or JSON) file with a
+// top-level checks list.
+func LoadChecks(path string) ([]Check, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checks file: %w", err)
+	}
+	return ParseChecks(data)
+}
+
+// ParseChecks parses and validates check definitions.
+func ParseChecks(data []byte) ([]Check, error) {
+	var file struct {
+		Checks []Check `yaml:"checks"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse checks file: %w", err)
+	}
+	seen := make(map[string]bool)
+	for i, check := range file.Checks {
+		if check.Name == "" {
+			return nil, fmt.Errorf("check %d: name is required", i+1)
+		}
+		if seen[check.Name] {
+			return nil, fmt.Errorf("check %s: duplicate name", check.Name)
+		}
+		seen[check.Name] = true
+		if err := validateCheck(check); err != nil {
+			return nil, fmt.Errorf("check %s: %w", check.Name, err)
+		}
+	}
+	return file.Checks, nil
+}
+
+// validateCheck verifies that a check has the fields its type needs
+func validateCheck(check Check) error {
+	if len(strings.Split(check.Table, ".")) != 3 {
+		return errors.New("table must be catalog.schema.table")
+	}
+	switch check.Type {
+	case CheckNotNull:
+		if check.Column == "" {
+			return errors.New("not_null requires column")
+		}
+		if check.MaxNullRate < 0 || check.MaxNullRate > 1 {
+			return errors.New("max_null_rate must be between 0 and 1")
+		}
+	case CheckUnique:
+		if len(check.Columns) == 0 {
+			return errors.New("unique requires columns")
+		}
+	case CheckFreshness:
+		if check.Column == "" || check.MaxAge <= 0 {
+			return errors.New("freshness requires column and a positive max_age")
+		}
+	case CheckReferential:
+		if check.Column == "" || check.RefColumn == "" {
+			return errors.New("referential requires column and ref_column")
+		}
+		if len(strings.Split(check.RefTable, ".")) != 3 {
+			return errors.New("ref_table must be catalog.schema.table")
+		}
+	default:
+		return fmt.Errorf("unknown type %q: use not_null, unique, freshness, or referential", check.Type)
+	}
+	return nil
+}
+
+// RunChecks runs each check in turn. A check whose query fails is reported
+// with an error status rather than stopping the run.
+func (c *Client) RunChecks(ctx context.Context, checks []Check) (*CheckRun, error) {
+	run := &CheckRun{Results: make([]CheckResult, 0, len(checks))}
+	for _, check := range checks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result := c.runCheck(ctx, check)
+		switch result.Status {
+		case CheckPass:
+			run.Passed++
+		case CheckFail:
+			run.Failed++
+		default:
+			run.Errors++
+		}
+		run.Results = append(run.Results, result)
+	}
+	return run, nil
+}
+
+// runCheck runs a single check against Trino
+func (c *Client) runCheck(ctx context.Context, check Check) CheckResult {
+	result := CheckResult{Name: check.Name, Type: check.Type, Table: check.Table}
+	for _, table := range []string{check.Table, check.RefTable} {
+		if table == "" {
+			continue
+		}
+		parts := strings.Split(table, ".")
+		if !c.tableAccessAllowed(parts[0], parts[1], parts[2]) {
+			result.Status = CheckError
+			result.Message = fmt.Sprintf("table access denied: %s not in allowlist", table)
+			return result
+		}
+	}
+
+	qr, err := c.ExecuteQueryWithContext(ctx, buildCheckQuery(check))
+	if err != nil {
+		result.Status = CheckError
+		result.Message = err.Error()
+		return result
+	}
+	var row map[string]interface{}
+	if len(qr.Rows) > 0 {
+		row = qr.Rows[0]
+	}
+	return evaluateCheck(check, row, time.Now())
+}
+
+// buildCheckQuery returns the single-row query that measures a check
+func buildCheckQuery(check Check) string {
+	table := checkTableName(check.Table)
+	where := ""
+	if check.Where != "" {
+		where = " WHERE " + check.Where
+	}
+
+	switch check.Type {
+	case CheckNotNull:
+		return fmt.Sprintf("SELECT count(*) AS total, count_if(%s IS NULL) AS nulls FROM %s%s", quoteIdentifier(check.Column), table, where)
+	case CheckUnique:
+		keys := make([]string, len(check.Columns))
+		for i, column := range check.Columns {
+			keys[i] = quoteIdentifier(column)
+		}
+		return fmt.Sprintf("SELECT coalesce(sum(n), 0) AS total, coalesce(sum(n - 1), 0) AS duplicates FROM (SELECT count(*) AS n FROM %s%s GROUP BY %s)",
+			table, where, strings.Join(keys, ", "))
+	case CheckFreshness:
+		return fmt.Sprintf("SELECT to_unixtime(CAST(max(%s) AS timestamp(3) with time zone)) AS latest FROM %s%s", quoteIdentifier(check.Column), table, where)
+	case CheckReferential:
+		return fmt.Sprintf("SELECT count(*) AS total, count_if(r.%s IS NULL) AS orphans FROM (SELECT %s AS k FROM %s%s) t LEFT JOIN %s r ON t.k = r.%s WHERE t.k IS NOT NULL",
+			quoteIdentifier(check.RefColumn), quoteIdentifier(check.Column), table, where,
+			checkTableName(check.RefTable), quoteIdentifier(check.RefColumn))
+	}
+	return ""
+}
+
+// checkTableName quotes a catalog.schema.table name from the checks file
+func checkTableName(name string) string {
+	parts := strings.Split(name, ".")
+	return qualifiedTableName(parts[0], parts[1], parts[2])
+}
+
+// evaluateCheck compares a check's measured row against its thresholds
+func evaluateCheck(check Check, row map[string]interface{}, now time.Time) CheckResult {
+	result := CheckResult{Name: check.Name, Type: check.Type, Table: check.Table, Metrics: make(map[string]float64)}
+	pass := func(ok bool, format string, args ...interface{}) CheckResult {
+		result.Status = CheckFail
+		if ok {
+			result.Status = CheckPass
+		}
+		result.Message = fmt.Sprintf(format, args...)
+		return result
+	}
+
+	switch check.Type {
+	case CheckNotNull:
+		total, nulls := numericValue(row["total"]), numericValue(row["nulls"])
+		rate := 0.0
+		if total > 0 {
+			rate = nulls / total
+		}
+		result.Metrics["rows"], result.Metrics["nulls"], result.Metrics["null_rate"] = total, nulls, rate
+		return pass(rate <= check.MaxNullRate, "%.0f of %.0f rows have NULL %s (rate %.4f, max %.4f)", nulls, total, check.Column, rate, check.MaxNullRate)
+	case CheckUnique:
+		total, duplicates := numericValue(row["total"]), numericValue(row["duplicates"])
+		result.Metrics["rows"], result.Metrics["duplicates"] = total, duplicates
+		return pass(duplicates <= float64(check.MaxDuplicates), "%.0f of %.0f rows duplicate another row's (%s) (max %d)",
+			duplicates, total, strings.Join(check.Columns, ", "), check.MaxDuplicates)
+	case CheckFreshness:
+		latest, ok := row["latest"].(float64)
+		if !ok {
+			return pass(false, "no rows with a %s value", check.Column)
+		}
+		age := now.Sub(time.Unix(0, int64(latest*float64(time.Second))))
+		result.Metrics["age_seconds"] = age.Seconds()
+		return pass(age <= check.MaxAge, "newest %s is %s old (max %s)", check.Column, age.Round(time.Second), check.MaxAge)
+	case CheckReferential:
+		total, orphans := numericValue(row["total"]), numericValue(row["orphans"])
+		result.Metrics["rows"], result.Metrics["orphans"] = total, orphans
+		return pass(orphans <= float64(check.MaxOrphans), "%.0f of %.0f non-NULL %s values have no match in %s.%s (max %d)",
+			orphans, total, check.Column, check.RefTable, check.RefColumn, check.MaxOrphans)
+	}
+	result.Status = CheckError
+	result.Message = fmt.Sprintf("unknown check type %q", check.Type)
+	return result
+}
+
+// numericValue converts a driver numeric value to float64
+func numericValue(value interface{}) float64 {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	case string:
+		var parsed float64
+		if _, err := fmt.Sscan(v, &parsed); err == nil {
+			return parsed
+		}
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package trino
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleChecksFile = `
+checks:
+  - name: orders_customer_not_null
+    type: not_null
+    table: hive.sales.orders
+    column: customer_id
+    max_null_rate: 0.01
+    where: ds = current_date
+  - name: orders_id_unique
+    type: unique
+    table: hive.sales.orders
+    columns: [order_id, line]
+  - name: orders_fresh
+    type: freshness
+    table: hive.sales.orders
+    column: updated_at
+    max_age: 6h
+  - name: orders_customers_fk
+    type: referential
+    table: hive.sales.orders
+    column: customer_id
+    ref_table: hive.sales.customers
+    ref_column: id
+`
+
+func TestParseChecks(t *testing.T) {
+	checks, err := ParseChecks([]byte(sampleChecksFile))
+	if err != nil {
+		t.Fatalf("ParseChecks() error = %v", err)
+	}
+	if len(checks) != 4 {
+		t.Fatalf("expected 4 checks, got %d", len(checks))
+	}
+	if checks[0].MaxNullRate != 0.01 || checks[0].Where != "ds = current_date" {
+		t.Errorf("not_null check = %+v", checks[0])
+	}
+	if checks[2].MaxAge != 6*time.Hour {
+		t.Errorf("MaxAge = %s, want 6h", checks[2].MaxAge)
+	}
+}
+
+func TestParseChecksInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{"missing name", "checks: [{type: unique, table: a.b.c, columns: [id]}]", "name is required"},
+		{"duplicate name", "checks: [{name: x, type: unique, table: a.b.c, columns: [id]}, {name: x, type: unique, table: a.b.c, columns: [id]}]", "duplicate name"},
+		{"unqualified table", "checks: [{name: x, type: unique, table: c, columns: [id]}]", "catalog.schema.table"},
+		{"unknown type", "checks: [{name: x, type: row_count, table: a.b.c}]", "unknown type"},
+		{"not_null without column", "checks: [{name: x, type: not_null, table: a.b.c}]", "requires column"},
+		{"bad null rate", "checks: [{name: x, type: not_null, table: a.b.c, column: id, max_null_rate: 2}]", "between 0 and 1"},
+		{"freshness without max_age", "checks: [{name: x, type: freshness, table: a.b.c, column: ts}]", "positive max_age"},
+		{"referential without ref_table", "checks: [{name: x, type: referential, table: a.b.c, column: id, ref_column: id}]", "ref_table"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseChecks([]byte(tt.file)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseChecks() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildCheckQuery(t *testing.T) {
+	checks, err := ParseChecks([]byte(sampleChecksFile))
+	if err != nil {
+		t.Fatalf("ParseChecks() error = %v", err)
+	}
+	want := []string{
+		`SELECT count(*) AS total, count_if("customer_id" IS NULL) AS nulls FROM "hive"."sales"."orders" WHERE ds = current_date`,
+		`SELECT coalesce(sum(n), 0) AS total, coalesce(sum(n - 1), 0) AS duplicates FROM (SELECT count(*) AS n FROM "hive"."sales"."orders" GROUP BY "order_id", "line")`,
+		`SELECT to_unixtime(CAST(max("updated_at") AS timestamp(3) with time zone)) AS latest FROM "hive"."sales"."orders"`,
+		`SELECT count(*) AS total, count_if(r."id" IS NULL) AS orphans FROM (SELECT "customer_id" AS k FROM "hive"."sales"."orders") t LEFT JOIN "hive"."sales"."customers" r ON t.k = r."id" WHERE t.k IS NOT NULL`,
+	}
+	for i, check := range checks {
+		if got := buildCheckQuery(check); got != want[i] {
+			t.Errorf("buildCheckQuery(%s) =\n%s\nwant\n%s", check.Name, got, want[i])
+		}
+	}
+}
+
+func TestEvaluateCheck(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		check      Check
+		row        map[string]interface{}
+		wantStatus string
+		metric     string
+		wantMetric float64
+	}{
+		{"null rate within threshold", Check{Type: CheckNotNull, Column: "c", MaxNullRate: 0.1}, map[string]interface{}{"total": int64(100), "nulls": int64(5)}, CheckPass, "null_rate", 0.05},
+		{"null rate over threshold", Check{Type: CheckNotNull, Column: "c"}, map[string]interface{}{"total": int64(100), "nulls": int64(1)}, CheckFail, "nulls", 1},
+		{"empty table has no nulls", Check{Type: CheckNotNull, Column: "c"}, map[string]interface{}{"total": int64(0), "nulls": int64(0)}, CheckPass, "null_rate", 0},
+		{"duplicates", Check{Type: CheckUnique, Columns: []string{"id"}}, map[string]interface{}{"total": int64(10), "duplicates": int64(2)}, CheckFail, "duplicates", 2},
+		{"fresh", Check{Type: CheckFreshness, Column: "ts", MaxAge: time.Hour}, map[string]interface{}{"latest": float64(now.Add(-30 * time.Minute).Unix())}, CheckPass, "age_seconds", 1800},
+		{"stale", Check{Type: CheckFreshness, Column: "ts", MaxAge: time.Hour}, map[string]interface{}{"latest": float64(now.Add(-2 * time.Hour).Unix())}, CheckFail, "age_seconds", 7200},
+		{"orphans within threshold", Check{Type: CheckReferential, Column: "c", RefTable: "a.b.c", RefColumn: "id", MaxOrphans: 3}, map[string]interface{}{"total": int64(50), "orphans": int64(3)}, CheckPass, "orphans", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := evaluateCheck(tt.check, tt.row, now)
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if got := result.Metrics[tt.metric]; got != tt.wantMetric {
+				t.Errorf("Metrics[%s] = %v, want %v", tt.metric, got, tt.wantMetric)
+			}
+		})
+	}
+
+	if result := evaluateCheck(Check{Type: CheckFreshness, Column: "ts", MaxAge: time.Hour}, map[string]interface{}{"latest": nil}, now); result.Status != CheckFail {
+		t.Errorf("freshness of an empty table: Status = %q, want fail", result.Status)
+	}
+}
@@ -3,16 +3,23 @@ package trino
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/trinodb/trino-go-client/trino"
 	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/memguard"
+	"github.com/tuannvm/mcp-trino/internal/redact"
+	"github.com/tuannvm/mcp-trino/internal/scheduler"
+	"github.com/tuannvm/mcp-trino/internal/spill"
 	oauth "github.com/tuannvm/oauth-mcp-proxy"
 )
 
@@ -43,7 +50,7 @@ var (
 	}
 
 	// Pre-compiled write operation patterns
-	writeOpPatterns     []*regexp.Regexp
+	writeOpPatterns      []*regexp.Regexp
 	writeOpsExceptCreate []*regexp.Regexp
 
 	// Pre-compiled sanitization patterns
@@ -83,7 +90,8 @@ const (
 	impersonatedUserKey contextKey = "impersonated_user"
 )
 
-// headerRoundTripper adds X-Trino-Source and X-Trino-User headers to requests
+// headerRoundTripper adds X-Trino-Source, X-Trino-User, and the configured
+// client identification headers to requests
 type headerRoundTripper struct {
 	base   http.RoundTripper
 	config *config.TrinoConfig
@@ -104,80 +112,200 @@ func (t *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 		}
 	}
 
-	return t.base.RoundTrip(req)
+	// Set client identification headers, over the driver's per-query values
+	setClientHeaders(t.config, req)
+
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		observeQueryID(req, resp)
+	}
+	return resp, err
+}
+
+// CloseIdleConnections lets http.Client.CloseIdleConnections reach the base transport
+func (t *headerRoundTripper) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
 }
 
 // Client is a wrapper around Trino client
 type Client struct {
-	db      *sql.DB
+	db      *sql.DB // replaced when the password rotates; read through pool()
 	config  *config.TrinoConfig
 	timeout time.Duration
+	spiller *spill.Spiller       // nil when disk spill is disabled
+	budget  *memguard.Budget     // nil when the result memory budget is disabled
+	sched   *scheduler.Scheduler // nil when concurrent queries are unlimited
+	batch   *Client              // nil when workload routing is disabled
+	aliases catalogAliases       // nil when no catalog aliases are configured
+	redact  *redact.Redactor     // nil when no columns are redacted
+
+	// Used to kill queries on cancellation, outside of the SQL driver, and
+	// registered with the driver under clientKey for the pool's queries
+	httpClient *http.Client
+	clientKey  string
+	baseURL    string
+
+	// Serializes connection recovery after a coordinator restart
+	recoveryMu  sync.Mutex
+	recoveredAt time.Time
+
+	// Guards db and password, which change when the password file rotates
+	credentialsMu sync.RWMutex
+	password      *string // rotated password (nil = config.Password)
 }
 
-// NewClient creates a new Trino client
-func NewClient(cfg *config.TrinoConfig) (*Client, error) {
-	dsnURL := url.URL{
-		Scheme: cfg.Scheme,
-		User:   url.UserPassword(cfg.User, cfg.Password),
-		Host:   fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
-	}
+// customClients numbers the HTTP clients registered with the driver
+var customClients atomic.Int64
 
-	params := url.Values{}
-	params.Add("catalog", cfg.Catalog)
-	params.Add("schema", cfg.Schema)
-	params.Add("SSL", fmt.Sprintf("%t", cfg.SSL))
-	params.Add("SSLInsecure", fmt.Sprintf("%t", cfg.SSLInsecure))
-	params.Add("custom_client", "mcp-trino")
+// maxIdleConns is the number of idle driver connections kept in the pool
+const maxIdleConns = 5
 
-	dsnURL.RawQuery = params.Encode()
-	dsn := dsnURL.String()
+// staleSpillAge is how old an orphaned spill file must be before startup
+// cleanup removes it. Other processes may share the spill directory, so only
+// files well past any plausible query lifetime are deleted.
+const staleSpillAge = 24 * time.Hour
+
+// NewClient creates a new Trino client with its own limits
+func NewClient(cfg *config.TrinoConfig) (*Client, error) {
+	return NewClientWithLimits(cfg, NewLimits(cfg))
+}
+
+// NewClientWithLimits creates a new Trino client whose queries count against
+// shared limits. nil limits are created from cfg.
+func NewClientWithLimits(cfg *config.TrinoConfig, limits *Limits) (*Client, error) {
+	if limits == nil {
+		limits = NewLimits(cfg)
+	}
 
 	httpClient := &http.Client{
 		Transport: &headerRoundTripper{
-			base:   http.DefaultTransport,
+			// A private transport so recovery can drop its idle connections
+			base:   http.DefaultTransport.(*http.Transport).Clone(),
 			config: cfg,
 		},
 	}
-	if err := trino.RegisterCustomClient("mcp-trino", httpClient); err != nil {
-		// Ignore "already registered" errors - this can happen in tests or when client is recreated
-		if !strings.Contains(err.Error(), "already registered") {
-			return nil, fmt.Errorf("failed to register custom HTTP client: %w", err)
-		}
+	// Each client registers under its own key, so the batch cluster, tenants,
+	// and profiles keep their own headers and transport
+	clientKey := fmt.Sprintf("mcp-trino-%d", customClients.Add(1))
+	if err := trino.RegisterCustomClient(clientKey, httpClient); err != nil {
+		return nil, fmt.Errorf("failed to register custom HTTP client: %w", err)
 	}
+	dsn := buildDSN(cfg, cfg.Password, clientKey)
 
-	db, err := sql.Open("trino", dsn)
+	db, err := openPool(dsn)
 	if err != nil {
+		trino.DeregisterCustomClient(clientKey)
 		// Sanitize error to prevent password exposure
 		sanitizedErr := sanitizeConnectionError(err, cfg.Password)
 		return nil, fmt.Errorf("failed to connect to Trino: %w", sanitizedErr)
 	}
 
-	// Set connection pool parameters
-	db.SetMaxOpenConns(10)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
-
 	// Test the connection
 	if err := db.Ping(); err != nil {
 		closeErr := db.Close()
 		if closeErr != nil {
 			log.Printf("Error closing DB connection: %v", closeErr)
 		}
+		trino.DeregisterCustomClient(clientKey)
 		// Sanitize error to prevent password exposure
 		sanitizedErr := sanitizeConnectionError(err, cfg.Password)
 		return nil, fmt.Errorf("failed to ping Trino: %w", sanitizedErr)
 	}
 
-	return &Client{
+	redactor, err := redact.New(cfg.RedactColumns, cfg.RedactColumnPattern)
+	if err != nil {
+		_ = db.Close()
+		trino.DeregisterCustomClient(clientKey)
+		return nil, err
+	}
+
+	var spiller *spill.Spiller
+	if cfg.SpillThresholdRows > 0 {
+		spiller, err = spill.New(spill.Options{Dir: cfg.SpillDir, Encrypt: cfg.SpillEncrypt})
+		if err != nil {
+			log.Printf("ERROR: Failed to initialize disk spill, large results will be held in memory: %v", err)
+		} else if removed, err := spill.CleanupStale(spiller.Dir(), staleSpillAge); err != nil {
+			log.Printf("WARNING: Failed to clean up stale spill files: %v", err)
+		} else if removed > 0 {
+			log.Printf("INFO: Removed %d stale spill files from %s", removed, spiller.Dir())
+		}
+	}
+
+	client := &Client{
 		db:      db,
 		config:  cfg,
 		timeout: cfg.QueryTimeout,
-	}, nil
+		spiller: spiller,
+		budget:  limits.budget,
+		sched:   limits.sched,
+		aliases: newCatalogAliases(cfg.CatalogAliases),
+		redact:  redactor,
+
+		httpClient: httpClient,
+		clientKey:  clientKey,
+		baseURL:    fmt.Sprintf("%s://%s:%d", cfg.Scheme, cfg.Host, cfg.Port),
+	}
+
+	if cfg.BatchHost != "" {
+		if client.batch, err = newBatchClient(cfg, limits); err != nil {
+			log.Printf("ERROR: Failed to connect to the batch cluster, all queries will run on %s: %v", cfg.Host, err)
+		} else {
+			log.Printf("INFO: Connected to batch cluster %s:%d", cfg.BatchHost, cfg.BatchPort)
+		}
+	}
+	return client, nil
+}
+
+// buildDSN returns the driver DSN for the configured coordinator, using the
+// HTTP client registered under clientKey
+func buildDSN(cfg *config.TrinoConfig, password, clientKey string) string {
+	dsnURL := url.URL{
+		Scheme: cfg.Scheme,
+		User:   url.UserPassword(cfg.User, password),
+		Host:   fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+	}
+
+	params := url.Values{}
+	params.Add("catalog", newCatalogAliases(cfg.CatalogAliases).resolve(cfg.Catalog))
+	params.Add("schema", cfg.Schema)
+	params.Add("SSL", fmt.Sprintf("%t", cfg.SSL))
+	params.Add("SSLInsecure", fmt.Sprintf("%t", cfg.SSLInsecure))
+	params.Add("custom_client", clientKey)
+
+	dsnURL.RawQuery = params.Encode()
+	return dsnURL.String()
+}
+
+// openPool opens a driver connection pool with the standard pool settings
+func openPool(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("trino", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetConnMaxLifetime(5 * time.Minute)
+	return db, nil
+}
+
+// Ping checks that the Trino coordinator is reachable and has finished
+// starting, without running a query
+func (c *Client) Ping(ctx context.Context) error {
+	return c.pingCoordinator(ctx)
 }
 
 // Close closes the database connection
 func (c *Client) Close() error {
-	return c.db.Close()
+	if c.batch != nil {
+		if err := c.batch.Close(); err != nil {
+			log.Printf("Error closing batch cluster connection: %v", err)
+		}
+	}
+	err := c.pool().Close()
+	trino.DeregisterCustomClient(c.clientKey)
+	return err
 }
 
 // WithImpersonatedUser adds impersonated user to context
@@ -302,11 +430,46 @@ func getOAuthUserAndUsername(ctx context.Context) (*oauth.User, string) {
 	return user, username
 }
 
+// UserIdentity returns the display username of the OAuth user in context,
+// or the default attribution user when no identity is available.
+func UserIdentity(ctx context.Context) string {
+	_, username := getOAuthUserAndUsername(ctx)
+	return username
+}
+
 // QueryResult holds query results along with metadata about truncation.
 type QueryResult struct {
 	Rows      []map[string]interface{}
-	Truncated bool // true if results were truncated by MaxRows limit
-	MaxRows   int  // the MaxRows limit that was applied (0 = unlimited)
+	Columns   []Column // result columns in order, with their Trino types
+	Truncated bool     // true if results were truncated by MaxRows limit
+	MaxRows   int      // the MaxRows limit that was applied (0 = unlimited)
+
+	// Spill holds the full result when it exceeded the spill threshold; Rows
+	// is then empty. Close removes the file.
+	Spill *spill.File
+
+	reservation *memguard.Reservation // memory budget held by Rows
+}
+
+// Column is a result column and its Trino type, such as decimal(10,2).
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ErrResultTooLarge is returned when a result would exceed the server's
+// result memory budget.
+var ErrResultTooLarge = errors.New("result too large: it exceeds the server's result memory budget, add a LIMIT to your query")
+
+// Close releases the result's memory budget and removes its spill file, if
+// any. Results from ExecuteQueryWithSpill must be closed once the response
+// has been built.
+func (qr *QueryResult) Close() error {
+	qr.reservation.Release()
+	if qr.Spill != nil {
+		return qr.Spill.Remove()
+	}
+	return nil
 }
 
 // ExecuteQuery executes a SQL query and returns the results
@@ -323,9 +486,30 @@ func (c *Client) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 // - User impersonation via X-Trino-User header (when EnableImpersonation is true)
 // - Query attribution via X-Trino-Client-Tags/Info/Source (from OAuth user context)
 func (c *Client) ExecuteQueryWithContext(ctx context.Context, query string) (*QueryResult, error) {
+	return c.executeQuery(ctx, query, false)
+}
+
+// ExecuteQueryWithSpill behaves like ExecuteQueryWithContext for
+// user-submitted queries whose results may be large. Rows are charged to the
+// result memory budget, and when disk spill is enabled, results past the
+// spill threshold (or past the budget) are streamed to a spill file instead
+// of being held in memory. The caller must Close the returned result.
+//
+// When workload routing is enabled, expensive queries run on the batch
+// cluster.
+func (c *Client) ExecuteQueryWithSpill(ctx context.Context, query string) (*QueryResult, error) {
+	return c.workloadClient(ctx, query).executeQuery(ctx, query, true)
+}
+
+func (c *Client) executeQuery(ctx context.Context, query string, managed bool) (*QueryResult, error) {
+	allowSpill := managed && c.spiller != nil
+
 	// Strip trailing semicolon that Trino doesn't allow
 	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
 
+	// Replace catalog aliases with the catalogs they stand for
+	query = c.aliases.rewrite(query)
+
 	// SQL injection protection: only allow read-only queries unless explicitly allowed in config
 	if !c.config.AllowWriteQueries && !isReadOnlyQuery(query) {
 		return nil, fmt.Errorf("security restriction: only SELECT, SHOW, DESCRIBE, and EXPLAIN queries are allowed. " +
@@ -358,10 +542,54 @@ func (c *Client) ExecuteQueryWithContext(ctx context.Context, query string) (*Qu
 		queryArgs = append(queryArgs, sql.Named("X-Trino-Source", userName))
 	}
 
+	// Wait for a query slot; excess queries are queued fairly per identity
+	waitCtx, waitCancel := context.WithTimeout(queryCtx, c.config.QueryQueueTimeout)
+	release, err := c.sched.Acquire(waitCtx, userName)
+	waitCancel()
+	if err != nil {
+		running, queued := c.sched.Stats()
+		return nil, fmt.Errorf("server is busy (%d queries running, %d queued); retry shortly", running, queued)
+	}
+	defer release()
+
+	// Queue behind in-flight results while the memory budget is exhausted
+	var reservation *memguard.Reservation
+	if managed && c.budget != nil {
+		waitCtx, waitCancel := context.WithTimeout(queryCtx, c.config.MemoryQueueTimeout)
+		res, err := c.budget.Begin(waitCtx)
+		waitCancel()
+		if err != nil {
+			return nil, fmt.Errorf("server is at its result memory budget (%d MB in use); retry shortly or add a LIMIT to your query", c.budget.Used()>>20)
+		}
+		reservation = res
+	}
+	// Released on error; on success ownership passes to the QueryResult
+	defer func() {
+		if reservation != nil {
+			reservation.Release()
+		}
+	}()
+
+	// When the caller cancels (client cancellation, disconnect, or timeout),
+	// kill the query explicitly; the driver only cancels queries it has
+	// already returned rows for. Every query that reached the coordinator is
+	// logged for usage accounting.
+	queryCtx, tracker := withQueryTracker(queryCtx)
+	defer func() {
+		queryID := tracker.ID()
+		if queryID == "" {
+			return
+		}
+		if queryCtx.Err() != nil {
+			c.killQuery(ctx, queryID)
+		}
+		logQuery(ctx, c, queryID)
+	}()
+
 	// Execute the query with optional attribution headers
-	rows, err := c.db.QueryContext(queryCtx, query, queryArgs...)
+	rows, err := c.queryWithRecovery(queryCtx, query, queryArgs...)
 	if err != nil {
-		return nil, fmt.Errorf("query execution failed: %w", err)
+		return nil, fmt.Errorf("query execution failed: %w", c.redact.Error(err, c.redact.References(query)))
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
@@ -375,6 +603,22 @@ func (c *Client) ExecuteQueryWithContext(ctx context.Context, query string) (*Qu
 		return nil, fmt.Errorf("failed to get column names: %w", err)
 	}
 
+	// Errors about a result with redacted columns may quote their values
+	redactValues := c.redact.References(query) || c.redact.AnyColumn(columns)
+
+	// Column database types drive value normalization (e.g. UUID, IPADDRESS)
+	columnTypes := make([]string, len(columns))
+	resultColumns := make([]Column, len(columns))
+	for i, name := range columns {
+		resultColumns[i].Name = name
+	}
+	if colTypes, err := rows.ColumnTypes(); err == nil {
+		for i, ct := range colTypes {
+			columnTypes[i] = ct.DatabaseTypeName()
+			resultColumns[i].Type = fullTypeName(ct)
+		}
+	}
+
 	// Prepare result container
 	maxRows := c.config.MaxRows
 	initialCap := 64
@@ -384,9 +628,18 @@ func (c *Client) ExecuteQueryWithContext(ctx context.Context, query string) (*Qu
 	results := make([]map[string]interface{}, 0, initialCap)
 	truncated := false
 
+	// Rows past the spill threshold move to disk
+	var spillWriter *spill.Writer
+	defer func() {
+		if spillWriter != nil {
+			spillWriter.Abort()
+		}
+	}()
+	rowCount := 0
+
 	// Iterate through rows
 	for rows.Next() {
-		if maxRows > 0 && len(results) >= maxRows {
+		if maxRows > 0 && rowCount >= maxRows {
 			truncated = true
 			break
 		}
@@ -402,18 +655,48 @@ func (c *Client) ExecuteQueryWithContext(ctx context.Context, query string) (*Qu
 
 		// Scan the row into values
 		if err := rows.Scan(valuePtrs...); err != nil {
-			log.Printf("Error scanning row: %v", err)
+			log.Printf("Error scanning row: %s", c.redact.Message(err.Error(), redactValues))
 			continue
 		}
 
 		// Create a map for the current row
 		rowMap := make(map[string]interface{})
 		for i, col := range columns {
-			val := values[i]
-			rowMap[col] = val
+			rowMap[col] = normalizeValue(columnTypes[i], values[i])
 		}
+		rowCount++
 
+		if spillWriter != nil {
+			if err := spillWriter.Write(rowMap); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		results = append(results, rowMap)
+
+		overBudget := false
+		if err := reservation.Grow(memguard.EstimateRow(rowMap)); err != nil {
+			if !allowSpill {
+				log.Printf("WARNING: Aborting query after %d rows: result exceeds the %d MB memory budget (MCP_MEMORY_BUDGET_MB)", rowCount, c.config.MemoryBudgetMB)
+				return nil, ErrResultTooLarge
+			}
+			overBudget = true
+		}
+
+		// Move rows to disk once past the spill threshold, or early when the
+		// memory budget runs out
+		if allowSpill && (overBudget || len(results) > c.config.SpillThresholdRows) {
+			if spillWriter, err = c.spiller.Create(); err != nil {
+				return nil, err
+			}
+			for _, row := range results {
+				if err := spillWriter.Write(row); err != nil {
+					return nil, err
+				}
+			}
+			results = nil
+			reservation.Release()
+		}
 	}
 
 	// When truncated, close rows immediately to stop server-side streaming
@@ -426,15 +709,27 @@ func (c *Client) ExecuteQueryWithContext(ctx context.Context, query string) (*Qu
 	} else {
 		// Only check rows.Err() when we consumed the full result set
 		if err := rows.Err(); err != nil {
-			return nil, fmt.Errorf("error iterating rows: %w", err)
+			return nil, fmt.Errorf("error iterating rows: %w", c.redact.Error(err, redactValues))
 		}
 	}
 
-	return &QueryResult{
+	result := &QueryResult{
 		Rows:      results,
+		Columns:   resultColumns,
 		Truncated: truncated,
 		MaxRows:   maxRows,
-	}, nil
+	}
+	if spillWriter != nil {
+		spillFile, err := spillWriter.Finish()
+		spillWriter = nil
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("INFO: Spilled %d result rows (%d bytes) to disk", spillFile.Rows(), spillFile.Bytes())
+		result.Spill = spillFile
+	}
+	result.reservation, reservation = reservation, nil
+	return result, nil
 }
 
 // ListCatalogs returns a list of available catalogs
@@ -461,7 +756,8 @@ func (c *Client) ListCatalogsWithContext(ctx context.Context) ([]string, error)
 		catalogs = c.filterCatalogs(catalogs)
 	}
 
-	return catalogs, nil
+	// List aliased catalogs under their aliases
+	return c.aliases.present(catalogs), nil
 }
 
 // ListSchemas returns a list of schemas in the specified catalog
@@ -474,6 +770,7 @@ func (c *Client) ListSchemasWithContext(ctx context.Context, catalog string) ([]
 	if catalog == "" {
 		catalog = c.config.Catalog
 	}
+	catalog = c.aliases.resolve(catalog)
 
 	query := fmt.Sprintf("SHOW SCHEMAS FROM %s", catalog)
 	result, err := c.ExecuteQueryWithContext(ctx, query)
@@ -506,6 +803,7 @@ func (c *Client) ListTablesWithContext(ctx context.Context, catalog, schema stri
 	if catalog == "" {
 		catalog = c.config.Catalog
 	}
+	catalog = c.aliases.resolve(catalog)
 	if schema == "" {
 		schema = c.config.Schema
 	}
@@ -539,28 +837,7 @@ func (c *Client) GetTableSchema(catalog, schema, table string) (*QueryResult, er
 // GetTableSchemaWithContext returns the schema of a table with context
 func (c *Client) GetTableSchemaWithContext(ctx context.Context, catalog, schema, table string) (*QueryResult, error) {
 	// Resolve catalog/schema/table parameters first
-	parts := strings.Split(table, ".")
-	if len(parts) == 3 {
-		// If table is already fully qualified, extract components
-		catalog = parts[0]
-		schema = parts[1]
-		table = parts[2]
-	} else if len(parts) == 2 {
-		// If table has schema.table format
-		schema = parts[0]
-		table = parts[1]
-		if catalog == "" {
-			catalog = c.config.Catalog
-		}
-	} else {
-		// Use provided or default catalog and schema
-		if catalog == "" {
-			catalog = c.config.Catalog
-		}
-		if schema == "" {
-			schema = c.config.Schema
-		}
-	}
+	catalog, schema, table = c.resolveTable(catalog, schema, table)
 
 	// Check if table access is allowed when table allowlist is configured (after resolution)
 	if len(c.config.AllowedTables) > 0 {
@@ -668,10 +945,16 @@ func (c *Client) filterTables(tables []string, catalog, schema string) []string
 	return filtered
 }
 
-// isCatalogAllowed checks if a catalog is in the allowed catalogs list
+// isCatalogAllowed checks if a catalog is in the allowed catalogs list. An
+// entry ending in "*" allows every catalog with that prefix.
 func (c *Client) isCatalogAllowed(catalog string) bool {
+	catalog = c.aliases.resolve(catalog)
 	for _, allowed := range c.config.AllowedCatalogs {
-		if strings.EqualFold(catalog, allowed) {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if len(catalog) >= len(prefix) && strings.EqualFold(catalog[:len(prefix)], prefix) {
+				return true
+			}
+		} else if strings.EqualFold(catalog, allowed) {
 			return true
 		}
 	}
@@ -680,7 +963,7 @@ func (c *Client) isCatalogAllowed(catalog string) bool {
 
 // isSchemaAllowed checks if a schema is in the allowed schemas list
 func (c *Client) isSchemaAllowed(catalog, schema string) bool {
-	fullSchemaName := catalog + "." + schema
+	fullSchemaName := c.aliases.resolve(catalog) + "." + schema
 	for _, allowed := range c.config.AllowedSchemas {
 		if strings.EqualFold(fullSchemaName, allowed) {
 			return true
@@ -691,7 +974,7 @@ func (c *Client) isSchemaAllowed(catalog, schema string) bool {
 
 // isTableAllowed checks if a table is in the allowed tables list
 func (c *Client) isTableAllowed(catalog, schema, table string) bool {
-	fullTableName := catalog + "." + schema + "." + table
+	fullTableName := c.aliases.resolve(catalog) + "." + schema + "." + table
 	for _, allowed := range c.config.AllowedTables {
 		if strings.EqualFold(fullTableName, allowed) {
 			return true
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/memguard"
+	"github.com/tuannvm/mcp-trino/internal/spill"
 	oauth "github.com/tuannvm/oauth-mcp-proxy"
 )
 
@@ -222,6 +224,27 @@ func TestIsCatalogAllowed(t *testing.T) {
 	}
 }
 
+func TestIsCatalogAllowedPrefix(t *testing.T) {
+	client := &Client{
+		config: &config.TrinoConfig{
+			AllowedCatalogs: []string{"acme_*", "shared"},
+		},
+	}
+
+	for catalog, expected := range map[string]bool{
+		"acme_iceberg": true,
+		"ACME_hive":    true,
+		"acme_":        true,
+		"acme":         false,
+		"globex_hive":  false,
+		"shared":       true,
+	} {
+		if got := client.isCatalogAllowed(catalog); got != expected {
+			t.Errorf("isCatalogAllowed(%q) = %v, want %v", catalog, got, expected)
+		}
+	}
+}
+
 func TestIsSchemaAllowed(t *testing.T) {
 	client := &Client{
 		config: &config.TrinoConfig{
@@ -500,10 +523,10 @@ func TestPrecompiledRegexConsistency(t *testing.T) {
 		{"REVOKE SELECT ON t FROM user1", false},
 
 		// Edge cases
-		{"SELECT*FROM users", true},       // word boundary handles this
-		{"SHOWTABLES", false},               // word boundary blocks
+		{"SELECT*FROM users", true},           // word boundary handles this
+		{"SHOWTABLES", false},                 // word boundary blocks
 		{"SELECT 1; DROP TABLE users", false}, // semicolon blocked
-		{"\n  SELECT * FROM t\n", true},    // newlines normalized
+		{"\n  SELECT * FROM t\n", true},       // newlines normalized
 	}
 
 	for _, tt := range queries {
@@ -652,3 +675,43 @@ func TestGetOAuthUserAndUsername(t *testing.T) {
 	}
 
 }
+
+func TestQueryResultCloseReleasesResources(t *testing.T) {
+	budget := memguard.NewBudget(1 << 20)
+	reservation, err := budget.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if err := reservation.Grow(1024); err != nil {
+		t.Fatalf("Grow() error = %v", err)
+	}
+
+	spiller, err := spill.New(spill.Options{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("spill.New() error = %v", err)
+	}
+	writer, err := spiller.Create()
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	file, err := writer.Finish()
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+
+	qr := &QueryResult{Spill: file, reservation: reservation}
+	if err := qr.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if budget.Used() != 0 {
+		t.Errorf("budget Used() = %d after Close, want 0", budget.Used())
+	}
+	if err := file.Iterate(func(map[string]interface{}) error { return nil }); err == nil {
+		t.Error("spill file still readable after Close")
+	}
+
+	// Results without managed resources close cleanly
+	if err := (&QueryResult{}).Close(); err != nil {
+		t.Errorf("Close() on plain result error = %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package trino
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// CostEstimate summarizes the IO plan of a query: how much data each input
+// table scan is expected to read and which column constraints limit it.
+type CostEstimate struct {
+	EstimatedBytes    *float64    `json:"estimated_bytes_scanned"` // nil when any table lacks statistics
+	EstimatedRows     *float64    `json:"estimated_rows_scanned"`
+	PartitionsTouched *int        `json:"partitions_touched,omitempty"`
+	Tables            []TableCost `json:"tables"`
+	OutputTable       string      `json:"output_table,omitempty"`
+	Warnings          []string    `json:"warnings,omitempty"`
+}
+
+// TableCost is the estimated scan of one input table.
+type TableCost struct {
+	Table          string             `json:"table"` // catalog.schema.table
+	EstimatedBytes *float64           `json:"estimated_bytes"`
+	EstimatedRows  *float64           `json:"estimated_rows"`
+	Unfiltered     bool               `json:"unfiltered"` // no predicate limits the scan
+	Partitions     *int               `json:"partitions_touched,omitempty"`
+	Constraints    []ColumnConstraint `json:"constraints,omitempty"`
+}
+
+// ColumnConstraint describes a predicate pushed into a table scan.
+type ColumnConstraint struct {
+	Column string `json:"column"`
+	Type   string `json:"type"`
+	Values int    `json:"values,omitempty"` // distinct values when the constraint is a value list
+	Ranges int    `json:"ranges"`
+}
+
+// EstimateQueryCost runs EXPLAIN (TYPE IO, FORMAT JSON) and parses the plan
+// into per-table scan estimates. Estimates come from connector statistics and
+// are missing for tables that have never been analyzed.
+func (c *Client) EstimateQueryCost(ctx context.Context, query string) (*CostEstimate, error) {
+	result, err := c.ExecuteQueryWithContext(ctx, "EXPLAIN (TYPE IO, FORMAT JSON) "+query)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Rows) == 0 {
+		return nil, errors.New("EXPLAIN returned no plan")
+	}
+	plan, ok := result.Rows[0]["Query Plan"].(string)
+	if !ok {
+		return nil, errors.New("EXPLAIN returned an unexpected plan format")
+	}
+	return parseIOPlan(plan)
+}
+
+// ioPlan mirrors the JSON printed by EXPLAIN (TYPE IO, FORMAT JSON)
+type ioPlan struct {
+	InputTableColumnInfos []struct {
+		Table      ioTable `json:"table"`
+		Constraint struct {
+			None              bool `json:"none"`
+			ColumnConstraints []struct {
+				ColumnName string `json:"columnName"`
+				Type       string `json:"type"`
+				Domain     struct {
+					Ranges []struct {
+						Low  ioMarker `json:"low"`
+						High ioMarker `json:"high"`
+					} `json:"ranges"`
+				} `json:"domain"`
+			} `json:"columnConstraints"`
+		} `json:"constraint"`
+		Estimate ioEstimate `json:"estimate"`
+	} `json:"inputTableColumnInfos"`
+	OutputTable *ioTable `json:"outputTable"`
+}
+
+type ioTable struct {
+	Catalog     string `json:"catalog"`
+	SchemaTable struct {
+		Schema string `json:"schema"`
+		Table  string `json:"table"`
+	} `json:"schemaTable"`
+}
+
+func (t ioTable) String() string {
+	return t.Catalog + "." + t.SchemaTable.Schema + "." + t.SchemaTable.Table
+}
+
+type ioMarker struct {
+	Value *string `json:"value"`
+	Bound string  `json:"bound"`
+}
+
+type ioEstimate struct {
+	OutputRowCount    ioNumber `json:"outputRowCount"`
+	OutputSizeInBytes ioNumber `json:"outputSizeInBytes"`
+}
+
+// ioNumber is a plan estimate. Trino prints unknown estimates as "NaN", which
+// decodes to nil.
+type ioNumber struct {
+	value *float64
+}
+
+func (n *ioNumber) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var value float64
+	switch v := raw.(type) {
+	case float64:
+		value = v
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil
+		}
+		value = parsed
+	default:
+		return nil
+	}
+	if !math.IsNaN(value) && !math.IsInf(value, 0) {
+		n.value = &value
+	}
+	return nil
+}
+
+// parseIOPlan converts an IO plan into a cost estimate
+func parseIOPlan(plan string) (*CostEstimate, error) {
+	var parsed ioPlan
+	if err := json.Unmarshal([]byte(plan), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse IO plan: %w", err)
+	}
+
+	estimate := &CostEstimate{Tables: make([]TableCost, 0, len(parsed.InputTableColumnInfos))}
+	if parsed.OutputTable != nil {
+		estimate.OutputTable = parsed.OutputTable.String()
+	}
+
+	var totalBytes, totalRows float64
+	bytesKnown, rowsKnown := true, true
+	totalPartitions, partitionsKnown := 0, len(parsed.InputTableColumnInfos) > 0
+	for _, input := range parsed.InputTableColumnInfos {
+		table := TableCost{
+			Table:          input.Table.String(),
+			EstimatedBytes: input.Estimate.OutputSizeInBytes.value,
+			EstimatedRows:  input.Estimate.OutputRowCount.value,
+			Unfiltered:     !input.Constraint.None && len(input.Constraint.ColumnConstraints) == 0,
+		}
+
+		partitions, discrete := 1, len(input.Constraint.ColumnConstraints) > 0
+		for _, cc := range input.Constraint.ColumnConstraints {
+			constraint := ColumnConstraint{Column: cc.ColumnName, Type: cc.Type, Ranges: len(cc.Domain.Ranges)}
+			values := 0
+			for _, r := range cc.Domain.Ranges {
+				if r.Low.Value != nil && r.High.Value != nil && *r.Low.Value == *r.High.Value &&
+					r.Low.Bound == "EXACTLY" && r.High.Bound == "EXACTLY" {
+					values++
+				}
+			}
+			if values == len(cc.Domain.Ranges) && values > 0 {
+				constraint.Values = values
+				partitions *= values
+			} else {
+				discrete = false
+			}
+			table.Constraints = append(table.Constraints, constraint)
+		}
+		if input.Constraint.None {
+			// The predicate is always false, so nothing is read
+			partitions, discrete = 0, true
+		}
+		if discrete {
+			table.Partitions = &partitions
+			totalPartitions += partitions
+		} else {
+			partitionsKnown = false
+		}
+
+		if table.EstimatedBytes != nil {
+			totalBytes += *table.EstimatedBytes
+		} else {
+			bytesKnown = false
+			estimate.Warnings = append(estimate.Warnings, fmt.Sprintf("no size statistics for %s; run ANALYZE to enable estimates", table.Table))
+		}
+		if table.EstimatedRows != nil {
+			totalRows += *table.EstimatedRows
+		} else {
+			rowsKnown = false
+		}
+		if table.Unfiltered {
+			estimate.Warnings = append(estimate.Warnings, fmt.Sprintf("%s is scanned without any filter", table.Table))
+		}
+		estimate.Tables = append(estimate.Tables, table)
+	}
+
+	if bytesKnown {
+		estimate.EstimatedBytes = &totalBytes
+	}
+	if rowsKnown {
+		estimate.EstimatedRows = &totalRows
+	}
+	if partitionsKnown {
+		estimate.PartitionsTouched = &totalPartitions
+	}
+	return estimate, nil
+}
@@ -0,0 +1,100 @@
+package trino
+
+import (
+	"testing"
+)
+
+const sampleIOPlan = `{
+  "inputTableColumnInfos" : [ {
+    "table" : { "catalog" : "hive", "schemaTable" : { "schema" : "web", "table" : "page_views" } },
+    "constraint" : {
+      "none" : false,
+      "columnConstraints" : [ {
+        "columnName" : "ds",
+        "type" : "varchar",
+        "domain" : {
+          "nullsAllowed" : false,
+          "ranges" : [
+            { "low" : { "value" : "2024-05-01", "bound" : "EXACTLY" }, "high" : { "value" : "2024-05-01", "bound" : "EXACTLY" } },
+            { "low" : { "value" : "2024-05-02", "bound" : "EXACTLY" }, "high" : { "value" : "2024-05-02", "bound" : "EXACTLY" } }
+          ]
+        }
+      } ]
+    },
+    "estimate" : { "outputRowCount" : 2000000.0, "outputSizeInBytes" : 5.0E8, "cpuCost" : 5.0E8, "maxMemory" : 0.0, "networkCost" : 0.0 }
+  }, {
+    "table" : { "catalog" : "hive", "schemaTable" : { "schema" : "web", "table" : "users" } },
+    "constraint" : { "none" : false, "columnConstraints" : [ ] },
+    "estimate" : { "outputRowCount" : "NaN", "outputSizeInBytes" : "NaN", "cpuCost" : "NaN", "maxMemory" : 0.0, "networkCost" : 0.0 }
+  } ],
+  "estimate" : { "outputRowCount" : "NaN", "outputSizeInBytes" : "NaN", "cpuCost" : "NaN", "maxMemory" : "NaN", "networkCost" : "NaN" }
+}`
+
+func TestParseIOPlan(t *testing.T) {
+	estimate, err := parseIOPlan(sampleIOPlan)
+	if err != nil {
+		t.Fatalf("parseIOPlan() error = %v", err)
+	}
+	if len(estimate.Tables) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(estimate.Tables))
+	}
+
+	views := estimate.Tables[0]
+	if views.Table != "hive.web.page_views" {
+		t.Errorf("Table = %q", views.Table)
+	}
+	if views.EstimatedBytes == nil || *views.EstimatedBytes != 5e8 {
+		t.Errorf("EstimatedBytes = %v, want 5e8", views.EstimatedBytes)
+	}
+	if views.Unfiltered {
+		t.Error("page_views has a ds filter and should not be unfiltered")
+	}
+	if views.Partitions == nil || *views.Partitions != 2 {
+		t.Errorf("Partitions = %v, want 2", views.Partitions)
+	}
+	if len(views.Constraints) != 1 || views.Constraints[0].Column != "ds" || views.Constraints[0].Values != 2 {
+		t.Errorf("Constraints = %+v", views.Constraints)
+	}
+
+	users := estimate.Tables[1]
+	if users.EstimatedBytes != nil || users.EstimatedRows != nil {
+		t.Errorf("expected NaN estimates to decode as unknown, got %v / %v", users.EstimatedBytes, users.EstimatedRows)
+	}
+	if !users.Unfiltered {
+		t.Error("users has no constraints and should be unfiltered")
+	}
+
+	// One table without statistics makes the totals unknown
+	if estimate.EstimatedBytes != nil || estimate.PartitionsTouched != nil {
+		t.Errorf("expected unknown totals, got bytes=%v partitions=%v", estimate.EstimatedBytes, estimate.PartitionsTouched)
+	}
+	if len(estimate.Warnings) != 2 {
+		t.Errorf("expected missing-statistics and unfiltered warnings, got %v", estimate.Warnings)
+	}
+}
+
+func TestParseIOPlanRangeConstraint(t *testing.T) {
+	plan := `{"inputTableColumnInfos":[{"table":{"catalog":"hive","schemaTable":{"schema":"s","table":"t"}},
+	  "constraint":{"none":false,"columnConstraints":[{"columnName":"ts","type":"timestamp(3)","domain":{"ranges":[
+	    {"low":{"value":"2024-01-01 00:00:00.000","bound":"EXACTLY"},"high":{"bound":"BELOW"}}]}}]},
+	  "estimate":{"outputRowCount":10.0,"outputSizeInBytes":100.0}}]}`
+	estimate, err := parseIOPlan(plan)
+	if err != nil {
+		t.Fatalf("parseIOPlan() error = %v", err)
+	}
+	if estimate.EstimatedBytes == nil || *estimate.EstimatedBytes != 100 {
+		t.Errorf("EstimatedBytes = %v, want 100", estimate.EstimatedBytes)
+	}
+	if estimate.Tables[0].Partitions != nil || estimate.PartitionsTouched != nil {
+		t.Error("open-ended ranges should not report a partition count")
+	}
+	if estimate.Tables[0].Constraints[0].Ranges != 1 {
+		t.Errorf("Ranges = %d, want 1", estimate.Tables[0].Constraints[0].Ranges)
+	}
+}
+
+func TestParseIOPlanInvalid(t *testing.T) {
+	if _, err := parseIOPlan("Fragment 0 [SINGLE]"); err == nil {
+		t.Error("expected an error for a non-JSON plan")
+	}
+}
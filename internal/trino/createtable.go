@@ -0,0 +1,271 @@
+package trino
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Connectors whose table types and properties create table suggestions
+// account for
+const (
+	hiveConnector  = "hive"
+	deltaConnector = "delta_lake"
+)
+
+var (
+	// unnamedColumnPattern matches the names Trino gives unaliased expressions
+	unnamedColumnPattern = regexp.MustCompile(`^_col\d+$`)
+	// boundedTypePattern matches varchar and char types with a length
+	boundedTypePattern = regexp.MustCompile(`^(var)?char\(\d+\)$`)
+	// timeTypePattern matches time and timestamp types, capturing the base
+	// type and any zone suffix
+	timeTypePattern = regexp.MustCompile(`^(time|timestamp)(?:\(\d+\))?( with time zone)?$`)
+	// partitionNamePattern matches column names conventionally used for
+	// date partitions
+	partitionNamePattern = regexp.MustCompile(`^(dt|ds|day|date|[a-z0-9_]*_(date|day|dt))$`)
+)
+
+// CreateTableOptions configures a create table suggestion.
+type CreateTableOptions struct {
+	Query   string
+	Catalog string
+	Schema  string
+	Table   string // target table name, optionally schema- or catalog-qualified
+}
+
+// CreateTableSuggestion is a table definition matching a query's result.
+type CreateTableSuggestion struct {
+	Table         string            `json:"table"`
+	Connector     string            `json:"connector,omitempty"`
+	Columns       []SuggestedColumn `json:"columns"`
+	Partitioning  []string          `json:"partitioning,omitempty"`
+	CreateTable   string            `json:"create_table"`    // an empty table, for loading later
+	CreateTableAs string            `json:"create_table_as"` // the table filled with the query's result
+	Notes         []string          `json:"notes,omitempty"`
+}
+
+// SuggestedColumn is one column of a suggested table.
+type SuggestedColumn struct {
+	Name       string `json:"name"`
+	ResultType string `json:"result_type"` // the type the query returns
+	Type       string `json:"type"`        // the suggested column type
+	Reason     string `json:"reason,omitempty"`
+}
+
+// SuggestCreateTable derives a query's result columns and types without
+// running it to completion, and suggests a CREATE TABLE and a CREATE TABLE
+// AS SELECT statement that persist its result. Types the target catalog's
+// connector cannot store are replaced, and a date column is suggested for
+// partitioning. Nothing is created.
+func (c *Client) SuggestCreateTable(ctx context.Context, opts CreateTableOptions) (*CreateTableSuggestion, error) {
+	query := strings.TrimSuffix(strings.TrimSpace(opts.Query), ";")
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if !isReadOnlyQuery(query) {
+		return nil, fmt.Errorf("only read-only queries (SELECT, SHOW, DESCRIBE, EXPLAIN) can be persisted with CREATE TABLE AS")
+	}
+	if opts.Table == "" {
+		return nil, fmt.Errorf("table is required")
+	}
+	catalog, schema, table := c.resolveTable(opts.Catalog, opts.Schema, opts.Table)
+	if len(c.config.AllowedCatalogs) > 0 && !c.isCatalogAllowed(catalog) {
+		return nil, fmt.Errorf("catalog access denied: %s not in allowlist", catalog)
+	}
+
+	// LIMIT 0 returns the result's columns and types without reading data
+	result, err := c.ExecuteQueryWithContext(ctx, "SELECT * FROM (\n"+query+"\n) LIMIT 0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the result columns: %w", err)
+	}
+	if len(result.Columns) == 0 {
+		return nil, fmt.Errorf("the query returns no columns")
+	}
+
+	var notes []string
+	connector, err := c.connectorName(ctx, catalog)
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("Could not look up the connector of catalog %s (%v), so types and properties are not adapted to it.", catalog, err))
+	}
+	exists, err := c.ExecuteQueryWithContext(ctx, fmt.Sprintf(
+		"SELECT table_name FROM %s.information_schema.tables WHERE table_schema = %s AND table_name = %s",
+		quoteIdentifier(catalog), quoteLiteral(schema), quoteLiteral(table)))
+	if err == nil && len(exists.Rows) > 0 {
+		notes = append(notes, fmt.Sprintf("Table %s.%s.%s already exists; pick another name or use INSERT INTO.", catalog, schema, table))
+	}
+
+	suggestion := buildCreateTable(qualifiedTableName(catalog, schema, table), connector, result.Columns, query)
+	suggestion.Notes = append(notes, suggestion.Notes...)
+	return suggestion, nil
+}
+
+// buildCreateTable suggests a table for a query's result columns
+func buildCreateTable(target, connector string, columns []Column, query string) *CreateTableSuggestion {
+	s := &CreateTableSuggestion{Table: target, Connector: connector}
+
+	// Name every column uniquely; the CTAS renames the query's columns by
+	// position, so unnamed and repeated columns need not be aliased in it
+	used := make(map[string]bool)
+	var unnamed, renamed []string
+	for i, column := range columns {
+		name := column.Name
+		if unnamedColumnPattern.MatchString(name) || name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+			unnamed = append(unnamed, name)
+		}
+		if used[name] {
+			base := name
+			for n := 2; used[name]; n++ {
+				name = fmt.Sprintf("%s_%d", base, n)
+			}
+			renamed = append(renamed, fmt.Sprintf("%s to %s", base, name))
+		}
+		used[name] = true
+		suggested, reason := suggestColumnType(connector, column.Type)
+		s.Columns = append(s.Columns, SuggestedColumn{Name: name, ResultType: column.Type, Type: suggested, Reason: reason})
+	}
+	if len(unnamed) > 0 {
+		s.Notes = append(s.Notes, fmt.Sprintf("Some result columns have no name and were called %s; alias them in the query to choose better names.", strings.Join(unnamed, ", ")))
+	}
+	if len(renamed) > 0 {
+		s.Notes = append(s.Notes, fmt.Sprintf("Repeated column names were renamed: %s.", strings.Join(renamed, ", ")))
+	}
+
+	partition, order, note := suggestPartitioning(connector, s.Columns)
+	if partition != "" {
+		s.Partitioning = []string{partition}
+	}
+	if note != "" {
+		s.Notes = append(s.Notes, note)
+	}
+
+	var properties []string
+	if connector == hiveConnector {
+		properties = append(properties, "format = 'PARQUET'")
+	}
+	if partition != "" {
+		key := "partitioned_by"
+		if connector == icebergConnector {
+			key = "partitioning"
+		}
+		properties = append(properties, fmt.Sprintf("%s = ARRAY[%s]", key, quoteLiteral(partition)))
+	}
+	with := ""
+	if len(properties) > 0 {
+		with = "\nWITH (\n  " + strings.Join(properties, ",\n  ") + "\n)"
+	}
+
+	definitions := make([]string, len(order))
+	selects := make([]string, len(order))
+	for i, index := range order {
+		column := s.Columns[index]
+		definitions[i] = quoteIdentifier(column.Name) + " " + column.Type
+		selects[i] = quoteIdentifier(column.Name)
+		if column.Type != column.ResultType {
+			selects[i] = fmt.Sprintf("CAST(%s AS %s) AS %s", selects[i], column.Type, selects[i])
+		}
+	}
+	aliases := make([]string, len(s.Columns))
+	for i, column := range s.Columns {
+		aliases[i] = quoteIdentifier(column.Name)
+	}
+	s.CreateTable = fmt.Sprintf("CREATE TABLE %s (\n  %s\n)%s", target, strings.Join(definitions, ",\n  "), with)
+	s.CreateTableAs = fmt.Sprintf("CREATE TABLE %s%s AS\nSELECT\n  %s\nFROM (\n%s\n) AS source (%s)",
+		target, with, strings.Join(selects, ",\n  "), query, strings.Join(aliases, ", "))
+	return s
+}
+
+// suggestColumnType returns the type to store a result type as on a
+// connector, and why it differs (empty when it does not)
+func suggestColumnType(connector, resultType string) (string, string) {
+	lakehouse := connector == hiveConnector || connector == icebergConnector || connector == deltaConnector
+	switch {
+	case resultType == "unknown":
+		return "varchar", "the query only returns NULL here, so the type is unknown; cast it in the query to the type you want"
+	case strings.HasPrefix(resultType, "char("):
+		return "varchar", "CHAR pads values with trailing spaces"
+	case lakehouse && (resultType == "json" || resultType == "ipaddress"):
+		return "varchar", fmt.Sprintf("the %s connector cannot store %s", connector, resultType)
+	case connector == hiveConnector && resultType == "uuid":
+		return "varchar", "the hive connector cannot store uuid"
+	}
+
+	switch connector {
+	case hiveConnector:
+		if m := timeTypePattern.FindStringSubmatch(resultType); m != nil {
+			if m[1] == "time" {
+				return "varchar", "the hive connector has no time type"
+			}
+			if m[2] != "" {
+				return strings.TrimSuffix(resultType, m[2]), "the hive connector cannot store time zones; values keep their local time in their zone"
+			}
+		}
+	case icebergConnector, deltaConnector:
+		if boundedTypePattern.MatchString(resultType) {
+			return "varchar", fmt.Sprintf("the %s connector stores strings without a length", connector)
+		}
+		if connector == icebergConnector {
+			if resultType == "tinyint" || resultType == "smallint" {
+				return "integer", "the iceberg connector has no integer types narrower than integer"
+			}
+			if m := timeTypePattern.FindStringSubmatch(resultType); m != nil {
+				if suggested := m[1] + "(6)" + m[2]; suggested != resultType {
+					return suggested, "the iceberg connector stores times with microsecond precision"
+				}
+			}
+		}
+	}
+	return resultType, ""
+}
+
+// suggestPartitioning picks a partition column or transform, the order the
+// columns should be defined in, and a note explaining the choice. Hive and
+// Delta Lake partition by a column's values, which must come last; Iceberg
+// can partition a timestamp by day.
+func suggestPartitioning(connector string, columns []SuggestedColumn) (string, []int, string) {
+	order := make([]int, len(columns))
+	for i := range order {
+		order[i] = i
+	}
+	if connector != hiveConnector && connector != icebergConnector && connector != deltaConnector {
+		return "", order, ""
+	}
+
+	// Prefer date columns with partition-like names, then any date column,
+	// then string columns with partition-like names
+	best, bestScore := -1, 0
+	for i, column := range columns {
+		score := 0
+		named := partitionNamePattern.MatchString(column.Name)
+		switch {
+		case column.Type == "date" && named:
+			score = 4
+		case column.Type == "date":
+			score = 3
+		case column.Type == "varchar" && named:
+			score = 2
+		case connector == icebergConnector && strings.HasPrefix(column.Type, "timestamp"):
+			score = 1
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return "", order, "No date column was found to partition by. Leave small results unpartitioned; for a table that will grow, add a date column, such as CAST(event_time AS date), and partition by it."
+	}
+
+	name := columns[best].Name
+	note := fmt.Sprintf("Partitioned by %s so queries filtering on it read only matching partitions. Drop the partitioning if the result is small, since each partition adds files.", name)
+	if bestScore == 1 {
+		return fmt.Sprintf("day(%s)", name), order, note
+	}
+	if connector == icebergConnector {
+		return name, order, note
+	}
+	// Partition columns must be defined and selected last
+	order = append(append(order[:best:best], order[best+1:]...), best)
+	return name, order, note
+}
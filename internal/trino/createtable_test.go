@@ -0,0 +1,159 @@
+package trino
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestSuggestColumnType(t *testing.T) {
+	tests := []struct {
+		connector, resultType, want string
+	}{
+		{"hive", "bigint", "bigint"},
+		{"hive", "unknown", "varchar"},
+		{"hive", "char(3)", "varchar"},
+		{"hive", "json", "varchar"},
+		{"hive", "uuid", "varchar"},
+		{"hive", "time(3)", "varchar"},
+		{"hive", "timestamp(3) with time zone", "timestamp(3)"},
+		{"hive", "varchar(10)", "varchar(10)"},
+		{"iceberg", "varchar(10)", "varchar"},
+		{"iceberg", "smallint", "integer"},
+		{"iceberg", "timestamp(3)", "timestamp(6)"},
+		{"iceberg", "timestamp(6) with time zone", "timestamp(6) with time zone"},
+		{"iceberg", "uuid", "uuid"},
+		{"delta_lake", "ipaddress", "varchar"},
+		{"postgresql", "json", "json"},
+		{"postgresql", "varchar(10)", "varchar(10)"},
+	}
+	for _, tt := range tests {
+		got, reason := suggestColumnType(tt.connector, tt.resultType)
+		if got != tt.want {
+			t.Errorf("suggestColumnType(%q, %q) = %q, want %q", tt.connector, tt.resultType, got, tt.want)
+		}
+		if (got != tt.resultType) != (reason != "") {
+			t.Errorf("suggestColumnType(%q, %q) reason = %q", tt.connector, tt.resultType, reason)
+		}
+	}
+}
+
+func TestBuildCreateTable(t *testing.T) {
+	columns := []Column{
+		{Name: "dt", Type: "date"},
+		{Name: "region", Type: "varchar(8)"},
+		{Name: "_col2", Type: "bigint"},
+		{Name: "region", Type: "varchar"},
+	}
+	query := "SELECT dt, region, count(*), upper(region) AS region FROM events GROUP BY 1, 2"
+
+	hive := buildCreateTable(`"hive"."sales"."daily"`, "hive", columns, query)
+	if got := strings.Join(hive.Partitioning, ","); got != "dt" {
+		t.Errorf("hive partitioning = %q, want dt", got)
+	}
+	wantDDL := "CREATE TABLE \"hive\".\"sales\".\"daily\" (\n  \"region\" varchar(8),\n  \"column_3\" bigint,\n  \"region_2\" varchar,\n  \"dt\" date\n)\nWITH (\n  format = 'PARQUET',\n  partitioned_by = ARRAY['dt']\n)"
+	if hive.CreateTable != wantDDL {
+		t.Errorf("hive CreateTable =\n%s\nwant\n%s", hive.CreateTable, wantDDL)
+	}
+	if !strings.HasSuffix(hive.CreateTableAs, `) AS source ("dt", "region", "column_3", "region_2")`) {
+		t.Errorf("hive CreateTableAs does not rename the query's columns by position:\n%s", hive.CreateTableAs)
+	}
+	if len(hive.Notes) != 3 {
+		t.Errorf("hive notes = %q, want unnamed, renamed, and partitioning notes", hive.Notes)
+	}
+
+	iceberg := buildCreateTable(`"lake"."sales"."daily"`, "iceberg", []Column{
+		{Name: "event_time", Type: "timestamp(3)"},
+		{Name: "kind", Type: "varchar(8)"},
+	}, "SELECT event_time, kind FROM events")
+	if got := strings.Join(iceberg.Partitioning, ","); got != "day(event_time)" {
+		t.Errorf("iceberg partitioning = %q, want day(event_time)", got)
+	}
+	if !strings.Contains(iceberg.CreateTableAs, `CAST("event_time" AS timestamp(6)) AS "event_time"`) ||
+		!strings.Contains(iceberg.CreateTableAs, `CAST("kind" AS varchar) AS "kind"`) ||
+		!strings.Contains(iceberg.CreateTableAs, "partitioning = ARRAY['day(event_time)']") {
+		t.Errorf("iceberg CreateTableAs =\n%s", iceberg.CreateTableAs)
+	}
+
+	other := buildCreateTable(`"pg"."public"."daily"`, "postgresql", columns[:2], query)
+	if len(other.Partitioning) != 0 || strings.Contains(other.CreateTable, "WITH") {
+		t.Errorf("postgresql suggestion has table properties:\n%s", other.CreateTable)
+	}
+}
+
+func TestSuggestCreateTableValidation(t *testing.T) {
+	c := &Client{config: &config.TrinoConfig{Catalog: "hive", Schema: "default", AllowedCatalogs: []string{"hive"}}}
+	tests := []struct {
+		name string
+		opts CreateTableOptions
+		want string
+	}{
+		{"missing query", CreateTableOptions{Table: "t"}, "query is required"},
+		{"write query", CreateTableOptions{Query: "DELETE FROM t", Table: "t"}, "only read-only queries"},
+		{"missing table", CreateTableOptions{Query: "SELECT 1"}, "table is required"},
+		{"catalog not allowed", CreateTableOptions{Query: "SELECT 1", Table: "other.s.t"}, "catalog access denied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.SuggestCreateTable(context.Background(), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("SuggestCreateTable() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestCreateTable(t *testing.T) {
+	c := newFakeCoordinator(t, func(statement string) fakeResult {
+		switch {
+		case strings.HasSuffix(statement, ") LIMIT 0"):
+			return fakeResult{columns: []fakeColumn{
+				{name: "order_date", rawType: "date"},
+				{name: "status", rawType: "varchar"},
+				{name: "code", rawType: "varchar", args: []int64{3}},
+				{name: "total", rawType: "decimal", args: []int64{10, 2}},
+				{name: "placed_at", rawType: "timestamp with time zone", args: []int64{3}},
+			}}
+		case strings.Contains(statement, "system.metadata.catalogs"):
+			return fakeResult{columns: []fakeColumn{{name: "connector_name", rawType: "varchar"}}, data: [][]any{{"hive"}}}
+		case strings.Contains(statement, "information_schema.tables"):
+			return fakeResult{columns: []fakeColumn{{name: "table_name", rawType: "varchar"}}, data: [][]any{{"orders_daily"}}}
+		}
+		return fakeResult{err: "unexpected statement: " + statement}
+	})
+
+	s, err := c.SuggestCreateTable(context.Background(), CreateTableOptions{
+		Query: "SELECT order_date, status, code, total, placed_at FROM orders;",
+		Table: "sales.orders_daily",
+	})
+	if err != nil {
+		t.Fatalf("SuggestCreateTable() error = %v", err)
+	}
+
+	if s.Table != `"hive"."sales"."orders_daily"` || s.Connector != "hive" {
+		t.Errorf("table = %s on %q", s.Table, s.Connector)
+	}
+	want := map[string]string{
+		"order_date": "date",
+		"status":     "varchar",
+		"code":       "varchar(3)",
+		"total":      "decimal(10,2)",
+		"placed_at":  "timestamp(3) with time zone",
+	}
+	for _, column := range s.Columns {
+		if column.ResultType != want[column.Name] {
+			t.Errorf("column %s result type = %q, want %q", column.Name, column.ResultType, want[column.Name])
+		}
+	}
+	if got := strings.Join(s.Partitioning, ","); got != "order_date" {
+		t.Errorf("partitioning = %q, want order_date", got)
+	}
+	if len(s.Notes) == 0 || !strings.Contains(s.Notes[0], "already exists") {
+		t.Errorf("notes = %q, want the existing table reported first", s.Notes)
+	}
+	if strings.Contains(s.CreateTableAs, "orders;") {
+		t.Errorf("CreateTableAs keeps the query's semicolon:\n%s", s.CreateTableAs)
+	}
+}
@@ -0,0 +1,357 @@
+package trino
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Diff defaults and caps
+const (
+	DefaultDiffSamples = 10
+
+	maxDiffSamples = 100
+)
+
+// DiffOptions configures a comparison of two query results. The two sides
+// can be different queries, the same query on two clusters, or the same
+// query against two versions of an Iceberg table.
+type DiffOptions struct {
+	Query      string
+	OtherQuery string  // defaults to Query
+	Other      *Client // runs OtherQuery (nil = the same client)
+
+	// Table is an Iceberg table the two sides read at different versions,
+	// optionally schema- or catalog-qualified. A side with neither a version
+	// nor a timestamp reads the current version.
+	Catalog        string
+	Schema         string
+	Table          string
+	Version        string
+	Timestamp      string
+	OtherVersion   string
+	OtherTimestamp string
+
+	KeyColumns []string // identify rows across sides; whole rows are compared when empty
+	Samples    int      // differing rows returned of each kind (defaults to DefaultDiffSamples)
+}
+
+// ResultDiff compares the results of two queries, the left and the right.
+type ResultDiff struct {
+	Left             DiffSide       `json:"left"`
+	Right            DiffSide       `json:"right"`
+	Identical        bool           `json:"identical"`
+	RowCountDelta    int            `json:"row_count_delta"` // right minus left
+	KeyColumns       []string       `json:"key_columns,omitempty"`
+	Matched          int            `json:"matched"`           // rows equal on both sides
+	Changed          int            `json:"changed,omitempty"` // keyed rows whose other columns differ
+	OnlyLeft         int            `json:"only_left"`
+	OnlyRight        int            `json:"only_right"`
+	ChangedColumns   map[string]int `json:"changed_columns,omitempty"` // changed rows per column
+	ColumnsOnlyLeft  []string       `json:"columns_only_left,omitempty"`
+	ColumnsOnlyRight []string       `json:"columns_only_right,omitempty"`
+	Samples          DiffSamples    `json:"samples"`
+	Notes            []string       `json:"notes,omitempty"`
+}
+
+// DiffSide describes one side of a diff.
+type DiffSide struct {
+	Query       string `json:"query"`
+	Environment string `json:"environment,omitempty"`
+	AsOf        string `json:"as_of,omitempty"`
+	RowCount    int    `json:"row_count"`
+	Truncated   bool   `json:"truncated,omitempty"`
+}
+
+// DiffSamples are the first differing rows of each kind, in result order.
+type DiffSamples struct {
+	OnlyLeft  []map[string]interface{} `json:"only_left,omitempty"`
+	OnlyRight []map[string]interface{} `json:"only_right,omitempty"`
+	Changed   []RowChange              `json:"changed,omitempty"`
+}
+
+// RowChange is a keyed row whose other columns differ between the sides.
+type RowChange struct {
+	Key     map[string]interface{} `json:"key"`
+	Columns map[string]ValueChange `json:"columns"`
+}
+
+// ValueChange is a column value on each side.
+type ValueChange struct {
+	Left  interface{} `json:"left"`
+	Right interface{} `json:"right"`
+}
+
+// DiffQueries runs two read-only queries and compares their results. Both
+// results are capped at TRINO_MAX_ROWS, so compare aggregates or filtered
+// slices of large tables rather than whole tables.
+func (c *Client) DiffQueries(ctx context.Context, opts DiffOptions) (*ResultDiff, error) {
+	other := opts.Other
+	if other == nil {
+		other = c
+	}
+	left := DiffSide{Query: strings.TrimSpace(opts.Query)}
+	right := DiffSide{Query: strings.TrimSpace(opts.OtherQuery)}
+	if right.Query == "" {
+		right.Query = left.Query
+	}
+	if left.Query == "" {
+		return nil, errors.New("query is required")
+	}
+	for _, side := range []*DiffSide{&left, &right} {
+		if !isReadOnlyQuery(strings.TrimSuffix(side.Query, ";")) {
+			return nil, errors.New("only read-only queries (SELECT, SHOW, DESCRIBE, EXPLAIN) can be compared")
+		}
+	}
+	// Each side must read only tables allowed on the client that runs it
+	if err := c.CheckQueryAccess(left.Query); err != nil {
+		return nil, err
+	}
+	if err := other.CheckQueryAccess(right.Query); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(opts.Table) != "" {
+		if opts.Version == "" && opts.Timestamp == "" && opts.OtherVersion == "" && opts.OtherTimestamp == "" {
+			return nil, errors.New("comparing versions of a table needs a version or timestamp for at least one side")
+		}
+		var err error
+		if left.Query, left.AsOf, err = c.queryAtTableVersion(ctx, opts, left.Query, opts.Version, opts.Timestamp); err != nil {
+			return nil, err
+		}
+		if right.Query, right.AsOf, err = other.queryAtTableVersion(ctx, opts, right.Query, opts.OtherVersion, opts.OtherTimestamp); err != nil {
+			return nil, err
+		}
+	}
+
+	leftResult, err := c.ExecuteQueryWithContext(ctx, left.Query)
+	if err != nil {
+		return nil, fmt.Errorf("left query failed: %w", err)
+	}
+	rightResult, err := other.ExecuteQueryWithContext(ctx, right.Query)
+	if err != nil {
+		return nil, fmt.Errorf("right query failed: %w", err)
+	}
+	left.RowCount, left.Truncated = len(leftResult.Rows), leftResult.Truncated
+	right.RowCount, right.Truncated = len(rightResult.Rows), rightResult.Truncated
+
+	diff, err := DiffResults(leftResult.Rows, rightResult.Rows, opts.KeyColumns, opts.Samples)
+	if err != nil {
+		return nil, err
+	}
+	diff.Left, diff.Right = left, right
+	for _, side := range []struct {
+		name   string
+		result *QueryResult
+	}{{"left", leftResult}, {"right", rightResult}} {
+		if side.result.Truncated {
+			diff.Notes = append(diff.Notes, fmt.Sprintf("The %s result was truncated at %d rows (TRINO_MAX_ROWS); later rows were not compared.", side.name, side.result.MaxRows))
+		}
+	}
+	return diff, nil
+}
+
+// queryAtTableVersion reads the diffed table at a version in query, or
+// leaves query as is when no version or timestamp is given
+func (c *Client) queryAtTableVersion(ctx context.Context, opts DiffOptions, query, version, timestamp string) (string, string, error) {
+	if version == "" && timestamp == "" {
+		return query, "current", nil
+	}
+	clause, asOf, err := versionClause(version, timestamp)
+	if err != nil {
+		return "", "", err
+	}
+	catalog, schema, table := c.resolveTable(opts.Catalog, opts.Schema, opts.Table)
+	if err := c.checkTimeTravelTable(ctx, catalog, schema, table); err != nil {
+		return "", "", err
+	}
+	query, err = c.applyTableVersion(query, catalog, schema, table, clause)
+	return query, asOf, err
+}
+
+// DiffResults compares two sets of rows. With key columns, rows are paired
+// by key and their other columns compared, so a changed row counts once as
+// changed. Without them, rows are compared whole as a multiset, and a
+// changed row counts once on each side. Only columns present on both sides
+// are compared.
+func DiffResults(left, right []map[string]interface{}, keyColumns []string, samples int) (*ResultDiff, error) {
+	samples = clampOption(samples, DefaultDiffSamples, maxDiffSamples)
+	leftColumns, rightColumns := resultColumns(left), resultColumns(right)
+	for _, key := range keyColumns {
+		for _, side := range []struct {
+			name    string
+			rows    []map[string]interface{}
+			columns map[string]bool
+		}{{"left", left, leftColumns}, {"right", right, rightColumns}} {
+			if len(side.rows) > 0 && !side.columns[key] {
+				return nil, fmt.Errorf("key column %q is not in the %s result", key, side.name)
+			}
+		}
+	}
+
+	diff := &ResultDiff{RowCountDelta: len(right) - len(left), KeyColumns: keyColumns}
+	var shared []string
+	for column := range leftColumns {
+		if rightColumns[column] {
+			shared = append(shared, column)
+		} else if len(right) > 0 {
+			diff.ColumnsOnlyLeft = append(diff.ColumnsOnlyLeft, column)
+		}
+	}
+	for column := range rightColumns {
+		if !leftColumns[column] && len(left) > 0 {
+			diff.ColumnsOnlyRight = append(diff.ColumnsOnlyRight, column)
+		}
+	}
+	sort.Strings(shared)
+	sort.Strings(diff.ColumnsOnlyLeft)
+	sort.Strings(diff.ColumnsOnlyRight)
+	if len(diff.ColumnsOnlyLeft)+len(diff.ColumnsOnlyRight) > 0 {
+		diff.Notes = append(diff.Notes, "Columns present on only one side were not compared.")
+	}
+
+	if len(keyColumns) > 0 {
+		diffKeyed(diff, left, right, shared, samples)
+	} else {
+		diffWhole(diff, left, right, shared, samples)
+	}
+	diff.Identical = diff.RowCountDelta == 0 && diff.Changed == 0 && diff.OnlyLeft == 0 && diff.OnlyRight == 0 &&
+		len(diff.ColumnsOnlyLeft) == 0 && len(diff.ColumnsOnlyRight) == 0
+	return diff, nil
+}
+
+// diffWhole compares rows as a multiset of their shared column values
+func diffWhole(diff *ResultDiff, left, right []map[string]interface{}, columns []string, samples int) {
+	unmatched := func(rows, against []map[string]interface{}) []map[string]interface{} {
+		remaining := make(map[string]int, len(against))
+		for _, row := range against {
+			remaining[encodeValues(row, columns)]++
+		}
+		var only []map[string]interface{}
+		for _, row := range rows {
+			encoded := encodeValues(row, columns)
+			if remaining[encoded] > 0 {
+				remaining[encoded]--
+				continue
+			}
+			only = append(only, row)
+		}
+		return only
+	}
+	onlyLeft, onlyRight := unmatched(left, right), unmatched(right, left)
+	diff.OnlyLeft, diff.OnlyRight = len(onlyLeft), len(onlyRight)
+	diff.Matched = len(left) - len(onlyLeft)
+	diff.Samples.OnlyLeft = onlyLeft[:min(len(onlyLeft), samples)]
+	diff.Samples.OnlyRight = onlyRight[:min(len(onlyRight), samples)]
+}
+
+// diffKeyed pairs rows by key and compares their other shared columns. Only
+// the first row with each key is compared.
+func diffKeyed(diff *ResultDiff, left, right []map[string]interface{}, columns []string, samples int) {
+	isKey := make(map[string]bool, len(diff.KeyColumns))
+	for _, key := range diff.KeyColumns {
+		isKey[key] = true
+	}
+	duplicates := 0
+	index := func(rows []map[string]interface{}) map[string]map[string]interface{} {
+		byKey := make(map[string]map[string]interface{}, len(rows))
+		for _, row := range rows {
+			key := encodeValues(row, diff.KeyColumns)
+			if _, ok := byKey[key]; ok {
+				duplicates++
+				continue
+			}
+			byKey[key] = row
+		}
+		return byKey
+	}
+	leftByKey, rightByKey := index(left), index(right)
+
+	seen := make(map[string]bool, len(leftByKey)+len(rightByKey))
+	for _, row := range left {
+		key := encodeValues(row, diff.KeyColumns)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		other, ok := rightByKey[key]
+		if !ok {
+			diff.OnlyLeft++
+			if len(diff.Samples.OnlyLeft) < samples {
+				diff.Samples.OnlyLeft = append(diff.Samples.OnlyLeft, row)
+			}
+			continue
+		}
+		changes := make(map[string]ValueChange)
+		for _, column := range columns {
+			if !isKey[column] && encodeValue(row[column]) != encodeValue(other[column]) {
+				changes[column] = ValueChange{Left: row[column], Right: other[column]}
+			}
+		}
+		if len(changes) == 0 {
+			diff.Matched++
+			continue
+		}
+		diff.Changed++
+		if diff.ChangedColumns == nil {
+			diff.ChangedColumns = make(map[string]int)
+		}
+		for column := range changes {
+			diff.ChangedColumns[column]++
+		}
+		if len(diff.Samples.Changed) < samples {
+			keyValues := make(map[string]interface{}, len(diff.KeyColumns))
+			for _, column := range diff.KeyColumns {
+				keyValues[column] = row[column]
+			}
+			diff.Samples.Changed = append(diff.Samples.Changed, RowChange{Key: keyValues, Columns: changes})
+		}
+	}
+	for _, row := range right {
+		key := encodeValues(row, diff.KeyColumns)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		diff.OnlyRight++
+		if len(diff.Samples.OnlyRight) < samples {
+			diff.Samples.OnlyRight = append(diff.Samples.OnlyRight, row)
+		}
+	}
+	if duplicates > 0 {
+		diff.Notes = append(diff.Notes, fmt.Sprintf("%d rows repeat a key seen earlier on the same side; only the first row with each key was compared. Choose key columns that are unique.", duplicates))
+	}
+}
+
+// resultColumns returns the column names of a result
+func resultColumns(rows []map[string]interface{}) map[string]bool {
+	columns := make(map[string]bool)
+	if len(rows) > 0 {
+		for column := range rows[0] {
+			columns[column] = true
+		}
+	}
+	return columns
+}
+
+// encodeValues encodes a row's values in the given columns for comparison
+func encodeValues(row map[string]interface{}, columns []string) string {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		values[i] = encodeValue(row[column])
+	}
+	return strings.Join(values, "\x00")
+}
+
+// encodeValue encodes a value as JSON, so equal values of different Go
+// types (such as int32 and int64 from different connectors) compare equal
+func encodeValue(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
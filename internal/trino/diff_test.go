@@ -0,0 +1,113 @@
+package trino
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestDiffResultsKeyed(t *testing.T) {
+	left := []map[string]interface{}{
+		{"id": int64(1), "region": "emea", "total": 10.5},
+		{"id": int64(2), "region": "apac", "total": 20.0},
+		{"id": int64(3), "region": "amer", "total": 30.0},
+		{"id": int64(3), "region": "amer", "total": 99.0}, // duplicate key
+	}
+	right := []map[string]interface{}{
+		{"id": int32(1), "region": "emea", "total": 10.5}, // same value, different Go type
+		{"id": int64(2), "region": "apac", "total": 25.0},
+		{"id": int64(4), "region": "amer", "total": 40.0},
+	}
+
+	diff, err := DiffResults(left, right, []string{"id"}, 0)
+	if err != nil {
+		t.Fatalf("DiffResults() error = %v", err)
+	}
+	if diff.Identical || diff.RowCountDelta != -1 || diff.Matched != 1 || diff.Changed != 1 || diff.OnlyLeft != 1 || diff.OnlyRight != 1 {
+		t.Errorf("diff = %+v", diff)
+	}
+	if diff.ChangedColumns["total"] != 1 || len(diff.ChangedColumns) != 1 {
+		t.Errorf("ChangedColumns = %v", diff.ChangedColumns)
+	}
+	if len(diff.Samples.Changed) != 1 {
+		t.Fatalf("changed samples = %+v", diff.Samples.Changed)
+	}
+	change := diff.Samples.Changed[0]
+	if change.Key["id"] != int64(2) || change.Columns["total"] != (ValueChange{Left: 20.0, Right: 25.0}) {
+		t.Errorf("changed sample = %+v", change)
+	}
+	if diff.Samples.OnlyLeft[0]["id"] != int64(3) || diff.Samples.OnlyRight[0]["id"] != int64(4) {
+		t.Errorf("samples = %+v", diff.Samples)
+	}
+	if len(diff.Notes) != 1 || !strings.Contains(diff.Notes[0], "1 rows repeat a key") {
+		t.Errorf("Notes = %q", diff.Notes)
+	}
+}
+
+func TestDiffResultsWhole(t *testing.T) {
+	left := []map[string]interface{}{
+		{"region": "emea", "n": int64(1), "legacy": true},
+		{"region": "emea", "n": int64(1), "legacy": true},
+		{"region": "apac", "n": int64(2), "legacy": false},
+	}
+	right := []map[string]interface{}{
+		{"region": "apac", "n": int64(2)},
+		{"region": "emea", "n": int64(1)},
+		{"region": "amer", "n": int64(3)},
+	}
+
+	diff, err := DiffResults(left, right, nil, 1)
+	if err != nil {
+		t.Fatalf("DiffResults() error = %v", err)
+	}
+	if diff.Identical || diff.Matched != 2 || diff.OnlyLeft != 1 || diff.OnlyRight != 1 || diff.Changed != 0 {
+		t.Errorf("diff = %+v", diff)
+	}
+	if len(diff.ColumnsOnlyLeft) != 1 || diff.ColumnsOnlyLeft[0] != "legacy" || len(diff.ColumnsOnlyRight) != 0 {
+		t.Errorf("columns only left = %v, only right = %v", diff.ColumnsOnlyLeft, diff.ColumnsOnlyRight)
+	}
+	if len(diff.Samples.OnlyRight) != 1 || diff.Samples.OnlyRight[0]["region"] != "amer" {
+		t.Errorf("samples = %+v", diff.Samples)
+	}
+
+	same, err := DiffResults(right, right, nil, 0)
+	if err != nil || !same.Identical || same.Matched != 3 {
+		t.Errorf("DiffResults(same) = %+v, %v; want identical", same, err)
+	}
+}
+
+func TestDiffResultsMissingKey(t *testing.T) {
+	rows := []map[string]interface{}{{"id": 1}}
+	if _, err := DiffResults(rows, []map[string]interface{}{{"key": 1}}, []string{"id"}, 0); err == nil || !strings.Contains(err.Error(), "right result") {
+		t.Errorf("DiffResults() error = %v, want the missing key column named", err)
+	}
+	// An empty side has no columns to check
+	if _, err := DiffResults(rows, nil, []string{"id"}, 0); err != nil {
+		t.Errorf("DiffResults() with an empty side error = %v", err)
+	}
+}
+
+func TestDiffQueriesValidation(t *testing.T) {
+	c := &Client{config: &config.TrinoConfig{AllowWriteQueries: true, Catalog: "iceberg", Schema: "sales"}}
+	restricted := &Client{config: &config.TrinoConfig{Catalog: "iceberg", Schema: "sales", AllowedCatalogs: []string{"iceberg"}}}
+	tests := []struct {
+		name string
+		opts DiffOptions
+		want string
+	}{
+		{"missing query", DiffOptions{}, "query is required"},
+		{"write", DiffOptions{Query: "SELECT 1", OtherQuery: "DELETE FROM orders"}, "read-only"},
+		{"other side outside its allowlist", DiffOptions{Query: "SELECT * FROM hr.salaries", OtherQuery: "SELECT * FROM hive.hr.salaries", Other: restricted}, "not in allowlist"},
+		{"version without versions", DiffOptions{Query: "SELECT * FROM orders", Table: "orders"}, "version or timestamp"},
+		{"both version and timestamp", DiffOptions{Query: "SELECT * FROM orders", Table: "orders", Version: "1", Timestamp: "2026-01-01"}, "not both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.DiffQueries(context.Background(), tt.opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DiffQueries() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package trino
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+const requestInfoKey contextKey = "request_info"
+
+// RequestInfo identifies the MCP tool call a Trino request is made for. It
+// fills the {session}, {request_id}, and {tool} placeholders of the client
+// identification header templates.
+type RequestInfo struct {
+	SessionID string // MCP session ID, or "" on sessionless transports
+	RequestID string // JSON-RPC request ID of the tool call
+	Tool      string // Name of the tool called
+}
+
+// WithRequestInfo adds the tool call a context's Trino requests are made for
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey, info)
+}
+
+// GetRequestInfo retrieves the tool call a context's Trino requests are made for
+func GetRequestInfo(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey).(RequestInfo)
+	return info, ok
+}
+
+// setClientHeaders sets the configured client identification headers on a
+// request, expanding their templates for the request's caller and tool call.
+// Headers whose template expands to nothing are left unset.
+func setClientHeaders(cfg *config.TrinoConfig, req *http.Request) {
+	if cfg.TrinoClientInfo == "" && cfg.TrinoTraceToken == "" && len(cfg.TrinoHeaders) == 0 {
+		return
+	}
+
+	info, _ := GetRequestInfo(req.Context())
+	expander := strings.NewReplacer(
+		"{user}", headerValue(UserIdentity(req.Context())),
+		"{session}", headerValue(info.SessionID),
+		"{request_id}", headerValue(info.RequestID),
+		"{tool}", headerValue(info.Tool),
+	)
+	set := func(name, template string) {
+		if template == "" {
+			return
+		}
+		if value := strings.TrimSpace(expander.Replace(template)); value != "" {
+			req.Header.Set(name, value)
+		}
+	}
+
+	set("X-Trino-Client-Info", cfg.TrinoClientInfo)
+	set("X-Trino-Trace-Token", cfg.TrinoTraceToken)
+	for name, template := range cfg.TrinoHeaders {
+		set(name, template)
+	}
+}
+
+// headerValue drops the control characters a header value cannot carry, so a
+// caller-controlled value cannot break or inject headers
+func headerValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if (r < ' ' && r != '\t') || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+}
@@ -0,0 +1,142 @@
+package trino
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+// recordingTransport records the headers of the requests it receives
+type recordingTransport struct {
+	headers http.Header
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.headers = req.Header.Clone()
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}, Request: req}, nil
+}
+
+func TestHeaderRoundTripper_ClientHeaders(t *testing.T) {
+	cfg := &config.TrinoConfig{
+		TrinoSource:     "mcp-trino/test",
+		TrinoClientInfo: "mcp-trino {tool} for {user}",
+		TrinoTraceToken: "{session}:{request_id}",
+		TrinoHeaders:    map[string]string{"X-Gateway-Route": "mcp-{user}", "X-Trino-Client-Tags": "mcp,{tool}"},
+	}
+	base := &recordingTransport{}
+	transport := &headerRoundTripper{base: base, config: cfg}
+
+	ctx := oauth.WithUser(context.Background(), &oauth.User{Username: "alice\r\nX-Injected: 1"})
+	ctx = WithRequestInfo(ctx, RequestInfo{SessionID: "session-1", RequestID: "7", Tool: "execute_query"})
+	req := httptest.NewRequest(http.MethodPost, "http://trino:8080/v1/statement", nil).WithContext(ctx)
+	req.Header.Set("X-Trino-Client-Info", "alice")
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	want := map[string]string{
+		"X-Trino-Source":      "mcp-trino/test",
+		"X-Trino-Client-Info": "mcp-trino execute_query for aliceX-Injected: 1",
+		"X-Trino-Trace-Token": "session-1:7",
+		"X-Gateway-Route":     "mcp-aliceX-Injected: 1",
+		"X-Trino-Client-Tags": "mcp,execute_query",
+	}
+	for name, value := range want {
+		if got := base.headers.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+	if got := base.headers.Get("X-Injected"); got != "" {
+		t.Errorf("X-Injected = %q, want control characters dropped from values", got)
+	}
+	if req.Header.Get("X-Trino-Client-Info") != "alice" {
+		t.Error("RoundTrip modified the caller's request headers")
+	}
+}
+
+func TestHeaderRoundTripper_ClientHeadersWithoutToolCall(t *testing.T) {
+	cfg := &config.TrinoConfig{TrinoTraceToken: "{request_id}", TrinoHeaders: map[string]string{"X-Caller": "{user}"}}
+	base := &recordingTransport{}
+	transport := &headerRoundTripper{base: base, config: cfg}
+
+	// Background requests, such as cache warming, have no tool call
+	req := httptest.NewRequest(http.MethodGet, "http://trino:8080/v1/info", nil)
+	req.Header.Set("X-Trino-Client-Info", "driver value")
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if got := base.headers.Get("X-Trino-Trace-Token"); got != "" {
+		t.Errorf("X-Trino-Trace-Token = %q, want unset when its template expands to nothing", got)
+	}
+	if got := base.headers.Get("X-Caller"); got != defaultAttributionUser {
+		t.Errorf("X-Caller = %q, want %q", got, defaultAttributionUser)
+	}
+	if got := base.headers.Get("X-Trino-Client-Info"); got != "driver value" {
+		t.Errorf("X-Trino-Client-Info = %q, want the driver's value kept without a template", got)
+	}
+}
+
+func TestNewClient_OwnHeaders(t *testing.T) {
+	// newSourceClient connects to a coordinator that records the
+	// X-Trino-Source of the statements it receives
+	newSourceClient := func(source string) (*Client, func() string) {
+		var mu sync.Mutex
+		var received string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			received = r.Header.Get("X-Trino-Source")
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      "20261017_000000_00001_abcde",
+				"columns": []map[string]any{{"name": "n", "type": "integer", "typeSignature": map[string]any{"rawType": "integer", "arguments": []any{}}}},
+				"data":    [][]any{{1}},
+				"stats":   map[string]any{"state": "FINISHED"},
+			})
+		}))
+		t.Cleanup(srv.Close)
+
+		u, _ := url.Parse(srv.URL)
+		port, _ := strconv.Atoi(u.Port())
+		c, err := NewClient(&config.TrinoConfig{
+			Host: u.Hostname(), Port: port, Scheme: "http", User: "svc",
+			Catalog: "hive", Schema: "default", QueryTimeout: 10 * time.Second, TrinoSource: source,
+		})
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		t.Cleanup(func() { _ = c.Close() })
+		return c, func() string {
+			mu.Lock()
+			defer mu.Unlock()
+			return received
+		}
+	}
+
+	// The second client must not take over the first one's HTTP client
+	first, firstSource := newSourceClient("mcp-trino/first")
+	second, secondSource := newSourceClient("mcp-trino/second")
+	for _, c := range []*Client{first, second} {
+		// Drop pooled connections, so the query opens one and looks up the
+		// client's HTTP client in the driver's registry
+		c.pool().SetMaxIdleConns(0)
+		if _, err := c.ExecuteQueryWithContext(context.Background(), "SELECT 1"); err != nil {
+			t.Fatalf("ExecuteQueryWithContext() error = %v", err)
+		}
+	}
+	if got := firstSource(); got != "mcp-trino/first" {
+		t.Errorf("first client sent X-Trino-Source %q, want mcp-trino/first", got)
+	}
+	if got := secondSource(); got != "mcp-trino/second" {
+		t.Errorf("second client sent X-Trino-Source %q, want mcp-trino/second", got)
+	}
+}
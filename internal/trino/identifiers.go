@@ -0,0 +1,54 @@
+package trino
+
+import "strings"
+
+// quoteIdentifier quotes a catalog, schema, table, or column name for use in SQL
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral quotes a string value for use as a SQL string literal
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// qualifiedTableName returns the quoted catalog.schema.table name
+func qualifiedTableName(catalog, schema, table string) string {
+	return quoteIdentifier(catalog) + "." + quoteIdentifier(schema) + "." + quoteIdentifier(table)
+}
+
+// tableAccessAllowed applies every configured allowlist to a table. Unlike
+// the per-level filters, it rejects tables whose catalog or schema is outside
+// a configured allowlist even when no table allowlist is set.
+func (c *Client) tableAccessAllowed(catalog, schema, table string) bool {
+	if len(c.config.AllowedCatalogs) > 0 && !c.isCatalogAllowed(catalog) {
+		return false
+	}
+	if len(c.config.AllowedSchemas) > 0 && !c.isSchemaAllowed(catalog, schema) {
+		return false
+	}
+	if len(c.config.AllowedTables) > 0 && !c.isTableAllowed(catalog, schema, table) {
+		return false
+	}
+	return true
+}
+
+// resolveTable splits a schema- or catalog-qualified table name, fills in
+// the configured catalog and schema for any part left unspecified, and
+// replaces a catalog alias with its catalog
+func (c *Client) resolveTable(catalog, schema, table string) (string, string, string) {
+	parts := strings.Split(table, ".")
+	switch len(parts) {
+	case 3:
+		catalog, schema, table = parts[0], parts[1], parts[2]
+	case 2:
+		schema, table = parts[0], parts[1]
+	}
+	if catalog == "" {
+		catalog = c.config.Catalog
+	}
+	if schema == "" {
+		schema = c.config.Schema
+	}
+	return c.aliases.resolve(catalog), schema, table
+}
@@ -0,0 +1,30 @@
+package trino
+
+import (
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/memguard"
+	"github.com/tuannvm/mcp-trino/internal/scheduler"
+)
+
+// Limits are the result memory budget (MCP_MEMORY_BUDGET_MB) and the
+// concurrent query scheduler (MCP_MAX_CONCURRENT_QUERIES). They bound the
+// whole process, so one set is shared by the server's client, its batch
+// cluster, tenants, and profiles.
+type Limits struct {
+	budget *memguard.Budget     // nil when the result memory budget is disabled
+	sched  *scheduler.Scheduler // nil when concurrent queries are unlimited
+}
+
+// NewLimits creates the limits configured in cfg
+func NewLimits(cfg *config.TrinoConfig) *Limits {
+	return &Limits{
+		budget: memguard.NewBudget(int64(cfg.MemoryBudgetMB) << 20),
+		sched:  scheduler.New(cfg.MaxConcurrentQueries),
+	}
+}
+
+// Limits returns the limits the client's queries count against, for sharing
+// with the clients of other clusters
+func (c *Client) Limits() *Limits {
+	return &Limits{budget: c.budget, sched: c.sched}
+}
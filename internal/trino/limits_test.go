@@ -0,0 +1,56 @@
+package trino
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestLimitsSharedAcrossClients(t *testing.T) {
+	coordinator := func() (string, int) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(srv.Close)
+		u, _ := url.Parse(srv.URL)
+		port, _ := strconv.Atoi(u.Port())
+		return u.Hostname(), port
+	}
+	host, port := coordinator()
+	batchHost, batchPort := coordinator()
+	cfg := &config.TrinoConfig{
+		Host: host, Port: port, Scheme: "http", User: "svc", Catalog: "hive", Schema: "default",
+		MemoryBudgetMB: 64, MaxConcurrentQueries: 2, BatchHost: batchHost, BatchPort: batchPort,
+	}
+
+	c, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = c.Close() }()
+	if c.batch == nil || c.batch.budget != c.budget || c.batch.sched != c.sched {
+		t.Error("the batch cluster client must share the server's limits")
+	}
+
+	tenantCfg := *cfg
+	tenantCfg.BatchHost = ""
+	tenant, err := NewClientWithLimits(&tenantCfg, c.Limits())
+	if err != nil {
+		t.Fatalf("NewClientWithLimits() error = %v", err)
+	}
+	defer func() { _ = tenant.Close() }()
+	if tenant.budget != c.budget || tenant.sched != c.sched {
+		t.Error("a client created with shared limits must count against them")
+	}
+
+	own, err := NewClient(&tenantCfg)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer func() { _ = own.Close() }()
+	if own.budget == c.budget || own.sched == c.sched || own.budget == nil || own.sched == nil {
+		t.Error("NewClient must create its own limits")
+	}
+}
@@ -0,0 +1,439 @@
+package trino
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Defaults and caps for BuildQueryContext
+const (
+	DefaultContextMaxTables   = 5
+	DefaultContextSampleRows  = 3
+	DefaultContextTokenBudget = 4000
+
+	maxContextTables     = 20
+	maxContextSampleRows = 20
+	maxContextJoinHints  = 20
+
+	// usageQueryLimit caps the recent coordinator queries scanned for usage
+	usageQueryLimit = 500
+)
+
+// QueryContextOptions controls which tables BuildQueryContext considers and
+// how large its payload may grow.
+type QueryContextOptions struct {
+	Question    string // natural-language question to find tables for
+	Catalog     string // catalog to search (defaults to the configured catalog)
+	Schema      string // schema to search (all schemas in the catalog when empty)
+	MaxTables   int    // tables to include (defaults to DefaultContextMaxTables)
+	SampleRows  int    // sample rows per table (defaults to DefaultContextSampleRows; negative disables)
+	TokenBudget int    // approximate token budget for the payload (defaults to DefaultContextTokenBudget)
+}
+
+// QueryContext is the schema context assembled for a natural-language question.
+type QueryContext struct {
+	Question        string         `json:"question"`
+	Catalog         string         `json:"catalog"`
+	Tables          []TableContext `json:"tables"`
+	JoinHints       []JoinHint     `json:"join_hints,omitempty"`
+	EstimatedTokens int            `json:"estimated_tokens"`
+	Truncated       bool           `json:"truncated"`
+	Notes           []string       `json:"notes,omitempty"`
+}
+
+// TableContext describes one table selected as relevant to the question.
+type TableContext struct {
+	Name       string                   `json:"name"` // catalog.schema.table
+	Comment    string                   `json:"comment,omitempty"`
+	Score      float64                  `json:"relevance"`
+	Columns    []ColumnContext          `json:"columns"`
+	SampleRows []map[string]interface{} `json:"sample_rows,omitempty"`
+
+	table string // unqualified table name, for join hints
+}
+
+// ColumnContext describes a column of a selected table.
+type ColumnContext struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// JoinHint suggests a join condition between two selected tables.
+type JoinHint struct {
+	Left   string `json:"left"`
+	Right  string `json:"right"`
+	Reason string `json:"reason"`
+}
+
+// tableCandidate accumulates the metadata used to score a table
+type tableCandidate struct {
+	schema  string
+	table   string
+	comment string
+	columns []string
+	usage   int
+	score   float64
+}
+
+// BuildQueryContext selects the tables most likely relevant to a question and
+// returns their schemas, sample rows, and join hints, trimmed to fit the token
+// budget. Tables are ranked by matching question terms against table names,
+// comments, and column names, with recent query usage breaking ties.
+func (c *Client) BuildQueryContext(ctx context.Context, opts QueryContextOptions) (*QueryContext, error) {
+	terms := questionTerms(opts.Question)
+	if len(terms) == 0 {
+		return nil, errors.New("question has no searchable terms")
+	}
+	catalog := opts.Catalog
+	if catalog == "" {
+		catalog = c.config.Catalog
+	}
+	catalog = c.aliases.resolve(catalog)
+	if len(c.config.AllowedCatalogs) > 0 && !c.isCatalogAllowed(catalog) {
+		return nil, fmt.Errorf("catalog access denied: %s not in allowlist", catalog)
+	}
+	maxTables := clampOption(opts.MaxTables, DefaultContextMaxTables, maxContextTables)
+	sampleRows := clampOption(opts.SampleRows, DefaultContextSampleRows, maxContextSampleRows)
+	if opts.SampleRows < 0 {
+		sampleRows = 0
+	}
+	budget := opts.TokenBudget
+	if budget <= 0 {
+		budget = DefaultContextTokenBudget
+	}
+
+	candidates, complete, err := c.contextCandidates(ctx, catalog, opts.Schema)
+	if err != nil {
+		return nil, err
+	}
+	qc := &QueryContext{Question: opts.Question, Catalog: catalog, Tables: []TableContext{}}
+	if !complete {
+		qc.Notes = append(qc.Notes, "column listing hit TRINO_MAX_ROWS; pass a schema to search all of its tables")
+	}
+	if usage, err := c.recentTableUsage(ctx); err != nil {
+		qc.Notes = append(qc.Notes, "recent query usage unavailable; ranking by name and comment matches only")
+	} else {
+		for _, candidate := range candidates {
+			candidate.usage = usage[strings.ToLower(candidate.table)]
+		}
+	}
+
+	for _, candidate := range rankCandidates(candidates, terms, maxTables) {
+		tc := TableContext{
+			Name:    catalog + "." + candidate.schema + "." + candidate.table,
+			Comment: candidate.comment,
+			Score:   math.Round(candidate.score*100) / 100,
+			table:   candidate.table,
+		}
+		if tc.Columns, err = c.describeColumns(ctx, catalog, candidate.schema, candidate.table); err != nil {
+			log.Printf("WARNING: Failed to describe %s for query context: %v", tc.Name, err)
+			continue
+		}
+		if sampleRows > 0 {
+			if tc.SampleRows, err = c.sampleRows(ctx, catalog, candidate.schema, candidate.table, sampleRows); err != nil {
+				qc.Notes = append(qc.Notes, fmt.Sprintf("sample rows unavailable for %s", tc.Name))
+			}
+		}
+		qc.Tables = append(qc.Tables, tc)
+	}
+	if len(qc.Tables) == 0 {
+		qc.Notes = append(qc.Notes, "no tables matched the question; try list_tables or a more specific question")
+	}
+
+	qc.JoinHints = joinHints(qc.Tables)
+	fitToBudget(qc, budget)
+	return qc, nil
+}
+
+// contextCandidates lists the visible tables in scope with their columns and
+// comments. complete is false when the column listing was truncated.
+func (c *Client) contextCandidates(ctx context.Context, catalog, schema string) (candidates []*tableCandidate, complete bool, err error) {
+	query := fmt.Sprintf("SELECT table_schema, table_name, column_name FROM %s.information_schema.columns WHERE table_schema <> 'information_schema'",
+		quoteIdentifier(catalog))
+	if schema != "" {
+		query += " AND table_schema = " + quoteLiteral(schema)
+	}
+	query += " ORDER BY table_schema, table_name, ordinal_position"
+	result, err := c.ExecuteQueryWithContext(ctx, query)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list columns: %w", err)
+	}
+
+	byName := make(map[string]*tableCandidate)
+	for _, row := range result.Rows {
+		schemaName, _ := row["table_schema"].(string)
+		tableName, _ := row["table_name"].(string)
+		columnName, _ := row["column_name"].(string)
+		if !c.tableAccessAllowed(catalog, schemaName, tableName) {
+			continue
+		}
+		key := schemaName + "." + tableName
+		candidate, ok := byName[key]
+		if !ok {
+			candidate = &tableCandidate{schema: schemaName, table: tableName}
+			byName[key] = candidate
+			candidates = append(candidates, candidate)
+		}
+		candidate.columns = append(candidate.columns, columnName)
+	}
+
+	// Comments improve ranking but are not available from every connector
+	commentQuery := "SELECT schema_name, table_name, comment FROM system.metadata.table_comments WHERE comment IS NOT NULL AND catalog_name = " + quoteLiteral(catalog)
+	if schema != "" {
+		commentQuery += " AND schema_name = " + quoteLiteral(schema)
+	}
+	if comments, err := c.ExecuteQueryWithContext(ctx, commentQuery); err == nil {
+		for _, row := range comments.Rows {
+			schemaName, _ := row["schema_name"].(string)
+			tableName, _ := row["table_name"].(string)
+			if candidate, ok := byName[schemaName+"."+tableName]; ok {
+				candidate.comment, _ = row["comment"].(string)
+			}
+		}
+	}
+	return candidates, !result.Truncated, nil
+}
+
+// recentTableUsage counts how many recent coordinator queries reference each
+// table name. Users without access to other users' queries see only their own.
+func (c *Client) recentTableUsage(ctx context.Context) (map[string]int, error) {
+	result, err := c.ExecuteQueryWithContext(ctx, fmt.Sprintf(
+		"SELECT query FROM system.runtime.queries WHERE state = 'FINISHED' ORDER BY created DESC LIMIT %d", usageQueryLimit))
+	if err != nil {
+		return nil, err
+	}
+	usage := make(map[string]int)
+	for _, row := range result.Rows {
+		text, _ := row["query"].(string)
+		seen := make(map[string]bool)
+		for _, word := range sqlWordPattern.FindAllString(strings.ToLower(text), -1) {
+			if !seen[word] {
+				seen[word] = true
+				usage[word]++
+			}
+		}
+	}
+	return usage, nil
+}
+
+// describeColumns returns a table's columns as reported by DESCRIBE
+func (c *Client) describeColumns(ctx context.Context, catalog, schema, table string) ([]ColumnContext, error) {
+	result, err := c.ExecuteQueryWithContext(ctx, "DESCRIBE "+qualifiedTableName(catalog, schema, table))
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]ColumnContext, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		column := ColumnContext{}
+		column.Name, _ = row["Column"].(string)
+		column.Type, _ = row["Type"].(string)
+		column.Comment, _ = row["Comment"].(string)
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// sampleRows returns up to limit rows from a table
+func (c *Client) sampleRows(ctx context.Context, catalog, schema, table string, limit int) ([]map[string]interface{}, error) {
+	result, err := c.ExecuteQueryWithContext(ctx, fmt.Sprintf("SELECT * FROM %s LIMIT %d", qualifiedTableName(catalog, schema, table), limit))
+	if err != nil {
+		return nil, err
+	}
+	return result.Rows, nil
+}
+
+var sqlWordPattern = regexp.MustCompile(`[a-z0-9_]+`)
+
+// questionStopwords are common words that say nothing about which table to use
+var questionStopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "what": true,
+	"which": true, "who": true, "how": true, "many": true, "much": true, "are": true,
+	"was": true, "were": true, "has": true, "have": true, "per": true, "each": true,
+	"all": true, "any": true, "show": true, "list": true, "give": true, "find": true,
+	"get": true, "top": true, "last": true, "this": true, "that": true, "than": true,
+	"into": true, "over": true, "between": true, "by": true, "in": true, "of": true,
+	"on": true, "to": true, "is": true, "me": true, "did": true, "does": true,
+	"most": true, "number": true, "count": true, "total": true, "average": true,
+}
+
+// questionTerms extracts normalized search terms from a question
+func questionTerms(question string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, word := range sqlWordPattern.FindAllString(strings.ToLower(question), -1) {
+		for _, part := range strings.Split(word, "_") {
+			term := stemTerm(part)
+			if len(term) < 3 || questionStopwords[part] || questionStopwords[term] || seen[term] {
+				continue
+			}
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// stemTerm strips common plural and tense suffixes so "orders" matches "order"
+func stemTerm(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "es") && strings.HasSuffix(word[:len(word)-2], "s"):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	case len(word) > 4 && strings.HasSuffix(word, "ed"):
+		return word[:len(word)-2]
+	}
+	return word
+}
+
+// matchedTerms counts the terms that match any word of text
+func matchedTerms(terms []string, text string) int {
+	words := make(map[string]bool)
+	for _, word := range sqlWordPattern.FindAllString(strings.ToLower(text), -1) {
+		for _, part := range strings.Split(word, "_") {
+			words[stemTerm(part)] = true
+		}
+	}
+	matched := 0
+	for _, term := range terms {
+		if words[term] {
+			matched++
+		}
+	}
+	return matched
+}
+
+// rankCandidates scores candidates against the question terms and returns the
+// best matches. Name matches weigh most, then comments, then column names;
+// usage only breaks ties between tables that already match.
+func rankCandidates(candidates []*tableCandidate, terms []string, limit int) []*tableCandidate {
+	var matched []*tableCandidate
+	for _, candidate := range candidates {
+		score := 3*float64(matchedTerms(terms, candidate.table)) +
+			2*float64(matchedTerms(terms, candidate.comment)) +
+			float64(matchedTerms(terms, strings.Join(candidate.columns, " ")))
+		if score == 0 {
+			continue
+		}
+		candidate.score = score + math.Log2(1+float64(candidate.usage))/4
+		matched = append(matched, candidate)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].score != matched[j].score {
+			return matched[i].score > matched[j].score
+		}
+		return matched[i].schema+"."+matched[i].table < matched[j].schema+"."+matched[j].table
+	})
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched
+}
+
+// joinHints suggests join conditions between tables: identically named key
+// columns, and <table>_id columns referencing another table's id column.
+func joinHints(tables []TableContext) []JoinHint {
+	var hints []JoinHint
+	for i := range tables {
+		for j := range tables {
+			if i == j {
+				continue
+			}
+			left, right := tables[i], tables[j]
+			for _, lc := range left.Columns {
+				name := strings.ToLower(lc.Name)
+				for _, rc := range right.Columns {
+					rname := strings.ToLower(rc.Name)
+					switch {
+					case i < j && name == rname && isKeyColumn(name):
+						hints = append(hints, JoinHint{
+							Left: left.Name + "." + lc.Name, Right: right.Name + "." + rc.Name,
+							Reason: "shared key column",
+						})
+					case rname == "id" && name == stemTerm(strings.ToLower(right.table))+"_id":
+						hints = append(hints, JoinHint{
+							Left: left.Name + "." + lc.Name, Right: right.Name + "." + rc.Name,
+							Reason: "foreign key naming",
+						})
+					}
+					if len(hints) >= maxContextJoinHints {
+						return hints
+					}
+				}
+			}
+		}
+	}
+	return hints
+}
+
+func isKeyColumn(name string) bool {
+	return strings.HasSuffix(name, "_id") || strings.HasSuffix(name, "_key") || strings.HasSuffix(name, "_sk")
+}
+
+// fitToBudget trims a query context until its estimated size fits the token
+// budget: sample rows go first, then column comments, then the least relevant
+// tables, and finally trailing columns of the remaining table.
+func fitToBudget(qc *QueryContext, budget int) {
+	fits := func() bool {
+		qc.EstimatedTokens = estimateTokens(qc)
+		return qc.EstimatedTokens <= budget
+	}
+	if fits() {
+		return
+	}
+	// The note counts against the budget too, so add it before trimming
+	qc.Truncated = true
+	qc.Notes = append(qc.Notes, fmt.Sprintf("context trimmed to fit the %d-token budget", budget))
+
+	for i := len(qc.Tables) - 1; i >= 0 && !fits(); i-- {
+		qc.Tables[i].SampleRows = nil
+	}
+	for i := len(qc.Tables) - 1; i >= 0 && !fits(); i-- {
+		for j := range qc.Tables[i].Columns {
+			qc.Tables[i].Columns[j].Comment = ""
+		}
+	}
+	for len(qc.Tables) > 1 && !fits() {
+		dropped := qc.Tables[len(qc.Tables)-1].Name
+		qc.Tables = qc.Tables[:len(qc.Tables)-1]
+		kept := qc.JoinHints[:0]
+		for _, hint := range qc.JoinHints {
+			if !strings.HasPrefix(hint.Left, dropped+".") && !strings.HasPrefix(hint.Right, dropped+".") {
+				kept = append(kept, hint)
+			}
+		}
+		qc.JoinHints = kept
+	}
+	for len(qc.Tables) == 1 && len(qc.Tables[0].Columns) > 1 && !fits() {
+		qc.Tables[0].Columns = qc.Tables[0].Columns[:len(qc.Tables[0].Columns)/2]
+	}
+	qc.EstimatedTokens = estimateTokens(qc)
+}
+
+// estimateTokens approximates the token count of a JSON payload at four bytes
+// per token, which is close for English text and identifiers.
+func estimateTokens(v interface{}) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return (len(data) + 3) / 4
+}
+
+// clampOption applies a default to a non-positive option and caps it
+func clampOption(value, fallback, maxValue int) int {
+	if value <= 0 {
+		return fallback
+	}
+	return min(value, maxValue)
+}
@@ -0,0 +1,108 @@
+package trino
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQuestionTerms(t *testing.T) {
+	got := questionTerms("How many orders did each customer place in the last_month?")
+	want := []string{"order", "customer", "place", "month"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("questionTerms() = %v, want %v", got, want)
+	}
+	if terms := questionTerms("how many are there?"); len(terms) != 1 || terms[0] != "there" {
+		t.Errorf("questionTerms() = %v, want only non-stopwords", terms)
+	}
+}
+
+func TestRankCandidates(t *testing.T) {
+	candidates := []*tableCandidate{
+		{schema: "sales", table: "customers", columns: []string{"id", "name", "region"}},
+		{schema: "sales", table: "orders", columns: []string{"id", "customer_id", "amount"}},
+		{schema: "ops", table: "deploys", columns: []string{"id", "service"}, usage: 1000},
+		{schema: "sales", table: "order_archive", columns: []string{"id", "customer_id"}, comment: "Old orders"},
+	}
+	ranked := rankCandidates(candidates, questionTerms("total order amount per customer"), 3)
+
+	var names []string
+	for _, candidate := range ranked {
+		names = append(names, candidate.table)
+	}
+	// orders matches by name and two columns; order_archive by name, comment,
+	// and one column; customers by name only; deploys not at all despite usage
+	want := []string{"order_archive", "orders", "customers"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("ranked tables = %v, want %v", names, want)
+	}
+}
+
+func TestRankCandidatesUsageBreaksTies(t *testing.T) {
+	candidates := []*tableCandidate{
+		{schema: "a", table: "events"},
+		{schema: "b", table: "events", usage: 12},
+	}
+	ranked := rankCandidates(candidates, []string{"event"}, 2)
+	if len(ranked) != 2 || ranked[0].schema != "b" {
+		t.Errorf("expected the frequently used table first, got %s.%s", ranked[0].schema, ranked[0].table)
+	}
+}
+
+func TestJoinHints(t *testing.T) {
+	tables := []TableContext{
+		{Name: "hive.sales.orders", table: "orders", Columns: []ColumnContext{{Name: "id"}, {Name: "customer_id"}, {Name: "store_key"}}},
+		{Name: "hive.sales.customers", table: "customers", Columns: []ColumnContext{{Name: "id"}, {Name: "name"}}},
+		{Name: "hive.sales.stores", table: "stores", Columns: []ColumnContext{{Name: "store_key"}}},
+	}
+	hints := joinHints(tables)
+
+	want := map[string]string{
+		"hive.sales.orders.customer_id=hive.sales.customers.id":   "foreign key naming",
+		"hive.sales.orders.store_key=hive.sales.stores.store_key": "shared key column",
+	}
+	if len(hints) != len(want) {
+		t.Fatalf("joinHints() = %+v, want %d hints", hints, len(want))
+	}
+	for _, hint := range hints {
+		if reason, ok := want[hint.Left+"="+hint.Right]; !ok || reason != hint.Reason {
+			t.Errorf("unexpected hint %+v", hint)
+		}
+	}
+}
+
+func TestFitToBudget(t *testing.T) {
+	wide := make([]ColumnContext, 40)
+	for i := range wide {
+		wide[i] = ColumnContext{Name: "column_with_a_long_name", Type: "varchar", Comment: strings.Repeat("comment ", 10)}
+	}
+	qc := &QueryContext{
+		Question: "q",
+		Tables: []TableContext{
+			{Name: "c.s.first", Columns: wide, SampleRows: []map[string]interface{}{{"v": strings.Repeat("x", 500)}}},
+			{Name: "c.s.second", Columns: wide},
+		},
+		JoinHints: []JoinHint{{Left: "c.s.first.id", Right: "c.s.second.id"}},
+	}
+
+	fitToBudget(qc, 600)
+
+	if !qc.Truncated {
+		t.Error("expected context to be marked truncated")
+	}
+	if qc.EstimatedTokens > 600 {
+		t.Errorf("EstimatedTokens = %d, want <= 600", qc.EstimatedTokens)
+	}
+	if len(qc.Tables) != 1 || qc.Tables[0].Name != "c.s.first" {
+		t.Fatalf("expected only the most relevant table to remain, got %d tables", len(qc.Tables))
+	}
+	if qc.Tables[0].SampleRows != nil || len(qc.JoinHints) != 0 {
+		t.Error("expected samples and hints for dropped tables to be removed")
+	}
+
+	small := &QueryContext{Question: "q", Tables: []TableContext{{Name: "c.s.t", Columns: wide[:1]}}}
+	fitToBudget(small, 4000)
+	if small.Truncated || small.EstimatedTokens == 0 {
+		t.Errorf("small context: Truncated = %v, EstimatedTokens = %d", small.Truncated, small.EstimatedTokens)
+	}
+}
@@ -0,0 +1,263 @@
+package trino
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const queryLogKey contextKey = "query_log"
+
+// Polling for the final stats of a query the coordinator is still finishing
+const (
+	queryStatsAttempts = 5
+	queryStatsDelay    = 200 * time.Millisecond
+)
+
+// QueryStats is the resource usage the coordinator reports for a query.
+type QueryStats struct {
+	QueryID         string
+	State           string
+	CPUTime         time.Duration
+	Elapsed         time.Duration
+	ScannedBytes    int64 // physical input read from storage
+	ProcessedBytes  int64 // input processed after connector filtering
+	PeakMemoryBytes int64 // peak user memory reservation
+}
+
+// Done reports whether the query has reached a final state, after which its
+// stats no longer change.
+func (s *QueryStats) Done() bool {
+	return s.State == "FINISHED" || s.State == "FAILED"
+}
+
+// queryInfo is the part of the coordinator's /v1/query/{id} response that
+// carries resource usage
+type queryInfo struct {
+	QueryID    string `json:"queryId"`
+	State      string `json:"state"`
+	QueryStats struct {
+		ElapsedTime               trinoDuration `json:"elapsedTime"`
+		TotalCPUTime              trinoDuration `json:"totalCpuTime"`
+		PhysicalInputDataSize     trinoDataSize `json:"physicalInputDataSize"`
+		ProcessedInputDataSize    trinoDataSize `json:"processedInputDataSize"`
+		PeakUserMemoryReservation trinoDataSize `json:"peakUserMemoryReservation"`
+	} `json:"queryStats"`
+}
+
+// QueryStats fetches a query's resource usage from the coordinator.
+func (c *Client) QueryStats(ctx context.Context, queryID string) (*QueryStats, error) {
+	req, err := c.newQueryRequest(ctx, http.MethodGet, queryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query info request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch query info: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch query info for %s: %s", queryID, resp.Status)
+	}
+
+	var info queryInfo
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<20)).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to decode query info for %s: %w", queryID, err)
+	}
+	return &QueryStats{
+		QueryID:         queryID,
+		State:           info.State,
+		CPUTime:         time.Duration(info.QueryStats.TotalCPUTime),
+		Elapsed:         time.Duration(info.QueryStats.ElapsedTime),
+		ScannedBytes:    int64(info.QueryStats.PhysicalInputDataSize),
+		ProcessedBytes:  int64(info.QueryStats.ProcessedInputDataSize),
+		PeakMemoryBytes: int64(info.QueryStats.PeakUserMemoryReservation),
+	}, nil
+}
+
+// QueryLog collects the Trino queries run on behalf of one caller, so their
+// resource usage can be looked up once they finish.
+type QueryLog struct {
+	parent *QueryLog // enclosing log, which also records the queries
+
+	mu      sync.Mutex
+	queries []loggedQuery
+}
+
+// loggedQuery is a query, the client whose coordinator ran it, and the user
+// it ran as when impersonating, who may view it
+type loggedQuery struct {
+	client *Client
+	id     string
+	user   string
+}
+
+// WithQueryLog returns a context whose queries are recorded in the log.
+// Queries are still recorded in any log the context already had.
+func WithQueryLog(ctx context.Context) (context.Context, *QueryLog) {
+	parent, _ := ctx.Value(queryLogKey).(*QueryLog)
+	queryLog := &QueryLog{parent: parent}
+	return context.WithValue(ctx, queryLogKey, queryLog), queryLog
+}
+
+// logQuery records a query in the context's query logs, if any
+func logQuery(ctx context.Context, c *Client, queryID string) {
+	queryLog, _ := ctx.Value(queryLogKey).(*QueryLog)
+	if queryLog == nil {
+		return
+	}
+	user, _ := GetImpersonatedUser(ctx)
+	for ; queryLog != nil; queryLog = queryLog.parent {
+		queryLog.mu.Lock()
+		queryLog.queries = append(queryLog.queries, loggedQuery{client: c, id: queryID, user: user})
+		queryLog.mu.Unlock()
+	}
+}
+
+// Len returns the number of queries recorded.
+func (l *QueryLog) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.queries)
+}
+
+// IDs returns the IDs of the recorded queries, in the order they ran.
+func (l *QueryLog) IDs() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ids := make([]string, len(l.queries))
+	for i, query := range l.queries {
+		ids[i] = query.id
+	}
+	return ids
+}
+
+// Stats fetches the final stats of every recorded query, polling briefly
+// while the coordinator finishes a query. Queries whose stats could not be
+// fetched are left out and reported in the error.
+func (l *QueryLog) Stats(ctx context.Context) ([]QueryStats, error) {
+	l.mu.Lock()
+	queries := append([]loggedQuery(nil), l.queries...)
+	l.mu.Unlock()
+
+	var stats []QueryStats
+	var errs []error
+	for _, query := range queries {
+		queryCtx := ctx
+		if query.user != "" {
+			queryCtx = WithImpersonatedUser(ctx, query.user)
+		}
+		s, err := query.client.finalQueryStats(queryCtx, query.id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		stats = append(stats, *s)
+	}
+	return stats, errors.Join(errs...)
+}
+
+// finalQueryStats fetches a query's stats, retrying until it has finished or
+// the attempts run out; the last stats seen are returned either way
+func (c *Client) finalQueryStats(ctx context.Context, queryID string) (*QueryStats, error) {
+	var stats *QueryStats
+	var err error
+	for attempt := 1; attempt <= queryStatsAttempts; attempt++ {
+		stats, err = c.QueryStats(ctx, queryID)
+		if err == nil && stats.Done() || attempt == queryStatsAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(attempt) * queryStatsDelay):
+		}
+	}
+	return stats, err
+}
+
+// trinoDuration decodes an airlift Duration, such as "1.50s" or "12.00ms"
+type trinoDuration time.Duration
+
+func (d *trinoDuration) UnmarshalJSON(data []byte) error {
+	value, err := unquoteStat(data)
+	if err != nil || value == "" {
+		return err
+	}
+	parsed, err := parseTrinoDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = trinoDuration(parsed)
+	return nil
+}
+
+// parseTrinoDuration parses a duration with a Go unit or "d" for days
+func parseTrinoDuration(value string) (time.Duration, error) {
+	if number, ok := strings.CutSuffix(value, "d"); ok {
+		days, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return parsed, nil
+}
+
+// trinoDataSize decodes an airlift DataSize, such as "1024B" or "1.50MB",
+// into bytes
+type trinoDataSize int64
+
+// dataSizeUnits are the airlift DataSize units, longest suffix first
+var dataSizeUnits = []struct {
+	suffix string
+	bytes  float64
+}{
+	{"kB", 1 << 10},
+	{"MB", 1 << 20},
+	{"GB", 1 << 30},
+	{"TB", 1 << 40},
+	{"PB", 1 << 50},
+	{"B", 1},
+}
+
+func (s *trinoDataSize) UnmarshalJSON(data []byte) error {
+	value, err := unquoteStat(data)
+	if err != nil || value == "" {
+		return err
+	}
+	for _, unit := range dataSizeUnits {
+		if number, ok := strings.CutSuffix(value, unit.suffix); ok {
+			size, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return fmt.Errorf("invalid data size %q", value)
+			}
+			*s = trinoDataSize(size * unit.bytes)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid data size %q", value)
+}
+
+// unquoteStat returns a stat's string value; null decodes as ""
+func unquoteStat(data []byte) (string, error) {
+	if string(data) == "null" {
+		return "", nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", fmt.Errorf("invalid query stat %s", data)
+	}
+	return strings.TrimSpace(value), nil
+}
@@ -0,0 +1,138 @@
+package trino
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestQueryStats(t *testing.T) {
+	var gotPath, gotUser string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotUser = r.URL.Path, r.Header.Get("X-Trino-User")
+		_, _ = w.Write([]byte(`{
+			"queryId": "20240101_000000_00001_abcde",
+			"state": "FINISHED",
+			"queryStats": {
+				"elapsedTime": "2.50s",
+				"totalCpuTime": "1.50m",
+				"physicalInputDataSize": "1.50GB",
+				"processedInputDataSize": "512.00MB",
+				"peakUserMemoryReservation": "64kB"
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	cfg := &config.TrinoConfig{User: "svc", EnableImpersonation: true}
+	c := &Client{config: cfg, httpClient: srv.Client(), baseURL: srv.URL}
+
+	stats, err := c.QueryStats(WithImpersonatedUser(context.Background(), "alice"), "20240101_000000_00001_abcde")
+	if err != nil {
+		t.Fatalf("QueryStats() error = %v", err)
+	}
+	if gotPath != "/v1/query/20240101_000000_00001_abcde" || gotUser != "alice" {
+		t.Errorf("request = %s as %q", gotPath, gotUser)
+	}
+	want := QueryStats{
+		QueryID:         "20240101_000000_00001_abcde",
+		State:           "FINISHED",
+		CPUTime:         90 * time.Second,
+		Elapsed:         2500 * time.Millisecond,
+		ScannedBytes:    3 << 29,
+		ProcessedBytes:  512 << 20,
+		PeakMemoryBytes: 64 << 10,
+	}
+	if *stats != want {
+		t.Errorf("QueryStats() = %+v, want %+v", *stats, want)
+	}
+	if !stats.Done() {
+		t.Error("Done() = false for a finished query")
+	}
+}
+
+func TestQueryStatsNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	c := &Client{config: &config.TrinoConfig{User: "svc"}, httpClient: srv.Client(), baseURL: srv.URL}
+	if _, err := c.QueryStats(context.Background(), "missing"); err == nil {
+		t.Error("QueryStats() succeeded for an unknown query")
+	}
+}
+
+func TestQueryLogStats(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		state := "FINISHING"
+		if calls > 1 {
+			state = "FINISHED"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"state":      state,
+			"queryStats": map[string]string{"totalCpuTime": "10.00ms", "physicalInputDataSize": "100B"},
+		})
+	}))
+	defer srv.Close()
+	c := &Client{config: &config.TrinoConfig{User: "svc"}, httpClient: srv.Client(), baseURL: srv.URL}
+
+	ctx, queryLog := WithQueryLog(context.Background())
+	logQuery(ctx, c, "20240101_000000_00002_abcde")
+	logQuery(context.Background(), c, "not-logged") // no log in this context
+	if queryLog.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", queryLog.Len())
+	}
+	nestedCtx, nested := WithQueryLog(ctx)
+	logQuery(nestedCtx, c, "20240101_000000_00003_abcde")
+	if nested.Len() != 1 || queryLog.Len() != 2 {
+		t.Fatalf("Len() = %d nested, %d outer; want queries also recorded in the enclosing log", nested.Len(), queryLog.Len())
+	}
+	if ids := queryLog.IDs(); len(ids) != 2 || ids[1] != "20240101_000000_00003_abcde" {
+		t.Errorf("IDs() = %q, want both queries in order", ids)
+	}
+	queryLog.queries = queryLog.queries[:1]
+
+	stats, err := queryLog.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if len(stats) != 1 || stats[0].State != "FINISHED" || stats[0].CPUTime != 10*time.Millisecond || stats[0].ScannedBytes != 100 {
+		t.Errorf("Stats() = %+v, want the finished query's stats", stats)
+	}
+	if calls != 2 {
+		t.Errorf("coordinator called %d times, want a retry while the query finished", calls)
+	}
+}
+
+func TestParseStats(t *testing.T) {
+	durations := map[string]time.Duration{
+		"15.00ns": 15 * time.Nanosecond,
+		"1.00us":  time.Microsecond,
+		"0.50h":   30 * time.Minute,
+		"2.00d":   48 * time.Hour,
+	}
+	for value, want := range durations {
+		if got, err := parseTrinoDuration(value); err != nil || got != want {
+			t.Errorf("parseTrinoDuration(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	if _, err := parseTrinoDuration("soon"); err == nil {
+		t.Error("parseTrinoDuration accepted an invalid duration")
+	}
+
+	var size trinoDataSize
+	if err := json.Unmarshal([]byte(`"2.00TB"`), &size); err != nil || size != 2<<40 {
+		t.Errorf("data size 2.00TB = %d, %v", size, err)
+	}
+	if err := json.Unmarshal([]byte(`"12 parsecs"`), &size); err == nil {
+		t.Error("invalid data size accepted")
+	}
+}
@@ -0,0 +1,129 @@
+package trino
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// Bounds on the backoff between coordinator health checks during recovery
+const (
+	recoveryInitialBackoff = 250 * time.Millisecond
+	recoveryMaxBackoff     = 5 * time.Second
+)
+
+// queryWithRecovery runs a statement, recovering from a coordinator restart.
+// When the statement fails at the connection level, the pooled connections
+// are reset, the coordinator is polled until it is serving again, and a
+// read-only statement is retried once. Writes are not retried since the
+// coordinator may have accepted them before the connection dropped.
+func (c *Client) queryWithRecovery(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	started := time.Now()
+	rows, err := c.pool().QueryContext(ctx, query, args...)
+	if err == nil || c.config.RecoveryTimeout <= 0 || ctx.Err() != nil || !isConnectionError(err) {
+		return rows, err
+	}
+
+	log.Printf("WARNING: Trino connection failed, resetting connection pool: %v", sanitizeConnectionError(err, c.currentPassword()))
+	if recoverErr := c.recoverConnection(ctx, started); recoverErr != nil {
+		log.Printf("ERROR: Trino did not recover: %v", recoverErr)
+		return nil, err
+	}
+	if !isReadOnlyQuery(query) {
+		return nil, err
+	}
+	log.Printf("INFO: Retrying query after Trino connection recovery")
+	return c.pool().QueryContext(ctx, query, args...)
+}
+
+// recoverConnection drops pooled connections and waits for the coordinator to
+// serve requests again. Concurrent failures share one recovery: calls that
+// started before the last successful recovery reuse its result.
+func (c *Client) recoverConnection(ctx context.Context, failedCallStart time.Time) error {
+	c.recoveryMu.Lock()
+	defer c.recoveryMu.Unlock()
+	if c.recoveredAt.After(failedCallStart) {
+		return nil
+	}
+
+	// Idle HTTP connections to the old coordinator process are dead; idle
+	// driver connections carry session state it no longer knows about
+	c.httpClient.CloseIdleConnections()
+	c.pool().SetMaxIdleConns(0)
+	c.pool().SetMaxIdleConns(maxIdleConns)
+
+	recoverCtx, cancel := context.WithTimeout(ctx, c.config.RecoveryTimeout)
+	defer cancel()
+	backoff := recoveryInitialBackoff
+	for {
+		err := c.pingCoordinator(recoverCtx)
+		if err == nil {
+			c.recoveredAt = time.Now()
+			log.Printf("INFO: Trino connection recovered")
+			return nil
+		}
+		select {
+		case <-recoverCtx.Done():
+			return fmt.Errorf("coordinator unavailable after %s: %w", c.config.RecoveryTimeout, err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, recoveryMaxBackoff)
+	}
+}
+
+// pingCoordinator checks that the coordinator is up and has finished starting.
+// database/sql's Ping never reaches the server with the Trino driver.
+func (c *Client) pingCoordinator(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/info", nil)
+	if err != nil {
+		return err
+	}
+	if password := c.currentPassword(); password != "" {
+		req.SetBasicAuth(c.config.User, password)
+	}
+	req.Header.Set("X-Trino-User", c.config.User)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("coordinator returned %s", resp.Status)
+	}
+	var info struct {
+		Starting bool `json:"starting"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&info); err != nil {
+		return fmt.Errorf("failed to decode coordinator info: %w", err)
+	}
+	if info.Starting {
+		return errors.New("coordinator is still starting")
+	}
+	return nil
+}
+
+// isConnectionError reports whether err means the connection to Trino was
+// lost or refused, as opposed to the query itself failing.
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, net.ErrClosed) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
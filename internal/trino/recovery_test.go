@@ -0,0 +1,147 @@
+package trino
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"query failure", errors.New("line 1:8: Column 'x' cannot be resolved"), false},
+		{"cancelled", fmt.Errorf("query failed: %w", context.Canceled), false},
+		{"timeout", context.DeadlineExceeded, false},
+		{"bad conn", driver.ErrBadConn, true},
+		{"eof", &url.Error{Op: "Post", URL: "http://trino/v1/statement", Err: io.EOF}, true},
+		{"refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{"reset", fmt.Errorf("wrapped: %w", syscall.ECONNRESET), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isConnectionError(tt.err); got != tt.want {
+				t.Errorf("isConnectionError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// restartingTrino simulates a coordinator that drops the first statement's
+// connection, as a restarted coordinator does, then serves normally.
+func restartingTrino(t *testing.T, statements *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/info":
+			_, _ = io.WriteString(w, `{"starting":false}`)
+		case "/v1/statement":
+			if statements.Add(1) == 1 {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err != nil {
+					t.Errorf("hijack failed: %v", err)
+					return
+				}
+				_ = conn.Close()
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"id":"20240101_000000_00001_abcde","stats":{"state":"FINISHED"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func newRecoveryTestClient(t *testing.T, srv *httptest.Server) *Client {
+	t.Helper()
+	db, err := sql.Open("trino", srv.URL+"?catalog=memory&schema=default")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return &Client{
+		db:         db,
+		config:     &config.TrinoConfig{User: "svc", RecoveryTimeout: 2 * time.Second},
+		httpClient: srv.Client(),
+		baseURL:    srv.URL,
+	}
+}
+
+func TestQueryWithRecoveryRetriesReadOnlyQuery(t *testing.T) {
+	var statements atomic.Int32
+	srv := restartingTrino(t, &statements)
+	defer srv.Close()
+	c := newRecoveryTestClient(t, srv)
+
+	rows, err := c.queryWithRecovery(context.Background(), "SELECT 1")
+	if err != nil {
+		t.Fatalf("queryWithRecovery() error = %v, want transparent retry", err)
+	}
+	_ = rows.Close()
+	if statements.Load() != 2 {
+		t.Errorf("statements sent = %d, want 2", statements.Load())
+	}
+	if c.recoveredAt.IsZero() {
+		t.Error("expected recovery to be recorded")
+	}
+}
+
+func TestQueryWithRecoveryDoesNotRetryWrites(t *testing.T) {
+	var statements atomic.Int32
+	srv := restartingTrino(t, &statements)
+	defer srv.Close()
+	c := newRecoveryTestClient(t, srv)
+
+	if _, err := c.queryWithRecovery(context.Background(), "INSERT INTO t VALUES (1)"); err == nil {
+		t.Fatal("expected the write to fail without a retry")
+	}
+	if statements.Load() != 1 {
+		t.Errorf("statements sent = %d, want 1", statements.Load())
+	}
+}
+
+func TestRecoverConnectionWaitsForStartup(t *testing.T) {
+	var infoCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if infoCalls.Add(1) < 3 {
+			_, _ = io.WriteString(w, `{"starting":true}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"starting":false}`)
+	}))
+	defer srv.Close()
+	c := newRecoveryTestClient(t, srv)
+
+	started := time.Now()
+	if err := c.recoverConnection(context.Background(), started); err != nil {
+		t.Fatalf("recoverConnection() error = %v", err)
+	}
+	if infoCalls.Load() != 3 {
+		t.Errorf("info calls = %d, want 3", infoCalls.Load())
+	}
+
+	// A call that failed before this recovery reuses it without polling again
+	if err := c.recoverConnection(context.Background(), started); err != nil {
+		t.Fatalf("recoverConnection() error = %v", err)
+	}
+	if infoCalls.Load() != 3 {
+		t.Errorf("info calls = %d after shared recovery, want 3", infoCalls.Load())
+	}
+}
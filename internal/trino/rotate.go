@@ -0,0 +1,60 @@
+package trino
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// UpdatePassword switches the client to a new Trino password. A new
+// connection pool is opened with the new credentials and takes all new
+// queries; the old pool closes in the background once its in-flight queries
+// finish, so rotation never interrupts a running query. The batch cluster,
+// when configured, shares the password and rotates with it.
+func (c *Client) UpdatePassword(password string) error {
+	if c.batch != nil {
+		if err := c.batch.UpdatePassword(password); err != nil {
+			return fmt.Errorf("batch cluster: %w", err)
+		}
+	}
+	if password == c.currentPassword() {
+		return nil
+	}
+	db, err := openPool(buildDSN(c.config, password, c.clientKey))
+	if err != nil {
+		return fmt.Errorf("failed to open Trino connection pool: %w", sanitizeConnectionError(err, password))
+	}
+
+	c.credentialsMu.Lock()
+	old := c.db
+	c.db = db
+	c.password = &password
+	c.credentialsMu.Unlock()
+
+	if old != nil {
+		go func() {
+			if err := old.Close(); err != nil {
+				log.Printf("WARNING: Failed to close previous Trino connection pool: %v", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// pool returns the current driver connection pool
+func (c *Client) pool() *sql.DB {
+	c.credentialsMu.RLock()
+	defer c.credentialsMu.RUnlock()
+	return c.db
+}
+
+// currentPassword returns the Trino password, including any rotation since
+// the client was created
+func (c *Client) currentPassword() string {
+	c.credentialsMu.RLock()
+	defer c.credentialsMu.RUnlock()
+	if c.password != nil {
+		return *c.password
+	}
+	return c.config.Password
+}
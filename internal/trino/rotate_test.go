@@ -0,0 +1,68 @@
+package trino
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestUpdatePassword(t *testing.T) {
+	var lastPassword atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, password, _ := r.BasicAuth()
+		lastPassword.Store(password)
+		_, _ = io.WriteString(w, `{"starting":false}`)
+	}))
+	defer srv.Close()
+
+	old, err := sql.Open("trino", srv.URL+"?catalog=memory&schema=default")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	c := &Client{
+		db:         old,
+		config:     &config.TrinoConfig{User: "svc", Password: "old-pass", Scheme: "http", Host: "localhost", Port: 8080},
+		httpClient: srv.Client(),
+		baseURL:    srv.URL,
+	}
+	defer func() { _ = c.Close() }()
+
+	if err := c.Ping(context.Background()); err != nil || lastPassword.Load() != "old-pass" {
+		t.Fatalf("Ping() error = %v, password = %v, want old-pass", err, lastPassword.Load())
+	}
+
+	if err := c.UpdatePassword("new-pass"); err != nil {
+		t.Fatalf("UpdatePassword() error = %v", err)
+	}
+	if c.pool() == old {
+		t.Error("expected a new connection pool after rotation")
+	}
+	if err := c.Ping(context.Background()); err != nil || lastPassword.Load() != "new-pass" {
+		t.Errorf("Ping() error = %v, password = %v, want new-pass", err, lastPassword.Load())
+	}
+	if c.config.Password != "old-pass" {
+		t.Error("rotation must not modify the shared configuration")
+	}
+
+	// The previous pool is closed in the background
+	deadline := time.Now().Add(2 * time.Second)
+	for err := old.Ping(); err == nil || err.Error() != "sql: database is closed"; err = old.Ping() {
+		if time.Now().After(deadline) {
+			t.Fatal("previous connection pool was not closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// An unchanged password keeps the current pool
+	current := c.pool()
+	if err := c.UpdatePassword("new-pass"); err != nil || c.pool() != current {
+		t.Errorf("UpdatePassword() with the same password replaced the pool (err = %v)", err)
+	}
+}
@@ -0,0 +1,63 @@
+package trino
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestNormalizeSampleMethod(t *testing.T) {
+	for input, want := range map[string]string{"": SampleBernoulli, "bernoulli": SampleBernoulli, " system ": SampleSystem} {
+		got, err := normalizeSampleMethod(input)
+		if err != nil || got != want {
+			t.Errorf("normalizeSampleMethod(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := normalizeSampleMethod("RANDOM"); err == nil {
+		t.Error("expected an error for an unsupported method")
+	}
+}
+
+func TestResolveTable(t *testing.T) {
+	c := &Client{config: &config.TrinoConfig{Catalog: "hive", Schema: "default"}}
+	tests := []struct {
+		catalog, schema, table string
+		want                   string
+	}{
+		{"", "", "events", "hive.default.events"},
+		{"", "", "web.events", "hive.web.events"},
+		{"iceberg", "", "web.events", "iceberg.web.events"},
+		{"", "", "iceberg.web.events", "iceberg.web.events"},
+		{"iceberg", "raw", "events", "iceberg.raw.events"},
+	}
+	for _, tt := range tests {
+		catalog, schema, table := c.resolveTable(tt.catalog, tt.schema, tt.table)
+		if got := catalog + "." + schema + "." + table; got != tt.want {
+			t.Errorf("resolveTable(%q, %q, %q) = %s, want %s", tt.catalog, tt.schema, tt.table, got, tt.want)
+		}
+	}
+}
+
+func TestSampleTableValidation(t *testing.T) {
+	c := &Client{config: &config.TrinoConfig{Catalog: "hive", Schema: "default", AllowedCatalogs: []string{"hive"}}}
+	tests := []struct {
+		name    string
+		opts    SampleOptions
+		wantErr string
+	}{
+		{"bad method", SampleOptions{Table: "events", Method: "RESERVOIR"}, "unsupported sampling method"},
+		{"percentage too high", SampleOptions{Table: "events", Percentage: 150}, "between 0 and 100"},
+		{"negative percentage", SampleOptions{Table: "events", Percentage: -1}, "between 0 and 100"},
+		{"catalog not allowed", SampleOptions{Table: "postgres.public.users"}, "table access denied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.SampleTable(context.Background(), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SampleTable() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package trino
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SnapshotQuery runs a read-only query whose whole result must be returned,
+// for callers that compare results across runs, such as query
+// subscriptions. A result truncated by TRINO_MAX_ROWS would compare unequal
+// for reasons unrelated to the data, so truncation is an error.
+func (c *Client) SnapshotQuery(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	// Re-running a write would apply it on every run
+	if !isReadOnlyQuery(query) {
+		return nil, fmt.Errorf("only read-only queries (SELECT, SHOW, DESCRIBE, EXPLAIN) can be re-run")
+	}
+	qr, err := c.ExecuteQueryWithContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if qr.Truncated {
+		return nil, fmt.Errorf("the result exceeds %d rows; aggregate it or add a LIMIT so the whole result can be compared", qr.MaxRows)
+	}
+	return qr.Rows, nil
+}
@@ -0,0 +1,18 @@
+package trino
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestSnapshotQueryRejectsWrites(t *testing.T) {
+	c := &Client{config: &config.TrinoConfig{AllowWriteQueries: true}}
+	if _, err := c.SnapshotQuery(context.Background(), "DELETE FROM t"); err == nil {
+		t.Error("SnapshotQuery() accepted a write")
+	}
+	if _, err := c.SnapshotQuery(context.Background(), ";"); err == nil {
+		t.Error("SnapshotQuery() accepted an empty query")
+	}
+}
@@ -0,0 +1,202 @@
+package trino
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Summary defaults and caps
+const (
+	DefaultSummaryGroups   = 20
+	DefaultSummaryMeasures = 3
+
+	maxSummaryGroups   = 100
+	maxSummaryMeasures = 10
+)
+
+// Aggregates summarize_table can compute for each measure
+const (
+	AggregateSum = "sum"
+	AggregateAvg = "avg"
+	AggregateMin = "min"
+	AggregateMax = "max"
+)
+
+// DefaultSummaryAggregates are computed when none are requested
+var DefaultSummaryAggregates = []string{AggregateSum, AggregateAvg}
+
+// SummaryOptions configures a grouped summary of a table.
+type SummaryOptions struct {
+	Catalog    string
+	Schema     string
+	Table      string   // table name, optionally schema- or catalog-qualified
+	Dimension  string   // column to group by; chosen from the table's columns if empty
+	Measures   []string // numeric columns to aggregate; chosen from the table's columns if empty
+	Aggregates []string // functions applied to each measure (defaults to DefaultSummaryAggregates)
+	MaxGroups  int      // groups to return, largest first (defaults to DefaultSummaryGroups)
+}
+
+// TableSummary is a table grouped by one dimension with aggregated measures.
+type TableSummary struct {
+	Table      string                   `json:"table"`
+	Dimension  string                   `json:"dimension"`
+	Measures   []string                 `json:"measures"`
+	Aggregates []string                 `json:"aggregates"`
+	Query      string                   `json:"query"`
+	Groups     []map[string]interface{} `json:"groups"`
+	Truncated  bool                     `json:"truncated"` // more groups exist than were returned
+}
+
+// SummarizeTable groups a table by a dimension and aggregates its measures in
+// a single query, ordered by group size. A missing dimension defaults to the
+// first text, boolean, or date column and missing measures to the first
+// numeric columns that are not IDs.
+func (c *Client) SummarizeTable(ctx context.Context, opts SummaryOptions) (*TableSummary, error) {
+	aggregates, err := normalizeAggregates(opts.Aggregates)
+	if err != nil {
+		return nil, err
+	}
+	catalog, schema, table := c.resolveTable(opts.Catalog, opts.Schema, opts.Table)
+	if !c.tableAccessAllowed(catalog, schema, table) {
+		return nil, fmt.Errorf("table access denied: %s.%s.%s not in allowlist", catalog, schema, table)
+	}
+
+	columns, err := c.describeColumns(ctx, catalog, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe table: %w", err)
+	}
+	dimension, measures, err := chooseSummaryColumns(columns, opts.Dimension, opts.Measures)
+	if err != nil {
+		return nil, err
+	}
+
+	maxGroups := clampOption(opts.MaxGroups, DefaultSummaryGroups, maxSummaryGroups)
+	query := buildSummaryQuery(qualifiedTableName(catalog, schema, table), dimension, measures, aggregates, maxGroups+1)
+	result, err := c.ExecuteQueryWithContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &TableSummary{
+		Table:      catalog + "." + schema + "." + table,
+		Dimension:  dimension,
+		Measures:   measures,
+		Aggregates: aggregates,
+		Query:      query,
+		Groups:     result.Rows,
+	}
+	if len(summary.Groups) > maxGroups {
+		summary.Groups = summary.Groups[:maxGroups]
+		summary.Truncated = true
+	}
+	return summary, nil
+}
+
+// normalizeAggregates validates requested aggregates, applying the default
+func normalizeAggregates(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return DefaultSummaryAggregates, nil
+	}
+	aggregates := make([]string, 0, len(requested))
+	seen := make(map[string]bool)
+	for _, aggregate := range requested {
+		aggregate = strings.ToLower(strings.TrimSpace(aggregate))
+		switch aggregate {
+		case AggregateSum, AggregateAvg, AggregateMin, AggregateMax:
+		default:
+			return nil, fmt.Errorf("unsupported aggregate %q: use sum, avg, min, or max", aggregate)
+		}
+		if !seen[aggregate] {
+			seen[aggregate] = true
+			aggregates = append(aggregates, aggregate)
+		}
+	}
+	return aggregates, nil
+}
+
+// chooseSummaryColumns validates the requested dimension and measures
+// against the table's columns and fills in defaults for those left out
+func chooseSummaryColumns(columns []ColumnContext, dimension string, measures []string) (string, []string, error) {
+	byName := make(map[string]ColumnContext, len(columns))
+	for _, column := range columns {
+		byName[strings.ToLower(column.Name)] = column
+	}
+
+	if dimension != "" {
+		column, ok := byName[strings.ToLower(dimension)]
+		if !ok {
+			return "", nil, fmt.Errorf("dimension column %q not found", dimension)
+		}
+		dimension = column.Name
+	} else {
+		for _, column := range columns {
+			if isDimensionType(column.Type) {
+				dimension = column.Name
+				break
+			}
+		}
+		if dimension == "" {
+			return "", nil, fmt.Errorf("no text, boolean, or date column to group by; specify a dimension")
+		}
+	}
+
+	var chosen []string
+	if len(measures) > 0 {
+		if len(measures) > maxSummaryMeasures {
+			return "", nil, fmt.Errorf("at most %d measures are allowed, got %d", maxSummaryMeasures, len(measures))
+		}
+		for _, measure := range measures {
+			column, ok := byName[strings.ToLower(measure)]
+			if !ok {
+				return "", nil, fmt.Errorf("measure column %q not found", measure)
+			}
+			if !isNumericType(column.Type) {
+				return "", nil, fmt.Errorf("measure column %q has non-numeric type %s", measure, column.Type)
+			}
+			chosen = append(chosen, column.Name)
+		}
+	} else {
+		for _, column := range columns {
+			name := strings.ToLower(column.Name)
+			if column.Name == dimension || !isNumericType(column.Type) || name == "id" || strings.HasSuffix(name, "_id") {
+				continue
+			}
+			chosen = append(chosen, column.Name)
+			if len(chosen) == DefaultSummaryMeasures {
+				break
+			}
+		}
+	}
+	return dimension, chosen, nil
+}
+
+// buildSummaryQuery groups by the dimension and computes a row count and
+// each aggregate of each measure, largest groups first
+func buildSummaryQuery(table, dimension string, measures, aggregates []string, limit int) string {
+	selects := []string{quoteIdentifier(dimension), `count(*) AS "row_count"`}
+	for _, measure := range measures {
+		for _, aggregate := range aggregates {
+			selects = append(selects, fmt.Sprintf("%s(%s) AS %s", aggregate, quoteIdentifier(measure), quoteIdentifier(aggregate+"_"+measure)))
+		}
+	}
+	return fmt.Sprintf("SELECT %s FROM %s GROUP BY 1 ORDER BY 2 DESC LIMIT %d", strings.Join(selects, ", "), table, limit)
+}
+
+// isDimensionType reports whether a column type makes a reasonable grouping key
+func isDimensionType(columnType string) bool {
+	columnType = strings.ToLower(columnType)
+	return strings.HasPrefix(columnType, "varchar") || strings.HasPrefix(columnType, "char") ||
+		columnType == "boolean" || columnType == "date"
+}
+
+// isNumericType reports whether a column type can be summed and averaged
+func isNumericType(columnType string) bool {
+	columnType = strings.ToLower(columnType)
+	switch {
+	case columnType == "tinyint", columnType == "smallint", columnType == "integer", columnType == "bigint",
+		columnType == "real", columnType == "double", strings.HasPrefix(columnType, "decimal"):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package trino
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var summaryColumns = []ColumnContext{
+	{Name: "order_id", Type: "bigint"},
+	{Name: "customer_id", Type: "bigint"},
+	{Name: "status", Type: "varchar(16)"},
+	{Name: "region", Type: "varchar"},
+	{Name: "amount", Type: "decimal(12,2)"},
+	{Name: "quantity", Type: "integer"},
+	{Name: "created_at", Type: "timestamp(3)"},
+}
+
+func TestChooseSummaryColumnsDefaults(t *testing.T) {
+	dimension, measures, err := chooseSummaryColumns(summaryColumns, "", nil)
+	if err != nil {
+		t.Fatalf("chooseSummaryColumns() error = %v", err)
+	}
+	if dimension != "status" {
+		t.Errorf("dimension = %q, want the first text column", dimension)
+	}
+	if want := []string{"amount", "quantity"}; !reflect.DeepEqual(measures, want) {
+		t.Errorf("measures = %v, want %v (ID columns skipped)", measures, want)
+	}
+}
+
+func TestChooseSummaryColumnsExplicit(t *testing.T) {
+	dimension, measures, err := chooseSummaryColumns(summaryColumns, "REGION", []string{"Quantity"})
+	if err != nil {
+		t.Fatalf("chooseSummaryColumns() error = %v", err)
+	}
+	if dimension != "region" || !reflect.DeepEqual(measures, []string{"quantity"}) {
+		t.Errorf("got %q %v, want columns matched case-insensitively", dimension, measures)
+	}
+
+	for _, tt := range []struct {
+		dimension string
+		measures  []string
+		wantErr   string
+	}{
+		{"missing", nil, "dimension column"},
+		{"", []string{"status"}, "non-numeric"},
+		{"", []string{"amount; DROP TABLE x"}, "not found"},
+	} {
+		if _, _, err := chooseSummaryColumns(summaryColumns, tt.dimension, tt.measures); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("chooseSummaryColumns(%q, %v) error = %v, want %q", tt.dimension, tt.measures, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNormalizeAggregates(t *testing.T) {
+	got, err := normalizeAggregates([]string{"MAX", "sum", "max"})
+	if err != nil || !reflect.DeepEqual(got, []string{"max", "sum"}) {
+		t.Errorf("normalizeAggregates() = %v, %v", got, err)
+	}
+	if got, _ := normalizeAggregates(nil); !reflect.DeepEqual(got, DefaultSummaryAggregates) {
+		t.Errorf("normalizeAggregates(nil) = %v, want defaults", got)
+	}
+	if _, err := normalizeAggregates([]string{"stddev"}); err == nil {
+		t.Error("expected an error for an unsupported aggregate")
+	}
+}
+
+func TestBuildSummaryQuery(t *testing.T) {
+	got := buildSummaryQuery(`"hive"."sales"."orders"`, "status", []string{"amount"}, []string{"sum", "avg"}, 21)
+	want := `SELECT "status", count(*) AS "row_count", sum("amount") AS "sum_amount", avg("amount") AS "avg_amount" ` +
+		`FROM "hive"."sales"."orders" GROUP BY 1 ORDER BY 2 DESC LIMIT 21`
+	if got != want {
+		t.Errorf("buildSummaryQuery() =\n%s\nwant\n%s", got, want)
+	}
+}
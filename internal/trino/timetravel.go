@@ -0,0 +1,231 @@
+package trino
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Time travel defaults and caps
+const (
+	DefaultSnapshotLimit = 20
+	DefaultVersionRows   = 100
+
+	maxSnapshotLimit = 500
+)
+
+// icebergConnector is the connector name of catalogs that support time travel
+const icebergConnector = "iceberg"
+
+// Snapshot is one committed version of an Iceberg table.
+type Snapshot struct {
+	SnapshotID  string            `json:"snapshot_id"` // a string because IDs exceed JSON's exact integer range
+	ParentID    string            `json:"parent_id,omitempty"`
+	CommittedAt time.Time         `json:"committed_at"`
+	Operation   string            `json:"operation"`
+	Summary     map[string]string `json:"summary,omitempty"`
+}
+
+// VersionOptions selects the table version a query reads. Exactly one of
+// Version and Timestamp must be set.
+type VersionOptions struct {
+	Catalog   string
+	Schema    string
+	Table     string // table name, optionally schema- or catalog-qualified
+	Version   string // snapshot ID, branch, or tag
+	Timestamp string // e.g. 2024-05-01 12:00:00 UTC
+	Query     string // SELECT reading Table; defaults to SELECT * with a row cap
+}
+
+// VersionResult is the result of a point-in-time query.
+type VersionResult struct {
+	Table     string                   `json:"table"`
+	AsOf      string                   `json:"as_of"`
+	Query     string                   `json:"query"`
+	RowCount  int                      `json:"row_count"`
+	Truncated bool                     `json:"truncated,omitempty"`
+	Rows      []map[string]interface{} `json:"rows"`
+}
+
+// ListSnapshots returns an Iceberg table's snapshots, newest first.
+func (c *Client) ListSnapshots(ctx context.Context, catalog, schema, table string, limit int) ([]Snapshot, error) {
+	catalog, schema, table = c.resolveTable(catalog, schema, table)
+	if err := c.checkTimeTravelTable(ctx, catalog, schema, table); err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT committed_at, CAST(snapshot_id AS varchar) AS snapshot_id, CAST(parent_id AS varchar) AS parent_id, operation, summary FROM %s ORDER BY committed_at DESC LIMIT %d",
+		qualifiedTableName(catalog, schema, table+"$snapshots"), clampOption(limit, DefaultSnapshotLimit, maxSnapshotLimit))
+	result, err := c.ExecuteQueryWithContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]Snapshot, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		snapshot := Snapshot{}
+		snapshot.SnapshotID, _ = row["snapshot_id"].(string)
+		snapshot.ParentID, _ = row["parent_id"].(string)
+		snapshot.CommittedAt, _ = row["committed_at"].(time.Time)
+		snapshot.Operation, _ = row["operation"].(string)
+		if summary, ok := row["summary"].(map[string]interface{}); ok {
+			snapshot.Summary = make(map[string]string, len(summary))
+			for key, value := range summary {
+				snapshot.Summary[key] = fmt.Sprint(value)
+			}
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// QueryAtVersion runs a read-only query against a table as of a snapshot,
+// branch, tag, or point in time. Every reference to the table in the query
+// gets a FOR VERSION AS OF or FOR TIMESTAMP AS OF clause; other tables are
+// read at their current version.
+func (c *Client) QueryAtVersion(ctx context.Context, opts VersionOptions) (*VersionResult, error) {
+	clause, asOf, err := versionClause(opts.Version, opts.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	catalog, schema, table := c.resolveTable(opts.Catalog, opts.Schema, opts.Table)
+	if err := c.checkTimeTravelTable(ctx, catalog, schema, table); err != nil {
+		return nil, err
+	}
+
+	var query string
+	if strings.TrimSpace(opts.Query) == "" {
+		query = fmt.Sprintf("SELECT * FROM %s %s LIMIT %d", qualifiedTableName(catalog, schema, table), clause, DefaultVersionRows)
+	} else if query, err = c.applyTableVersion(opts.Query, catalog, schema, table, clause); err != nil {
+		return nil, err
+	}
+
+	result, err := c.ExecuteQueryWithContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionResult{
+		Table:     catalog + "." + schema + "." + table,
+		AsOf:      asOf,
+		Query:     query,
+		RowCount:  len(result.Rows),
+		Truncated: result.Truncated,
+		Rows:      result.Rows,
+	}, nil
+}
+
+// applyTableVersion adds clause to every reference to catalog.schema.table
+// in query, resolving unqualified references against the defaults
+func (c *Client) applyTableVersion(query, catalog, schema, table, clause string) (string, error) {
+	target := strings.ToLower(catalog + "." + schema + "." + table)
+	return applyVersionClause(query, clause, func(reference string) bool {
+		refCatalog, refSchema, refTable := c.resolveTable("", "", reference)
+		return strings.ToLower(refCatalog+"."+refSchema+"."+refTable) == target
+	})
+}
+
+// checkTimeTravelTable rejects tables outside the allowlists and catalogs
+// whose connector has no snapshots to travel to
+func (c *Client) checkTimeTravelTable(ctx context.Context, catalog, schema, table string) error {
+	if !c.tableAccessAllowed(catalog, schema, table) {
+		return fmt.Errorf("table access denied: %s.%s.%s not in allowlist", catalog, schema, table)
+	}
+	connector, err := c.connectorName(ctx, catalog)
+	if err != nil {
+		return err
+	}
+	if connector != icebergConnector {
+		return fmt.Errorf("time travel requires an Iceberg table; catalog %s uses the %s connector", catalog, connector)
+	}
+	return nil
+}
+
+// connectorName returns the name of the connector a catalog uses
+func (c *Client) connectorName(ctx context.Context, catalog string) (string, error) {
+	result, err := c.ExecuteQueryWithContext(ctx,
+		"SELECT connector_name FROM system.metadata.catalogs WHERE catalog_name = "+quoteLiteral(catalog))
+	if err != nil {
+		return "", fmt.Errorf("failed to look up connector for catalog %s: %w", catalog, err)
+	}
+	if len(result.Rows) == 0 {
+		return "", fmt.Errorf("catalog %s not found", catalog)
+	}
+	connector, _ := result.Rows[0]["connector_name"].(string)
+	return connector, nil
+}
+
+var (
+	snapshotIDPattern = regexp.MustCompile(`^\d+$`)
+	// versionTimestampPattern accepts a date, optional time, and optional zone
+	versionTimestampPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(?: \d{2}:\d{2}(?::\d{2}(?:\.\d{1,12})?)?)?(?: [A-Za-z][A-Za-z0-9_/+\-]*| ?[+-]\d{2}:\d{2})?$`)
+	hasZonePattern          = regexp.MustCompile(`(?: [A-Za-z][A-Za-z0-9_/+\-]*| ?[+-]\d{2}:\d{2})$`)
+	existingVersionClause   = regexp.MustCompile(`(?i)\bfor\s+(?:version|timestamp|system_time)\s+as\s+of\b`)
+)
+
+// versionClause builds the FOR ... AS OF clause for a version or timestamp,
+// and a description of the point in time it selects. Numeric versions are
+// snapshot IDs; other versions name a branch or tag. Timestamps without a
+// zone are taken as UTC.
+func versionClause(version, timestamp string) (clause, asOf string, err error) {
+	version, timestamp = strings.TrimSpace(version), strings.TrimSpace(timestamp)
+	switch {
+	case version != "" && timestamp != "":
+		return "", "", errors.New("specify either version or timestamp, not both")
+	case version != "":
+		if snapshotIDPattern.MatchString(version) {
+			return "FOR VERSION AS OF " + version, "snapshot " + version, nil
+		}
+		return "FOR VERSION AS OF " + quoteLiteral(version), "ref " + version, nil
+	case timestamp != "":
+		if !versionTimestampPattern.MatchString(timestamp) {
+			return "", "", fmt.Errorf("invalid timestamp %q: use YYYY-MM-DD [HH:MM[:SS[.fff]]] [zone]", timestamp)
+		}
+		if !hasZonePattern.MatchString(timestamp) {
+			timestamp += " UTC"
+		}
+		return "FOR TIMESTAMP AS OF TIMESTAMP " + quoteLiteral(timestamp), timestamp, nil
+	default:
+		return "", "", errors.New("either version or timestamp is required")
+	}
+}
+
+// applyVersionClause inserts clause after every FROM or JOIN reference in
+// query for which matches reports the target table
+func applyVersionClause(query, clause string, matches func(reference string) bool) (string, error) {
+	masked := maskLiteralsAndComments(query)
+	if existingVersionClause.MatchString(masked) {
+		return "", errors.New("query already contains a time travel clause; pass the version or timestamp as a parameter instead")
+	}
+
+	var insertAt []int
+	for _, loc := range tableReference.FindAllStringSubmatchIndex(masked, -1) {
+		if matches(normalizeTableReference(masked[loc[2]:loc[3]])) {
+			insertAt = append(insertAt, loc[3])
+		}
+	}
+	if len(insertAt) == 0 {
+		return "", errors.New("query does not read the table; reference it in a FROM or JOIN clause")
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, at := range insertAt {
+		b.WriteString(query[last:at])
+		b.WriteString(" " + clause)
+		last = at
+	}
+	b.WriteString(query[last:])
+	return b.String(), nil
+}
+
+// maskLiteralsAndComments blanks out string literals and comments while
+// keeping every other byte at its original offset
+func maskLiteralsAndComments(query string) string {
+	blank := func(s string) string { return strings.Repeat(" ", len(s)) }
+	query = singleQuoteLiteral.ReplaceAllStringFunc(query, blank)
+	query = singleLineComment.ReplaceAllStringFunc(query, blank)
+	return multiLineComment.ReplaceAllStringFunc(query, blank)
+}
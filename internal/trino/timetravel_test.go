@@ -0,0 +1,67 @@
+package trino
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVersionClause(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		timestamp  string
+		wantClause string
+		wantAsOf   string
+		wantErr    string
+	}{
+		{"snapshot id", "8954597067493422955", "", "FOR VERSION AS OF 8954597067493422955", "snapshot 8954597067493422955", ""},
+		{"branch", "audit-branch", "", "FOR VERSION AS OF 'audit-branch'", "ref audit-branch", ""},
+		{"timestamp with zone", "", "2024-05-01 12:00:00 America/New_York", "FOR TIMESTAMP AS OF TIMESTAMP '2024-05-01 12:00:00 America/New_York'", "2024-05-01 12:00:00 America/New_York", ""},
+		{"timestamp with offset", "", "2024-05-01 12:00:00.123 +02:00", "FOR TIMESTAMP AS OF TIMESTAMP '2024-05-01 12:00:00.123 +02:00'", "2024-05-01 12:00:00.123 +02:00", ""},
+		{"timestamp defaults to UTC", "", "2024-05-01 12:00", "FOR TIMESTAMP AS OF TIMESTAMP '2024-05-01 12:00 UTC'", "2024-05-01 12:00 UTC", ""},
+		{"invalid timestamp", "", "yesterday'; DROP TABLE x", "", "", "invalid timestamp"},
+		{"both", "1", "2024-05-01", "", "", "not both"},
+		{"neither", "", "", "", "", "is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clause, asOf, err := versionClause(tt.version, tt.timestamp)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("versionClause() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || clause != tt.wantClause || asOf != tt.wantAsOf {
+				t.Errorf("versionClause() = %q, %q, %v; want %q, %q", clause, asOf, err, tt.wantClause, tt.wantAsOf)
+			}
+		})
+	}
+}
+
+func TestApplyVersionClause(t *testing.T) {
+	isOrders := func(reference string) bool {
+		return reference == "orders" || reference == "iceberg.sales.orders"
+	}
+	const clause = "FOR VERSION AS OF 42"
+
+	got, err := applyVersionClause(
+		`SELECT o.id, c.name FROM iceberg.sales.orders o JOIN customers c ON o.cid = c.id WHERE o.note <> 'from orders' `+
+			`UNION ALL SELECT id, NULL FROM "ORDERS"`,
+		clause, isOrders)
+	if err != nil {
+		t.Fatalf("applyVersionClause() error = %v", err)
+	}
+	want := `SELECT o.id, c.name FROM iceberg.sales.orders FOR VERSION AS OF 42 o JOIN customers c ON o.cid = c.id WHERE o.note <> 'from orders' ` +
+		`UNION ALL SELECT id, NULL FROM "ORDERS" FOR VERSION AS OF 42`
+	if got != want {
+		t.Errorf("applyVersionClause() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := applyVersionClause("SELECT * FROM customers", clause, isOrders); err == nil {
+		t.Error("expected an error when the query does not read the table")
+	}
+	if _, err := applyVersionClause("SELECT * FROM orders FOR TIMESTAMP AS OF now()", clause, isOrders); err == nil {
+		t.Error("expected an error when the query already time travels")
+	}
+}
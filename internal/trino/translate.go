@@ -0,0 +1,769 @@
+package trino
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxTranslationEdits bounds the rewrites of one idiom in a query
+const maxTranslationEdits = 500
+
+// maxTranslationExamples caps the examples kept per idiom
+const maxTranslationExamples = 3
+
+// Translation is a query rewritten from another SQL dialect into Trino SQL.
+type Translation struct {
+	Query    string              `json:"query"`   // the query with every rewrite applied
+	Changed  bool                `json:"changed"` // Query differs from the input
+	Rewrites []TranslationChange `json:"rewrites,omitempty"`
+	Hints    []TranslationChange `json:"hints,omitempty"` // idioms left for the caller to fix
+}
+
+// TranslationChange describes one idiom found in a query.
+type TranslationChange struct {
+	Idiom    string   `json:"idiom"`
+	Count    int      `json:"count"`
+	Message  string   `json:"message"`
+	Examples []string `json:"examples"` // original text, with its rewrite for rewrites
+}
+
+// Summary names the idioms found, for pointing a failed query at
+// translate_sql, or returns "" when none were found.
+func (t *Translation) Summary() string {
+	var idioms []string
+	for _, change := range append(append([]TranslationChange(nil), t.Rewrites...), t.Hints...) {
+		if !slices.Contains(idioms, change.Idiom) {
+			idioms = append(idioms, change.Idiom)
+		}
+	}
+	if len(idioms) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("The query uses syntax from other SQL dialects (%s); translate_sql can rewrite it for Trino", strings.Join(idioms, ", "))
+}
+
+// TranslateSQL finds idioms of other SQL dialects that Trino rejects or reads
+// differently, such as Postgres :: casts, MySQL backticks and LIMIT offsets,
+// and MySQL, Spark, and SQL Server date arithmetic. Idioms with a clear Trino
+// equivalent are rewritten; the others are reported as hints. String
+// literals and comments are left alone. The query is not run or validated.
+func TranslateSQL(query string) *Translation {
+	t := &Translation{}
+	for _, rule := range dialectRules {
+		pos := 0
+		for range maxTranslationEdits {
+			edit, ok := nextDialectEdit(rule, query, lexDialect(query), pos)
+			if !ok {
+				break
+			}
+			original := query[edit.start:edit.end]
+			if edit.hint != "" {
+				t.Hints = recordChange(t.Hints, rule.idiom, edit.hint, original)
+				pos = edit.resume
+				continue
+			}
+			t.Rewrites = recordChange(t.Rewrites, rule.idiom, rule.message, original+"  =>  "+edit.replacement)
+			query = query[:edit.start] + edit.replacement + query[edit.end:]
+			pos = edit.start
+		}
+	}
+	t.Query = query
+	t.Changed = len(t.Rewrites) > 0
+	return t
+}
+
+// recordChange counts an occurrence of an idiom, keeping a few examples
+func recordChange(changes []TranslationChange, idiom, message, example string) []TranslationChange {
+	for i := range changes {
+		if changes[i].Idiom == idiom && changes[i].Message == message {
+			changes[i].Count++
+			if len(changes[i].Examples) < maxTranslationExamples {
+				changes[i].Examples = append(changes[i].Examples, example)
+			}
+			return changes
+		}
+	}
+	return append(changes, TranslationChange{Idiom: idiom, Count: 1, Message: message, Examples: []string{example}})
+}
+
+// dialectRule rewrites or flags one idiom. match is called for each token at
+// or after the search position and reports whether the idiom starts there.
+type dialectRule struct {
+	idiom   string
+	message string // explains a rewrite; hints carry their own message
+	match   func(query string, tokens []dialectToken, i int) (dialectEdit, bool)
+}
+
+// dialectEdit replaces query[start:end], or flags it when hint is set.
+// Searching resumes at resume after a hint.
+type dialectEdit struct {
+	start, end  int
+	replacement string
+	hint        string
+	resume      int
+}
+
+// nextDialectEdit finds the first occurrence of a rule's idiom at or after pos
+func nextDialectEdit(rule dialectRule, query string, tokens []dialectToken, pos int) (dialectEdit, bool) {
+	for i, tok := range tokens {
+		if tok.start < pos {
+			continue
+		}
+		if edit, ok := rule.match(query, tokens, i); ok {
+			if edit.resume == 0 {
+				edit.resume = tok.end
+			}
+			return edit, true
+		}
+	}
+	return dialectEdit{}, false
+}
+
+// dialectRules run in order; backticks are rewritten first so later rules
+// see ordinary quoted identifiers
+var dialectRules = []dialectRule{
+	{
+		idiom:   "mysql_backticks",
+		message: "MySQL quotes identifiers with backticks; Trino uses double quotes.",
+		match:   matchBacktick,
+	},
+	{
+		idiom:   "date_arithmetic",
+		message: "Trino adds to dates with date_add(unit, value, date) and subtracts them with date_diff(unit, start, end). The date must be a date or timestamp; write string dates as DATE '2026-10-17'.",
+		match:   matchDateFunction,
+	},
+	{
+		idiom:   "interval_literal",
+		message: "Trino interval literals quote their value and name a single unit: INTERVAL '7' DAY. Weeks and quarters are written as days and months.",
+		match:   matchInterval,
+	},
+	{
+		idiom:   "postgres_cast",
+		message: "Trino has no :: cast operator; use CAST(value AS type) with Trino type names.",
+		match:   matchPostgresCast,
+	},
+	{
+		idiom:   "limit_offset",
+		message: "Trino skips rows with OFFSET before LIMIT: OFFSET 20 LIMIT 10.",
+		match:   matchLimitOffset,
+	},
+	{
+		idiom:   "function_name",
+		message: "Renamed functions Trino spells differently (IFNULL and NVL are coalesce, STR_TO_DATE is date_parse and returns a timestamp, UCASE and LCASE are upper and lower).",
+		match:   matchRenamedFunction,
+	},
+	{
+		idiom: "unsupported_syntax",
+		match: matchUnsupported,
+	},
+}
+
+// renamedFunctions maps functions of other dialects to their Trino names
+var renamedFunctions = map[string]string{
+	"ifnull":      "coalesce",
+	"nvl":         "coalesce",
+	"str_to_date": "date_parse",
+	"ucase":       "upper",
+	"lcase":       "lower",
+}
+
+// unsupportedFunctions maps functions of other dialects that need more than
+// a rename to a hint
+var unsupportedFunctions = map[string]string{
+	"group_concat": "Trino has no GROUP_CONCAT; use listagg(value, ',') WITHIN GROUP (ORDER BY value), or array_join(array_agg(value), ',').",
+	"string_agg":   "Trino has no STRING_AGG; use listagg(value, ',') WITHIN GROUP (ORDER BY value).",
+	"to_char":      "Trino has no TO_CHAR; format timestamps with format_datetime(ts, 'yyyy-MM-dd') (Joda patterns) or date_format(ts, '%Y-%m-%d') (MySQL patterns), and numbers with format('%.2f', x).",
+	"getdate":      "Trino has no GETDATE; use current_timestamp, or now().",
+	"isnull":       "ISNULL(value, fallback) is coalesce(value, fallback) in Trino; to test for NULL, use value IS NULL.",
+	"len":          "Trino has no LEN; use length(value), which counts trailing spaces.",
+}
+
+// dateUnits maps date part names and abbreviations of other dialects to
+// Trino's date_add and date_diff units
+var dateUnits = map[string]string{
+	"millisecond": "millisecond", "ms": "millisecond",
+	"second": "second", "ss": "second", "s": "second",
+	"minute": "minute", "mi": "minute", "n": "minute",
+	"hour": "hour", "hh": "hour",
+	"day": "day", "dd": "day", "d": "day", "dayofyear": "day", "dy": "day", "y": "day",
+	"week": "week", "wk": "week", "ww": "week",
+	"month": "month", "mm": "month", "m": "month",
+	"quarter": "quarter", "qq": "quarter", "q": "quarter",
+	"year": "year", "yy": "year", "yyyy": "year",
+}
+
+// postgresTypes maps Postgres type names, and Trino's own, to Trino's
+var postgresTypes = map[string]string{
+	"int": "integer", "int4": "integer", "integer": "integer", "serial": "integer",
+	"int8": "bigint", "bigint": "bigint", "bigserial": "bigint",
+	"int2": "smallint", "smallint": "smallint", "tinyint": "tinyint",
+	"float8": "double", "double precision": "double", "double": "double", "float": "double",
+	"float4": "real", "real": "real",
+	"numeric": "decimal", "decimal": "decimal",
+	"text": "varchar", "varchar": "varchar", "character varying": "varchar", "citext": "varchar", "string": "varchar",
+	"char": "char", "character": "char", "bpchar": "char",
+	"bool": "boolean", "boolean": "boolean",
+	"date": "date", "uuid": "uuid", "inet": "ipaddress", "ipaddress": "ipaddress",
+	"timestamp": "timestamp", "timestamp without time zone": "timestamp",
+	"timestamptz": "timestamp with time zone", "timestamp with time zone": "timestamp with time zone",
+	"time": "time", "time without time zone": "time",
+	"timetz": "time with time zone", "time with time zone": "time with time zone",
+	"json": "json", "jsonb": "json",
+	"bytea": "varbinary", "varbinary": "varbinary",
+	"array": "array", "map": "map", "row": "row",
+}
+
+// intervalStringPattern matches a Postgres interval string such as '7 days'
+var intervalStringPattern = regexp.MustCompile(`^'\s*(-?\d+)\s*([a-z]+)\s*'$`)
+
+// isIntervalUnit reports whether word is a unit spelled out, as MySQL and
+// Postgres intervals name them
+func isIntervalUnit(word string) bool {
+	return word != "" && dateUnits[word] == word
+}
+
+// matchBacktick rewrites a backtick-quoted identifier
+func matchBacktick(query string, tokens []dialectToken, i int) (dialectEdit, bool) {
+	tok := tokens[i]
+	if tok.kind != backtickDialectToken {
+		return dialectEdit{}, false
+	}
+	name := strings.ReplaceAll(strings.TrimSuffix(query[tok.start+1:tok.end], "`"), "``", "`")
+	return dialectEdit{start: tok.start, end: tok.end, replacement: quoteIdentifier(name)}, true
+}
+
+// matchDateFunction rewrites DATE_ADD, DATE_SUB, ADDDATE, and SUBDATE with an
+// interval or day count, DATEADD, DATEDIFF, and TIMESTAMPDIFF
+func matchDateFunction(query string, tokens []dialectToken, i int) (dialectEdit, bool) {
+	name := tokens[i].word()
+	switch name {
+	case "date_add", "adddate", "date_sub", "subdate", "dateadd", "datediff", "timestampdiff":
+	default:
+		return dialectEdit{}, false
+	}
+	args, end, ok := callArguments(tokens, i)
+	if !ok {
+		return dialectEdit{}, false
+	}
+	text := func(arg []dialectToken) string { return query[arg[0].start:arg[len(arg)-1].end] }
+	edit := dialectEdit{start: tokens[i].start, end: tokens[end].end}
+
+	switch {
+	case (name == "date_add" || name == "adddate" || name == "date_sub" || name == "subdate") && len(args) == 2:
+		amount, unit, hint := dateAmount(query, args[1])
+		if hint != "" {
+			edit.hint = hint
+			return edit, true
+		}
+		if name == "date_sub" || name == "subdate" {
+			amount = negate(amount)
+		}
+		edit.replacement = fmt.Sprintf("date_add('%s', %s, %s)", unit, amount, text(args[0]))
+	case name == "dateadd" && len(args) == 3:
+		unit, ok := dateUnit(args[0])
+		if !ok {
+			edit.hint = fmt.Sprintf("DATEADD unit %s has no Trino equivalent; date_add accepts millisecond, second, minute, hour, day, week, month, quarter, and year.", text(args[0]))
+			return edit, true
+		}
+		edit.replacement = fmt.Sprintf("date_add('%s', %s, %s)", unit, text(args[1]), text(args[2]))
+	case name == "datediff" && len(args) == 2:
+		// MySQL counts the days from the second date to the first
+		edit.replacement = fmt.Sprintf("date_diff('day', %s, %s)", text(args[1]), text(args[0]))
+	case (name == "datediff" || name == "timestampdiff") && len(args) == 3:
+		unit, ok := dateUnit(args[0])
+		if !ok {
+			edit.hint = fmt.Sprintf("%s unit %s has no Trino equivalent; date_diff accepts millisecond, second, minute, hour, day, week, month, quarter, and year.", strings.ToUpper(name), text(args[0]))
+			return edit, true
+		}
+		edit.replacement = fmt.Sprintf("date_diff('%s', %s, %s)", unit, text(args[1]), text(args[2]))
+	default:
+		// Trino's own date_add(unit, value, date), or an arity no dialect uses
+		return dialectEdit{}, false
+	}
+	return edit, true
+}
+
+// dateAmount reads the second argument of DATE_ADD or DATE_SUB: an
+// INTERVAL n unit, or a number of days
+func dateAmount(query string, arg []dialectToken) (amount, unit, hint string) {
+	if arg[0].word() != "interval" {
+		return query[arg[0].start:arg[len(arg)-1].end], "day", ""
+	}
+	if len(arg) < 3 || arg[len(arg)-1].kind != wordDialectToken {
+		return "", "", "Write the interval as INTERVAL <number> <unit> so it can be translated to date_add(unit, value, date)."
+	}
+	// MySQL spells interval units out; the abbreviations are SQL Server's
+	unit = arg[len(arg)-1].word()
+	if dateUnits[unit] != unit {
+		return "", "", fmt.Sprintf("Interval unit %s has no Trino equivalent; use date_add with one of millisecond, second, minute, hour, day, week, month, quarter, or year.", strings.ToUpper(unit))
+	}
+	value := arg[1 : len(arg)-1]
+	amount = query[value[0].start:value[len(value)-1].end]
+	if len(value) == 1 && value[0].kind == stringDialectToken {
+		amount = strings.Trim(amount, "'")
+		if _, err := strconv.ParseFloat(amount, 64); err != nil {
+			return "", "", fmt.Sprintf("Interval value %s is not a number; compound MySQL intervals have no Trino equivalent, so split them into several date_add calls.", query[value[0].start:value[0].end])
+		}
+	} else if len(value) > 1 {
+		amount = "(" + amount + ")"
+	}
+	return amount, unit, ""
+}
+
+// dateUnit reads a bare or quoted date part argument
+func dateUnit(arg []dialectToken) (string, bool) {
+	if len(arg) != 1 {
+		return "", false
+	}
+	unit, ok := dateUnits[strings.Trim(arg[0].word(), "'")]
+	return unit, ok
+}
+
+// negate negates an amount, parenthesizing expressions
+func negate(amount string) string {
+	if strings.HasPrefix(amount, "-") {
+		if _, err := strconv.ParseFloat(amount[1:], 64); err == nil {
+			return amount[1:]
+		}
+	}
+	if _, err := strconv.ParseFloat(amount, 64); err == nil {
+		return "-" + amount
+	}
+	if strings.HasPrefix(amount, "(") && strings.HasSuffix(amount, ")") {
+		return "-" + amount
+	}
+	return "-(" + amount + ")"
+}
+
+// matchInterval rewrites MySQL intervals with an unquoted value, such as
+// INTERVAL 7 DAY, and Postgres intervals with the unit in the string, such as
+// INTERVAL '7 days'
+func matchInterval(query string, tokens []dialectToken, i int) (dialectEdit, bool) {
+	if tokens[i].word() != "interval" || i+1 >= len(tokens) {
+		return dialectEdit{}, false
+	}
+	next := i + 1
+	sign := ""
+	if tokens[next].text(query) == "-" && next+1 < len(tokens) {
+		sign, next = "-", next+1
+	}
+	var value, unit string
+	end := next
+	switch {
+	case tokens[next].kind == numberDialectToken && next+1 < len(tokens) && tokens[next+1].kind == wordDialectToken:
+		value, unit, end = sign+tokens[next].text(query), tokens[next+1].word(), next+1
+	case tokens[next].kind == stringDialectToken && sign == "" && (next+1 >= len(tokens) || !isIntervalUnit(tokens[next+1].word())):
+		m := intervalStringPattern.FindStringSubmatch(tokens[next].value)
+		if m == nil {
+			return dialectEdit{}, false
+		}
+		value, unit = m[1], m[2]
+		if !isIntervalUnit(unit) {
+			unit = strings.TrimSuffix(unit, "s")
+		}
+	default:
+		return dialectEdit{}, false
+	}
+
+	edit := dialectEdit{start: tokens[i].start, end: tokens[end].end}
+	n, err := strconv.Atoi(value)
+	switch unit {
+	case "second", "minute", "hour", "day", "month", "year":
+		edit.replacement = fmt.Sprintf("INTERVAL '%s' %s", value, strings.ToUpper(dateUnits[unit]))
+	case "week":
+		if err != nil {
+			return dialectEdit{}, false
+		}
+		edit.replacement = fmt.Sprintf("INTERVAL '%d' DAY", n*7)
+	case "quarter":
+		if err != nil {
+			return dialectEdit{}, false
+		}
+		edit.replacement = fmt.Sprintf("INTERVAL '%d' MONTH", n*3)
+	default:
+		edit.hint = fmt.Sprintf("Interval unit %s has no Trino equivalent; Trino intervals use SECOND, MINUTE, HOUR, DAY, MONTH, or YEAR.", strings.ToUpper(unit))
+	}
+	return edit, true
+}
+
+// matchPostgresCast rewrites value::type as CAST(value AS type)
+func matchPostgresCast(query string, tokens []dialectToken, i int) (dialectEdit, bool) {
+	if tokens[i].text(query) != "::" {
+		return dialectEdit{}, false
+	}
+	first := castOperandStart(tokens, i-1)
+	if first < 0 {
+		return dialectEdit{start: tokens[i].start, end: tokens[i].end, hint: "Rewrite this :: cast as CAST(value AS type)."}, true
+	}
+	typeName, last, ok := castType(query, tokens, i+1)
+	edit := dialectEdit{start: tokens[first].start, end: tokens[i].end}
+	if !ok {
+		edit.hint = "Rewrite this :: cast as CAST(value AS type)."
+		return edit, true
+	}
+	edit.end = tokens[last].end
+	if strings.HasPrefix(typeName, "!") {
+		edit.hint = typeName[1:]
+		return edit, true
+	}
+	edit.replacement = fmt.Sprintf("CAST(%s AS %s)", query[tokens[first].start:tokens[i-1].end], typeName)
+	return edit, true
+}
+
+// castOperandStart returns the first token of the operand ending at token
+// last, such as a column, literal, parenthesized expression, or function
+// call, or -1 when there is none
+func castOperandStart(tokens []dialectToken, last int) int {
+	if last < 0 {
+		return -1
+	}
+	first := last
+	// Subscripts and member access belong to the operand they follow, as in
+	// items[1], t.col, and f(x)[1].name
+	for {
+		tok := tokens[first]
+		if tok.kind == punctDialectToken && tok.value == "]" {
+			if open := matchingOpen(tokens, first); open > 0 && endsOperand(tokens[open-1]) {
+				first = open - 1
+				continue
+			}
+		} else if (tok.kind == wordDialectToken || tok.kind == quotedDialectToken) && first >= 2 && tokens[first-1].value == "." && endsOperand(tokens[first-2]) {
+			first -= 2
+			continue
+		}
+		break
+	}
+	switch tok := tokens[first]; {
+	case tok.kind == punctDialectToken && (tok.value == ")" || tok.value == "]"):
+		first = matchingOpen(tokens, first)
+		if first <= 0 {
+			return first
+		}
+		// A function call or ARRAY[...] includes its name
+		if prev := tokens[first-1]; prev.kind == wordDialectToken && !operandKeywords[prev.value] || prev.kind == quotedDialectToken {
+			first--
+		} else {
+			return first
+		}
+	case tok.kind == stringDialectToken:
+		// Typed literals such as DATE '2026-10-17'
+		if first > 0 && tokens[first-1].kind == wordDialectToken && postgresTypes[tokens[first-1].value] != "" {
+			first--
+		}
+		return first
+	case tok.kind == numberDialectToken:
+		return first
+	case tok.kind == wordDialectToken && !operandKeywords[tok.value], tok.kind == quotedDialectToken:
+	default:
+		return -1
+	}
+	// Qualified names such as catalog.schema.table.column
+	for first >= 2 && tokens[first-1].value == "." && (tokens[first-2].kind == wordDialectToken || tokens[first-2].kind == quotedDialectToken) {
+		first -= 2
+	}
+	return first
+}
+
+// endsOperand reports whether tok can end an operand, such as a column, a
+// call, or a subscript
+func endsOperand(tok dialectToken) bool {
+	switch tok.kind {
+	case wordDialectToken:
+		return !operandKeywords[tok.value]
+	case quotedDialectToken:
+		return true
+	case punctDialectToken:
+		return tok.value == ")" || tok.value == "]"
+	}
+	return false
+}
+
+// operandKeywords are words that end or precede an expression rather than
+// name a column or function
+var operandKeywords = map[string]bool{
+	"select": true, "where": true, "and": true, "or": true, "not": true, "in": true, "on": true,
+	"when": true, "then": true, "else": true, "by": true, "as": true, "from": true, "join": true,
+	"exists": true, "values": true, "having": true, "case": true, "end": true, "is": true, "like": true,
+	"between": true, "distinct": true, "all": true, "any": true, "some": true, "using": true,
+}
+
+// castType reads the type after ::, returning its Trino name and the index
+// of its last token. A name starting with ! is a hint instead.
+func castType(query string, tokens []dialectToken, i int) (string, int, bool) {
+	if i >= len(tokens) || tokens[i].kind != wordDialectToken && tokens[i].kind != quotedDialectToken {
+		return "", 0, false
+	}
+	name, last := tokens[i].value, i
+	words := func(next ...string) bool {
+		for k, word := range next {
+			if last+1+k >= len(tokens) || tokens[last+1+k].word() != word {
+				return false
+			}
+		}
+		last += len(next)
+		name += " " + strings.Join(next, " ")
+		return true
+	}
+	var params string
+	readParams := func() {
+		if last+1 < len(tokens) && tokens[last+1].value == "(" {
+			if closing := matchingClose(tokens, last+1); closing > 0 {
+				params = query[tokens[last+1].start:tokens[closing].end]
+				last = closing
+			}
+		}
+	}
+	switch name {
+	case "double":
+		words("precision")
+	case "character":
+		words("varying")
+	case "timestamp", "time":
+		readParams()
+		if !words("with", "time", "zone") {
+			words("without", "time", "zone")
+		}
+	}
+	if params == "" {
+		readParams()
+	}
+	array := 0
+	for last+2 < len(tokens) && tokens[last+1].value == "[" && tokens[last+2].value == "]" {
+		array++
+		last += 2
+	}
+
+	trinoName, ok := postgresTypes[name]
+	switch {
+	case name == "interval":
+		return "!Trino cannot cast to INTERVAL; write an interval literal such as INTERVAL '7' DAY, or use parse_duration('7d').", last, true
+	case !ok:
+		return fmt.Sprintf("!Trino has no %s type; cast to a Trino type with CAST(value AS type).", name), last, true
+	}
+	// Precision goes between the type and its zone: timestamp(3) with time zone
+	if base, zone, found := strings.Cut(trinoName, " with"); found && params != "" {
+		trinoName = base + params + " with" + zone
+	} else {
+		trinoName += params
+	}
+	for range array {
+		trinoName = "array(" + trinoName + ")"
+	}
+	return trinoName, last, true
+}
+
+// matchLimitOffset rewrites MySQL's LIMIT offset, count and Postgres' LIMIT
+// count OFFSET offset as OFFSET offset LIMIT count
+func matchLimitOffset(query string, tokens []dialectToken, i int) (dialectEdit, bool) {
+	if tokens[i].word() != "limit" || i+3 >= len(tokens) {
+		return dialectEdit{}, false
+	}
+	count := func(tok dialectToken) bool { return tok.kind == numberDialectToken || tok.value == "?" }
+	first, sep, second := tokens[i+1], tokens[i+2], tokens[i+3]
+	if !count(first) || !count(second) {
+		return dialectEdit{}, false
+	}
+	edit := dialectEdit{start: tokens[i].start, end: second.end}
+	switch {
+	case sep.value == ",":
+		edit.replacement = fmt.Sprintf("OFFSET %s LIMIT %s", first.text(query), second.text(query))
+	case sep.word() == "offset":
+		edit.replacement = fmt.Sprintf("OFFSET %s LIMIT %s", second.text(query), first.text(query))
+	default:
+		return dialectEdit{}, false
+	}
+	return edit, true
+}
+
+// matchRenamedFunction rewrites calls of functions Trino names differently
+func matchRenamedFunction(query string, tokens []dialectToken, i int) (dialectEdit, bool) {
+	target, ok := renamedFunctions[tokens[i].word()]
+	if !ok || i+1 >= len(tokens) || tokens[i+1].value != "(" || i > 0 && tokens[i-1].value == "." {
+		return dialectEdit{}, false
+	}
+	return dialectEdit{start: tokens[i].start, end: tokens[i].end, replacement: target}, true
+}
+
+// matchUnsupported flags idioms that need more than a mechanical rewrite
+func matchUnsupported(query string, tokens []dialectToken, i int) (dialectEdit, bool) {
+	tok := tokens[i]
+	edit := dialectEdit{start: tok.start, end: tok.end}
+	switch word := tok.word(); {
+	case word == "ilike":
+		edit.hint = "Trino has no ILIKE; compare lowercased values: lower(value) LIKE lower(pattern)."
+	case word == "top" && i > 0 && (tokens[i-1].word() == "select" || tokens[i-1].word() == "distinct") && i+1 < len(tokens) && tokens[i+1].kind == numberDialectToken:
+		edit.end = tokens[i+1].end
+		edit.hint = "Trino has no SELECT TOP; end the query with LIMIT n instead."
+	case unsupportedFunctions[word] != "" && i+1 < len(tokens) && tokens[i+1].value == "(" && (i == 0 || tokens[i-1].value != "."):
+		edit.hint = unsupportedFunctions[word]
+	default:
+		return dialectEdit{}, false
+	}
+	return edit, true
+}
+
+// callArguments splits the arguments of the function call whose name is
+// token i, returning them and the index of the closing parenthesis
+func callArguments(tokens []dialectToken, i int) ([][]dialectToken, int, bool) {
+	if i+1 >= len(tokens) || tokens[i+1].value != "(" || i > 0 && tokens[i-1].value == "." {
+		return nil, 0, false
+	}
+	closing := matchingClose(tokens, i+1)
+	if closing < 0 || closing == i+2 {
+		return nil, 0, false
+	}
+	var args [][]dialectToken
+	depth, start := 0, i+2
+	for k := i + 2; k < closing; k++ {
+		switch tokens[k].value {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		case ",":
+			if depth == 0 {
+				if k == start {
+					return nil, 0, false
+				}
+				args = append(args, tokens[start:k])
+				start = k + 1
+			}
+		}
+	}
+	if start == closing {
+		return nil, 0, false
+	}
+	return append(args, tokens[start:closing]), closing, true
+}
+
+// matchingClose returns the index of the bracket closing the one at open,
+// or -1 when it is unclosed
+func matchingClose(tokens []dialectToken, open int) int {
+	depth := 0
+	for k := open; k < len(tokens); k++ {
+		switch tokens[k].value {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+			if depth == 0 {
+				return k
+			}
+		}
+	}
+	return -1
+}
+
+// matchingOpen returns the index of the bracket opening the one at closing,
+// or -1 when it is unopened
+func matchingOpen(tokens []dialectToken, closing int) int {
+	depth := 0
+	for k := closing; k >= 0; k-- {
+		switch tokens[k].value {
+		case ")", "]":
+			depth++
+		case "(", "[":
+			depth--
+			if depth == 0 {
+				return k
+			}
+		}
+	}
+	return -1
+}
+
+type dialectTokenKind int
+
+const (
+	wordDialectToken dialectTokenKind = iota
+	quotedDialectToken
+	backtickDialectToken
+	stringDialectToken
+	numberDialectToken
+	punctDialectToken
+)
+
+// dialectToken is a token of a query being translated. Unlike scanSQL's
+// tokens, string literals are kept, so they can be cast and moved. Words
+// have a lowercased value; other tokens keep their text.
+type dialectToken struct {
+	kind       dialectTokenKind
+	value      string
+	start, end int
+}
+
+// word returns a word's lowercased value, or "" for other tokens
+func (t dialectToken) word() string {
+	if t.kind != wordDialectToken && t.kind != stringDialectToken {
+		return ""
+	}
+	return t.value
+}
+
+// text returns the token as written in the query
+func (t dialectToken) text(query string) string {
+	return query[t.start:t.end]
+}
+
+// lexDialect splits a query into tokens, skipping whitespace and comments
+func lexDialect(query string) []dialectToken {
+	var tokens []dialectToken
+	add := func(kind dialectTokenKind, start, end int, value string) {
+		tokens = append(tokens, dialectToken{kind: kind, value: value, start: start, end: end})
+	}
+	for i := 0; i < len(query); {
+		ch := query[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case strings.HasPrefix(query[i:], "--") || ch == '#':
+			end := strings.IndexAny(query[i:], "\r\n")
+			if end < 0 {
+				return tokens
+			}
+			i += end
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case ch == '\'':
+			end := skipQuoted(query, i, '\'')
+			add(stringDialectToken, i, end, strings.ToLower(query[i:end]))
+			i = end
+		case ch == '"':
+			end := skipQuoted(query, i, '"')
+			add(quotedDialectToken, i, end, query[i:end])
+			i = end
+		case ch == '`':
+			end := skipQuoted(query, i, '`')
+			add(backtickDialectToken, i, end, query[i:end])
+			i = end
+		case isIdentStart(ch):
+			end := i + 1
+			for end < len(query) && (isIdentStart(query[end]) || isDigit(query[end]) || query[end] == '$') {
+				end++
+			}
+			add(wordDialectToken, i, end, strings.ToLower(query[i:end]))
+			i = end
+		case isDigit(ch) || ch == '.' && i+1 < len(query) && isDigit(query[i+1]):
+			end := i + 1
+			for end < len(query) && (isDigit(query[end]) || query[end] == '.' || query[end] == 'e' || query[end] == 'E') {
+				end++
+			}
+			add(numberDialectToken, i, end, query[i:end])
+			i = end
+		case strings.HasPrefix(query[i:], "::"):
+			add(punctDialectToken, i, i+2, "::")
+			i += 2
+		default:
+			add(punctDialectToken, i, i+1, query[i:i+1])
+			i++
+		}
+	}
+	return tokens
+}
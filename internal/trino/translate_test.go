@@ -0,0 +1,118 @@
+package trino
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslateSQLRewrites(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"postgres cast", "SELECT id::text FROM t", "SELECT CAST(id AS varchar) FROM t"},
+		{"cast of a qualified column", "SELECT o.total::numeric(10,2) FROM orders o", "SELECT CAST(o.total AS decimal(10,2)) FROM orders o"},
+		{"chained casts", "SELECT x::text::int FROM t", "SELECT CAST(CAST(x AS varchar) AS integer) FROM t"},
+		{"cast of a call", "SELECT count(*)::float8 FROM t", "SELECT CAST(count(*) AS double) FROM t"},
+		{"cast of a parenthesized expression", "SELECT (a + b)::bigint FROM t", "SELECT CAST((a + b) AS bigint) FROM t"},
+		{"cast of a literal", "SELECT '2026-10-17'::date", "SELECT CAST('2026-10-17' AS date)"},
+		{"cast to a zoned timestamp", "SELECT ts::timestamp(3) with time zone FROM t", "SELECT CAST(ts AS timestamp(3) with time zone) FROM t"},
+		{"cast to an array", "SELECT ids::int8[] FROM t", "SELECT CAST(ids AS array(bigint)) FROM t"},
+		{"cast of a subscript", "SELECT array[1,2][1]::bigint", "SELECT CAST(array[1,2][1] AS bigint)"},
+		{"cast of a column subscript", "SELECT tags[1]::text FROM t", "SELECT CAST(tags[1] AS varchar) FROM t"},
+		{"cast of a call's field", "SELECT f(x)[1].name::text FROM t", "SELECT CAST(f(x)[1].name AS varchar) FROM t"},
+		{"cast of a parenthesized field", "SELECT (r).total::float8 FROM t", "SELECT CAST((r).total AS double) FROM t"},
+		{"cast to a Trino type", "SELECT x::double, y::tinyint, z::varbinary, ip::ipaddress FROM t", "SELECT CAST(x AS double), CAST(y AS tinyint), CAST(z AS varbinary), CAST(ip AS ipaddress) FROM t"},
+		{"backticks", "SELECT `order`, `a``b` FROM `db`.`t`", `SELECT "order", "a` + "`" + `b" FROM "db"."t"`},
+		{"mysql limit offset", "SELECT * FROM t LIMIT 20, 10", "SELECT * FROM t OFFSET 20 LIMIT 10"},
+		{"postgres limit offset", "SELECT * FROM t LIMIT 10 OFFSET 20", "SELECT * FROM t OFFSET 20 LIMIT 10"},
+		{"date_sub with interval", "SELECT DATE_SUB(CURRENT_DATE, INTERVAL 7 DAY)", "SELECT date_add('day', -7, CURRENT_DATE)"},
+		{"date_add with interval", "SELECT date_add(ts, INTERVAL 2 hour) FROM t", "SELECT date_add('hour', 2, ts) FROM t"},
+		{"date_sub with days", "SELECT date_sub(d, n + 1) FROM t", "SELECT date_add('day', -(n + 1), d) FROM t"},
+		{"nested date_sub", "SELECT DATE_SUB(DATE_SUB(d, INTERVAL 1 MONTH), INTERVAL 1 DAY) FROM t", "SELECT date_add('day', -1, date_add('month', -1, d)) FROM t"},
+		{"sql server dateadd", "SELECT DATEADD(dd, -1, GETUTCDATE_X) FROM t", "SELECT date_add('day', -1, GETUTCDATE_X) FROM t"},
+		{"mysql datediff", "SELECT DATEDIFF(end_date, start_date) FROM t", "SELECT date_diff('day', start_date, end_date) FROM t"},
+		{"timestampdiff", "SELECT TIMESTAMPDIFF(MINUTE, a, b) FROM t", "SELECT date_diff('minute', a, b) FROM t"},
+		{"mysql interval", "SELECT * FROM t WHERE ts > now() - INTERVAL 30 MINUTE", "SELECT * FROM t WHERE ts > now() - INTERVAL '30' MINUTE"},
+		{"postgres interval", "SELECT now() - interval '2 weeks'", "SELECT now() - INTERVAL '14' DAY"},
+		{"renamed functions", "SELECT IFNULL(a, 0), nvl(b, ''), ucase(c) FROM t", "SELECT coalesce(a, 0), coalesce(b, ''), upper(c) FROM t"},
+		{"everything at once", "SELECT `name`, created::date FROM events WHERE created > DATE_SUB(NOW(), INTERVAL 1 WEEK) LIMIT 5, 5",
+			`SELECT "name", CAST(created AS date) FROM events WHERE created > date_add('week', -1, NOW()) OFFSET 5 LIMIT 5`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TranslateSQL(tt.query)
+			if got.Query != tt.want {
+				t.Errorf("TranslateSQL(%q).Query = %q, want %q", tt.query, got.Query, tt.want)
+			}
+			if !got.Changed || len(got.Rewrites) == 0 {
+				t.Errorf("TranslateSQL(%q) reported no rewrites", tt.query)
+			}
+		})
+	}
+}
+
+func TestTranslateSQLLeavesTrinoAlone(t *testing.T) {
+	queries := []string{
+		"SELECT CAST(id AS varchar) FROM t OFFSET 20 LIMIT 10",
+		"SELECT date_add('day', -7, current_date), date_diff('day', a, b) FROM t",
+		"SELECT now() - INTERVAL '7' DAY, INTERVAL '1' DAY TO SECOND",
+		"SELECT 'a::b `c` LIMIT 1, 2' AS s -- x::int\nFROM t /* DATE_SUB(d, 1) */",
+		`SELECT "ifnull" FROM t WHERE x.nvl(1) IS NULL`,
+		"SELECT * FROM t LIMIT 10",
+	}
+	for _, query := range queries {
+		got := TranslateSQL(query)
+		if got.Query != query || got.Changed || len(got.Hints) != 0 || got.Summary() != "" {
+			t.Errorf("TranslateSQL(%q) = %+v, want it unchanged", query, got)
+		}
+	}
+}
+
+func TestTranslateSQLHints(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		hint  string
+	}{
+		{"ilike", "SELECT * FROM t WHERE name ILIKE '%bob%'", "lower(value) LIKE lower(pattern)"},
+		{"top", "SELECT TOP 10 * FROM t", "LIMIT n"},
+		{"group_concat", "SELECT GROUP_CONCAT(name) FROM t", "listagg"},
+		{"to_char", "SELECT to_char(ts, 'YYYY-MM') FROM t", "format_datetime"},
+		{"unknown cast type", "SELECT oid::regclass FROM t", "no regclass type"},
+		{"interval cast", "SELECT '1 day'::interval", "cannot cast to INTERVAL"},
+		{"microseconds", "SELECT DATE_SUB(ts, INTERVAL 5 MICROSECOND) FROM t", "MICROSECOND"},
+		{"compound interval", "SELECT DATE_ADD(ts, INTERVAL '1:30' HOUR_MINUTE) FROM t", "HOUR_MINUTE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TranslateSQL(tt.query)
+			if got.Query != tt.query {
+				t.Errorf("TranslateSQL(%q).Query = %q, want hinted idioms left as written", tt.query, got.Query)
+			}
+			var messages []string
+			for _, hint := range got.Hints {
+				messages = append(messages, hint.Message)
+			}
+			if !strings.Contains(strings.Join(messages, "\n"), tt.hint) {
+				t.Errorf("TranslateSQL(%q) hints = %q, want one mentioning %q", tt.query, messages, tt.hint)
+			}
+		})
+	}
+}
+
+func TestTranslateSQLReport(t *testing.T) {
+	got := TranslateSQL("SELECT `a`, `b`, `c`, `d` FROM t WHERE x::int > 0 AND y ILIKE 'z'")
+	if len(got.Rewrites) != 2 {
+		t.Fatalf("Rewrites = %+v, want backticks and casts", got.Rewrites)
+	}
+	backticks := got.Rewrites[0]
+	if backticks.Idiom != "mysql_backticks" || backticks.Count != 4 || len(backticks.Examples) != maxTranslationExamples || backticks.Examples[0] != "`a`  =>  \"a\"" {
+		t.Errorf("backtick rewrite = %+v, want 4 counted and %d examples", backticks, maxTranslationExamples)
+	}
+	want := "The query uses syntax from other SQL dialects (mysql_backticks, postgres_cast, unsupported_syntax); translate_sql can rewrite it for Trino"
+	if summary := got.Summary(); summary != want {
+		t.Errorf("Summary() = %q, want %q", summary, want)
+	}
+}
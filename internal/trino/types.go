@@ -0,0 +1,142 @@
+package trino
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Trino type names as reported by ColumnTypeDatabaseTypeName
+const (
+	uuidTypeName      = "UUID"
+	ipAddressTypeName = "IPADDRESS"
+)
+
+// unboundedLength is the length Trino reports for varchar without a bound
+const unboundedLength = 2147483647
+
+// fullTypeName rebuilds a column's Trino type with its parameters, such as
+// varchar(10) or timestamp(3) with time zone. The driver's database type name
+// keeps the parameters of nested types but drops them for scalars, and the
+// defaults they fall back to differ (decimal alone is decimal(38,0)).
+func fullTypeName(ct *sql.ColumnType) string {
+	name := strings.ToLower(ct.DatabaseTypeName())
+	switch name {
+	case "char", "varchar":
+		if length, ok := ct.Length(); ok && length != unboundedLength {
+			return fmt.Sprintf("%s(%d)", name, length)
+		}
+	case "decimal":
+		if precision, scale, ok := ct.DecimalSize(); ok {
+			return fmt.Sprintf("decimal(%d,%d)", precision, scale)
+		}
+	case "time", "timestamp", "time with time zone", "timestamp with time zone":
+		if precision, _, ok := ct.DecimalSize(); ok {
+			base, zone, _ := strings.Cut(name, " ")
+			name = fmt.Sprintf("%s(%d)", base, precision)
+			if zone != "" {
+				name += " " + zone
+			}
+		}
+	}
+	return name
+}
+
+// normalizeValue converts driver values for types that would otherwise render
+// poorly in JSON output. Some connectors hand UUID and IPADDRESS values back as
+// raw bytes, which encoding/json emits as base64; this renders them in their
+// canonical string forms instead. Arrays of these types are normalized element-wise.
+func normalizeValue(dbTypeName string, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	typeName := strings.ToUpper(strings.TrimSpace(dbTypeName))
+	switch typeName {
+	case uuidTypeName:
+		return formatUUID(value)
+	case ipAddressTypeName:
+		return formatIPAddress(value)
+	}
+
+	if elemType, ok := arrayElementType(typeName); ok && (elemType == uuidTypeName || elemType == ipAddressTypeName) {
+		if items, ok := value.([]interface{}); ok {
+			normalized := make([]interface{}, len(items))
+			for i, item := range items {
+				normalized[i] = normalizeValue(elemType, item)
+			}
+			return normalized
+		}
+	}
+
+	return value
+}
+
+// arrayElementType returns the element type of an ARRAY(...) type name
+func arrayElementType(typeName string) (string, bool) {
+	if !strings.HasPrefix(typeName, "ARRAY(") || !strings.HasSuffix(typeName, ")") {
+		return "", false
+	}
+	return strings.TrimSpace(typeName[len("ARRAY(") : len(typeName)-1]), true
+}
+
+// formatUUID renders a UUID value in canonical 8-4-4-4-12 lowercase form
+func formatUUID(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		if len(v) == 16 {
+			return encodeUUID(v)
+		}
+		// Textual UUID delivered as bytes
+		return formatUUID(string(v))
+	case string:
+		compact := strings.ReplaceAll(strings.Trim(v, "{}"), "-", "")
+		if len(compact) != 32 {
+			return v
+		}
+		raw, err := hex.DecodeString(compact)
+		if err != nil {
+			return v
+		}
+		return encodeUUID(raw)
+	default:
+		return value
+	}
+}
+
+func encodeUUID(b []byte) string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:16])
+	return string(buf)
+}
+
+// formatIPAddress renders an IPADDRESS value as dotted IPv4 or RFC 5952 IPv6 text.
+// Trino stores all addresses as 16-byte IPv6, so IPv4-mapped addresses are
+// rendered in their IPv4 form to match Trino's own CAST(... AS VARCHAR).
+func formatIPAddress(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		if len(v) == net.IPv4len || len(v) == net.IPv6len {
+			return net.IP(v).String()
+		}
+		return formatIPAddress(string(v))
+	case string:
+		ip := net.ParseIP(strings.TrimSpace(v))
+		if ip == nil {
+			return v
+		}
+		return ip.String()
+	default:
+		return value
+	}
+}
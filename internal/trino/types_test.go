@@ -0,0 +1,57 @@
+package trino
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeValue(t *testing.T) {
+	uuidBytes := []byte{
+		0x12, 0x15, 0x1f, 0xd2, 0x75, 0x86, 0x11, 0xe9,
+		0x8f, 0x9e, 0x2a, 0x86, 0xe4, 0x08, 0x5a, 0x59,
+	}
+	ipv4Mapped := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 192, 168, 0, 1}
+	ipv6 := []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+
+	tests := []struct {
+		name     string
+		typeName string
+		input    interface{}
+		expected interface{}
+	}{
+		{"nil value", "UUID", nil, nil},
+		{"uuid from bytes", "UUID", uuidBytes, "12151fd2-7586-11e9-8f9e-2a86e4085a59"},
+		{"uuid string canonicalized", "UUID", "12151FD2-7586-11E9-8F9E-2A86E4085A59", "12151fd2-7586-11e9-8f9e-2a86e4085a59"},
+		{"uuid compact string", "uuid", "12151fd2758611e98f9e2a86e4085a59", "12151fd2-7586-11e9-8f9e-2a86e4085a59"},
+		{"uuid textual bytes", "UUID", []byte("12151fd2-7586-11e9-8f9e-2a86e4085a59"), "12151fd2-7586-11e9-8f9e-2a86e4085a59"},
+		{"uuid invalid string unchanged", "UUID", "not-a-uuid", "not-a-uuid"},
+		{"ipv4-mapped bytes", "IPADDRESS", ipv4Mapped, "192.168.0.1"},
+		{"ipv4 bytes", "IPADDRESS", []byte{10, 0, 0, 1}, "10.0.0.1"},
+		{"ipv6 bytes", "IPADDRESS", ipv6, "2001:db8::1"},
+		{"ipv6 string canonicalized", "IPADDRESS", "2001:0DB8:0000:0000:0000:0000:0000:0001", "2001:db8::1"},
+		{"ip invalid string unchanged", "IPADDRESS", "bogus", "bogus"},
+		{
+			"array of uuid",
+			"ARRAY(UUID)",
+			[]interface{}{uuidBytes, nil},
+			[]interface{}{"12151fd2-7586-11e9-8f9e-2a86e4085a59", nil},
+		},
+		{
+			"array of ipaddress",
+			"ARRAY(IPADDRESS)",
+			[]interface{}{"10.0.0.1", ipv4Mapped},
+			[]interface{}{"10.0.0.1", "192.168.0.1"},
+		},
+		{"other types untouched", "VARBINARY", []byte{1, 2}, []byte{1, 2}},
+		{"unknown type name untouched", "", int64(42), int64(42)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeValue(tt.typeName, tt.input)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("normalizeValue(%q, %v) = %#v, want %#v", tt.typeName, tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package trino
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Defaults and caps for usage analytics
+const (
+	DefaultUsageWindowHours = 24
+	DefaultUsageLimit       = 10
+
+	maxUsageWindowHours = 24 * 30
+	maxUsageLimit       = 100
+)
+
+// UsageRecord is one query from the coordinator's query history.
+type UsageRecord struct {
+	QueryID   string
+	State     string
+	User      string
+	Source    string
+	Query     string
+	ErrorType string
+	ErrorCode string
+	Created   time.Time
+	ElapsedMs float64
+}
+
+// Failed reports whether the query ended in an error.
+func (r UsageRecord) Failed() bool {
+	return r.State == "FAILED"
+}
+
+// TableUsage summarizes the queries that read one table.
+type TableUsage struct {
+	Table          string  `json:"table"`
+	Queries        int     `json:"queries"`
+	Users          int     `json:"users"`
+	Failures       int     `json:"failures"`
+	TotalElapsedMs float64 `json:"total_elapsed_ms"`
+}
+
+// UserUsage summarizes the queries run by one user.
+type UserUsage struct {
+	User           string  `json:"user"`
+	Queries        int     `json:"queries"`
+	Failures       int     `json:"failures"`
+	TotalElapsedMs float64 `json:"total_elapsed_ms"`
+	AvgElapsedMs   float64 `json:"avg_elapsed_ms"`
+}
+
+// FailureHotspot groups failed queries by error code and table.
+type FailureHotspot struct {
+	ErrorCode      string    `json:"error_code"`
+	ErrorType      string    `json:"error_type"`
+	Table          string    `json:"table,omitempty"`
+	Failures       int       `json:"failures"`
+	Users          int       `json:"users"`
+	LastSeen       time.Time `json:"last_seen"`
+	ExampleQueryID string    `json:"example_query_id"`
+}
+
+// UsageOptions scopes a usage report.
+type UsageOptions struct {
+	WindowHours int  // how far back to look (defaults to DefaultUsageWindowHours)
+	Limit       int  // entries to return (defaults to DefaultUsageLimit)
+	AllSources  bool // include queries not sent by this server
+}
+
+// Normalize applies defaults and caps to the options.
+func (o UsageOptions) Normalize() UsageOptions {
+	o.WindowHours = clampOption(o.WindowHours, DefaultUsageWindowHours, maxUsageWindowHours)
+	o.Limit = clampOption(o.Limit, DefaultUsageLimit, maxUsageLimit)
+	return o
+}
+
+// QueryHistory returns the queries the coordinator remembers from the window.
+// Unless AllSources is set, only queries sent with this server's
+// X-Trino-Source are included. The coordinator retains a bounded history
+// (query.max-history), and users without permission to view other users'
+// queries see only their own.
+func (c *Client) QueryHistory(ctx context.Context, opts UsageOptions) ([]UsageRecord, error) {
+	opts = opts.Normalize()
+	query := fmt.Sprintf(`SELECT query_id, state, "user", source, query, error_type, error_code, created, `+
+		`date_diff('millisecond', coalesce(started, created), coalesce("end", last_heartbeat)) AS elapsed_ms `+
+		`FROM system.runtime.queries WHERE created >= date_add('hour', -%d, current_timestamp) `+
+		`AND query NOT LIKE '%%system.runtime.queries%%'`, opts.WindowHours)
+	if !opts.AllSources && c.config.TrinoSource != "" {
+		query += " AND source = " + quoteLiteral(c.config.TrinoSource)
+	}
+	result, err := c.ExecuteQueryWithContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query history: %w", err)
+	}
+
+	records := make([]UsageRecord, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		record := UsageRecord{}
+		record.QueryID, _ = row["query_id"].(string)
+		record.State, _ = row["state"].(string)
+		record.User, _ = row["user"].(string)
+		record.Source, _ = row["source"].(string)
+		record.Query, _ = row["query"].(string)
+		record.ErrorType, _ = row["error_type"].(string)
+		record.ErrorCode, _ = row["error_code"].(string)
+		record.Created, _ = row["created"].(time.Time)
+		switch elapsed := row["elapsed_ms"].(type) {
+		case int64:
+			record.ElapsedMs = float64(elapsed)
+		case float64:
+			record.ElapsedMs = elapsed
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// tableNamePattern matches a table name: up to three dot-separated parts,
+// each bare or double-quoted
+const tableNamePattern = `((?:"(?:[^"]|"")+"|[a-z_][a-z0-9_]*)(?:\s*\.\s*(?:"(?:[^"]|"")+"|[a-z_][a-z0-9_]*)){0,2})`
+
+var (
+	// tableReference matches the relation after FROM or JOIN
+	tableReference = regexp.MustCompile(`(?i)\b(?:from|join)\s+` + tableNamePattern)
+	// cteName matches the names defined in a WITH clause
+	cteName = regexp.MustCompile(`(?i)(?:\bwith|,)\s*([a-z_][a-z0-9_]*)\s+as\s*\(`)
+	// fromInFunction matches functions whose arguments use FROM, such as
+	// extract(year FROM ts), which would otherwise read as table references
+	fromInFunction = regexp.MustCompile(`(?i)\b(?:extract|substring|trim|position)\s*\([^()]*\)`)
+)
+
+// queryTables extracts the distinct tables a query reads, in order of first
+// reference. Names are lowercased and unquoted; CTE names are skipped.
+func queryTables(query string) []string {
+	query = singleQuoteLiteral.ReplaceAllString(query, "''")
+	query = multiLineComment.ReplaceAllString(singleLineComment.ReplaceAllString(query, ""), "")
+	query = fromInFunction.ReplaceAllString(query, "()")
+
+	ctes := make(map[string]bool)
+	for _, match := range cteName.FindAllStringSubmatch(query, -1) {
+		ctes[strings.ToLower(match[1])] = true
+	}
+
+	var tables []string
+	seen := make(map[string]bool)
+	for _, loc := range tableReference.FindAllStringSubmatchIndex(query, -1) {
+		if strings.HasPrefix(strings.TrimSpace(query[loc[1]:]), "(") {
+			continue // a table function such as unnest(...)
+		}
+		name := normalizeTableReference(query[loc[2]:loc[3]])
+		if ctes[name] || seen[name] {
+			continue
+		}
+		seen[name] = true
+		tables = append(tables, name)
+	}
+	return tables
+}
+
+// normalizeTableReference lowercases and unquotes a dotted table reference
+func normalizeTableReference(reference string) string {
+	var parts []string
+	for _, part := range strings.Split(reference, ".") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, `"`) {
+			part = strings.ReplaceAll(strings.Trim(part, `"`), `""`, `"`)
+		}
+		parts = append(parts, strings.ToLower(part))
+	}
+	return strings.Join(parts, ".")
+}
+
+// TopTables ranks tables by the number of queries that read them.
+func TopTables(records []UsageRecord, limit int) []TableUsage {
+	byTable := make(map[string]*TableUsage)
+	users := make(map[string]map[string]bool)
+	for _, record := range records {
+		for _, table := range queryTables(record.Query) {
+			usage, ok := byTable[table]
+			if !ok {
+				usage = &TableUsage{Table: table}
+				byTable[table] = usage
+				users[table] = make(map[string]bool)
+			}
+			usage.Queries++
+			usage.TotalElapsedMs += record.ElapsedMs
+			if record.Failed() {
+				usage.Failures++
+			}
+			users[table][record.User] = true
+		}
+	}
+
+	ranked := make([]TableUsage, 0, len(byTable))
+	for table, usage := range byTable {
+		usage.Users = len(users[table])
+		ranked = append(ranked, *usage)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Queries != ranked[j].Queries {
+			return ranked[i].Queries > ranked[j].Queries
+		}
+		return ranked[i].Table < ranked[j].Table
+	})
+	return ranked[:min(limit, len(ranked))]
+}
+
+// TopUsers ranks users by total query time, the best proxy for load.
+func TopUsers(records []UsageRecord, limit int) []UserUsage {
+	byUser := make(map[string]*UserUsage)
+	for _, record := range records {
+		usage, ok := byUser[record.User]
+		if !ok {
+			usage = &UserUsage{User: record.User}
+			byUser[record.User] = usage
+		}
+		usage.Queries++
+		usage.TotalElapsedMs += record.ElapsedMs
+		if record.Failed() {
+			usage.Failures++
+		}
+	}
+
+	ranked := make([]UserUsage, 0, len(byUser))
+	for _, usage := range byUser {
+		usage.AvgElapsedMs = usage.TotalElapsedMs / float64(usage.Queries)
+		ranked = append(ranked, *usage)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].TotalElapsedMs != ranked[j].TotalElapsedMs {
+			return ranked[i].TotalElapsedMs > ranked[j].TotalElapsedMs
+		}
+		return ranked[i].User < ranked[j].User
+	})
+	return ranked[:min(limit, len(ranked))]
+}
+
+// FailureHotspots groups failed queries by error code and the first table
+// they read, ranked by failure count.
+func FailureHotspots(records []UsageRecord, limit int) []FailureHotspot {
+	type hotspotKey struct{ code, table string }
+	byKey := make(map[hotspotKey]*FailureHotspot)
+	users := make(map[hotspotKey]map[string]bool)
+	for _, record := range records {
+		if !record.Failed() {
+			continue
+		}
+		key := hotspotKey{code: record.ErrorCode}
+		if tables := queryTables(record.Query); len(tables) > 0 {
+			key.table = tables[0]
+		}
+		hotspot, ok := byKey[key]
+		if !ok {
+			hotspot = &FailureHotspot{ErrorCode: record.ErrorCode, ErrorType: record.ErrorType, Table: key.table}
+			byKey[key] = hotspot
+			users[key] = make(map[string]bool)
+		}
+		hotspot.Failures++
+		users[key][record.User] = true
+		if record.Created.After(hotspot.LastSeen) || hotspot.ExampleQueryID == "" {
+			hotspot.LastSeen = record.Created
+			hotspot.ExampleQueryID = record.QueryID
+		}
+	}
+
+	ranked := make([]FailureHotspot, 0, len(byKey))
+	for key, hotspot := range byKey {
+		hotspot.Users = len(users[key])
+		ranked = append(ranked, *hotspot)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Failures != ranked[j].Failures {
+			return ranked[i].Failures > ranked[j].Failures
+		}
+		return ranked[i].LastSeen.After(ranked[j].LastSeen)
+	})
+	return ranked[:min(limit, len(ranked))]
+}
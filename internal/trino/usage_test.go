@@ -0,0 +1,110 @@
+package trino
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryTables(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "qualified and quoted names",
+			query: `SELECT * FROM hive.web.page_views v JOIN "Hive"."Web"."Users" u ON v.user_id = u.id`,
+			want:  []string{"hive.web.page_views", "hive.web.users"},
+		},
+		{
+			name:  "CTEs are skipped",
+			query: "WITH recent AS (SELECT * FROM orders WHERE ds > '2024-01-01'), big AS (SELECT * FROM recent) SELECT * FROM big JOIN customers ON true",
+			want:  []string{"orders", "customers"},
+		},
+		{
+			name:  "literals, comments, and functions",
+			query: "SELECT extract(year FROM ts), 'from fake' -- from other\nFROM events CROSS JOIN unnest(tags) AS t(tag)",
+			want:  []string{"events"},
+		},
+		{
+			name:  "duplicates collapse",
+			query: "SELECT * FROM a.b.c UNION ALL SELECT * FROM A.B.C",
+			want:  []string{"a.b.c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryTables(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("queryTables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func sampleUsageRecords() []UsageRecord {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return []UsageRecord{
+		{QueryID: "q1", State: "FINISHED", User: "alice", Query: "SELECT * FROM hive.web.page_views", ElapsedMs: 1000, Created: base},
+		{QueryID: "q2", State: "FINISHED", User: "bob", Query: "SELECT * FROM hive.web.page_views JOIN hive.web.users ON true", ElapsedMs: 9000, Created: base},
+		{QueryID: "q3", State: "FAILED", User: "alice", Query: "SELECT * FROM hive.web.users", ErrorCode: "EXCEEDED_TIME_LIMIT", ErrorType: "INSUFFICIENT_RESOURCES", ElapsedMs: 500, Created: base.Add(time.Minute)},
+		{QueryID: "q4", State: "FAILED", User: "bob", Query: "SELECT * FROM hive.web.users", ErrorCode: "EXCEEDED_TIME_LIMIT", ErrorType: "INSUFFICIENT_RESOURCES", ElapsedMs: 500, Created: base.Add(2 * time.Minute)},
+		{QueryID: "q5", State: "FAILED", User: "alice", Query: "SELECT nope FROM hive.web.page_views", ErrorCode: "COLUMN_NOT_FOUND", ErrorType: "USER_ERROR", Created: base},
+	}
+}
+
+func TestTopTables(t *testing.T) {
+	tables := TopTables(sampleUsageRecords(), 10)
+	if len(tables) != 2 {
+		t.Fatalf("expected 2 tables, got %+v", tables)
+	}
+	// Ties on query count break alphabetically
+	want := TableUsage{Table: "hive.web.page_views", Queries: 3, Users: 2, Failures: 1, TotalElapsedMs: 10000}
+	if tables[0] != want {
+		t.Errorf("tables[0] = %+v, want %+v", tables[0], want)
+	}
+	if tables[1].Table != "hive.web.users" || tables[1].Failures != 2 {
+		t.Errorf("tables[1] = %+v", tables[1])
+	}
+	if limited := TopTables(sampleUsageRecords(), 1); len(limited) != 1 {
+		t.Errorf("expected limit to apply, got %d tables", len(limited))
+	}
+}
+
+func TestTopUsers(t *testing.T) {
+	users := TopUsers(sampleUsageRecords(), 10)
+	if len(users) != 2 || users[0].User != "bob" {
+		t.Fatalf("expected bob first by total time, got %+v", users)
+	}
+	if users[0].Queries != 2 || users[0].Failures != 1 || users[0].AvgElapsedMs != 4750 {
+		t.Errorf("users[0] = %+v", users[0])
+	}
+	if users[1].Queries != 3 || users[1].Failures != 2 {
+		t.Errorf("users[1] = %+v", users[1])
+	}
+}
+
+func TestFailureHotspots(t *testing.T) {
+	hotspots := FailureHotspots(sampleUsageRecords(), 10)
+	if len(hotspots) != 2 {
+		t.Fatalf("expected 2 hotspots, got %+v", hotspots)
+	}
+	top := hotspots[0]
+	if top.ErrorCode != "EXCEEDED_TIME_LIMIT" || top.Table != "hive.web.users" || top.Failures != 2 || top.Users != 2 {
+		t.Errorf("hotspots[0] = %+v", top)
+	}
+	if top.ExampleQueryID != "q4" {
+		t.Errorf("ExampleQueryID = %q, want the most recent failure q4", top.ExampleQueryID)
+	}
+}
+
+func TestUsageOptionsNormalize(t *testing.T) {
+	opts := UsageOptions{}.Normalize()
+	if opts.WindowHours != DefaultUsageWindowHours || opts.Limit != DefaultUsageLimit {
+		t.Errorf("defaults = %+v", opts)
+	}
+	opts = UsageOptions{WindowHours: 100000, Limit: 100000}.Normalize()
+	if opts.WindowHours != maxUsageWindowHours || opts.Limit != maxUsageLimit {
+		t.Errorf("caps = %+v", opts)
+	}
+}
@@ -0,0 +1,88 @@
+package trino
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxWarmupErrors caps the failures a warm-up report describes
+const maxWarmupErrors = 5
+
+// WarmupReport summarizes one metadata warm-up.
+type WarmupReport struct {
+	Catalogs       int           // catalogs listed
+	Schemas        int           // schemas listed across catalogs
+	TableLists     int           // schemas whose tables were listed
+	SkippedSchemas int           // schemas past the table listing cap
+	TableSchemas   int           // hot tables described
+	Failed         int           // metadata queries that failed
+	Errors         []string      // the first failures
+	Duration       time.Duration // time the warm-up took
+}
+
+// WarmMetadata lists the allowed catalogs and their schemas, lists the tables
+// in up to maxSchemas of those schemas, and describes each hot table, so the
+// metadata caches of the coordinator and its connectors are populated before
+// agents first browse. Hot tables are catalog.schema.table or schema.table
+// names. Queries run one at a time to spare a busy coordinator, and failures
+// are counted rather than stopping the warm-up.
+func (c *Client) WarmMetadata(ctx context.Context, hotTables []string, maxSchemas int) WarmupReport {
+	start := time.Now()
+	var report WarmupReport
+	fail := func(what string, err error) {
+		report.Failed++
+		if len(report.Errors) < maxWarmupErrors {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", what, err))
+		}
+	}
+
+	catalogs, err := c.ListCatalogsWithContext(ctx)
+	if err != nil {
+		fail("listing catalogs", err)
+	}
+	report.Catalogs = len(catalogs)
+	for _, catalog := range catalogs {
+		if ctx.Err() != nil {
+			break
+		}
+		schemas, err := c.ListSchemasWithContext(ctx, catalog)
+		if err != nil {
+			fail("listing schemas in "+catalog, err)
+			continue
+		}
+		report.Schemas += len(schemas)
+		for _, schema := range schemas {
+			// Every catalog has one, and its tables are rarely browsed
+			if schema == "information_schema" {
+				continue
+			}
+			if report.TableLists >= maxSchemas {
+				report.SkippedSchemas++
+				continue
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			if _, err := c.ListTablesWithContext(ctx, catalog, schema); err != nil {
+				fail(fmt.Sprintf("listing tables in %s.%s", catalog, schema), err)
+				continue
+			}
+			report.TableLists++
+		}
+	}
+
+	for _, table := range hotTables {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := c.GetTableSchemaWithContext(ctx, "", "", table); err != nil {
+			fail("describing "+table, err)
+			continue
+		}
+		report.TableSchemas++
+	}
+
+	report.Duration = time.Since(start)
+	return report
+}
@@ -0,0 +1,157 @@
+package trino
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestWarmMetadata(t *testing.T) {
+	var mu sync.Mutex
+	var statements []string
+	c := newFakeCoordinator(t, func(statement string) fakeResult {
+		mu.Lock()
+		statements = append(statements, statement)
+		mu.Unlock()
+		rows := func(column string, values ...string) fakeResult {
+			result := fakeResult{columns: []fakeColumn{{name: column, rawType: "varchar"}}}
+			for _, v := range values {
+				result.data = append(result.data, []any{v})
+			}
+			return result
+		}
+		switch statement {
+		case "SHOW CATALOGS":
+			return rows("Catalog", "hive", "system")
+		case "SHOW SCHEMAS FROM hive":
+			return rows("Schema", "information_schema", "events", "sales", "hr")
+		case "SHOW TABLES FROM hive.events", "SHOW TABLES FROM hive.sales":
+			return rows("Table", "orders")
+		case "DESCRIBE hive.sales.orders":
+			return rows("Column", "id")
+		}
+		return fakeResult{err: "Access Denied: " + statement}
+	})
+
+	report := c.WarmMetadata(context.Background(), []string{"sales.orders"}, 2)
+
+	if report.Catalogs != 2 || report.Schemas != 4 || report.TableLists != 2 || report.SkippedSchemas != 1 || report.TableSchemas != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	if report.Failed != 1 || len(report.Errors) != 1 || !strings.Contains(report.Errors[0], "listing schemas in system") {
+		t.Errorf("failures = %d %q, want the denied schema listing reported", report.Failed, report.Errors)
+	}
+	for _, statement := range statements {
+		if strings.Contains(statement, "information_schema") {
+			t.Errorf("warm-up listed tables in information_schema: %q", statement)
+		}
+	}
+}
+
+// fakeColumn is a result column of a fake coordinator, with its type
+// signature's raw type and numeric arguments
+type fakeColumn struct {
+	name    string
+	rawType string
+	args    []int64
+}
+
+// fakeResult is a fake coordinator's answer to a statement: columns and
+// rows, or an error message
+type fakeResult struct {
+	columns []fakeColumn
+	data    [][]any
+	err     string
+}
+
+// newFakeCoordinator returns a client of a coordinator that answers each
+// statement with answer
+func newFakeCoordinator(t *testing.T, answer func(statement string) fakeResult) *Client {
+	t.Helper()
+	var mu sync.Mutex
+	statements := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/statement":
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			queryID := fmt.Sprintf("20261017_000000_%05d_abcde", len(statements)+1)
+			statements[queryID] = string(body)
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      queryID,
+				"nextUri": "http://" + r.Host + "/v1/statement/executing/" + queryID,
+				"stats":   map[string]any{"state": "QUEUED"},
+			})
+		case strings.HasPrefix(r.URL.Path, "/v1/statement/executing/"):
+			queryID := strings.TrimPrefix(r.URL.Path, "/v1/statement/executing/")
+			mu.Lock()
+			statement := statements[queryID]
+			mu.Unlock()
+			result := answer(statement)
+			if result.err != "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"id":    queryID,
+					"error": map[string]any{"message": result.err, "errorName": "PERMISSION_DENIED"},
+					"stats": map[string]any{"state": "FAILED"},
+				})
+				return
+			}
+			columns := make([]map[string]any, len(result.columns))
+			for i, column := range result.columns {
+				args := []any{}
+				typeName := column.rawType
+				for j, arg := range column.args {
+					args = append(args, map[string]any{"kind": "LONG", "value": arg})
+					if j == 0 {
+						typeName += "("
+					} else {
+						typeName += ","
+					}
+					typeName += strconv.FormatInt(arg, 10)
+				}
+				if len(column.args) > 0 {
+					typeName += ")"
+				}
+				columns[i] = map[string]any{"name": column.name, "type": typeName, "typeSignature": map[string]any{"rawType": column.rawType, "arguments": args}}
+			}
+			data := result.data
+			if data == nil {
+				data = [][]any{}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      queryID,
+				"columns": columns,
+				"data":    data,
+				"stats":   map[string]any{"state": "FINISHED"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.Atoi(u.Port())
+	c, err := NewClient(&config.TrinoConfig{
+		Host: u.Hostname(), Port: port, Scheme: "http", User: "svc",
+		Catalog: "hive", Schema: "default", QueryTimeout: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
@@ -0,0 +1,68 @@
+package trino
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+// metadataQueryPattern matches statements that only read catalog metadata or
+// plans, which are always cheap enough for the interactive cluster
+var metadataQueryPattern = regexp.MustCompile(`^\s*(show|describe|explain)\b`)
+
+// newBatchClient connects to the batch cluster configured by
+// TRINO_BATCH_HOST. It shares the server's credentials, settings, and limits.
+func newBatchClient(cfg *config.TrinoConfig, limits *Limits) (*Client, error) {
+	batchCfg := *cfg
+	batchCfg.Host, batchCfg.Port = cfg.BatchHost, cfg.BatchPort
+	batchCfg.BatchHost = ""
+	return NewClientWithLimits(&batchCfg, limits)
+}
+
+// workloadClient picks the cluster for a user-submitted query. Queries whose
+// estimated scan is expensive go to the batch cluster; metadata queries and
+// queries that cannot be estimated stay on the interactive cluster.
+func (c *Client) workloadClient(ctx context.Context, query string) *Client {
+	if c.batch == nil {
+		return c
+	}
+	normalized := strings.ToLower(sanitizeQueryForKeywordDetection(strings.TrimSpace(query)))
+	if metadataQueryPattern.MatchString(normalized) {
+		return c
+	}
+	if !c.config.AllowWriteQueries && !isReadOnlyQuery(query) {
+		return c // rejected before it runs
+	}
+
+	estimate, err := c.EstimateQueryCost(ctx, query)
+	if err != nil {
+		log.Printf("WARNING: Failed to estimate query cost, running on the interactive cluster: %v", err)
+		return c
+	}
+	if reason := batchReason(estimate, c.config.BatchMinScanMB, c.config.BatchFullScans); reason != "" {
+		log.Printf("INFO: Routing query to the batch cluster: %s", reason)
+		return c.batch
+	}
+	return c
+}
+
+// batchReason explains why a query belongs on the batch cluster, or returns
+// "" when it is cheap. Missing statistics never route a query, since an
+// unknown size is more often a small unanalyzed table than a large one.
+func batchReason(estimate *CostEstimate, minScanMB int, fullScans bool) string {
+	if estimate.EstimatedBytes != nil && *estimate.EstimatedBytes >= float64(minScanMB)*(1<<20) {
+		return fmt.Sprintf("estimated scan of %.0f MB is at least %d MB", *estimate.EstimatedBytes/(1<<20), minScanMB)
+	}
+	if fullScans {
+		for _, table := range estimate.Tables {
+			if table.Unfiltered {
+				return fmt.Sprintf("%s is scanned without any filter", table.Table)
+			}
+		}
+	}
+	return ""
+}
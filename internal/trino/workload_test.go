@@ -0,0 +1,61 @@
+package trino
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/mcp-trino/internal/config"
+)
+
+func TestBatchReason(t *testing.T) {
+	bytes := func(mb float64) *float64 {
+		b := mb * (1 << 20)
+		return &b
+	}
+	filtered := []TableCost{{Table: "hive.web.events"}}
+	unfiltered := []TableCost{{Table: "hive.web.events", Unfiltered: true}}
+
+	tests := []struct {
+		name      string
+		estimate  CostEstimate
+		fullScans bool
+		want      string
+	}{
+		{"large scan", CostEstimate{EstimatedBytes: bytes(2048), Tables: filtered}, false, "estimated scan of 2048 MB"},
+		{"small scan", CostEstimate{EstimatedBytes: bytes(10), Tables: filtered}, false, ""},
+		{"no statistics", CostEstimate{Tables: filtered}, false, ""},
+		{"unfiltered scan", CostEstimate{EstimatedBytes: bytes(10), Tables: unfiltered}, true, "hive.web.events is scanned without any filter"},
+		{"unfiltered scan not routed", CostEstimate{EstimatedBytes: bytes(10), Tables: unfiltered}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := batchReason(&tt.estimate, 1024, tt.fullScans)
+			if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+				t.Errorf("batchReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkloadClientKeepsCheapQueriesInteractive(t *testing.T) {
+	batch := &Client{config: &config.TrinoConfig{}}
+	client := &Client{config: &config.TrinoConfig{}, batch: batch}
+
+	// None of these reach EstimateQueryCost, which would need a connection
+	for _, query := range []string{
+		"SHOW CATALOGS",
+		"  describe hive.web.events",
+		"EXPLAIN SELECT * FROM hive.web.events",
+		"DELETE FROM hive.web.events",
+	} {
+		if got := client.workloadClient(context.Background(), query); got != client {
+			t.Errorf("workloadClient(%q) chose the batch cluster", query)
+		}
+	}
+
+	unrouted := &Client{config: &config.TrinoConfig{}}
+	if got := unrouted.workloadClient(context.Background(), "SELECT * FROM hive.web.events"); got != unrouted {
+		t.Error("workloadClient() chose another client with routing disabled")
+	}
+}
@@ -0,0 +1,127 @@
+package usage
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Chargeback groupings
+const (
+	GroupByTeam = "team"
+	GroupByUser = "user"
+)
+
+// Rates price resource usage. Zero rates leave costs out of reports.
+type Rates struct {
+	PerTBScanned float64 // per 2^40 bytes read from storage
+	PerCPUHour   float64 // per hour of Trino CPU time
+}
+
+// Enabled reports whether any rate is set.
+func (r Rates) Enabled() bool {
+	return r.PerTBScanned > 0 || r.PerCPUHour > 0
+}
+
+// Charge aggregates the usage of one team or user.
+type Charge struct {
+	Group           string   `json:"group"` // team or user; an empty team collects callers without one
+	Users           int      `json:"users"`
+	Queries         int      `json:"queries"`
+	FailedQueries   int      `json:"failed_queries"`
+	CPUSeconds      float64  `json:"cpu_seconds"`
+	ScannedBytes    int64    `json:"scanned_bytes"`
+	PeakMemoryBytes int64    `json:"peak_memory_bytes"` // largest peak of a single query
+	Cost            *float64 `json:"cost,omitempty"`    // nil when no rates are set
+}
+
+// Chargeback aggregates records in [from, to) per team or per user, sorted
+// by group. Costs are filled in when rates are set.
+func Chargeback(records []Record, from, to time.Time, groupBy string, rates Rates) ([]Charge, error) {
+	if groupBy != GroupByTeam && groupBy != GroupByUser {
+		return nil, fmt.Errorf("invalid grouping %q (supported: %s, %s)", groupBy, GroupByTeam, GroupByUser)
+	}
+	byGroup := make(map[string]*Charge)
+	users := make(map[string]map[string]bool)
+	for _, record := range records {
+		if record.Time.Before(from) || !record.Time.Before(to) {
+			continue
+		}
+		group := record.Team
+		if groupBy == GroupByUser {
+			group = record.User
+		}
+		charge, ok := byGroup[group]
+		if !ok {
+			charge = &Charge{Group: group}
+			byGroup[group] = charge
+			users[group] = make(map[string]bool)
+		}
+		users[group][record.User] = true
+		charge.Queries++
+		if record.State == "FAILED" {
+			charge.FailedQueries++
+		}
+		charge.CPUSeconds += float64(record.CPUMs) / 1000
+		charge.ScannedBytes += record.ScannedBytes
+		charge.PeakMemoryBytes = max(charge.PeakMemoryBytes, record.PeakMemoryBytes)
+	}
+
+	charges := make([]Charge, 0, len(byGroup))
+	for group, charge := range byGroup {
+		charge.Users = len(users[group])
+		if rates.Enabled() {
+			cost := float64(charge.ScannedBytes)/(1<<40)*rates.PerTBScanned + charge.CPUSeconds/3600*rates.PerCPUHour
+			charge.Cost = &cost
+		}
+		charges = append(charges, *charge)
+	}
+	sort.Slice(charges, func(i, j int) bool { return charges[i].Group < charges[j].Group })
+	return charges, nil
+}
+
+// WriteChargebackCSV writes a chargeback report as CSV with a header row
+// naming the grouping. The cost column is present only when costs are.
+func WriteChargebackCSV(w io.Writer, groupBy string, charges []Charge) error {
+	withCost := len(charges) > 0 && charges[0].Cost != nil
+	header := []string{groupBy, "users", "queries", "failed_queries", "cpu_seconds", "scanned_bytes", "peak_memory_bytes"}
+	if withCost {
+		header = append(header, "cost")
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, charge := range charges {
+		row := []string{
+			spreadsheetSafe(charge.Group),
+			strconv.Itoa(charge.Users),
+			strconv.Itoa(charge.Queries),
+			strconv.Itoa(charge.FailedQueries),
+			strconv.FormatFloat(charge.CPUSeconds, 'f', 3, 64),
+			strconv.FormatInt(charge.ScannedBytes, 10),
+			strconv.FormatInt(charge.PeakMemoryBytes, 10),
+		}
+		if withCost {
+			row = append(row, strconv.FormatFloat(*charge.Cost, 'f', 4, 64))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// spreadsheetSafe keeps team and user names from token claims from being
+// evaluated as formulas when the report is opened in a spreadsheet
+func spreadsheetSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
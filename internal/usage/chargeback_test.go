@@ -0,0 +1,84 @@
+package usage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func chargebackRecords() []Record {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC) }
+	return []Record{
+		{Time: day(1), User: "alice", Team: "growth", State: "FINISHED", CPUMs: 3_600_000, ScannedBytes: 1 << 40, PeakMemoryBytes: 100},
+		{Time: day(2), User: "carol", Team: "growth", State: "FAILED", CPUMs: 1_800_000, PeakMemoryBytes: 300},
+		{Time: day(2), User: "bob", Team: "finance", State: "FINISHED", ScannedBytes: 1 << 39},
+		{Time: day(3), User: "=cmd", State: "FINISHED", CPUMs: 1000},
+		{Time: day(20), User: "alice", Team: "growth", State: "FINISHED", CPUMs: 1000}, // outside the range
+	}
+}
+
+func TestChargebackByTeam(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	charges, err := Chargeback(chargebackRecords(), from, from.AddDate(0, 0, 7), GroupByTeam, Rates{PerTBScanned: 5, PerCPUHour: 2})
+	if err != nil {
+		t.Fatalf("Chargeback() error = %v", err)
+	}
+	if len(charges) != 3 {
+		t.Fatalf("Chargeback() = %+v, want 3 groups", charges)
+	}
+	unassigned, finance, growth := charges[0], charges[1], charges[2]
+	if unassigned.Group != "" || finance.Group != "finance" || growth.Group != "growth" {
+		t.Fatalf("groups = %q, %q, %q", unassigned.Group, finance.Group, growth.Group)
+	}
+	if growth.Users != 2 || growth.Queries != 2 || growth.FailedQueries != 1 || growth.CPUSeconds != 5400 || growth.PeakMemoryBytes != 300 {
+		t.Errorf("growth = %+v", growth)
+	}
+	// 1 TB at 5 plus 1.5 CPU hours at 2
+	if growth.Cost == nil || *growth.Cost != 8 {
+		t.Errorf("growth cost = %v, want 8", growth.Cost)
+	}
+	if finance.Cost == nil || *finance.Cost != 2.5 {
+		t.Errorf("finance cost = %v, want 2.5", finance.Cost)
+	}
+}
+
+func TestChargebackByUserWithoutRates(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	charges, err := Chargeback(chargebackRecords(), from, from.AddDate(0, 1, 0), GroupByUser, Rates{})
+	if err != nil {
+		t.Fatalf("Chargeback() error = %v", err)
+	}
+	if len(charges) != 4 || charges[1].Group != "alice" || charges[1].Queries != 2 {
+		t.Fatalf("Chargeback() = %+v, want alice's two queries", charges)
+	}
+	for _, charge := range charges {
+		if charge.Cost != nil {
+			t.Errorf("%s has a cost without rates", charge.Group)
+		}
+	}
+
+	if _, err := Chargeback(nil, from, from, "tenant", Rates{}); err == nil {
+		t.Error("Chargeback() accepted an invalid grouping")
+	}
+}
+
+func TestWriteChargebackCSV(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	charges, _ := Chargeback(chargebackRecords(), from, from.AddDate(0, 0, 7), GroupByUser, Rates{PerCPUHour: 1})
+
+	var buf bytes.Buffer
+	if err := WriteChargebackCSV(&buf, GroupByUser, charges); err != nil {
+		t.Fatalf("WriteChargebackCSV() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "user,users,queries,failed_queries,cpu_seconds,scanned_bytes,peak_memory_bytes,cost" {
+		t.Errorf("header = %q", lines[0])
+	}
+	if lines[1] != "'=cmd,1,1,0,1.000,0,0,0.0003" {
+		t.Errorf("formula-like user row = %q", lines[1])
+	}
+	if len(lines) != 1+len(charges) {
+		t.Errorf("got %d lines for %d charges", len(lines), len(charges))
+	}
+}
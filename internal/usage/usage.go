@@ -0,0 +1,168 @@
+// Package usage records the Trino resources each query consumed, attributed
+// to the caller and their team, in an append-only JSON Lines file, and
+// aggregates the records into chargeback reports.
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Record is the resource usage of one Trino query.
+type Record struct {
+	Time            time.Time `json:"time"`
+	User            string    `json:"user"`
+	Team            string    `json:"team,omitempty"` // empty when the caller's token names no team
+	Tool            string    `json:"tool"`
+	QueryID         string    `json:"query_id"`
+	State           string    `json:"state"`
+	CPUMs           int64     `json:"cpu_ms"`
+	ElapsedMs       int64     `json:"elapsed_ms"`
+	ScannedBytes    int64     `json:"scanned_bytes"`
+	ProcessedBytes  int64     `json:"processed_bytes"`
+	PeakMemoryBytes int64     `json:"peak_memory_bytes"`
+}
+
+// Store appends records to a usage file.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// Open opens the usage file for appending, creating it if needed.
+func Open(path string) (*Store, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 -- operator-configured usage file
+	if err != nil {
+		return nil, fmt.Errorf("failed to open usage log: %w", err)
+	}
+	return &Store{path: path, file: file}, nil
+}
+
+// Log appends the record as one JSON line.
+func (s *Store) Log(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage record: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage record: %w", err)
+	}
+	return nil
+}
+
+// Path returns the usage file's path.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Size returns the current size of the usage file in bytes.
+func (s *Store) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := s.file.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// Rotate renames the usage file with a timestamp suffix and continues in a
+// new file. An empty file is not rotated.
+func (s *Store) Rotate(now time.Time) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := s.file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to rotate usage log: %w", err)
+	}
+	if info.Size() == 0 {
+		return "", nil
+	}
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	rotated := s.path + "." + stamp
+	for i := 1; fileExists(rotated); i++ {
+		rotated = fmt.Sprintf("%s.%s-%d", s.path, stamp, i)
+	}
+	if err := os.Rename(s.path, rotated); err != nil {
+		return "", fmt.Errorf("failed to rotate usage log: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 -- operator-configured usage file
+	if err != nil {
+		return "", fmt.Errorf("failed to reopen usage log after rotation: %w", err)
+	}
+	_ = s.file.Close()
+	s.file = file
+	return rotated, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Close closes the usage file.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// ReadAll reads the records in a usage file and its rotated files, oldest
+// first. A missing live file is not an error when rotated files exist.
+func ReadAll(path string) ([]Record, error) {
+	rotated, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rotated usage logs: %w", err)
+	}
+	sort.Strings(rotated)
+
+	var records []Record
+	for _, name := range append(rotated, path) {
+		file, err := os.Open(name) // #nosec G304 -- operator-configured usage file
+		if errors.Is(err, os.ErrNotExist) && len(rotated) > 0 {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open usage log: %w", err)
+		}
+		fileRecords, err := Read(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+	return records, nil
+}
+
+// Read decodes records from JSON Lines.
+func Read(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("usage log line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+	return records, nil
+}
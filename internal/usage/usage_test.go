@@ -0,0 +1,63 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	first := Record{Time: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), User: "alice", Team: "growth", Tool: "execute_query", QueryID: "q1", State: "FINISHED", CPUMs: 1500, ScannedBytes: 1 << 30}
+	second := Record{Time: time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC), User: "bob", Tool: "execute_query", QueryID: "q2", State: "FAILED"}
+	if err := store.Log(first); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	rotated, err := store.Rotate(time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil || rotated == "" {
+		t.Fatalf("Rotate() = %q, %v", rotated, err)
+	}
+	if err := store.Log(second); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if store.Size() == 0 {
+		t.Error("Size() = 0 after logging a record")
+	}
+
+	records, err := ReadAll(path)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(records) != 2 || records[0] != first || records[1] != second {
+		t.Errorf("ReadAll() = %+v, want both records oldest first", records)
+	}
+}
+
+func TestStoreRotateEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	if rotated, err := store.Rotate(time.Now()); err != nil || rotated != "" {
+		t.Errorf("Rotate() of an empty file = %q, %v", rotated, err)
+	}
+}
+
+func TestReadAllInvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	if err := os.WriteFile(path, []byte("{not json}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadAll(path); err == nil {
+		t.Error("ReadAll() accepted an invalid line")
+	}
+}
@@ -0,0 +1,37 @@
+package mcptrino_test
+
+import (
+	"log"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/pkg/mcptrino"
+)
+
+func Example() {
+	cfg, err := mcptrino.LoadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	client, err := mcptrino.NewClient(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	options := []server.ServerOption{server.WithToolCapabilities(true)}
+	if cfg.OAuthEnabled {
+		auth, err := mcptrino.NewAuth(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		options = append(options, server.WithToolHandlerMiddleware(mcptrino.AuthMiddleware(auth)))
+	}
+
+	s := server.NewMCPServer("my-server", "1.0.0", options...)
+	mcptrino.RegisterTools(s, mcptrino.NewToolHandlers(client, cfg))
+
+	httpServer := server.NewStreamableHTTPServer(s, server.WithHTTPContextFunc(mcptrino.HTTPContextFunc()))
+	if err := httpServer.Start(":8080"); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,116 @@
+// Package mcptrino embeds the mcp-trino tools in another Go MCP server.
+//
+// It is the stable public surface over the packages under internal/: the
+// Trino client with its read-only enforcement and catalog/schema/table
+// allowlists, registration of the Trino tools on an mcp-go server, and the
+// OAuth middleware that authenticates tool calls and supplies the user for
+// impersonation. A typical embedding:
+//
+//	cfg, err := mcptrino.LoadConfig()
+//	...
+//	client, err := mcptrino.NewClient(cfg)
+//	...
+//	s := server.NewMCPServer("my-server", "1.0.0", server.WithToolCapabilities(true))
+//	mcptrino.RegisterTools(s, mcptrino.NewToolHandlers(client, cfg))
+package mcptrino
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tuannvm/mcp-trino/internal/config"
+	"github.com/tuannvm/mcp-trino/internal/mcp"
+	"github.com/tuannvm/mcp-trino/internal/trino"
+	oauth "github.com/tuannvm/oauth-mcp-proxy"
+)
+
+// Config holds the Trino connection, allowlist, and OAuth settings. The
+// allowlists (AllowedCatalogs, AllowedSchemas, AllowedTables) are enforced by
+// every Client call and tool built from it.
+type Config = config.TrinoConfig
+
+// Client runs queries against Trino, rejecting writes unless
+// Config.AllowWriteQueries is set and filtering results by the allowlists.
+type Client = trino.Client
+
+// QueryResult is the result of a query run through Client.
+type QueryResult = trino.QueryResult
+
+// ToolHandlers serves the Trino tools. Fields left nil disable the matching
+// optional features, such as result pagination or data quality checks.
+type ToolHandlers = mcp.TrinoHandlers
+
+// Check is a data quality check served by the run_checks tool.
+type Check = trino.Check
+
+// ToolHook runs deployment code before and after each tool call, for custom
+// policy, transformation, or logging.
+type ToolHook = mcp.ToolHook
+
+// ToolHookFuncs adapts plain functions to ToolHook.
+type ToolHookFuncs = mcp.ToolHookFuncs
+
+// LoadConfig reads the configuration from the same environment variables as
+// the mcp-trino server.
+func LoadConfig() (*Config, error) {
+	return config.NewTrinoConfig()
+}
+
+// NewClient connects to Trino.
+func NewClient(cfg *Config) (*Client, error) {
+	return trino.NewClient(cfg)
+}
+
+// NewToolHandlers creates the tool handlers for a client.
+func NewToolHandlers(client *Client, cfg *Config) *ToolHandlers {
+	return mcp.NewTrinoHandlers(client, cfg)
+}
+
+// RegisterTools adds the Trino tools to an MCP server.
+func RegisterTools(s *server.MCPServer, h *ToolHandlers) {
+	mcp.RegisterTrinoTools(s, h)
+}
+
+// HookMiddleware runs hooks around each tool call. Add it after
+// AuthMiddleware so hooks can see the authenticated user.
+func HookMiddleware(hooks ...ToolHook) server.ToolHandlerMiddleware {
+	return mcp.ToolHookMiddleware(hooks...)
+}
+
+// RegisterToolHook adds a hook to the standalone mcp-trino server. Call it
+// from an init function in a file added to cmd/ in a custom build.
+func RegisterToolHook(hook ToolHook) {
+	mcp.RegisterToolHook(hook)
+}
+
+// UserIdentity returns the authenticated caller of a tool call, for use in
+// hooks.
+func UserIdentity(ctx context.Context) string {
+	return trino.UserIdentity(ctx)
+}
+
+// LoadChecks reads data quality checks for ToolHandlers.Checks from a YAML
+// file.
+func LoadChecks(path string) ([]Check, error) {
+	return trino.LoadChecks(path)
+}
+
+// NewAuth creates the OAuth server for cfg's OAuth settings. Add
+// AuthMiddleware to the MCP server, register the OAuth endpoints with
+// RegisterHandlers, and pass the token from HTTP requests with
+// HTTPContextFunc.
+func NewAuth(cfg *Config) (*oauth.Server, error) {
+	return mcp.NewOAuthServer(cfg)
+}
+
+// AuthMiddleware authenticates each tool call and places the user in the
+// context, where tool handlers read it for impersonation and attribution.
+func AuthMiddleware(auth *oauth.Server) server.ToolHandlerMiddleware {
+	return auth.Middleware()
+}
+
+// HTTPContextFunc copies the bearer token of each HTTP request into the
+// context for AuthMiddleware. Pass it to server.WithHTTPContextFunc.
+func HTTPContextFunc() server.HTTPContextFunc {
+	return oauth.CreateHTTPContextFunc()
+}
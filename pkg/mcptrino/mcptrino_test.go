@@ -0,0 +1,38 @@
+package mcptrino
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRegisterTools(t *testing.T) {
+	s := server.NewMCPServer("embedder", "1.0.0", server.WithToolCapabilities(true))
+	RegisterTools(s, NewToolHandlers(nil, &Config{}))
+
+	tools := s.ListTools()
+	for _, name := range []string{"execute_query", "list_catalogs", "get_table_schema", "run_checks"} {
+		if _, ok := tools[name]; !ok {
+			t.Errorf("tool %s was not registered", name)
+		}
+	}
+}
+
+func TestAuthMiddlewareRequiresToken(t *testing.T) {
+	auth, err := NewAuth(&Config{OAuthMode: "native", OAuthProvider: "hmac", OIDCAudience: "trino", JWTSecret: strings.Repeat("k", 32)})
+	if err != nil {
+		t.Fatalf("NewAuth() error = %v", err)
+	}
+
+	called := false
+	handler := AuthMiddleware(auth)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	})
+	if _, err := handler(context.Background(), mcp.CallToolRequest{}); err == nil || called {
+		t.Errorf("handler error = %v, called = %v; want the call rejected without a token", err, called)
+	}
+}